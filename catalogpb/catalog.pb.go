@@ -0,0 +1,1129 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: catalog.proto
+
+package catalogpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Album struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Release date in YYYY-MM-DD format.
+	ReleaseDate string  `protobuf:"bytes,3,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	Genre       string  `protobuf:"bytes,4,opt,name=genre,proto3" json:"genre,omitempty"`
+	Price       float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Description string  `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// Credits of the requested musician; only set by ListAlbumsByMusician.
+	Credits []*Credit `protobuf:"bytes,7,rep,name=credits,proto3" json:"credits,omitempty"`
+	// 0 for albums without a label.
+	LabelId uint32 `protobuf:"varint,8,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	// Unique within the label.
+	CatalogNumber string `protobuf:"bytes,9,opt,name=catalog_number,json=catalogNumber,proto3" json:"catalog_number,omitempty"`
+	// ISO 4217 code the price is in; defaults to USD.
+	Currency      string `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Album) Reset() {
+	*x = Album{}
+	mi := &file_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Album) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Album) ProtoMessage() {}
+
+func (x *Album) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Album.ProtoReflect.Descriptor instead.
+func (*Album) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Album) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Album) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Album) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *Album) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *Album) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Album) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Album) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+func (x *Album) GetLabelId() uint32 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
+func (x *Album) GetCatalogNumber() string {
+	if x != nil {
+		return x.CatalogNumber
+	}
+	return ""
+}
+
+func (x *Album) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Musician struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MusicianType string                 `protobuf:"bytes,3,opt,name=musician_type,json=musicianType,proto3" json:"musician_type,omitempty"`
+	// Either "person" or "group"; defaults to "person" on creation.
+	Kind string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Credits on the requested album; only set by ListMusiciansByAlbum.
+	Credits       []*Credit `protobuf:"bytes,5,rep,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Musician) Reset() {
+	*x = Musician{}
+	mi := &file_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Musician) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Musician) ProtoMessage() {}
+
+func (x *Musician) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Musician.ProtoReflect.Descriptor instead.
+func (*Musician) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Musician) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Musician) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Musician) GetMusicianType() string {
+	if x != nil {
+		return x.MusicianType
+	}
+	return ""
+}
+
+func (x *Musician) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Musician) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+// Credit records what a musician did on an album.
+type Credit struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	MusicianId uint32                 `protobuf:"varint,1,opt,name=musician_id,json=musicianId,proto3" json:"musician_id,omitempty"`
+	// One of performer, producer, composer, engineer or featured; defaults to performer.
+	Role       string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Instrument string `protobuf:"bytes,3,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	// Track number the credit is limited to; 0 credits the whole album.
+	Track         uint32 `protobuf:"varint,4,opt,name=track,proto3" json:"track,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credit) Reset() {
+	*x = Credit{}
+	mi := &file_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credit) ProtoMessage() {}
+
+func (x *Credit) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credit.ProtoReflect.Descriptor instead.
+func (*Credit) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Credit) GetMusicianId() uint32 {
+	if x != nil {
+		return x.MusicianId
+	}
+	return 0
+}
+
+func (x *Credit) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Credit) GetInstrument() string {
+	if x != nil {
+		return x.Instrument
+	}
+	return ""
+}
+
+func (x *Credit) GetTrack() uint32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+type CreateAlbumRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Album *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	// Credited as performers on the whole album.
+	MusicianIds   []uint32  `protobuf:"varint,2,rep,packed,name=musician_ids,json=musicianIds,proto3" json:"musician_ids,omitempty"`
+	Credits       []*Credit `protobuf:"bytes,3,rep,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlbumRequest) Reset() {
+	*x = CreateAlbumRequest{}
+	mi := &file_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlbumRequest) ProtoMessage() {}
+
+func (x *CreateAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlbumRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateAlbumRequest) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+func (x *CreateAlbumRequest) GetMusicianIds() []uint32 {
+	if x != nil {
+		return x.MusicianIds
+	}
+	return nil
+}
+
+func (x *CreateAlbumRequest) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+type UpdateAlbumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlbumRequest) Reset() {
+	*x = UpdateAlbumRequest{}
+	mi := &file_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlbumRequest) ProtoMessage() {}
+
+func (x *UpdateAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlbumRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateAlbumRequest) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+type DeleteAlbumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlbumRequest) Reset() {
+	*x = DeleteAlbumRequest{}
+	mi := &file_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlbumRequest) ProtoMessage() {}
+
+func (x *DeleteAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlbumRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteAlbumRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteAlbumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlbumResponse) Reset() {
+	*x = DeleteAlbumResponse{}
+	mi := &file_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlbumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlbumResponse) ProtoMessage() {}
+
+func (x *DeleteAlbumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlbumResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlbumResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+type ListAlbumsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Albums tagged with this genre or any of its descendants; matched by name or alias.
+	Genre      string `protobuf:"bytes,1,opt,name=genre,proto3" json:"genre,omitempty"`
+	MusicianId uint32 `protobuf:"varint,2,opt,name=musician_id,json=musicianId,proto3" json:"musician_id,omitempty"`
+	// ISO 4217 code to report prices in; empty for each album's own currency.
+	Currency      string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlbumsRequest) Reset() {
+	*x = ListAlbumsRequest{}
+	mi := &file_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlbumsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlbumsRequest) ProtoMessage() {}
+
+func (x *ListAlbumsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlbumsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlbumsRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListAlbumsRequest) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *ListAlbumsRequest) GetMusicianId() uint32 {
+	if x != nil {
+		return x.MusicianId
+	}
+	return 0
+}
+
+func (x *ListAlbumsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type LinkMusiciansToAlbumRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AlbumId uint32                 `protobuf:"varint,1,opt,name=album_id,json=albumId,proto3" json:"album_id,omitempty"`
+	// Credited as performers on the whole album.
+	MusicianIds   []uint32  `protobuf:"varint,2,rep,packed,name=musician_ids,json=musicianIds,proto3" json:"musician_ids,omitempty"`
+	Credits       []*Credit `protobuf:"bytes,3,rep,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkMusiciansToAlbumRequest) Reset() {
+	*x = LinkMusiciansToAlbumRequest{}
+	mi := &file_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkMusiciansToAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkMusiciansToAlbumRequest) ProtoMessage() {}
+
+func (x *LinkMusiciansToAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkMusiciansToAlbumRequest.ProtoReflect.Descriptor instead.
+func (*LinkMusiciansToAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LinkMusiciansToAlbumRequest) GetAlbumId() uint32 {
+	if x != nil {
+		return x.AlbumId
+	}
+	return 0
+}
+
+func (x *LinkMusiciansToAlbumRequest) GetMusicianIds() []uint32 {
+	if x != nil {
+		return x.MusicianIds
+	}
+	return nil
+}
+
+func (x *LinkMusiciansToAlbumRequest) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+type LinkMusiciansToAlbumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkMusiciansToAlbumResponse) Reset() {
+	*x = LinkMusiciansToAlbumResponse{}
+	mi := &file_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkMusiciansToAlbumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkMusiciansToAlbumResponse) ProtoMessage() {}
+
+func (x *LinkMusiciansToAlbumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkMusiciansToAlbumResponse.ProtoReflect.Descriptor instead.
+func (*LinkMusiciansToAlbumResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+type ListAlbumsByMusicianRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	MusicianId uint32                 `protobuf:"varint,1,opt,name=musician_id,json=musicianId,proto3" json:"musician_id,omitempty"`
+	// Only credits with this role; empty for all.
+	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// ISO 4217 code to report prices in; empty for each album's own currency.
+	Currency      string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlbumsByMusicianRequest) Reset() {
+	*x = ListAlbumsByMusicianRequest{}
+	mi := &file_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlbumsByMusicianRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlbumsByMusicianRequest) ProtoMessage() {}
+
+func (x *ListAlbumsByMusicianRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlbumsByMusicianRequest.ProtoReflect.Descriptor instead.
+func (*ListAlbumsByMusicianRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListAlbumsByMusicianRequest) GetMusicianId() uint32 {
+	if x != nil {
+		return x.MusicianId
+	}
+	return 0
+}
+
+func (x *ListAlbumsByMusicianRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListAlbumsByMusicianRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreateMusicianRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Musician      *Musician              `protobuf:"bytes,1,opt,name=musician,proto3" json:"musician,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMusicianRequest) Reset() {
+	*x = CreateMusicianRequest{}
+	mi := &file_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMusicianRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMusicianRequest) ProtoMessage() {}
+
+func (x *CreateMusicianRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMusicianRequest.ProtoReflect.Descriptor instead.
+func (*CreateMusicianRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateMusicianRequest) GetMusician() *Musician {
+	if x != nil {
+		return x.Musician
+	}
+	return nil
+}
+
+type UpdateMusicianRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Musician      *Musician              `protobuf:"bytes,1,opt,name=musician,proto3" json:"musician,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMusicianRequest) Reset() {
+	*x = UpdateMusicianRequest{}
+	mi := &file_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMusicianRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMusicianRequest) ProtoMessage() {}
+
+func (x *UpdateMusicianRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMusicianRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMusicianRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateMusicianRequest) GetMusician() *Musician {
+	if x != nil {
+		return x.Musician
+	}
+	return nil
+}
+
+type DeleteMusicianRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMusicianRequest) Reset() {
+	*x = DeleteMusicianRequest{}
+	mi := &file_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMusicianRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMusicianRequest) ProtoMessage() {}
+
+func (x *DeleteMusicianRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMusicianRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMusicianRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteMusicianRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteMusicianResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMusicianResponse) Reset() {
+	*x = DeleteMusicianResponse{}
+	mi := &file_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMusicianResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMusicianResponse) ProtoMessage() {}
+
+func (x *DeleteMusicianResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMusicianResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMusicianResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+type ListMusiciansRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMusiciansRequest) Reset() {
+	*x = ListMusiciansRequest{}
+	mi := &file_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMusiciansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMusiciansRequest) ProtoMessage() {}
+
+func (x *ListMusiciansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMusiciansRequest.ProtoReflect.Descriptor instead.
+func (*ListMusiciansRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+type ListMusiciansByAlbumRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AlbumId uint32                 `protobuf:"varint,1,opt,name=album_id,json=albumId,proto3" json:"album_id,omitempty"`
+	// Only credits with this role; empty for all.
+	Role          string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMusiciansByAlbumRequest) Reset() {
+	*x = ListMusiciansByAlbumRequest{}
+	mi := &file_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMusiciansByAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMusiciansByAlbumRequest) ProtoMessage() {}
+
+func (x *ListMusiciansByAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMusiciansByAlbumRequest.ProtoReflect.Descriptor instead.
+func (*ListMusiciansByAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListMusiciansByAlbumRequest) GetAlbumId() uint32 {
+	if x != nil {
+		return x.AlbumId
+	}
+	return 0
+}
+
+func (x *ListMusiciansByAlbumRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+var File_catalog_proto protoreflect.FileDescriptor
+
+const file_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\rcatalog.proto\x12\x12jukebox.catalog.v1\"\xb0\x02\n" +
+	"\x05Album\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\frelease_date\x18\x03 \x01(\tR\vreleaseDate\x12\x14\n" +
+	"\x05genre\x18\x04 \x01(\tR\x05genre\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x124\n" +
+	"\acredits\x18\a \x03(\v2\x1a.jukebox.catalog.v1.CreditR\acredits\x12\x19\n" +
+	"\blabel_id\x18\b \x01(\rR\alabelId\x12%\n" +
+	"\x0ecatalog_number\x18\t \x01(\tR\rcatalogNumber\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrency\"\x9d\x01\n" +
+	"\bMusician\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12#\n" +
+	"\rmusician_type\x18\x03 \x01(\tR\fmusicianType\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x124\n" +
+	"\acredits\x18\x05 \x03(\v2\x1a.jukebox.catalog.v1.CreditR\acredits\"s\n" +
+	"\x06Credit\x12\x1f\n" +
+	"\vmusician_id\x18\x01 \x01(\rR\n" +
+	"musicianId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\x03 \x01(\tR\n" +
+	"instrument\x12\x14\n" +
+	"\x05track\x18\x04 \x01(\rR\x05track\"\x9e\x01\n" +
+	"\x12CreateAlbumRequest\x12/\n" +
+	"\x05album\x18\x01 \x01(\v2\x19.jukebox.catalog.v1.AlbumR\x05album\x12!\n" +
+	"\fmusician_ids\x18\x02 \x03(\rR\vmusicianIds\x124\n" +
+	"\acredits\x18\x03 \x03(\v2\x1a.jukebox.catalog.v1.CreditR\acredits\"E\n" +
+	"\x12UpdateAlbumRequest\x12/\n" +
+	"\x05album\x18\x01 \x01(\v2\x19.jukebox.catalog.v1.AlbumR\x05album\"$\n" +
+	"\x12DeleteAlbumRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"\x15\n" +
+	"\x13DeleteAlbumResponse\"f\n" +
+	"\x11ListAlbumsRequest\x12\x14\n" +
+	"\x05genre\x18\x01 \x01(\tR\x05genre\x12\x1f\n" +
+	"\vmusician_id\x18\x02 \x01(\rR\n" +
+	"musicianId\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\x91\x01\n" +
+	"\x1bLinkMusiciansToAlbumRequest\x12\x19\n" +
+	"\balbum_id\x18\x01 \x01(\rR\aalbumId\x12!\n" +
+	"\fmusician_ids\x18\x02 \x03(\rR\vmusicianIds\x124\n" +
+	"\acredits\x18\x03 \x03(\v2\x1a.jukebox.catalog.v1.CreditR\acredits\"\x1e\n" +
+	"\x1cLinkMusiciansToAlbumResponse\"n\n" +
+	"\x1bListAlbumsByMusicianRequest\x12\x1f\n" +
+	"\vmusician_id\x18\x01 \x01(\rR\n" +
+	"musicianId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"Q\n" +
+	"\x15CreateMusicianRequest\x128\n" +
+	"\bmusician\x18\x01 \x01(\v2\x1c.jukebox.catalog.v1.MusicianR\bmusician\"Q\n" +
+	"\x15UpdateMusicianRequest\x128\n" +
+	"\bmusician\x18\x01 \x01(\v2\x1c.jukebox.catalog.v1.MusicianR\bmusician\"'\n" +
+	"\x15DeleteMusicianRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"\x18\n" +
+	"\x16DeleteMusicianResponse\"\x16\n" +
+	"\x14ListMusiciansRequest\"L\n" +
+	"\x1bListMusiciansByAlbumRequest\x12\x19\n" +
+	"\balbum_id\x18\x01 \x01(\rR\aalbumId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role2\xa3\b\n" +
+	"\aCatalog\x12P\n" +
+	"\vCreateAlbum\x12&.jukebox.catalog.v1.CreateAlbumRequest\x1a\x19.jukebox.catalog.v1.Album\x12P\n" +
+	"\vUpdateAlbum\x12&.jukebox.catalog.v1.UpdateAlbumRequest\x1a\x19.jukebox.catalog.v1.Album\x12^\n" +
+	"\vDeleteAlbum\x12&.jukebox.catalog.v1.DeleteAlbumRequest\x1a'.jukebox.catalog.v1.DeleteAlbumResponse\x12P\n" +
+	"\n" +
+	"ListAlbums\x12%.jukebox.catalog.v1.ListAlbumsRequest\x1a\x19.jukebox.catalog.v1.Album0\x01\x12y\n" +
+	"\x14LinkMusiciansToAlbum\x12/.jukebox.catalog.v1.LinkMusiciansToAlbumRequest\x1a0.jukebox.catalog.v1.LinkMusiciansToAlbumResponse\x12d\n" +
+	"\x14ListAlbumsByMusician\x12/.jukebox.catalog.v1.ListAlbumsByMusicianRequest\x1a\x19.jukebox.catalog.v1.Album0\x01\x12Y\n" +
+	"\x0eCreateMusician\x12).jukebox.catalog.v1.CreateMusicianRequest\x1a\x1c.jukebox.catalog.v1.Musician\x12Y\n" +
+	"\x0eUpdateMusician\x12).jukebox.catalog.v1.UpdateMusicianRequest\x1a\x1c.jukebox.catalog.v1.Musician\x12g\n" +
+	"\x0eDeleteMusician\x12).jukebox.catalog.v1.DeleteMusicianRequest\x1a*.jukebox.catalog.v1.DeleteMusicianResponse\x12Y\n" +
+	"\rListMusicians\x12(.jukebox.catalog.v1.ListMusiciansRequest\x1a\x1c.jukebox.catalog.v1.Musician0\x01\x12g\n" +
+	"\x14ListMusiciansByAlbum\x12/.jukebox.catalog.v1.ListMusiciansByAlbumRequest\x1a\x1c.jukebox.catalog.v1.Musician0\x01B\x13Z\x11jukebox/catalogpbb\x06proto3"
+
+var (
+	file_catalog_proto_rawDescOnce sync.Once
+	file_catalog_proto_rawDescData []byte
+)
+
+func file_catalog_proto_rawDescGZIP() []byte {
+	file_catalog_proto_rawDescOnce.Do(func() {
+		file_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_catalog_proto_rawDesc), len(file_catalog_proto_rawDesc)))
+	})
+	return file_catalog_proto_rawDescData
+}
+
+var file_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_catalog_proto_goTypes = []any{
+	(*Album)(nil),                        // 0: jukebox.catalog.v1.Album
+	(*Musician)(nil),                     // 1: jukebox.catalog.v1.Musician
+	(*Credit)(nil),                       // 2: jukebox.catalog.v1.Credit
+	(*CreateAlbumRequest)(nil),           // 3: jukebox.catalog.v1.CreateAlbumRequest
+	(*UpdateAlbumRequest)(nil),           // 4: jukebox.catalog.v1.UpdateAlbumRequest
+	(*DeleteAlbumRequest)(nil),           // 5: jukebox.catalog.v1.DeleteAlbumRequest
+	(*DeleteAlbumResponse)(nil),          // 6: jukebox.catalog.v1.DeleteAlbumResponse
+	(*ListAlbumsRequest)(nil),            // 7: jukebox.catalog.v1.ListAlbumsRequest
+	(*LinkMusiciansToAlbumRequest)(nil),  // 8: jukebox.catalog.v1.LinkMusiciansToAlbumRequest
+	(*LinkMusiciansToAlbumResponse)(nil), // 9: jukebox.catalog.v1.LinkMusiciansToAlbumResponse
+	(*ListAlbumsByMusicianRequest)(nil),  // 10: jukebox.catalog.v1.ListAlbumsByMusicianRequest
+	(*CreateMusicianRequest)(nil),        // 11: jukebox.catalog.v1.CreateMusicianRequest
+	(*UpdateMusicianRequest)(nil),        // 12: jukebox.catalog.v1.UpdateMusicianRequest
+	(*DeleteMusicianRequest)(nil),        // 13: jukebox.catalog.v1.DeleteMusicianRequest
+	(*DeleteMusicianResponse)(nil),       // 14: jukebox.catalog.v1.DeleteMusicianResponse
+	(*ListMusiciansRequest)(nil),         // 15: jukebox.catalog.v1.ListMusiciansRequest
+	(*ListMusiciansByAlbumRequest)(nil),  // 16: jukebox.catalog.v1.ListMusiciansByAlbumRequest
+}
+var file_catalog_proto_depIdxs = []int32{
+	2,  // 0: jukebox.catalog.v1.Album.credits:type_name -> jukebox.catalog.v1.Credit
+	2,  // 1: jukebox.catalog.v1.Musician.credits:type_name -> jukebox.catalog.v1.Credit
+	0,  // 2: jukebox.catalog.v1.CreateAlbumRequest.album:type_name -> jukebox.catalog.v1.Album
+	2,  // 3: jukebox.catalog.v1.CreateAlbumRequest.credits:type_name -> jukebox.catalog.v1.Credit
+	0,  // 4: jukebox.catalog.v1.UpdateAlbumRequest.album:type_name -> jukebox.catalog.v1.Album
+	2,  // 5: jukebox.catalog.v1.LinkMusiciansToAlbumRequest.credits:type_name -> jukebox.catalog.v1.Credit
+	1,  // 6: jukebox.catalog.v1.CreateMusicianRequest.musician:type_name -> jukebox.catalog.v1.Musician
+	1,  // 7: jukebox.catalog.v1.UpdateMusicianRequest.musician:type_name -> jukebox.catalog.v1.Musician
+	3,  // 8: jukebox.catalog.v1.Catalog.CreateAlbum:input_type -> jukebox.catalog.v1.CreateAlbumRequest
+	4,  // 9: jukebox.catalog.v1.Catalog.UpdateAlbum:input_type -> jukebox.catalog.v1.UpdateAlbumRequest
+	5,  // 10: jukebox.catalog.v1.Catalog.DeleteAlbum:input_type -> jukebox.catalog.v1.DeleteAlbumRequest
+	7,  // 11: jukebox.catalog.v1.Catalog.ListAlbums:input_type -> jukebox.catalog.v1.ListAlbumsRequest
+	8,  // 12: jukebox.catalog.v1.Catalog.LinkMusiciansToAlbum:input_type -> jukebox.catalog.v1.LinkMusiciansToAlbumRequest
+	10, // 13: jukebox.catalog.v1.Catalog.ListAlbumsByMusician:input_type -> jukebox.catalog.v1.ListAlbumsByMusicianRequest
+	11, // 14: jukebox.catalog.v1.Catalog.CreateMusician:input_type -> jukebox.catalog.v1.CreateMusicianRequest
+	12, // 15: jukebox.catalog.v1.Catalog.UpdateMusician:input_type -> jukebox.catalog.v1.UpdateMusicianRequest
+	13, // 16: jukebox.catalog.v1.Catalog.DeleteMusician:input_type -> jukebox.catalog.v1.DeleteMusicianRequest
+	15, // 17: jukebox.catalog.v1.Catalog.ListMusicians:input_type -> jukebox.catalog.v1.ListMusiciansRequest
+	16, // 18: jukebox.catalog.v1.Catalog.ListMusiciansByAlbum:input_type -> jukebox.catalog.v1.ListMusiciansByAlbumRequest
+	0,  // 19: jukebox.catalog.v1.Catalog.CreateAlbum:output_type -> jukebox.catalog.v1.Album
+	0,  // 20: jukebox.catalog.v1.Catalog.UpdateAlbum:output_type -> jukebox.catalog.v1.Album
+	6,  // 21: jukebox.catalog.v1.Catalog.DeleteAlbum:output_type -> jukebox.catalog.v1.DeleteAlbumResponse
+	0,  // 22: jukebox.catalog.v1.Catalog.ListAlbums:output_type -> jukebox.catalog.v1.Album
+	9,  // 23: jukebox.catalog.v1.Catalog.LinkMusiciansToAlbum:output_type -> jukebox.catalog.v1.LinkMusiciansToAlbumResponse
+	0,  // 24: jukebox.catalog.v1.Catalog.ListAlbumsByMusician:output_type -> jukebox.catalog.v1.Album
+	1,  // 25: jukebox.catalog.v1.Catalog.CreateMusician:output_type -> jukebox.catalog.v1.Musician
+	1,  // 26: jukebox.catalog.v1.Catalog.UpdateMusician:output_type -> jukebox.catalog.v1.Musician
+	14, // 27: jukebox.catalog.v1.Catalog.DeleteMusician:output_type -> jukebox.catalog.v1.DeleteMusicianResponse
+	1,  // 28: jukebox.catalog.v1.Catalog.ListMusicians:output_type -> jukebox.catalog.v1.Musician
+	1,  // 29: jukebox.catalog.v1.Catalog.ListMusiciansByAlbum:output_type -> jukebox.catalog.v1.Musician
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_catalog_proto_init() }
+func file_catalog_proto_init() {
+	if File_catalog_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_catalog_proto_rawDesc), len(file_catalog_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_catalog_proto_goTypes,
+		DependencyIndexes: file_catalog_proto_depIdxs,
+		MessageInfos:      file_catalog_proto_msgTypes,
+	}.Build()
+	File_catalog_proto = out.File
+	file_catalog_proto_goTypes = nil
+	file_catalog_proto_depIdxs = nil
+}
@@ -0,0 +1,528 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: catalog.proto
+
+package catalogpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Catalog_CreateAlbum_FullMethodName          = "/jukebox.catalog.v1.Catalog/CreateAlbum"
+	Catalog_UpdateAlbum_FullMethodName          = "/jukebox.catalog.v1.Catalog/UpdateAlbum"
+	Catalog_DeleteAlbum_FullMethodName          = "/jukebox.catalog.v1.Catalog/DeleteAlbum"
+	Catalog_ListAlbums_FullMethodName           = "/jukebox.catalog.v1.Catalog/ListAlbums"
+	Catalog_LinkMusiciansToAlbum_FullMethodName = "/jukebox.catalog.v1.Catalog/LinkMusiciansToAlbum"
+	Catalog_ListAlbumsByMusician_FullMethodName = "/jukebox.catalog.v1.Catalog/ListAlbumsByMusician"
+	Catalog_CreateMusician_FullMethodName       = "/jukebox.catalog.v1.Catalog/CreateMusician"
+	Catalog_UpdateMusician_FullMethodName       = "/jukebox.catalog.v1.Catalog/UpdateMusician"
+	Catalog_DeleteMusician_FullMethodName       = "/jukebox.catalog.v1.Catalog/DeleteMusician"
+	Catalog_ListMusicians_FullMethodName        = "/jukebox.catalog.v1.Catalog/ListMusicians"
+	Catalog_ListMusiciansByAlbum_FullMethodName = "/jukebox.catalog.v1.Catalog/ListMusiciansByAlbum"
+)
+
+// CatalogClient is the client API for Catalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Catalog exposes the album and musician operations of the REST API over gRPC.
+// List operations stream one message per record so large results are not
+// buffered into a single response.
+type CatalogClient interface {
+	CreateAlbum(ctx context.Context, in *CreateAlbumRequest, opts ...grpc.CallOption) (*Album, error)
+	UpdateAlbum(ctx context.Context, in *UpdateAlbumRequest, opts ...grpc.CallOption) (*Album, error)
+	DeleteAlbum(ctx context.Context, in *DeleteAlbumRequest, opts ...grpc.CallOption) (*DeleteAlbumResponse, error)
+	// Albums sorted by release date, oldest first, optionally filtered by genre or musician.
+	ListAlbums(ctx context.Context, in *ListAlbumsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Album], error)
+	LinkMusiciansToAlbum(ctx context.Context, in *LinkMusiciansToAlbumRequest, opts ...grpc.CallOption) (*LinkMusiciansToAlbumResponse, error)
+	// Albums of a musician sorted by price, lowest first, with the musician's credits.
+	ListAlbumsByMusician(ctx context.Context, in *ListAlbumsByMusicianRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Album], error)
+	CreateMusician(ctx context.Context, in *CreateMusicianRequest, opts ...grpc.CallOption) (*Musician, error)
+	UpdateMusician(ctx context.Context, in *UpdateMusicianRequest, opts ...grpc.CallOption) (*Musician, error)
+	DeleteMusician(ctx context.Context, in *DeleteMusicianRequest, opts ...grpc.CallOption) (*DeleteMusicianResponse, error)
+	ListMusicians(ctx context.Context, in *ListMusiciansRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Musician], error)
+	// Musicians on an album sorted by name, with their credits.
+	ListMusiciansByAlbum(ctx context.Context, in *ListMusiciansByAlbumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Musician], error)
+}
+
+type catalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatalogClient(cc grpc.ClientConnInterface) CatalogClient {
+	return &catalogClient{cc}
+}
+
+func (c *catalogClient) CreateAlbum(ctx context.Context, in *CreateAlbumRequest, opts ...grpc.CallOption) (*Album, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Album)
+	err := c.cc.Invoke(ctx, Catalog_CreateAlbum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) UpdateAlbum(ctx context.Context, in *UpdateAlbumRequest, opts ...grpc.CallOption) (*Album, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Album)
+	err := c.cc.Invoke(ctx, Catalog_UpdateAlbum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) DeleteAlbum(ctx context.Context, in *DeleteAlbumRequest, opts ...grpc.CallOption) (*DeleteAlbumResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlbumResponse)
+	err := c.cc.Invoke(ctx, Catalog_DeleteAlbum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListAlbums(ctx context.Context, in *ListAlbumsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Album], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[0], Catalog_ListAlbums_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListAlbumsRequest, Album]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListAlbumsClient = grpc.ServerStreamingClient[Album]
+
+func (c *catalogClient) LinkMusiciansToAlbum(ctx context.Context, in *LinkMusiciansToAlbumRequest, opts ...grpc.CallOption) (*LinkMusiciansToAlbumResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkMusiciansToAlbumResponse)
+	err := c.cc.Invoke(ctx, Catalog_LinkMusiciansToAlbum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListAlbumsByMusician(ctx context.Context, in *ListAlbumsByMusicianRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Album], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[1], Catalog_ListAlbumsByMusician_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListAlbumsByMusicianRequest, Album]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListAlbumsByMusicianClient = grpc.ServerStreamingClient[Album]
+
+func (c *catalogClient) CreateMusician(ctx context.Context, in *CreateMusicianRequest, opts ...grpc.CallOption) (*Musician, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Musician)
+	err := c.cc.Invoke(ctx, Catalog_CreateMusician_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) UpdateMusician(ctx context.Context, in *UpdateMusicianRequest, opts ...grpc.CallOption) (*Musician, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Musician)
+	err := c.cc.Invoke(ctx, Catalog_UpdateMusician_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) DeleteMusician(ctx context.Context, in *DeleteMusicianRequest, opts ...grpc.CallOption) (*DeleteMusicianResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMusicianResponse)
+	err := c.cc.Invoke(ctx, Catalog_DeleteMusician_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListMusicians(ctx context.Context, in *ListMusiciansRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Musician], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[2], Catalog_ListMusicians_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListMusiciansRequest, Musician]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListMusiciansClient = grpc.ServerStreamingClient[Musician]
+
+func (c *catalogClient) ListMusiciansByAlbum(ctx context.Context, in *ListMusiciansByAlbumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Musician], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Catalog_ServiceDesc.Streams[3], Catalog_ListMusiciansByAlbum_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListMusiciansByAlbumRequest, Musician]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListMusiciansByAlbumClient = grpc.ServerStreamingClient[Musician]
+
+// CatalogServer is the server API for Catalog service.
+// All implementations must embed UnimplementedCatalogServer
+// for forward compatibility.
+//
+// Catalog exposes the album and musician operations of the REST API over gRPC.
+// List operations stream one message per record so large results are not
+// buffered into a single response.
+type CatalogServer interface {
+	CreateAlbum(context.Context, *CreateAlbumRequest) (*Album, error)
+	UpdateAlbum(context.Context, *UpdateAlbumRequest) (*Album, error)
+	DeleteAlbum(context.Context, *DeleteAlbumRequest) (*DeleteAlbumResponse, error)
+	// Albums sorted by release date, oldest first, optionally filtered by genre or musician.
+	ListAlbums(*ListAlbumsRequest, grpc.ServerStreamingServer[Album]) error
+	LinkMusiciansToAlbum(context.Context, *LinkMusiciansToAlbumRequest) (*LinkMusiciansToAlbumResponse, error)
+	// Albums of a musician sorted by price, lowest first, with the musician's credits.
+	ListAlbumsByMusician(*ListAlbumsByMusicianRequest, grpc.ServerStreamingServer[Album]) error
+	CreateMusician(context.Context, *CreateMusicianRequest) (*Musician, error)
+	UpdateMusician(context.Context, *UpdateMusicianRequest) (*Musician, error)
+	DeleteMusician(context.Context, *DeleteMusicianRequest) (*DeleteMusicianResponse, error)
+	ListMusicians(*ListMusiciansRequest, grpc.ServerStreamingServer[Musician]) error
+	// Musicians on an album sorted by name, with their credits.
+	ListMusiciansByAlbum(*ListMusiciansByAlbumRequest, grpc.ServerStreamingServer[Musician]) error
+	mustEmbedUnimplementedCatalogServer()
+}
+
+// UnimplementedCatalogServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCatalogServer struct{}
+
+func (UnimplementedCatalogServer) CreateAlbum(context.Context, *CreateAlbumRequest) (*Album, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAlbum not implemented")
+}
+func (UnimplementedCatalogServer) UpdateAlbum(context.Context, *UpdateAlbumRequest) (*Album, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAlbum not implemented")
+}
+func (UnimplementedCatalogServer) DeleteAlbum(context.Context, *DeleteAlbumRequest) (*DeleteAlbumResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAlbum not implemented")
+}
+func (UnimplementedCatalogServer) ListAlbums(*ListAlbumsRequest, grpc.ServerStreamingServer[Album]) error {
+	return status.Error(codes.Unimplemented, "method ListAlbums not implemented")
+}
+func (UnimplementedCatalogServer) LinkMusiciansToAlbum(context.Context, *LinkMusiciansToAlbumRequest) (*LinkMusiciansToAlbumResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LinkMusiciansToAlbum not implemented")
+}
+func (UnimplementedCatalogServer) ListAlbumsByMusician(*ListAlbumsByMusicianRequest, grpc.ServerStreamingServer[Album]) error {
+	return status.Error(codes.Unimplemented, "method ListAlbumsByMusician not implemented")
+}
+func (UnimplementedCatalogServer) CreateMusician(context.Context, *CreateMusicianRequest) (*Musician, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateMusician not implemented")
+}
+func (UnimplementedCatalogServer) UpdateMusician(context.Context, *UpdateMusicianRequest) (*Musician, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateMusician not implemented")
+}
+func (UnimplementedCatalogServer) DeleteMusician(context.Context, *DeleteMusicianRequest) (*DeleteMusicianResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteMusician not implemented")
+}
+func (UnimplementedCatalogServer) ListMusicians(*ListMusiciansRequest, grpc.ServerStreamingServer[Musician]) error {
+	return status.Error(codes.Unimplemented, "method ListMusicians not implemented")
+}
+func (UnimplementedCatalogServer) ListMusiciansByAlbum(*ListMusiciansByAlbumRequest, grpc.ServerStreamingServer[Musician]) error {
+	return status.Error(codes.Unimplemented, "method ListMusiciansByAlbum not implemented")
+}
+func (UnimplementedCatalogServer) mustEmbedUnimplementedCatalogServer() {}
+func (UnimplementedCatalogServer) testEmbeddedByValue()                 {}
+
+// UnsafeCatalogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CatalogServer will
+// result in compilation errors.
+type UnsafeCatalogServer interface {
+	mustEmbedUnimplementedCatalogServer()
+}
+
+func RegisterCatalogServer(s grpc.ServiceRegistrar, srv CatalogServer) {
+	// If the following call panics, it indicates UnimplementedCatalogServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Catalog_ServiceDesc, srv)
+}
+
+func _Catalog_CreateAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_CreateAlbum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateAlbum(ctx, req.(*CreateAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_UpdateAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).UpdateAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_UpdateAlbum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).UpdateAlbum(ctx, req.(*UpdateAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_DeleteAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).DeleteAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_DeleteAlbum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).DeleteAlbum(ctx, req.(*DeleteAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListAlbums_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAlbumsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListAlbums(m, &grpc.GenericServerStream[ListAlbumsRequest, Album]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListAlbumsServer = grpc.ServerStreamingServer[Album]
+
+func _Catalog_LinkMusiciansToAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkMusiciansToAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).LinkMusiciansToAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_LinkMusiciansToAlbum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).LinkMusiciansToAlbum(ctx, req.(*LinkMusiciansToAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListAlbumsByMusician_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAlbumsByMusicianRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListAlbumsByMusician(m, &grpc.GenericServerStream[ListAlbumsByMusicianRequest, Album]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListAlbumsByMusicianServer = grpc.ServerStreamingServer[Album]
+
+func _Catalog_CreateMusician_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMusicianRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateMusician(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_CreateMusician_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateMusician(ctx, req.(*CreateMusicianRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_UpdateMusician_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMusicianRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).UpdateMusician(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_UpdateMusician_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).UpdateMusician(ctx, req.(*UpdateMusicianRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_DeleteMusician_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMusicianRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).DeleteMusician(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_DeleteMusician_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).DeleteMusician(ctx, req.(*DeleteMusicianRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListMusicians_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListMusiciansRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListMusicians(m, &grpc.GenericServerStream[ListMusiciansRequest, Musician]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListMusiciansServer = grpc.ServerStreamingServer[Musician]
+
+func _Catalog_ListMusiciansByAlbum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListMusiciansByAlbumRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListMusiciansByAlbum(m, &grpc.GenericServerStream[ListMusiciansByAlbumRequest, Musician]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Catalog_ListMusiciansByAlbumServer = grpc.ServerStreamingServer[Musician]
+
+// Catalog_ServiceDesc is the grpc.ServiceDesc for Catalog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Catalog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "jukebox.catalog.v1.Catalog",
+	HandlerType: (*CatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAlbum",
+			Handler:    _Catalog_CreateAlbum_Handler,
+		},
+		{
+			MethodName: "UpdateAlbum",
+			Handler:    _Catalog_UpdateAlbum_Handler,
+		},
+		{
+			MethodName: "DeleteAlbum",
+			Handler:    _Catalog_DeleteAlbum_Handler,
+		},
+		{
+			MethodName: "LinkMusiciansToAlbum",
+			Handler:    _Catalog_LinkMusiciansToAlbum_Handler,
+		},
+		{
+			MethodName: "CreateMusician",
+			Handler:    _Catalog_CreateMusician_Handler,
+		},
+		{
+			MethodName: "UpdateMusician",
+			Handler:    _Catalog_UpdateMusician_Handler,
+		},
+		{
+			MethodName: "DeleteMusician",
+			Handler:    _Catalog_DeleteMusician_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListAlbums",
+			Handler:       _Catalog_ListAlbums_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListAlbumsByMusician",
+			Handler:       _Catalog_ListAlbumsByMusician_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListMusicians",
+			Handler:       _Catalog_ListMusicians_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListMusiciansByAlbum",
+			Handler:       _Catalog_ListMusiciansByAlbum_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "catalog.proto",
+}
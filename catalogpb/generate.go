@@ -0,0 +1,4 @@
+// Package catalogpb contains the protobuf messages and gRPC stubs generated from proto/catalog.proto.
+package catalogpb
+
+//go:generate protoc --proto_path=../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative catalog.proto
@@ -0,0 +1,120 @@
+// Command scan syncs the catalog with the audio files under library folders.
+//
+// Usage:
+//
+//	scan [-db path] [-dry-run] [-json] [-price amount] [-currency code] [folder ...]
+//
+// Folders default to those listed in JUKEBOX_LIBRARY, separated like PATH. Only files whose size or
+// modification time changed since the last scan are read again.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+)
+
+func main() {
+	dbPath := flag.String("db", "./jukebox.db", "SQLite database holding the catalog")
+	dryRun := flag.Bool("dry-run", false, "print the planned changes without making them")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	price := flag.Float64("price", 0, fmt.Sprintf("price of albums created from new files (default %d)", services.MinAlbumPrice))
+	currency := flag.String("currency", "", "currency of -price (default "+models.BaseCurrency+")")
+	flag.Parse()
+
+	roots := flag.Args()
+	if len(roots) == 0 {
+		roots = filepath.SplitList(os.Getenv("JUKEBOX_LIBRARY"))
+	}
+	if len(roots) == 0 {
+		fmt.Fprintln(os.Stderr, "scan: give the library folders as arguments or in JUKEBOX_LIBRARY")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := sql.Open("sqlite3", *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	// Wire the services the way the server does, so created albums are tagged with genres and
+	// their events are delivered by the server's outbox dispatcher
+	outboxRepo := &repositories.OutboxRepository{DB: db}
+	albumRepo := &repositories.AlbumRepository{DB: db, Outbox: outboxRepo}
+	musicianRepo := &repositories.MusicianRepository{DB: db, Outbox: outboxRepo}
+	genreService := &services.GenreService{Repo: &repositories.GenreRepository{DB: db}, AlbumRepo: albumRepo}
+	priceService := &services.PriceService{Repo: &repositories.PriceRepository{DB: db}, AlbumRepo: albumRepo}
+	albumService := &services.AlbumService{Repo: albumRepo, Genres: genreService, Prices: priceService}
+	scanner := &services.LibraryScanner{
+		Repo: &repositories.LibraryRepository{DB: db},
+		Ingest: &services.IngestService{
+			Repo:      &repositories.IngestRepository{DB: db},
+			Tracks:    &repositories.TrackRepository{DB: db},
+			Albums:    albumService,
+			Musicians: &services.MusicianService{Repo: musicianRepo},
+		},
+		Price:    *price,
+		Currency: *currency,
+		DryRun:   *dryRun,
+	}
+
+	report, err := scanner.Scan(roots)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+		return
+	}
+	printReport(report)
+}
+
+// printReport writes one line per change, then the totals.
+func printReport(report *models.ScanReport) {
+	for _, change := range report.Changes {
+		line := fmt.Sprintf("%-8s %s", change.Action, change.Path)
+		if change.From != "" {
+			line += " (from " + change.From + ")"
+		}
+		if change.Details != "" {
+			line += ": " + change.Details
+		}
+		if change.TrackID != 0 {
+			line += fmt.Sprintf(" (album %d, track %d)", change.AlbumID, change.TrackID)
+		}
+		if result := change.Result; result != nil {
+			line += " [" + result.Status
+			for _, conflict := range result.Conflicts {
+				line += fmt.Sprintf("; %s %s %q ≠ %q", conflict.Kind, conflict.Field, conflict.Existing, conflict.Incoming)
+			}
+			if result.Error != "" {
+				line += "; " + result.Error
+			}
+			line += "]"
+		}
+		fmt.Println(line)
+	}
+
+	summary := fmt.Sprintf("scanned %d files in %s: %d added, %d changed, %d moved, %d missing, %d retried, %d failed, %d conflicts, %d unchanged",
+		report.Scanned, strings.Join(report.Roots, ", "), report.Added, report.Changed, report.Moved, report.Missing,
+		report.Retried, report.Failed, report.Conflicts, report.Unchanged)
+	if report.DryRun {
+		summary = "dry run, nothing changed: " + summary
+	}
+	fmt.Println(summary)
+}
@@ -1,7 +1,8 @@
 package controllers
 
 import (
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"jukebox/models"
 	"jukebox/services"
 	"net/http"
@@ -14,29 +15,51 @@ type AlbumController struct {
 	Service services.AlbumServiceInterface
 }
 
-var albumDTO struct {
-	Name        string  `json:"name"`
-	ReleaseDate string  `json:"release_date"`
-	Genre       string  `json:"genre"`
-	Price       float64 `json:"price"`
-	Description string  `json:"description"`
-	MusicianIDs []uint  `json:"musician_ids"`
+type albumDTO struct {
+	Name          string  `json:"name" xml:"name"`
+	ReleaseDate   string  `json:"release_date" xml:"release_date"`
+	Genre         string  `json:"genre" xml:"genre"`
+	Price         float64 `json:"price" xml:"price"`
+	Currency      string  `json:"currency" xml:"currency"`
+	Description   string  `json:"description" xml:"description"`
+	LabelID       uint    `json:"label_id" xml:"label_id"`
+	CatalogNumber string  `json:"catalog_number" xml:"catalog_number"`
+	MusicianIDs   []uint  `json:"musician_ids" xml:"musician_ids>id"`
+	// Credits add roles; musicians listed in MusicianIDs are credited as performers. CSV bodies cannot
+	// carry credits.
+	Credits []models.Credit `json:"credits" xml:"credits>credit" csv:"-"`
 }
 
 func (c *AlbumController) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var albumDTO albumDTO
 
 	// Decode the request body into albumDTO
-	if err := json.NewDecoder(r.Body).Decode(&albumDTO); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(r, &albumDTO); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
 		return
 	}
 
+	credits := append(services.PerformerCredits(albumDTO.MusicianIDs), albumDTO.Credits...)
+	for i := range credits {
+		if err := services.ValidateCredit(&credits[i]); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	album := models.Album{
-		Name:        albumDTO.Name,
-		ReleaseDate: albumDTO.ReleaseDate,
-		Genre:       albumDTO.Genre,
-		Price:       albumDTO.Price,
-		Description: albumDTO.Description,
+		Name:          albumDTO.Name,
+		ReleaseDate:   albumDTO.ReleaseDate,
+		Genre:         albumDTO.Genre,
+		Price:         albumDTO.Price,
+		Currency:      albumDTO.Currency,
+		Description:   albumDTO.Description,
+		LabelID:       albumDTO.LabelID,
+		CatalogNumber: albumDTO.CatalogNumber,
 	}
 
 	// Insert album into the albums table
@@ -46,32 +69,40 @@ func (c *AlbumController) CreateAlbum(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Automatically link album to musicians by calling a helper function
-	if err := c.Service.LinkMusiciansToAlbum(album.ID, albumDTO.MusicianIDs); err != nil {
+	if err := c.Service.LinkMusiciansToAlbum(album.ID, credits); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(album)
+	writeResponse(w, r, http.StatusCreated, album)
 }
 
-// GetAlbums handles retrieving all albums from the database.
+// GetAlbums handles retrieving albums from the database, optionally filtered by musician or genre.
 func (c *AlbumController) GetAlbums(w http.ResponseWriter, r *http.Request) {
-	albums, err := c.Service.GetAlbums()
+	filter, err := albumFilterFromQuery(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	albums, err := c.Service.GetAlbums(filter)
+	if err != nil {
+		http.Error(w, err.Error(), priceErrorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(albums)
+	writeResponse(w, r, http.StatusOK, albums)
 }
 
 // UpdateAlbum handles updating an existing album.
 func (c *AlbumController) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
 	var album models.Album
-	if err := json.NewDecoder(r.Body).Decode(&album); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(r, &album); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
 		return
 	}
 
@@ -86,12 +117,25 @@ func (c *AlbumController) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 	album.ID = uint(albumID)
 
 	if err := c.Service.UpdateAlbum(&album); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), albumErrorStatus(err))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(album)
+	writeResponse(w, r, http.StatusOK, album)
+}
+
+// albumErrorStatus maps an invalid album to 400, a missing one to 404, and a locked currency or a catalog
+// number already in use to 409.
+func albumErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidAlbum):
+		return http.StatusBadRequest
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCurrencyLocked), errors.Is(err, services.ErrCatalogNumberTaken):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
 }
 
 // DeleteAlbum handles deleting an album by ID.
@@ -112,7 +156,7 @@ func (c *AlbumController) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// GetAlbumsByMusician handles retrieving albums for a specific musician, sorted by price.
+// GetAlbumsByMusician handles retrieving albums for a specific musician with their credits, sorted by price.
 func (c *AlbumController) GetAlbumsByMusician(w http.ResponseWriter, r *http.Request) {
 	// Parse musician ID from the request URL
 	vars := mux.Vars(r)
@@ -122,12 +166,43 @@ func (c *AlbumController) GetAlbumsByMusician(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	albums, err := c.Service.GetAlbumsByMusician(uint(musicianID))
+	role, ok := roleFromQuery(w, r)
+	if !ok {
+		return
+	}
+	currency, err := currencyFromQuery(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	albums, err := c.Service.GetAlbumsByMusician(uint(musicianID), role, currency)
+	if err != nil {
+		http.Error(w, err.Error(), priceErrorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(albums)
+	writeResponse(w, r, http.StatusOK, albums)
+}
+
+// currencyFromQuery reads the optional currency prices are reported in.
+func currencyFromQuery(r *http.Request) (string, error) {
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		return "", nil
+	}
+	return services.NormalizeCurrency(currency)
+}
+
+// roleFromQuery reads the optional credit role filter, answering 400 if it is not a known role.
+func roleFromQuery(w http.ResponseWriter, r *http.Request) (string, bool) {
+	role := r.URL.Query().Get("role")
+	if role == "" {
+		return "", true
+	}
+	if err := services.ValidateCreditRole(role); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return role, true
 }
@@ -57,6 +57,31 @@ func TestCreateAlbumController(t *testing.T) {
 	}
 }
 
+func TestCreateAlbumControllerCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	prices := &services.PriceService{Repo: &repositories.PriceRepository{DB: db}, AlbumRepo: albumRepo}
+	controller := &AlbumController{Service: &services.AlbumService{Repo: albumRepo, Prices: prices}}
+	if _, err := db.Exec("INSERT INTO exchange_rates (currency, rate) VALUES ('EUR', 0.5)"); err != nil {
+		t.Fatalf("failed to insert exchange rate: %v", err)
+	}
+
+	payload := `{"name": "Euro Album", "release_date": "2022-01-01", "price": 100, "currency": "EUR"}`
+	rr := httptest.NewRecorder()
+	controller.CreateAlbum(rr, httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	stored, err := albumRepo.GetAlbumByID(1)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if stored.Currency != "EUR" || stored.Price != 100 {
+		t.Errorf("expected the album stored at 100 EUR, got %v %s", stored.Price, stored.Currency)
+	}
+}
+
 func TestGetAlbumsController(t *testing.T) {
 	service := setupTestService(t)
 	controller := &AlbumController{Service: service}
@@ -121,6 +146,45 @@ func TestUpdateAlbumController(t *testing.T) {
 	}
 }
 
+func TestUpdateAlbumControllerErrors(t *testing.T) {
+	db := setupTestDB(t)
+	labels := &repositories.LabelRepository{DB: db}
+	service := &services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}, Labels: labels}
+	controller := &AlbumController{Service: service}
+
+	label := &models.Label{Name: "Blue Note"}
+	if err := labels.CreateLabel(label); err != nil {
+		t.Fatalf("failed to create label: %v", err)
+	}
+	for _, album := range []*models.Album{
+		{Name: "First Album", ReleaseDate: "2022-01-01", Price: 150, LabelID: label.ID, CatalogNumber: "BN-1"},
+		{Name: "Second Album", ReleaseDate: "2022-01-01", Price: 150},
+	} {
+		if err := service.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	tests := []struct {
+		id       string
+		payload  string
+		expected int
+	}{
+		{"2", `{"name": "Second Album", "release_date": "2022-01-01", "price": 150, "currency": "XYZ"}`, http.StatusBadRequest},
+		{"2", `{"name": "Second Album", "release_date": "2022-01-01", "price": 150, "label_id": 9}`, http.StatusBadRequest},
+		{"2", `{"name": "Second Album", "release_date": "2022-01-01", "price": 150, "label_id": 1, "catalog_number": "BN-1"}`, http.StatusConflict},
+		{"9", `{"name": "Missing Album", "release_date": "2022-01-01", "price": 150}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := mux.SetURLVars(httptest.NewRequest("PUT", "/albums/"+tt.id, bytes.NewBufferString(tt.payload)), map[string]string{"id": tt.id})
+		rr := httptest.NewRecorder()
+		controller.UpdateAlbum(rr, req)
+		if rr.Code != tt.expected {
+			t.Errorf("%s: expected status code %v, got %v: %s", tt.payload, tt.expected, rr.Code, rr.Body.String())
+		}
+	}
+}
+
 func TestDeleteAlbumController(t *testing.T) {
 	service := setupTestService(t)
 	controller := &AlbumController{Service: service}
@@ -138,7 +202,7 @@ func TestDeleteAlbumController(t *testing.T) {
 		t.Errorf("expected status code %v, got %v", http.StatusNoContent, rr.Code)
 	}
 
-	albums, _ := service.GetAlbums()
+	albums, _ := service.GetAlbums(models.AlbumFilter{})
 	if len(albums) != 0 {
 		t.Errorf("expected 0 albums, got %d", len(albums))
 	}
@@ -153,8 +217,8 @@ func TestGetAlbumsByMusicianController(t *testing.T) {
 
 	service.CreateAlbum(album1)
 	service.CreateAlbum(album2)
-	service.LinkMusiciansToAlbum(album1.ID, []uint{101})
-	service.LinkMusiciansToAlbum(album2.ID, []uint{101})
+	service.LinkMusiciansToAlbum(album1.ID, services.PerformerCredits([]uint{101}))
+	service.LinkMusiciansToAlbum(album2.ID, services.PerformerCredits([]uint{101}))
 
 	req := httptest.NewRequest("GET", "/musicians/101/albums", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "101"})
@@ -176,3 +240,56 @@ func TestGetAlbumsByMusicianController(t *testing.T) {
 		t.Errorf("expected 2 albums for musician 101, got %d", len(albums))
 	}
 }
+
+func TestGetAlbumsByMusicianControllerRole(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	payload := `{"name": "Produced Album", "release_date": "2022-01-01", "price": 200,
+		"musician_ids": [101], "credits": [{"musician_id": 101, "role": "producer"}, {"musician_id": 102, "role": "engineer"}]}`
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload))
+	rr := httptest.NewRecorder()
+	controller.CreateAlbum(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/musicians/101/albums?role=producer", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "101"})
+	rr = httptest.NewRecorder()
+	controller.GetAlbumsByMusician(rr, req)
+
+	var albums []models.CreditedAlbum
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if len(albums) != 1 || len(albums[0].Credits) != 1 || albums[0].Credits[0].Role != models.CreditProducer {
+		t.Errorf("expected one producer credit, got %+v", albums)
+	}
+
+	req = httptest.NewRequest("GET", "/musicians/101/albums?role=roadie", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "101"})
+	rr = httptest.NewRecorder()
+	controller.GetAlbumsByMusician(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestCreateAlbumControllerInvalidCredit(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	payload := `{"name": "Test Album", "release_date": "2022-01-01", "price": 200, "credits": [{"musician_id": 101, "role": "roadie"}]}`
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload))
+	rr := httptest.NewRecorder()
+	controller.CreateAlbum(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+	albums, _ := service.GetAlbums(models.AlbumFilter{})
+	if len(albums) != 0 {
+		t.Errorf("expected no album to be created, got %d", len(albums))
+	}
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ChartController struct {
+	Service services.ChartServiceInterface
+}
+
+// GetChart handles retrieving a weekly chart. Accepts period, genre and limit.
+func (c *ChartController) GetChart(w http.ResponseWriter, r *http.Request) {
+	limit, err := limitFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	chart, err := c.Service.GetChart(mux.Vars(r)["type"], query.Get("period"), query.Get("genre"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), chartErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, chart)
+}
+
+// chartErrorStatus maps a missing genre to 404 and anything else to 400.
+func chartErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
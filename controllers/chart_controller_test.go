@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGetChart(t *testing.T) {
+	db := setupTestDB(t)
+	service := &services.ChartService{Repo: &repositories.ChartRepository{DB: db}, Genres: &repositories.GenreRepository{DB: db}}
+	controller := &ChartController{Service: service}
+
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'Album 1', '2001-01-01', '', 1000, ''), (2, 'Album 2', '2002-01-01', '', 1000, '')`)
+	if err != nil {
+		t.Fatalf("failed to seed albums: %v", err)
+	}
+	lastWeek := time.Now().UTC().AddDate(0, 0, -7)
+	for albumID, count := range map[uint]int{1: 2, 2: 5} {
+		if err := service.RecordPlays(albumID, lastWeek, count); err != nil {
+			t.Fatalf("failed to record plays: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	controller.GetChart(rr, mux.SetURLVars(httptest.NewRequest("GET", "/charts/plays?limit=1", nil), map[string]string{"type": "plays"}))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body)
+	}
+	var chart models.Chart
+	if err := json.NewDecoder(rr.Body).Decode(&chart); err != nil {
+		t.Fatalf("failed to decode chart: %v", err)
+	}
+	if chart.Period != services.PeriodOf(lastWeek) || len(chart.Entries) != 1 || chart.Entries[0].Album.ID != 2 || chart.Entries[0].Movement != models.MovementNew {
+		t.Errorf("unexpected chart %+v", chart)
+	}
+
+	tests := []struct {
+		url, chartType string
+		status         int
+	}{
+		{"/charts/streams", "streams", http.StatusBadRequest},
+		{"/charts/plays?limit=many", "plays", http.StatusBadRequest},
+		{"/charts/plays?period=2026-41", "plays", http.StatusBadRequest},
+		{"/charts/ratings?genre=Polka", "ratings", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		controller.GetChart(rr, mux.SetURLVars(httptest.NewRequest("GET", tt.url, nil), map[string]string{"type": tt.chartType}))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status code %v, got %v", tt.url, tt.status, rr.Code)
+		}
+	}
+}
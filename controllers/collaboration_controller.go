@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type CollaborationController struct {
+	Service services.CollaborationServiceInterface
+}
+
+// GetCollaborators handles retrieving the musicians a musician shares albums with.
+func (c *CollaborationController) GetCollaborators(w http.ResponseWriter, r *http.Request) {
+	musicianID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+
+	collaborators, err := c.Service.GetCollaborators(uint(musicianID))
+	if err != nil {
+		http.Error(w, err.Error(), collaborationErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, collaborators)
+}
+
+// GetPath handles finding the shortest chain of collaborations between two musicians.
+func (c *CollaborationController) GetPath(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	fromID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+	toID, err := strconv.ParseUint(vars["to_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+
+	path, err := c.Service.GetPath(uint(fromID), uint(toID))
+	if err != nil {
+		http.Error(w, err.Error(), collaborationErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, path)
+}
+
+// GetStats handles summarising the connected components of the collaboration graph.
+func (c *CollaborationController) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.Service.GetStats()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, stats)
+}
+
+// collaborationErrorStatus maps a missing musician, or musicians without a path between them, to 404.
+func collaborationErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, services.ErrNotConnected) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestCollaborationGraph(t *testing.T) {
+	db := setupTestDB(t)
+	controller := &CollaborationController{Service: &services.CollaborationService{Repo: &repositories.CollaborationRepository{DB: db},
+		MusicianRepo: &repositories.MusicianRepository{DB: db}, AlbumRepo: &repositories.AlbumRepository{DB: db}}}
+
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'Ann', 'Singer'), (2, 'Bob', 'Drummer'), (3, 'Cat', 'Bassist');
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'Album 1', '2001-01-01', '', 1000, '');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES (1, 1), (1, 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed collaborations: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	controller.GetCollaborators(rr, mux.SetURLVars(httptest.NewRequest("GET", "/musicians/1/collaborators", nil), map[string]string{"id": "1"}))
+	var collaborators []models.Collaborator
+	if err := json.NewDecoder(rr.Body).Decode(&collaborators); err != nil {
+		t.Fatalf("failed to decode collaborators: %v", err)
+	}
+	if len(collaborators) != 1 || collaborators[0].Name != "Bob" || collaborators[0].SharedAlbums != 1 {
+		t.Errorf("unexpected collaborators %+v", collaborators)
+	}
+
+	tests := []struct {
+		from, to string
+		status   int
+	}{
+		{"1", "2", http.StatusOK},
+		{"1", "3", http.StatusNotFound},
+		{"1", "99", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/musicians/"+tt.from+"/path/"+tt.to, nil), map[string]string{"id": tt.from, "to_id": tt.to})
+		rr := httptest.NewRecorder()
+		controller.GetPath(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s to %s: expected status code %v, got %v", tt.from, tt.to, tt.status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetStats(rr, httptest.NewRequest("GET", "/collaborations/components", nil))
+	var stats models.CollaborationStats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+	if stats.Components != 1 || stats.LargestComponent != 2 || stats.Isolated != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
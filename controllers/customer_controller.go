@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type CustomerController struct {
+	Service services.CustomerServiceInterface
+}
+
+// Register handles creating a customer account.
+func (c *CustomerController) Register(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var registration models.Registration
+	if err := decodeRequest(r, &registration); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	customer, err := c.Service.Register(registration)
+	if err != nil {
+		http.Error(w, err.Error(), customerErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, customer)
+}
+
+// Login handles signing a customer in, answering with a session token for the Authorization header.
+func (c *CustomerController) Login(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var credentials models.Credentials
+	if err := decodeRequest(r, &credentials); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	session, err := c.Service.Login(credentials)
+	if err != nil {
+		http.Error(w, err.Error(), customerErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, session)
+}
+
+// Logout handles ending the session the request is signed in with.
+func (c *CustomerController) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := c.Service.Logout(bearerToken(r)); err != nil {
+		unauthorized(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMe handles retrieving the signed-in customer.
+func (c *CustomerController) GetMe(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Service)
+	if !ok {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, customer)
+}
+
+// GetLibrary handles retrieving the albums the signed-in customer owns.
+func (c *CustomerController) GetLibrary(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Service)
+	if !ok {
+		return
+	}
+
+	library, err := c.Service.GetLibrary(customer.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, library)
+}
+
+// AddToLibrary handles adding an album to the signed-in customer's library by hand.
+func (c *CustomerController) AddToLibrary(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	customer, ok := authenticate(w, r, c.Service)
+	if !ok {
+		return
+	}
+
+	var add models.LibraryAdd
+	if err := decodeRequest(r, &add); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	library, err := c.Service.AddToLibrary(customer.ID, add.AlbumID)
+	if err != nil {
+		http.Error(w, err.Error(), customerErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, library)
+}
+
+// RemoveFromLibrary handles removing an album from the signed-in customer's library.
+func (c *CustomerController) RemoveFromLibrary(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Service)
+	if !ok {
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["album_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.RemoveFromLibrary(customer.ID, uint(albumID)); err != nil {
+		http.Error(w, err.Error(), customerErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOrders handles retrieving the signed-in customer's orders.
+func (c *CustomerController) GetOrders(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Service)
+	if !ok {
+		return
+	}
+
+	orders, err := c.Service.GetOrders(customer.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, orders)
+}
+
+// authenticate resolves the customer the request is signed in as, answering 401 Unauthorized when it is not.
+func authenticate(w http.ResponseWriter, r *http.Request, customers services.CustomerServiceInterface) (*models.Customer, bool) {
+	customer, err := customers.Authenticate(bearerToken(r))
+	if err != nil {
+		unauthorized(w, err)
+		return nil, false
+	}
+	return customer, true
+}
+
+// authorizeAdmin resolves the customer of the request's session like authenticate, answering 403 Forbidden
+// unless they are an admin.
+func authorizeAdmin(w http.ResponseWriter, r *http.Request, customers services.CustomerServiceInterface) (*models.Customer, bool) {
+	if customers == nil {
+		http.Error(w, "admin access is not configured", http.StatusForbidden)
+		return nil, false
+	}
+	customer, ok := authenticate(w, r, customers)
+	if !ok {
+		return nil, false
+	}
+	if !customer.Admin {
+		http.Error(w, "admin access required", http.StatusForbidden)
+		return nil, false
+	}
+	return customer, true
+}
+
+// bearerToken returns the session token of an "Authorization: Bearer <token>" header, or "" without one.
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// unauthorized answers a request whose session could not be resolved.
+func unauthorized(w http.ResponseWriter, err error) {
+	if !errors.Is(err, services.ErrUnauthorized) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="jukebox"`)
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+}
+
+// customerErrorStatus maps bad credentials to 401, a missing album to 404 and a registered email to 409.
+func customerErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, services.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrEmailTaken):
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestCustomerSession(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	orderRepo := &repositories.OrderRepository{DB: db}
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}, AlbumRepo: albumRepo, Orders: orderRepo}
+	controller := &CustomerController{Service: customers}
+	orderController := &OrderController{Service: &services.OrderService{Repo: orderRepo, AlbumRepo: albumRepo}, Customers: customers}
+
+	if err := albumRepo.CreateAlbum(&models.Album{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200}); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	registration := `{"email": "ann@example.com", "name": "Ann", "password": "correct horse"}`
+	for _, status := range []int{http.StatusCreated, http.StatusConflict} {
+		rr := httptest.NewRecorder()
+		controller.Register(rr, httptest.NewRequest("POST", "/customers", bytes.NewBufferString(registration)))
+		if rr.Code != status {
+			t.Fatalf("expected status code %v, got %v: %s", status, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	controller.Login(rr, httptest.NewRequest("POST", "/sessions", bytes.NewBufferString(`{"email": "ann@example.com", "password": "wrong horse"}`)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %v, got %v", http.StatusUnauthorized, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.Login(rr, httptest.NewRequest("POST", "/sessions", bytes.NewBufferString(`{"email": "ann@example.com", "password": "correct horse"}`)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var session models.Session
+	if err := json.NewDecoder(rr.Body).Decode(&session); err != nil {
+		t.Fatalf("failed to decode session: %v", err)
+	}
+	signedIn := func(req *http.Request) *http.Request {
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+		return req
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetMe(rr, httptest.NewRequest("GET", "/me", nil))
+	if rr.Code != http.StatusUnauthorized || rr.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("expected an unauthenticated request to be challenged, got %v", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetMe(rr, signedIn(httptest.NewRequest("GET", "/me", nil)))
+	var me models.Customer
+	if err := json.NewDecoder(rr.Body).Decode(&me); err != nil {
+		t.Fatalf("failed to decode customer: %v", err)
+	}
+	if me.Email != "ann@example.com" {
+		t.Errorf("unexpected customer %+v", me)
+	}
+
+	rr = httptest.NewRecorder()
+	orderController.CreateCart(rr, signedIn(httptest.NewRequest("POST", "/carts", nil)))
+	var cart models.Cart
+	if err := json.NewDecoder(rr.Body).Decode(&cart); err != nil {
+		t.Fatalf("failed to decode cart: %v", err)
+	}
+	if cart.CustomerID != me.ID {
+		t.Errorf("expected a signed-in cart to belong to the customer, got %+v", cart)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.AddToLibrary(rr, signedIn(httptest.NewRequest("POST", "/me/library", bytes.NewBufferString(`{"album_id": 1}`))))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetLibrary(rr, signedIn(httptest.NewRequest("GET", "/me/library", nil)))
+	var library []models.LibraryEntry
+	if err := json.NewDecoder(rr.Body).Decode(&library); err != nil {
+		t.Fatalf("failed to decode library: %v", err)
+	}
+	if len(library) != 1 || library[0].Album.Name != "Album 1" {
+		t.Errorf("unexpected library %+v", library)
+	}
+
+	for _, status := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req := mux.SetURLVars(signedIn(httptest.NewRequest("DELETE", "/me/library/1", nil)), map[string]string{"album_id": "1"})
+		rr = httptest.NewRecorder()
+		controller.RemoveFromLibrary(rr, req)
+		if rr.Code != status {
+			t.Errorf("expected status code %v, got %v", status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetOrders(rr, signedIn(httptest.NewRequest("GET", "/me/orders", nil)))
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	for _, status := range []int{http.StatusNoContent, http.StatusUnauthorized} {
+		rr = httptest.NewRecorder()
+		controller.Logout(rr, signedIn(httptest.NewRequest("DELETE", "/sessions", nil)))
+		if rr.Code != status {
+			t.Errorf("expected status code %v, got %v", status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	orderController.CreateCart(rr, signedIn(httptest.NewRequest("POST", "/carts", nil)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected a cart with a stale token to be refused, got %v", rr.Code)
+	}
+}
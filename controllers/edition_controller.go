@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type EditionController struct {
+	Service services.EditionServiceInterface
+}
+
+// AddEdition handles adding an edition to an album.
+func (c *EditionController) AddEdition(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var edition models.Edition
+	if err := decodeRequest(r, &edition); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	edition.AlbumID = uint(albumID)
+
+	if err := c.Service.AddEdition(&edition); err != nil {
+		http.Error(w, err.Error(), editionErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, edition)
+}
+
+// GetEditions handles retrieving the editions of an album.
+func (c *EditionController) GetEditions(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	editions, err := c.Service.GetEditions(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), editionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, editions)
+}
+
+// UpdateEdition handles updating an edition of an album.
+func (c *EditionController) UpdateEdition(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var edition models.Edition
+	if err := decodeRequest(r, &edition); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	editionID, err := strconv.ParseUint(vars["edition_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid edition ID", http.StatusBadRequest)
+		return
+	}
+	edition.AlbumID = uint(albumID)
+	edition.ID = uint(editionID)
+
+	if err := c.Service.UpdateEdition(&edition); err != nil {
+		http.Error(w, err.Error(), editionErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, edition)
+}
+
+// RemoveEdition handles deleting an edition of an album.
+func (c *EditionController) RemoveEdition(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	editionID, err := strconv.ParseUint(vars["edition_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid edition ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.RemoveEdition(uint(albumID), uint(editionID)); err != nil {
+		http.Error(w, err.Error(), editionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// editionErrorStatus maps a missing album or edition to 404.
+func editionErrorStatus(err error, fallback int) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestEditionControllerLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	controller := &EditionController{Service: &services.EditionService{Repo: &repositories.EditionRepository{DB: db}, AlbumRepo: albumRepo}}
+	albumController := &AlbumController{Service: &services.AlbumService{Repo: albumRepo}}
+
+	if err := albumRepo.CreateAlbum(&models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 500}); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	id := map[string]string{"id": "1"}
+	for _, body := range []string{
+		`{"format": "vinyl", "name": "Deluxe", "release_date": "2021-06-01", "price": 400}`,
+		`{"format": "digital", "price": 150}`,
+	} {
+		req := mux.SetURLVars(httptest.NewRequest("POST", "/albums/1/editions", bytes.NewBufferString(body)), id)
+		rr := httptest.NewRecorder()
+		controller.AddEdition(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	// GET /albums reports the lowest edition price
+	req := httptest.NewRequest("GET", "/albums", nil)
+	rr := httptest.NewRecorder()
+	albumController.GetAlbums(rr, req)
+	var albums []models.Album
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode albums: %v", err)
+	}
+	if len(albums) != 1 || albums[0].Price != 150 || albums[0].ReleaseDate != "2021-06-01" {
+		t.Errorf("expected lowest price and earliest date, got %+v", albums)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/albums/1/editions", bytes.NewBufferString(`{"format": "cd", "barcode": "4006381333932", "price": 200}`)), id)
+	rr = httptest.NewRecorder()
+	controller.AddEdition(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/albums/1/editions/9", nil), map[string]string{"id": "1", "edition_id": "9"})
+	rr = httptest.NewRecorder()
+	controller.RemoveEdition(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/9/editions", nil), map[string]string{"id": "9"})
+	rr = httptest.NewRecorder()
+	controller.GetEditions(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,448 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Media types the album and musician endpoints can read and write.
+const (
+	mediaJSON = "application/json"
+	mediaXML  = "application/xml"
+	mediaCSV  = "text/csv"
+)
+
+// errUnsupportedMediaType is returned by decodeRequest for bodies it cannot read.
+var errUnsupportedMediaType = errors.New("Unsupported content type, use application/json, application/xml or text/csv")
+
+// negotiate picks the response media type from the Accept header. It returns
+// an empty string when the client accepts none of the supported types.
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return mediaJSON
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		switch c.mediaType {
+		case mediaJSON, "*/*", "application/*":
+			return mediaJSON
+		case mediaXML, "text/xml":
+			return mediaXML
+		case mediaCSV, "text/*":
+			return mediaCSV
+		}
+	}
+	return ""
+}
+
+// acceptable reports whether the client accepts a supported representation,
+// responding 406 Not Acceptable if not. Handlers that change data call it
+// before doing any work so a rejected request has no side effects.
+func acceptable(w http.ResponseWriter, r *http.Request) bool {
+	if negotiate(r.Header.Get("Accept")) == "" {
+		w.Header().Add("Vary", "Accept")
+		http.Error(w, "Not acceptable, use application/json, application/xml or text/csv", http.StatusNotAcceptable)
+		return false
+	}
+	return true
+}
+
+// writeResponse encodes v in the representation requested by the Accept header,
+// or responds 406 Not Acceptable when no supported representation is accepted.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	mediaType := negotiate(r.Header.Get("Accept"))
+	w.Header().Add("Vary", "Accept")
+	if mediaType == "" {
+		http.Error(w, "Not acceptable, use application/json, application/xml or text/csv", http.StatusNotAcceptable)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+
+	switch mediaType {
+	case mediaXML:
+		encodeXML(w, v)
+	case mediaCSV:
+		encodeCSV(w, v)
+	default:
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// decodeRequest decodes the request body into v according to its Content-Type.
+// A missing Content-Type is treated as JSON.
+func decodeRequest(r *http.Request, v interface{}) error {
+	mediaType := mediaJSON
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return errUnsupportedMediaType
+		}
+		mediaType = parsed
+	}
+
+	switch mediaType {
+	case mediaJSON:
+		return json.NewDecoder(r.Body).Decode(v)
+	case mediaXML, "text/xml":
+		return decodeXML(r.Body, v)
+	case mediaCSV:
+		return decodeCSV(r.Body, v)
+	}
+	return errUnsupportedMediaType
+}
+
+// decodeStatus maps a decodeRequest error to its HTTP status code.
+func decodeStatus(err error) int {
+	if errors.Is(err, errUnsupportedMediaType) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+// encodeXML writes v as XML. Slices are wrapped in a root element named after
+// their element type, e.g. <albums><album>...</album></albums>.
+func encodeXML(w io.Writer, v interface{}) error {
+	io.WriteString(w, xml.Header)
+	encoder := xml.NewEncoder(w)
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		name := elementName(value.Type())
+		if err := encoder.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+		return encoder.Flush()
+	}
+
+	name := elementName(value.Type().Elem())
+	root := xml.StartElement{Name: xml.Name{Local: name + "s"}}
+	if err := encoder.EncodeToken(root); err != nil {
+		return err
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err := encoder.EncodeElement(value.Index(i).Interface(), xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	if err := encoder.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+// elementName derives an XML element name from a Go type, e.g. models.Album becomes "album".
+// A struct can choose its own name with the tag of an XMLName field.
+func elementName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		if field, ok := t.FieldByName("XMLName"); ok {
+			if name := strings.Split(field.Tag.Get("xml"), ",")[0]; name != "" {
+				return name
+			}
+		}
+	}
+	name := []rune(t.Name())
+	if len(name) == 0 {
+		return "item"
+	}
+	name[0] = unicode.ToLower(name[0])
+	return string(name)
+}
+
+// csvFields lists the exported fields of a struct type with their JSON names,
+// which double as CSV column headers. Fields tagged `csv:"-"` have no CSV form.
+func csvFields(t reflect.Type) ([]string, [][]int) {
+	var names []string
+	var indexes [][]int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			embeddedNames, embeddedIndexes := csvFields(field.Type)
+			names = append(names, embeddedNames...)
+			for _, index := range embeddedIndexes {
+				indexes = append(indexes, append([]int{i}, index...))
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.Tag.Get("csv") == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+		indexes = append(indexes, []int{i})
+	}
+	return names, indexes
+}
+
+// encodeCSV writes a struct or a slice of structs as CSV with a header row.
+// Slice fields are joined with semicolons.
+func encodeCSV(w io.Writer, v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	rows := []reflect.Value{value}
+	elemType := value.Type()
+	if value.Kind() == reflect.Slice {
+		rows = rows[:0]
+		for i := 0; i < value.Len(); i++ {
+			rows = append(rows, reflect.Indirect(value.Index(i)))
+		}
+		elemType = value.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot encode %s as CSV", elemType)
+	}
+
+	names, indexes := csvFields(elemType)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(names); err != nil {
+		return err
+	}
+
+	record := make([]string, len(indexes))
+	for _, row := range rows {
+		for i, index := range indexes {
+			record[i] = formatCSVValue(row.FieldByIndex(index))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = formatCSVValue(value.Index(i))
+		}
+		return strings.Join(items, ";")
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case reflect.Struct:
+		if name := value.FieldByName("Name"); name.IsValid() {
+			return fmt.Sprint(name.Interface())
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// decodeXML decodes an XML document into v. A slice is read from the children of the root
+// element, mirroring encodeXML.
+func decodeXML(r io.Reader, v interface{}) error {
+	decoder := xml.NewDecoder(r)
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return decoder.Decode(v)
+	}
+	target = target.Elem()
+
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if depth != 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				depth++
+				continue
+			}
+			item := reflect.New(target.Type().Elem())
+			if err := decoder.DecodeElement(item.Interface(), &t); err != nil {
+				return err
+			}
+			target.Set(reflect.Append(target, item.Elem()))
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// decodeCSV reads a header row and a single data row into the struct pointed to by v,
+// or every data row into the slice of structs pointed to by v.
+func decodeCSV(r io.Reader, v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr {
+		return fmt.Errorf("cannot decode CSV into %T", v)
+	}
+	target = target.Elem()
+
+	rowType := target.Type()
+	if target.Kind() == reflect.Slice {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode CSV into %T", v)
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	names, indexes := csvFields(rowType)
+	fields := make(map[string][]int, len(names))
+	for i, name := range names {
+		fields[name] = indexes[i]
+	}
+
+	if target.Kind() == reflect.Struct {
+		record, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("reading CSV row: %w", err)
+		}
+		return setCSVRecord(target, fields, header, record)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading CSV row: %w", err)
+		}
+		row := reflect.New(rowType).Elem()
+		if err := setCSVRecord(row, fields, header, record); err != nil {
+			return err
+		}
+		target.Set(reflect.Append(target, row))
+	}
+}
+
+// setCSVRecord sets the fields of a struct named by the header from one CSV record.
+func setCSVRecord(target reflect.Value, fields map[string][]int, header, record []string) error {
+	if len(record) != len(header) {
+		return fmt.Errorf("expected %d CSV fields, got %d", len(header), len(record))
+	}
+	for i, column := range header {
+		index, ok := fields[strings.TrimSpace(column)]
+		if !ok {
+			return fmt.Errorf("unknown CSV field %q", column)
+		}
+		if err := setCSVValue(target.FieldByIndex(index), record[i]); err != nil {
+			return fmt.Errorf("CSV field %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+func setCSVValue(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Float32, reflect.Float64:
+		if raw == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(f)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be a positive integer")
+		}
+		field.SetUint(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		if raw == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		// Lists are written as semicolon-separated values
+		slice := reflect.MakeSlice(field.Type(), 0, 0)
+		if raw != "" {
+			for _, item := range strings.Split(raw, ";") {
+				elem := reflect.New(field.Type().Elem()).Elem()
+				if err := setCSVValue(elem, item); err != nil {
+					return err
+				}
+				slice = reflect.Append(slice, elem)
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
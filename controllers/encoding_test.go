@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"jukebox/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", mediaJSON},
+		{"*/*", mediaJSON},
+		{"application/xml", mediaXML},
+		{"text/xml", mediaXML},
+		{"text/csv", mediaCSV},
+		{"text/html, application/xml;q=0.5, text/csv;q=0.9", mediaCSV},
+		{"application/json;q=0, text/*", mediaCSV},
+		{"text/html", ""},
+	}
+
+	for _, test := range tests {
+		if got := negotiate(test.accept); got != test.expected {
+			t.Errorf("negotiate(%q) = %q, expected %q", test.accept, got, test.expected)
+		}
+	}
+}
+
+func TestGetAlbumsControllerXML(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	service.CreateAlbum(&models.Album{Name: "Album 1", ReleaseDate: "2022-01-01", Genre: "Rock", Price: 150, Description: "Description 1"})
+	service.CreateAlbum(&models.Album{Name: "Album 2", ReleaseDate: "2022-02-01", Genre: "Pop", Price: 200, Description: "Description 2"})
+
+	req := httptest.NewRequest("GET", "/albums", nil)
+	req.Header.Set("Accept", "application/xml")
+	rr := httptest.NewRecorder()
+
+	controller.GetAlbums(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != mediaXML {
+		t.Errorf("expected content type %s, got %s", mediaXML, rr.Header().Get("Content-Type"))
+	}
+
+	var response struct {
+		XMLName xml.Name       `xml:"albums"`
+		Albums  []models.Album `xml:"album"`
+	}
+	if err := xml.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if len(response.Albums) != 2 || response.Albums[1].ReleaseDate != "2022-02-01" {
+		t.Errorf("expected 2 albums, got %+v", response.Albums)
+	}
+}
+
+func TestGetMusiciansControllerCSV(t *testing.T) {
+	service := setupTestMusicianService(t)
+	controller := &MusicianController{Service: service}
+
+	service.CreateMusician(&models.Musician{Name: "Musician 1", MusicianType: "Guitarist"})
+	service.CreateMusician(&models.Musician{Name: "Musician, Jr.", MusicianType: "Drummer"})
+
+	req := httptest.NewRequest("GET", "/musicians", nil)
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+
+	controller.GetMusicians(rr, req)
+
+	expected := "id,name,musician_type,kind\n1,Musician 1,Guitarist,person\n2,\"Musician, Jr.\",Drummer,person\n"
+	if rr.Body.String() != expected {
+		t.Errorf("expected CSV body %q, got %q", expected, rr.Body.String())
+	}
+}
+
+func TestCreateAlbumControllerNotAcceptable(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	payload := `{"name": "Test Album", "release_date": "2022-01-01", "price": 150}`
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload))
+	req.Header.Set("Accept", "text/html")
+	rr := httptest.NewRecorder()
+
+	controller.CreateAlbum(rr, req)
+
+	if rr.Code != http.StatusNotAcceptable {
+		t.Errorf("expected status code %v, got %v", http.StatusNotAcceptable, rr.Code)
+	}
+
+	albums, _ := service.GetAlbums(models.AlbumFilter{})
+	if len(albums) != 0 {
+		t.Errorf("expected no album to be created, got %d", len(albums))
+	}
+}
+
+func TestCreateAlbumControllerFromXMLAndCSV(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	bodies := map[string]string{
+		"application/xml": `<album><name>XML Album</name><release_date>2022-01-01</release_date><price>150</price><musician_ids><id>1</id><id>2</id></musician_ids></album>`,
+		"text/csv":        "name,release_date,price,musician_ids\nCSV Album,2022-01-01,150,1;2\n",
+	}
+
+	for contentType, body := range bodies {
+		req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		rr := httptest.NewRecorder()
+
+		controller.CreateAlbum(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Errorf("%s: expected status code %v, got %v: %s", contentType, http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	albums, _ := service.GetAlbumsByMusician(2, "", "")
+	if len(albums) != 2 {
+		t.Errorf("expected both albums linked to musician 2, got %d", len(albums))
+	}
+}
+
+func TestCreateAlbumControllerCSVCredits(t *testing.T) {
+	service := setupTestService(t)
+	controller := &AlbumController{Service: service}
+
+	body := "name,release_date,price,credits\nCSV Album,2022-01-01,150,1\n"
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+
+	controller.CreateAlbum(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestEncodeCSVCreditedAlbums(t *testing.T) {
+	albums := []models.CreditedAlbum{{Credits: []models.Credit{{Role: "producer"}}}}
+
+	var buf bytes.Buffer
+	if err := encodeCSV(&buf, albums); err != nil {
+		t.Fatalf("failed to encode credited albums: %v", err)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte("credits")) {
+		t.Errorf("expected no credits column, got %q", buf.String())
+	}
+}
+
+func TestCreateMusicianControllerUnsupportedMediaType(t *testing.T) {
+	controller := &MusicianController{Service: setupTestMusicianService(t)}
+
+	req := httptest.NewRequest("POST", "/musicians", bytes.NewBufferString("name=John"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+
+	controller.CreateMusician(rr, req)
+
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status code %v, got %v", http.StatusUnsupportedMediaType, rr.Code)
+	}
+}
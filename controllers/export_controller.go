@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type ExportController struct {
+	Service services.ExportServiceInterface
+}
+
+// Export handles streaming the catalog as a CSV, JSON Lines or OpenDocument spreadsheet download.
+func (c *ExportController) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.FormatCSV
+	}
+
+	contentType, extension, err := services.ExportContentType(format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := albumFilterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="albums.`+extension+`"`)
+
+	response := &exportResponse{ResponseWriter: w}
+	if err := c.Service.Export(format, filter, response); err != nil {
+		if !response.started {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), priceErrorStatus(err, http.StatusInternalServerError))
+			return
+		}
+		// The status line has already been sent, so a failure part-way through can only be logged
+		log.Println("Error exporting catalog:", err)
+	}
+}
+
+// exportResponse sends the status line of an export with its first bytes, so that an export failing
+// before it starts, such as for a currency without an exchange rate, can still answer an error.
+type exportResponse struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *exportResponse) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+// albumFilterFromQuery reads the album list filters from the query string.
+func albumFilterFromQuery(r *http.Request) (models.AlbumFilter, error) {
+	var filter models.AlbumFilter
+
+	if value := r.URL.Query().Get("musician_id"); value != "" {
+		musicianID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, errors.New("Invalid musician_id")
+		}
+		filter.MusicianID = uint(musicianID)
+	}
+	filter.Genre = r.URL.Query().Get("genre")
+	if value := r.URL.Query().Get("in_stock"); value != "" {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("Invalid in_stock")
+		}
+		filter.InStock = &inStock
+	}
+	currency, err := currencyFromQuery(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.Currency = currency
+
+	return filter, nil
+}
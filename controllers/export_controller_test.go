@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportController(t *testing.T) {
+	db := setupTestDB(t)
+	controller := &ExportController{Service: &services.ExportService{Repo: &repositories.AlbumRepository{DB: db}}}
+
+	_, err := db.Exec(`INSERT INTO albums (name, release_date, genre, price_minor, description) VALUES ('Test Album', '2022-01-01', 'Rock', 15000, '')`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/export?format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	controller.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("expected text/csv, got %s", rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Body.String(), "Test Album") {
+		t.Errorf("expected export to contain the album, got %s", rr.Body.String())
+	}
+}
+
+func TestExportControllerInvalidFormat(t *testing.T) {
+	controller := &ExportController{Service: &services.ExportService{}}
+
+	req := httptest.NewRequest("GET", "/export?format=xlsx", nil)
+	rr := httptest.NewRecorder()
+
+	controller.Export(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestExportControllerCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	prices := &services.PriceService{Repo: &repositories.PriceRepository{DB: db}}
+	controller := &ExportController{Service: &services.ExportService{Repo: &repositories.AlbumRepository{DB: db}, Prices: prices}}
+
+	_, err := db.Exec(`
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'Test Album', '2022-01-01', 'Rock', 15000, ''),
+		(2, 'Listed Album', '2022-02-01', 'Rock', 20000, '');
+		INSERT INTO exchange_rates (currency, rate) VALUES ('EUR', 0.5);
+		INSERT INTO album_prices (album_id, currency, price_minor) VALUES (2, 'EUR', 9999);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	controller.Export(rr, httptest.NewRequest("GET", "/export?format=csv&currency=eur", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Test Album,2022-01-01,Rock,75,EUR") || !strings.Contains(body, "Listed Album,2022-02-01,Rock,99.99,EUR") {
+		t.Errorf("expected prices converted or listed in EUR, got %s", body)
+	}
+
+	// A currency without an exchange rate fails before the download starts
+	rr = httptest.NewRecorder()
+	controller.Export(rr, httptest.NewRequest("GET", "/export?format=csv&currency=JPY", nil))
+	if rr.Code != http.StatusBadRequest || rr.Header().Get("Content-Disposition") != "" {
+		t.Errorf("expected status code %v without a download, got %v: %v", http.StatusBadRequest, rr.Code, rr.Header())
+	}
+}
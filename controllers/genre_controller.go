@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type GenreController struct {
+	Service services.GenreServiceInterface
+}
+
+// albumGenresDTO is the body of PUT /albums/{id}/genres.
+type albumGenresDTO struct {
+	// Genre names or aliases; an empty list removes every genre from the album.
+	Genres []string `json:"genres" xml:"genres>genre"`
+}
+
+// CreateGenre handles the creation of a new genre.
+func (c *GenreController) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var genre models.Genre
+	if err := decodeRequest(r, &genre); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	if err := c.Service.CreateGenre(&genre); err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, genre)
+}
+
+// GetGenres handles retrieving all genres.
+func (c *GenreController) GetGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := c.Service.GetGenres()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, genres)
+}
+
+// GetGenre handles retrieving a single genre by ID.
+func (c *GenreController) GetGenre(w http.ResponseWriter, r *http.Request) {
+	genreID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid genre ID", http.StatusBadRequest)
+		return
+	}
+
+	genre, err := c.Service.GetGenre(uint(genreID))
+	if err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, genre)
+}
+
+// UpdateGenre handles updating an existing genre.
+func (c *GenreController) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var genre models.Genre
+	if err := decodeRequest(r, &genre); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	genreID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid genre ID", http.StatusBadRequest)
+		return
+	}
+	genre.ID = uint(genreID)
+
+	if err := c.Service.UpdateGenre(&genre); err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, genre)
+}
+
+// DeleteGenre handles deleting a genre by ID.
+func (c *GenreController) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	genreID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid genre ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.DeleteGenre(uint(genreID)); err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAlbumGenres handles retrieving the genres of an album.
+func (c *GenreController) GetAlbumGenres(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	genres, err := c.Service.GetAlbumGenres(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, genres)
+}
+
+// SetAlbumGenres handles replacing the genres of an album.
+func (c *GenreController) SetAlbumGenres(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var body albumGenresDTO
+	if err := decodeRequest(r, &body); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	genres, err := c.Service.SetAlbumGenres(uint(albumID), body.Genres)
+	if err != nil {
+		http.Error(w, err.Error(), genreErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, genres)
+}
+
+// genreErrorStatus maps a missing genre or album to 404 and a genre with children to 409.
+func genreErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrGenreHasChildren):
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGenreControllerLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	genreService := &services.GenreService{Repo: &repositories.GenreRepository{DB: db}, AlbumRepo: &repositories.AlbumRepository{DB: db}}
+	controller := &GenreController{Service: genreService}
+	albumController := &AlbumController{Service: &services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}, Genres: genreService}}
+
+	for _, body := range []string{`{"name": "Rock"}`, `{"name": "Metal", "parent_id": 1, "aliases": ["Heavy Metal"]}`} {
+		req := httptest.NewRequest("POST", "/genres", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+		controller.CreateGenre(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	// Creating an album tags it with the genre its genre text names
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"name": "Paranoid", "release_date": "1970-09-18", "genre": "heavy metal", "price": 200}`))
+	rr := httptest.NewRecorder()
+	albumController.CreateAlbum(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/albums?genre=rock", nil)
+	rr = httptest.NewRecorder()
+	albumController.GetAlbums(rr, req)
+	var albums []models.Album
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode albums: %v", err)
+	}
+	if len(albums) != 1 || albums[0].Name != "Paranoid" {
+		t.Errorf("expected the metal album under rock, got %+v", albums)
+	}
+
+	id := map[string]string{"id": "1"}
+
+	req = mux.SetURLVars(httptest.NewRequest("PUT", "/albums/1/genres", bytes.NewBufferString(`{"genres": ["Rock", "Metal"]}`)), id)
+	rr = httptest.NewRecorder()
+	controller.SetAlbumGenres(rr, req)
+	var genres []models.Genre
+	if err := json.NewDecoder(rr.Body).Decode(&genres); err != nil {
+		t.Fatalf("failed to decode genres: %v", err)
+	}
+	if len(genres) != 2 {
+		t.Errorf("expected 2 genres, got %+v", genres)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("PUT", "/albums/1/genres", bytes.NewBufferString(`{"genres": ["Polka"]}`)), id)
+	rr = httptest.NewRecorder()
+	controller.SetAlbumGenres(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/genres/1", nil), id)
+	rr = httptest.NewRecorder()
+	controller.DeleteGenre(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status code %v, got %v", http.StatusConflict, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/9/genres", nil), map[string]string{"id": "9"})
+	rr = httptest.NewRecorder()
+	controller.GetAlbumGenres(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
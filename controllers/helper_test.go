@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"database/sql"
+	"jukebox/models"
+	"jukebox/services"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -15,22 +17,288 @@ func setupTestDB(t *testing.T) *sql.DB {
 	}
 
 	_, err = db.Exec(`
+		CREATE TABLE labels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			country TEXT NOT NULL DEFAULT '',
+			parent_id INTEGER
+		);
 		CREATE TABLE albums (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			name TEXT,
-			release_date TEXT,
+			release_date DATE,
 			genre TEXT,
-			price REAL,
-			description TEXT
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
 		);
 		CREATE TABLE album_musicians (
 			album_id INTEGER,
-			musician_id INTEGER
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE genres (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL UNIQUE,
+			parent_id INTEGER
+		);
+		CREATE TABLE genre_aliases (
+			key TEXT PRIMARY KEY,
+			alias TEXT NOT NULL,
+			genre_id INTEGER NOT NULL
+		);
+		CREATE TABLE album_genres (
+			album_id INTEGER NOT NULL,
+			genre_id INTEGER NOT NULL,
+			PRIMARY KEY (album_id, genre_id)
+		);
+		CREATE TABLE editions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			format TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			barcode TEXT UNIQUE,
+			release_date DATE NOT NULL,
+			price_minor INTEGER NOT NULL
+		);
+		CREATE TABLE exchange_rates (
+			currency TEXT PRIMARY KEY,
+			rate REAL NOT NULL CHECK (rate > 0)
+		);
+		CREATE TABLE album_prices (
+			album_id INTEGER NOT NULL,
+			currency TEXT NOT NULL,
+			price_minor INTEGER NOT NULL,
+			PRIMARY KEY (album_id, currency)
+		);
+		CREATE TABLE promotions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			percent_off REAL,
+			amount_off_minor INTEGER,
+			currency TEXT,
+			album_id INTEGER,
+			genre_id INTEGER,
+			label_id INTEGER,
+			musician_id INTEGER,
+			starts_at TEXT NOT NULL,
+			ends_at TEXT NOT NULL
+		);
+		CREATE TABLE price_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			price_minor INTEGER NOT NULL,
+			starts_at TEXT NOT NULL,
+			applied_at TEXT
+		);
+		CREATE TABLE stock_levels (
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+			low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (album_id, edition_id)
+		);
+		CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			quantity_change INTEGER NOT NULL,
+			balance INTEGER NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);
+		CREATE TRIGGER stock_movements_no_update BEFORE UPDATE ON stock_movements
+		BEGIN
+			SELECT RAISE(ABORT, 'stock movements are append-only');
+		END;
+		CREATE TRIGGER stock_movements_no_delete BEFORE DELETE ON stock_movements
+		BEGIN
+			SELECT RAISE(ABORT, 'stock movements are append-only');
+		END;
+		CREATE TABLE carts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			created_at TEXT NOT NULL,
+			checked_out_at TEXT,
+			customer_id INTEGER
+		);
+		CREATE TABLE cart_items (
+			cart_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			PRIMARY KEY (cart_id, album_id, edition_id)
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			cart_id INTEGER NOT NULL UNIQUE,
+			status TEXT NOT NULL,
+			country TEXT NOT NULL,
+			currency TEXT NOT NULL,
+			subtotal_minor INTEGER NOT NULL,
+			tax_rate REAL NOT NULL,
+			tax_minor INTEGER NOT NULL,
+			total_minor INTEGER NOT NULL,
+			payment_reference TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			customer_id INTEGER
+		);
+		CREATE TABLE order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			name TEXT NOT NULL,
+			quantity INTEGER NOT NULL,
+			unit_price_minor INTEGER NOT NULL,
+			total_minor INTEGER NOT NULL,
+			stock_tracked INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE tax_rates (
+			country TEXT PRIMARY KEY,
+			rate REAL NOT NULL
+		);
+		CREATE TABLE customers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			password_hash TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			is_admin INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE sessions (
+			token_hash TEXT PRIMARY KEY,
+			customer_id INTEGER NOT NULL,
+			created_at TEXT NOT NULL,
+			expires_at TEXT NOT NULL
+		);
+		CREATE TABLE library_entries (
+			customer_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			source TEXT NOT NULL,
+			order_id INTEGER,
+			added_at TEXT NOT NULL,
+			PRIMARY KEY (customer_id, album_id)
+		);
+		CREATE TABLE reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			customer_id INTEGER NOT NULL,
+			rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			body TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE (album_id, customer_id)
+		);
+		CREATE TABLE album_recommendations (
+			album_id INTEGER NOT NULL,
+			related_album_id INTEGER NOT NULL,
+			score REAL NOT NULL,
+			reasons TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			PRIMARY KEY (album_id, related_album_id)
+		);
+		CREATE TABLE musician_recommendations (
+			musician_id INTEGER NOT NULL,
+			related_musician_id INTEGER NOT NULL,
+			score REAL NOT NULL,
+			reasons TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			PRIMARY KEY (musician_id, related_musician_id)
+		);
+		CREATE TABLE chart_counters (
+			period TEXT NOT NULL,
+			metric TEXT NOT NULL,
+			album_id INTEGER NOT NULL,
+			count INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (period, metric, album_id)
+		);
+		CREATE TABLE charts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chart_type TEXT NOT NULL,
+			genre TEXT NOT NULL DEFAULT '',
+			period TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			UNIQUE (chart_type, genre, period)
+		);
+		CREATE TABLE chart_entries (
+			chart_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			value REAL NOT NULL,
+			count INTEGER NOT NULL,
+			previous_position INTEGER,
+			movement TEXT NOT NULL,
+			PRIMARY KEY (chart_id, position)
+		);
+		CREATE TABLE plays (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			customer_id INTEGER NOT NULL,
+			client_id TEXT NOT NULL,
+			album_id INTEGER NOT NULL,
+			track INTEGER NOT NULL DEFAULT 0,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			played_at TEXT NOT NULL,
+			received_at TEXT NOT NULL,
+			UNIQUE (customer_id, client_id)
+		);
+		CREATE TABLE tracks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			disc INTEGER NOT NULL DEFAULT 1,
+			number INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (album_id, disc, number)
+		);
+		CREATE TABLE library_files (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			path TEXT NOT NULL UNIQUE,
+			size INTEGER NOT NULL,
+			modified_ns INTEGER NOT NULL,
+			hash TEXT NOT NULL,
+			status TEXT NOT NULL,
+			album_id INTEGER NOT NULL DEFAULT 0,
+			track_id INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			scanned_at TEXT NOT NULL,
+			missing_since TEXT
+		);
+		CREATE TABLE ingest_conflicts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			file_name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			entity_id INTEGER NOT NULL DEFAULT 0,
+			field TEXT NOT NULL,
+			existing TEXT NOT NULL,
+			incoming TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open',
+			created_at TEXT NOT NULL,
+			reviewed_at TEXT
 		);
 		CREATE TABLE musicians (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			name TEXT NOT NULL,
-			musician_type TEXT NOT NULL
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE group_memberships (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_id INTEGER NOT NULL,
+			member_id INTEGER NOT NULL,
+			instrument TEXT NOT NULL DEFAULT '',
+			joined_on DATE NOT NULL,
+			left_on DATE
 		);
 	`)
 	if err != nil {
@@ -39,3 +307,21 @@ func setupTestDB(t *testing.T) *sql.DB {
 
 	return db
 }
+
+// signIn registers a customer with email, promoting them to admin when admin is set, and returns a session token.
+func signIn(t *testing.T, db *sql.DB, customers *services.CustomerService, email string, admin bool) string {
+	customer, err := customers.Register(models.Registration{Email: email, Name: "Customer", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to register customer: %v", err)
+	}
+	if admin {
+		if _, err := db.Exec("UPDATE customers SET is_admin = 1 WHERE id = ?", customer.ID); err != nil {
+			t.Fatalf("failed to promote customer: %v", err)
+		}
+	}
+	session, err := customers.Login(models.Credentials{Email: email, Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	return session.Token
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"encoding/json"
+	"jukebox/services"
+	"mime"
+	"net/http"
+)
+
+type ImportController struct {
+	Service services.ImportServiceInterface
+}
+
+// Import handles bulk uploads of albums, musicians or album–musician links.
+// The body is streamed straight into the import so large files are never held in memory.
+func (c *ImportController) Import(w http.ResponseWriter, r *http.Request) {
+	entity := r.URL.Query().Get("entity")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importFormat(r.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		http.Error(w, "Unsupported content type, use text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report, err := c.Service.Import(entity, format, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// importFormat maps a Content-Type header to an import format.
+func importFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return services.FormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return services.FormatJSONL
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestImportService(t *testing.T) services.ImportServiceInterface {
+	db := setupTestDB(t)
+	return &services.ImportService{
+		AlbumRepo:    &repositories.AlbumRepository{DB: db},
+		MusicianRepo: &repositories.MusicianRepository{DB: db},
+	}
+}
+
+func TestImportController(t *testing.T) {
+	controller := &ImportController{Service: setupTestImportService(t)}
+
+	upload := "name,musician_type\nJohn Doe,Guitarist\nJo,Drummer\n"
+	req := httptest.NewRequest("POST", "/import?entity=musicians", bytes.NewBufferString(upload))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	rr := httptest.NewRecorder()
+
+	controller.Import(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var report models.ImportReport
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if report.Created != 1 || report.Failed != 1 {
+		t.Errorf("expected 1 created and 1 failed, got %+v", report)
+	}
+}
+
+func TestImportControllerUnsupportedContentType(t *testing.T) {
+	controller := &ImportController{Service: setupTestImportService(t)}
+
+	req := httptest.NewRequest("POST", "/import?entity=musicians", bytes.NewBufferString("<musicians/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	rr := httptest.NewRecorder()
+
+	controller.Import(rr, req)
+
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status code %v, got %v", http.StatusUnsupportedMediaType, rr.Code)
+	}
+}
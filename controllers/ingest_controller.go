@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ingestMemory is how much of an upload is held in memory; larger files are spooled to disk.
+const ingestMemory = 32 << 20
+
+type IngestController struct {
+	Service services.IngestServiceInterface
+}
+
+// Ingest handles uploads of audio files as multipart/form-data "files" parts. Accepts the price and
+// currency given to albums created from the files.
+func (c *IngestController) Ingest(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "multipart/form-data" {
+		http.Error(w, "Unsupported content type, use multipart/form-data", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var price float64
+	if value := r.URL.Query().Get("price"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			http.Error(w, "price must be a number", http.StatusBadRequest)
+			return
+		}
+		price = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxIngestUploadBytes)
+	if err := r.ParseMultipartForm(ingestMemory); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	headers := r.MultipartForm.File["files"]
+	files := make([]services.IngestFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		files = append(files, services.IngestFile{Name: header.Filename, Reader: file, Size: header.Size})
+	}
+
+	report, err := c.Service.Ingest(files, price, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, report)
+}
+
+// GetConflicts handles listing ingestion conflicts. Accepts status, open by default.
+func (c *IngestController) GetConflicts(w http.ResponseWriter, r *http.Request) {
+	conflicts, err := c.Service.GetConflicts(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, conflicts)
+}
+
+// ReviewConflict handles resolving, dismissing or reopening an ingestion conflict.
+func (c *IngestController) ReviewConflict(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	conflictID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid conflict ID", http.StatusBadRequest)
+		return
+	}
+
+	var review models.ConflictReview
+	if err := decodeRequest(r, &review); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	conflict, err := c.Service.ReviewConflict(uint(conflictID), review.Status)
+	if err != nil {
+		http.Error(w, err.Error(), ingestErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, conflict)
+}
+
+// GetTracks handles listing the tracks of an album.
+func (c *IngestController) GetTracks(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	tracks, err := c.Service.GetTracks(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), ingestErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, tracks)
+}
+
+// ingestErrorStatus maps a missing conflict or album to 404 and anything else to 400.
+func ingestErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func setupIngestController(t *testing.T) *IngestController {
+	db := setupTestDB(t)
+	return &IngestController{Service: &services.IngestService{
+		Repo:      &repositories.IngestRepository{DB: db},
+		Tracks:    &repositories.TrackRepository{DB: db},
+		Albums:    &services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}},
+		Musicians: &services.MusicianService{Repo: &repositories.MusicianRepository{DB: db}},
+	}}
+}
+
+// taggedMP3 builds an MP3 file holding only an ID3v2.3 tag for one track of "First Light".
+func taggedMP3(title, number string) []byte {
+	var body bytes.Buffer
+	for _, frame := range [][2]string{{"TIT2", title}, {"TALB", "First Light"}, {"TPE1", "The Lanterns"}, {"TYER", "1999"}, {"TRCK", number}} {
+		body.WriteString(frame[0])
+		binary.Write(&body, binary.BigEndian, uint32(len(frame[1])+1))
+		body.Write([]byte{0, 0, 0})
+		body.WriteString(frame[1])
+	}
+	size := body.Len()
+	return append([]byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}, body.Bytes()...)
+}
+
+func ingestRequest(t *testing.T, url string, files map[string][]byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, data := range files {
+		part, err := form.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatalf("failed to create form file: %v", err)
+		}
+		part.Write(data)
+	}
+	form.Close()
+
+	req := httptest.NewRequest("POST", url, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestIngest(t *testing.T) {
+	controller := setupIngestController(t)
+
+	rr := httptest.NewRecorder()
+	controller.Ingest(rr, ingestRequest(t, "/ingest?price=150", map[string][]byte{"01.mp3": taggedMP3("Dawn", "1")}))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body)
+	}
+	var report models.IngestReport
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.Created != 1 || len(report.Files) != 1 || report.Files[0].FileName != "01.mp3" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	albumID := report.Files[0].AlbumID
+
+	rr = httptest.NewRecorder()
+	controller.Ingest(rr, ingestRequest(t, "/ingest", map[string][]byte{"01.mp3": taggedMP3("Sunrise", "1")}))
+	report = models.IngestReport{}
+	json.NewDecoder(rr.Body).Decode(&report)
+	if rr.Code != http.StatusOK || report.Conflicts != 1 || report.Files[0].Conflicts[0].Field != "title" {
+		t.Fatalf("expected a title conflict, got %v: %+v", rr.Code, report)
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"not multipart", httptest.NewRequest("POST", "/ingest", strings.NewReader(`{}`)), http.StatusUnsupportedMediaType},
+		{"no files", ingestRequest(t, "/ingest", nil), http.StatusBadRequest},
+		{"invalid price", ingestRequest(t, "/ingest?price=cheap", map[string][]byte{"01.mp3": taggedMP3("Dawn", "1")}), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		tt.req.Header.Set("Accept", "application/json")
+		controller.Ingest(rr, tt.req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status code %v, got %v", tt.name, tt.status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetTracks(rr, mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/tracks", nil), map[string]string{"id": "1"}))
+	var tracks []models.Track
+	json.NewDecoder(rr.Body).Decode(&tracks)
+	if rr.Code != http.StatusOK || albumID != 1 || len(tracks) != 1 || tracks[0].Title != "Dawn" {
+		t.Errorf("unexpected tracks %v: %+v", rr.Code, tracks)
+	}
+	rr = httptest.NewRecorder()
+	controller.GetTracks(rr, mux.SetURLVars(httptest.NewRequest("GET", "/albums/9/tracks", nil), map[string]string{"id": "9"}))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v for a missing album, got %v", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestReviewConflict(t *testing.T) {
+	controller := setupIngestController(t)
+	controller.Ingest(httptest.NewRecorder(), ingestRequest(t, "/ingest", map[string][]byte{"01.mp3": taggedMP3("Dawn", "1")}))
+	controller.Ingest(httptest.NewRecorder(), ingestRequest(t, "/ingest", map[string][]byte{"01.mp3": taggedMP3("Sunrise", "1")}))
+
+	rr := httptest.NewRecorder()
+	controller.GetConflicts(rr, httptest.NewRequest("GET", "/ingest/conflicts", nil))
+	var conflicts []models.IngestConflict
+	json.NewDecoder(rr.Body).Decode(&conflicts)
+	if rr.Code != http.StatusOK || len(conflicts) != 1 {
+		t.Fatalf("expected one open conflict, got %v: %+v", rr.Code, conflicts)
+	}
+
+	tests := []struct {
+		id, body string
+		status   int
+	}{
+		{"1", `{"status":"resolved"}`, http.StatusOK},
+		{"1", `{"status":"ignored"}`, http.StatusBadRequest},
+		{"x", `{"status":"resolved"}`, http.StatusBadRequest},
+		{"9", `{"status":"resolved"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest("PUT", "/ingest/conflicts/"+tt.id, strings.NewReader(tt.body)), map[string]string{"id": tt.id})
+		controller.ReviewConflict(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected status code %v, got %v", tt.id, tt.body, tt.status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetConflicts(rr, httptest.NewRequest("GET", "/ingest/conflicts?status=resolved", nil))
+	conflicts = nil
+	json.NewDecoder(rr.Body).Decode(&conflicts)
+	if len(conflicts) != 1 || conflicts[0].ReviewedAt == "" {
+		t.Errorf("expected the resolved conflict, got %+v", conflicts)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetConflicts(rr, httptest.NewRequest("GET", "/ingest/conflicts?status=closed", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v for an unknown status, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
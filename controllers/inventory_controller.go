@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type InventoryController struct {
+	Service services.InventoryServiceInterface
+}
+
+// RecordMovement handles recording a stock movement of an album or one of its editions.
+func (c *InventoryController) RecordMovement(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var movement models.StockMovement
+	if err := decodeRequest(r, &movement); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	movement.AlbumID = uint(albumID)
+
+	if err := c.Service.RecordMovement(&movement); err != nil {
+		http.Error(w, err.Error(), inventoryErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, movement)
+}
+
+// GetMovements handles retrieving the stock ledger of an album.
+func (c *InventoryController) GetMovements(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	movements, err := c.Service.GetMovements(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), inventoryErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, movements)
+}
+
+// SetLowStockThreshold handles setting the low-stock threshold of an album or one of its editions.
+func (c *InventoryController) SetLowStockThreshold(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var level models.StockLevel
+	if err := decodeRequest(r, &level); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	level.AlbumID = uint(albumID)
+
+	if err := c.Service.SetLowStockThreshold(&level); err != nil {
+		http.Error(w, err.Error(), inventoryErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, level)
+}
+
+// GetStock handles retrieving the stock levels of an album and its editions.
+func (c *InventoryController) GetStock(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	levels, err := c.Service.GetStock(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), inventoryErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, levels)
+}
+
+// GetLowStock handles retrieving every stock level at or below its low-stock threshold.
+func (c *InventoryController) GetLowStock(w http.ResponseWriter, r *http.Request) {
+	levels, err := c.Service.GetLowStock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, levels)
+}
+
+// inventoryErrorStatus maps a missing album or edition to 404 and a movement the stock cannot cover to 409.
+func inventoryErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInsufficientStock):
+		return http.StatusConflict
+	}
+	return fallback
+}
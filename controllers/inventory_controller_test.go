@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestInventoryEndpoints(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	controller := &InventoryController{Service: &services.InventoryService{
+		Repo:      &repositories.InventoryRepository{DB: db},
+		AlbumRepo: albumRepo,
+		Editions:  &repositories.EditionRepository{DB: db},
+	}}
+	albumController := &AlbumController{Service: &services.AlbumService{Repo: albumRepo}}
+
+	for _, album := range []*models.Album{
+		{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200},
+		{Name: "Album 2", ReleaseDate: "2021-01-01", Price: 300},
+	} {
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest("PUT", "/albums/1/stock", bytes.NewBufferString(`{"low_stock_threshold": 1}`)), map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+	controller.SetLowStockThreshold(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	for _, body := range []string{`{"kind": "receive", "quantity": 2}`, `{"kind": "sell", "quantity": 1}`} {
+		req = mux.SetURLVars(httptest.NewRequest("POST", "/albums/1/stock/movements", bytes.NewBufferString(body)), map[string]string{"id": "1"})
+		rr = httptest.NewRecorder()
+		controller.RecordMovement(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/albums/1/stock/movements", bytes.NewBufferString(`{"kind": "sell", "quantity": 2}`)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.RecordMovement(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status code %v, got %v", http.StatusConflict, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/albums/99/stock/movements", bytes.NewBufferString(`{"kind": "receive", "quantity": 2}`)), map[string]string{"id": "99"})
+	rr = httptest.NewRecorder()
+	controller.RecordMovement(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/stock/movements", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetMovements(rr, req)
+	var movements []models.StockMovement
+	if err := json.NewDecoder(rr.Body).Decode(&movements); err != nil {
+		t.Fatalf("failed to decode movements: %v", err)
+	}
+	if len(movements) != 2 || movements[1].Kind != models.MovementSell || movements[1].Balance != 1 {
+		t.Errorf("unexpected movements %+v", movements)
+	}
+
+	req = httptest.NewRequest("GET", "/stock/low", nil)
+	rr = httptest.NewRecorder()
+	controller.GetLowStock(rr, req)
+	var low []models.StockLevel
+	if err := json.NewDecoder(rr.Body).Decode(&low); err != nil {
+		t.Fatalf("failed to decode stock levels: %v", err)
+	}
+	if len(low) != 1 || low[0] != (models.StockLevel{AlbumID: 1, Quantity: 1, LowStockThreshold: 1, LowStock: true}) {
+		t.Errorf("unexpected low stock %+v", low)
+	}
+
+	req = httptest.NewRequest("GET", "/albums?in_stock=true", nil)
+	rr = httptest.NewRecorder()
+	albumController.GetAlbums(rr, req)
+	var albums []models.Album
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode albums: %v", err)
+	}
+	if len(albums) != 2 || albums[0].ID != 1 || albums[1].ID != 2 {
+		t.Errorf("expected the album in stock and the untracked album, got %+v", albums)
+	}
+
+	req = httptest.NewRequest("GET", "/albums?in_stock=maybe", nil)
+	rr = httptest.NewRecorder()
+	albumController.GetAlbums(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type LabelController struct {
+	Service services.LabelServiceInterface
+}
+
+// CreateLabel handles the creation of a new label.
+func (c *LabelController) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var label models.Label
+	if err := decodeRequest(r, &label); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	if err := c.Service.CreateLabel(&label); err != nil {
+		http.Error(w, err.Error(), labelErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, label)
+}
+
+// GetLabels handles retrieving all labels.
+func (c *LabelController) GetLabels(w http.ResponseWriter, r *http.Request) {
+	labels, err := c.Service.GetLabels()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, labels)
+}
+
+// GetLabel handles retrieving a single label by ID.
+func (c *LabelController) GetLabel(w http.ResponseWriter, r *http.Request) {
+	labelID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid label ID", http.StatusBadRequest)
+		return
+	}
+
+	label, err := c.Service.GetLabel(uint(labelID))
+	if err != nil {
+		http.Error(w, err.Error(), labelErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, label)
+}
+
+// UpdateLabel handles updating an existing label.
+func (c *LabelController) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var label models.Label
+	if err := decodeRequest(r, &label); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	labelID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid label ID", http.StatusBadRequest)
+		return
+	}
+	label.ID = uint(labelID)
+
+	if err := c.Service.UpdateLabel(&label); err != nil {
+		http.Error(w, err.Error(), labelErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, label)
+}
+
+// DeleteLabel handles deleting a label by ID.
+func (c *LabelController) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	labelID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid label ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.DeleteLabel(uint(labelID)); err != nil {
+		http.Error(w, err.Error(), labelErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAlbumsByLabel handles retrieving the albums of a label sorted by release date.
+func (c *LabelController) GetAlbumsByLabel(w http.ResponseWriter, r *http.Request) {
+	labelID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid label ID", http.StatusBadRequest)
+		return
+	}
+
+	albums, err := c.Service.GetAlbumsByLabel(uint(labelID))
+	if err != nil {
+		http.Error(w, err.Error(), labelErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, albums)
+}
+
+// labelErrorStatus maps a missing label to 404 and a label still in use to 409.
+func labelErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrLabelInUse):
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestLabelControllerLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	controller := &LabelController{Service: &services.LabelService{Repo: &repositories.LabelRepository{DB: db}}}
+
+	req := httptest.NewRequest("POST", "/labels", bytes.NewBufferString(`{"name": "Island", "country": "GB"}`))
+	rr := httptest.NewRecorder()
+	controller.CreateLabel(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var label models.Label
+	if err := json.NewDecoder(rr.Body).Decode(&label); err != nil {
+		t.Fatalf("failed to decode label: %v", err)
+	}
+
+	_, err := db.Exec(`INSERT INTO albums (name, release_date, genre, price_minor, description, label_id, catalog_number) VALUES ('Island Album', '2020-01-01', 'Rock', 20000, '', ?, 'ILPS 1')`, label.ID)
+	if err != nil {
+		t.Fatalf("failed to insert test album: %v", err)
+	}
+
+	id := map[string]string{"id": "1"}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/labels/1/albums", nil), id)
+	rr = httptest.NewRecorder()
+	controller.GetAlbumsByLabel(rr, req)
+	var albums []models.Album
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode albums: %v", err)
+	}
+	if len(albums) != 1 || albums[0].CatalogNumber != "ILPS 1" {
+		t.Errorf("expected the label's album, got %+v", albums)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/labels/1", nil), id)
+	rr = httptest.NewRecorder()
+	controller.DeleteLabel(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status code %v, got %v", http.StatusConflict, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/labels/9", nil), map[string]string{"id": "9"})
+	rr = httptest.NewRecorder()
+	controller.GetLabel(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
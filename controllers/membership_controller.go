@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type MembershipController struct {
+	Service services.MembershipServiceInterface
+}
+
+// AddMember handles adding a member to a group.
+func (c *MembershipController) AddMember(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var membership models.Membership
+	if err := decodeRequest(r, &membership); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+	membership.GroupID = uint(groupID)
+
+	if err := c.Service.AddMember(&membership); err != nil {
+		http.Error(w, err.Error(), membershipErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, membership)
+}
+
+// GetMembers handles retrieving the members of a group, optionally on a given date.
+func (c *MembershipController) GetMembers(w http.ResponseWriter, r *http.Request) {
+	groupID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+
+	at := r.URL.Query().Get("at")
+	if at != "" {
+		if err := services.ValidateDate("at", at); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	memberships, err := c.Service.GetMembers(uint(groupID), at)
+	if err != nil {
+		http.Error(w, err.Error(), membershipErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, memberships)
+}
+
+// UpdateMembership handles updating a membership of a group.
+func (c *MembershipController) UpdateMembership(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var membership models.Membership
+	if err := decodeRequest(r, &membership); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	vars := mux.Vars(r)
+	groupID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+	membershipID, err := strconv.ParseUint(vars["membership_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid membership ID", http.StatusBadRequest)
+		return
+	}
+	membership.GroupID = uint(groupID)
+	membership.ID = uint(membershipID)
+
+	if err := c.Service.UpdateMembership(&membership); err != nil {
+		http.Error(w, err.Error(), membershipErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, membership)
+}
+
+// RemoveMember handles deleting a membership of a group.
+func (c *MembershipController) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+	membershipID, err := strconv.ParseUint(vars["membership_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid membership ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.RemoveMember(uint(groupID), uint(membershipID)); err != nil {
+		http.Error(w, err.Error(), membershipErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAlbumLineup handles retrieving the group members who played on an album.
+func (c *MembershipController) GetAlbumLineup(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	lineup, err := c.Service.GetAlbumLineup(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), membershipErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, lineup)
+}
+
+// membershipErrorStatus maps a missing group, member, membership or album to 404.
+func membershipErrorStatus(err error, fallback int) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
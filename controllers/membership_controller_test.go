@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func setupMembershipController(t *testing.T) *MembershipController {
+	db := setupTestDB(t)
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type, kind) VALUES
+		(1, 'The Band', 'Rock', 'group'),
+		(2, 'Alice', 'Vocalist', 'person');
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'Early Days', '1992-03-01', 'Rock', 20000, '');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES (1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	return &MembershipController{Service: &services.MembershipService{
+		Repo:         &repositories.MembershipRepository{DB: db},
+		MusicianRepo: &repositories.MusicianRepository{DB: db},
+		AlbumRepo:    &repositories.AlbumRepository{DB: db},
+	}}
+}
+
+func TestAddMemberAndGetLineup(t *testing.T) {
+	controller := setupMembershipController(t)
+
+	body := `{"member_id": 2, "instrument": "vocals", "joined_on": "1990-01-01"}`
+	req := httptest.NewRequest("POST", "/musicians/1/members", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	controller.AddMember(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/albums/1/lineup", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+
+	controller.GetAlbumLineup(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v", http.StatusOK, rr.Code)
+	}
+	var lineup []models.Membership
+	if err := json.NewDecoder(rr.Body).Decode(&lineup); err != nil {
+		t.Fatalf("failed to decode lineup: %v", err)
+	}
+	if len(lineup) != 1 || lineup[0].Member.Name != "Alice" {
+		t.Errorf("expected Alice in the lineup, got %+v", lineup)
+	}
+}
+
+func TestMembershipControllerNotFound(t *testing.T) {
+	controller := setupMembershipController(t)
+
+	req := httptest.NewRequest("GET", "/albums/9/lineup", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	rr := httptest.NewRecorder()
+
+	controller.GetAlbumLineup(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestGetMembersInvalidDate(t *testing.T) {
+	controller := setupMembershipController(t)
+
+	req := httptest.NewRequest("GET", "/musicians/1/members?at=yesterday", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	controller.GetMembers(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -1,7 +1,8 @@
 package controllers
 
 import (
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"jukebox/models"
 	"jukebox/services"
 	"net/http"
@@ -16,9 +17,13 @@ type MusicianController struct {
 
 // CreateMusician handles the creation of a new musician.
 func (c *MusicianController) CreateMusician(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
 	var musician models.Musician
-	if err := json.NewDecoder(r.Body).Decode(&musician); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(r, &musician); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
 		return
 	}
 
@@ -27,8 +32,7 @@ func (c *MusicianController) CreateMusician(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(musician)
+	writeResponse(w, r, http.StatusCreated, musician)
 }
 
 // GetMusicians handles retrieving a list of musicians.
@@ -38,14 +42,18 @@ func (c *MusicianController) GetMusicians(w http.ResponseWriter, r *http.Request
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	json.NewEncoder(w).Encode(musicians)
+	writeResponse(w, r, http.StatusOK, musicians)
 }
 
 // UpdateMusician handles updating an existing musician.
 func (c *MusicianController) UpdateMusician(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
 	var musician models.Musician
-	if err := json.NewDecoder(r.Body).Decode(&musician); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(r, &musician); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
 		return
 	}
 
@@ -60,12 +68,18 @@ func (c *MusicianController) UpdateMusician(w http.ResponseWriter, r *http.Reque
 	musician.ID = uint(musicianID)
 
 	if err := c.Service.UpdateMusician(&musician); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidMusician):
+			status = http.StatusBadRequest
+		case errors.Is(err, sql.ErrNoRows):
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(musician)
+	writeResponse(w, r, http.StatusOK, musician)
 }
 
 // DeleteMusician handles deleting a musician by ID.
@@ -86,7 +100,7 @@ func (c *MusicianController) DeleteMusician(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// GetMusiciansByAlbum handles retrieving musicians for a specific album.
+// GetMusiciansByAlbum handles retrieving musicians for a specific album with their credits.
 func (c *MusicianController) GetMusiciansByAlbum(w http.ResponseWriter, r *http.Request) {
 	// Parse album ID from the URL
 	vars := mux.Vars(r)
@@ -96,12 +110,16 @@ func (c *MusicianController) GetMusiciansByAlbum(w http.ResponseWriter, r *http.
 		return
 	}
 
-	musicians, err := c.Service.GetMusiciansByAlbum(uint(albumID))
+	role, ok := roleFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	musicians, err := c.Service.GetMusiciansByAlbum(uint(albumID), role)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(musicians)
+	writeResponse(w, r, http.StatusOK, musicians)
 }
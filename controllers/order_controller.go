@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type OrderController struct {
+	Service services.OrderServiceInterface
+	// Customers, when set, lets signed-in customers create carts whose orders are theirs, and admins manage
+	// orders. Without it, only guest carts and orders can be used.
+	Customers services.CustomerServiceInterface
+}
+
+// CreateCart handles creating an empty cart. The body, which may be omitted, can set the cart's currency. A
+// request signed in with a session token creates a cart for that customer.
+func (c *OrderController) CreateCart(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var cart models.Cart
+	if err := decodeRequest(r, &cart); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	cart.CustomerID = 0
+	if token := bearerToken(r); token != "" && c.Customers != nil {
+		customer, err := c.Customers.Authenticate(token)
+		if err != nil {
+			unauthorized(w, err)
+			return
+		}
+		cart.CustomerID = customer.ID
+	}
+
+	if err := c.Service.CreateCart(&cart); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, cart)
+}
+
+// GetCart handles retrieving a cart with its items. A customer's cart needs their session.
+func (c *OrderController) GetCart(w http.ResponseWriter, r *http.Request) {
+	cart, ok := c.cart(w, r)
+	if !ok {
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, cart)
+}
+
+// SetCartItem handles setting the quantity of an album or edition in a cart. A customer's cart needs their
+// session.
+func (c *OrderController) SetCartItem(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var item models.CartItem
+	if err := decodeRequest(r, &item); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	cart, ok := c.cart(w, r)
+	if !ok {
+		return
+	}
+
+	cart, err := c.Service.SetCartItem(cart.ID, item)
+	if err != nil {
+		http.Error(w, err.Error(), orderErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, cart)
+}
+
+// Checkout handles turning a cart into an order. A customer's cart needs their session.
+func (c *OrderController) Checkout(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var checkout models.Checkout
+	if err := decodeRequest(r, &checkout); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	cart, ok := c.cart(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := c.Service.Checkout(cart.ID, checkout)
+	if err != nil {
+		http.Error(w, err.Error(), orderErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, order)
+}
+
+// GetOrders handles retrieving the orders, optionally only those with the status query parameter. Only admins
+// may list every order.
+func (c *OrderController) GetOrders(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+		return
+	}
+
+	orders, err := c.Service.GetOrders(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, orders)
+}
+
+// GetOrder handles retrieving a single order by ID. A customer's order needs their session.
+func (c *OrderController) GetOrder(w http.ResponseWriter, r *http.Request) {
+	order, ok := c.order(w, r)
+	if !ok {
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, order)
+}
+
+// PayOrder handles charging a pending order. A customer's order needs their session.
+func (c *OrderController) PayOrder(w http.ResponseWriter, r *http.Request) {
+	c.transition(w, r, false, c.Service.PayOrder)
+}
+
+// ShipOrder handles marking a paid order shipped. Only admins may ship orders.
+func (c *OrderController) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	c.transition(w, r, true, c.Service.ShipOrder)
+}
+
+// CancelOrder handles cancelling a pending or paid order. Only admins may cancel orders.
+func (c *OrderController) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	c.transition(w, r, true, c.Service.CancelOrder)
+}
+
+// transition moves the order in the request URL on to another status with change, for admins only when
+// adminOnly is set.
+func (c *OrderController) transition(w http.ResponseWriter, r *http.Request, adminOnly bool, change func(orderID uint) (*models.Order, error)) {
+	if !acceptable(w, r) {
+		return
+	}
+	if adminOnly {
+		if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+			return
+		}
+	}
+
+	order, ok := c.order(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := change(order.ID)
+	if err != nil {
+		http.Error(w, err.Error(), orderErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, order)
+}
+
+// UploadTaxRates handles replacing the tax rate table; admins only.
+func (c *OrderController) UploadTaxRates(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+		return
+	}
+
+	var rates []models.TaxRate
+	if err := decodeRequest(r, &rates); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	stored, err := c.Service.UploadTaxRates(rates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, stored)
+}
+
+// GetTaxRates handles retrieving the tax rate table.
+func (c *OrderController) GetTaxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := c.Service.GetTaxRates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, rates)
+}
+
+// cart retrieves the cart in the request URL if the request may use it, answering the request otherwise.
+func (c *OrderController) cart(w http.ResponseWriter, r *http.Request) (*models.Cart, bool) {
+	cartID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid cart ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	cart, err := c.Service.GetCart(uint(cartID))
+	if err != nil {
+		http.Error(w, err.Error(), orderErrorStatus(err, http.StatusInternalServerError))
+		return nil, false
+	}
+	if !c.permits(w, r, cart.CustomerID, fmt.Sprintf("cart %d does not exist", cart.ID)) {
+		return nil, false
+	}
+	return cart, true
+}
+
+// order retrieves the order in the request URL if the request may use it, answering the request otherwise.
+func (c *OrderController) order(w http.ResponseWriter, r *http.Request) (*models.Order, bool) {
+	orderID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	order, err := c.Service.GetOrder(uint(orderID))
+	if err != nil {
+		http.Error(w, err.Error(), orderErrorStatus(err, http.StatusInternalServerError))
+		return nil, false
+	}
+	if !c.permits(w, r, order.CustomerID, fmt.Sprintf("order %d does not exist", order.ID)) {
+		return nil, false
+	}
+	return order, true
+}
+
+// permits reports whether the request may use a cart or order of customerID, answering it with notFound when
+// not. Guest carts and orders are open to anyone with their ID; a customer's are open only to that customer's
+// session and to admins, and look missing to everyone else so their IDs cannot be probed.
+func (c *OrderController) permits(w http.ResponseWriter, r *http.Request, customerID uint, notFound string) bool {
+	if customerID == 0 {
+		return true
+	}
+	if token := bearerToken(r); token != "" && c.Customers != nil {
+		customer, err := c.Customers.Authenticate(token)
+		if err != nil {
+			unauthorized(w, err)
+			return false
+		}
+		if customer.ID == customerID || customer.Admin {
+			return true
+		}
+	}
+	http.Error(w, notFound, http.StatusNotFound)
+	return false
+}
+
+// orderErrorStatus maps a missing cart, order, album or edition to 404, a declined payment to 402, and a cart
+// that was already checked out, stock that cannot cover it or a disallowed status change to 409.
+func orderErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPaymentDeclined):
+		return http.StatusPaymentRequired
+	case errors.Is(err, services.ErrCartCheckedOut), errors.Is(err, services.ErrInsufficientStock), errors.Is(err, services.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrNoExchangeRate):
+		return http.StatusBadRequest
+	}
+	return fallback
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	orderRepo := &repositories.OrderRepository{DB: db}
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}, AlbumRepo: albumRepo, Orders: orderRepo}
+	controller := &OrderController{Service: &services.OrderService{
+		Repo:      orderRepo,
+		AlbumRepo: albumRepo,
+		Editions:  &repositories.EditionRepository{DB: db},
+		Payments:  &services.FakePaymentProvider{},
+	}, Customers: customers}
+	admin := signIn(t, db, customers, "staff@example.com", true)
+	asAdmin := func(req *http.Request) *http.Request {
+		req.Header.Set("Authorization", "Bearer "+admin)
+		return req
+	}
+
+	album := &models.Album{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200}
+	if err := albumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	req := httptest.NewRequest("PUT", "/tax-rates", bytes.NewBufferString(`[{"country": "gb", "rate": 0.9}]`))
+	rr := httptest.NewRecorder()
+	controller.UploadTaxRates(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %v, got %v", http.StatusUnauthorized, rr.Code)
+	}
+
+	req = asAdmin(httptest.NewRequest("PUT", "/tax-rates", bytes.NewBufferString(`[{"country": "gb", "rate": 0.2}]`)))
+	rr = httptest.NewRecorder()
+	controller.UploadTaxRates(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/carts", nil)
+	rr = httptest.NewRecorder()
+	controller.CreateCart(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("PUT", "/carts/1/items", bytes.NewBufferString(`{"album_id": 1, "quantity": 2}`)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.SetCartItem(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("PUT", "/carts/1/items", bytes.NewBufferString(`{"album_id": 99, "quantity": 1}`)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.SetCartItem(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"country": "GB"}`)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.Checkout(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var order models.Order
+	if err := json.NewDecoder(rr.Body).Decode(&order); err != nil {
+		t.Fatalf("failed to decode order: %v", err)
+	}
+	if order.Subtotal != 400 || order.Tax != 80 || order.Total != 480 || order.Status != models.OrderPending {
+		t.Errorf("unexpected order %+v", order)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"country": "GB"}`)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.Checkout(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status code %v, got %v", http.StatusConflict, rr.Code)
+	}
+
+	for _, step := range []struct {
+		handler http.HandlerFunc
+		admin   bool
+		status  int
+	}{
+		{controller.ShipOrder, false, http.StatusUnauthorized},
+		{controller.ShipOrder, true, http.StatusConflict},
+		{controller.PayOrder, false, http.StatusOK},
+		{controller.ShipOrder, true, http.StatusOK},
+		{controller.CancelOrder, true, http.StatusConflict},
+	} {
+		req = mux.SetURLVars(httptest.NewRequest("POST", "/orders/1", nil), map[string]string{"id": "1"})
+		if step.admin {
+			req = asAdmin(req)
+		}
+		rr = httptest.NewRecorder()
+		step.handler(rr, req)
+		if rr.Code != step.status {
+			t.Errorf("expected status code %v, got %v: %s", step.status, rr.Code, rr.Body.String())
+		}
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/orders/99", nil), map[string]string{"id": "99"})
+	rr = httptest.NewRecorder()
+	controller.GetOrder(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetOrders(rr, httptest.NewRequest("GET", "/orders", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected listing every order to need a session, got %v", rr.Code)
+	}
+
+	req = asAdmin(httptest.NewRequest("GET", "/orders?status=shipped", nil))
+	rr = httptest.NewRecorder()
+	controller.GetOrders(rr, req)
+	var orders []models.Order
+	if err := json.NewDecoder(rr.Body).Decode(&orders); err != nil {
+		t.Fatalf("failed to decode orders: %v", err)
+	}
+	if len(orders) != 1 || orders[0].PaymentReference == "" {
+		t.Errorf("expected the shipped order with its payment reference, got %+v", orders)
+	}
+}
+
+func TestOrderOwnership(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	orderRepo := &repositories.OrderRepository{DB: db}
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}, AlbumRepo: albumRepo, Orders: orderRepo}
+	controller := &OrderController{Service: &services.OrderService{
+		Repo:      orderRepo,
+		AlbumRepo: albumRepo,
+		Editions:  &repositories.EditionRepository{DB: db},
+		Payments:  &services.FakePaymentProvider{},
+	}, Customers: customers}
+
+	if err := albumRepo.CreateAlbum(&models.Album{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200}); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	if err := orderRepo.ReplaceTaxRates([]models.TaxRate{{Country: "GB", Rate: 0.2}}); err != nil {
+		t.Fatalf("failed to store tax rates: %v", err)
+	}
+	tokens := map[string]string{
+		"ann":   signIn(t, db, customers, "ann@example.com", false),
+		"bob":   signIn(t, db, customers, "bob@example.com", false),
+		"staff": signIn(t, db, customers, "staff@example.com", true),
+	}
+	as := func(who string, req *http.Request) *http.Request {
+		if who != "" {
+			req.Header.Set("Authorization", "Bearer "+tokens[who])
+		}
+		return mux.SetURLVars(req, map[string]string{"id": "1"})
+	}
+
+	rr := httptest.NewRecorder()
+	controller.CreateCart(rr, as("ann", httptest.NewRequest("POST", "/carts", nil)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	for _, tt := range []struct {
+		who    string
+		status int
+	}{
+		{"", http.StatusNotFound},
+		{"bob", http.StatusNotFound},
+		{"ann", http.StatusOK},
+		{"staff", http.StatusOK},
+	} {
+		rr = httptest.NewRecorder()
+		controller.SetCartItem(rr, as(tt.who, httptest.NewRequest("PUT", "/carts/1/items", bytes.NewBufferString(`{"album_id": 1, "quantity": 1}`))))
+		if rr.Code != tt.status {
+			t.Errorf("%q: expected setting an item to answer %v, got %v", tt.who, tt.status, rr.Code)
+		}
+		rr = httptest.NewRecorder()
+		controller.GetCart(rr, as(tt.who, httptest.NewRequest("GET", "/carts/1", nil)))
+		if rr.Code != tt.status {
+			t.Errorf("%q: expected retrieving the cart to answer %v, got %v", tt.who, tt.status, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.Checkout(rr, as("bob", httptest.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"country": "GB"}`))))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected another customer's checkout to answer %v, got %v", http.StatusNotFound, rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	controller.Checkout(rr, as("ann", httptest.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"country": "GB"}`))))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	for _, tt := range []struct {
+		handler http.HandlerFunc
+		who     string
+		status  int
+	}{
+		{controller.GetOrder, "", http.StatusNotFound},
+		{controller.GetOrder, "bob", http.StatusNotFound},
+		{controller.PayOrder, "bob", http.StatusNotFound},
+		{controller.CancelOrder, "ann", http.StatusForbidden},
+		{controller.GetOrder, "ann", http.StatusOK},
+		{controller.PayOrder, "ann", http.StatusOK},
+		{controller.CancelOrder, "staff", http.StatusOK},
+	} {
+		rr = httptest.NewRecorder()
+		tt.handler(rr, as(tt.who, httptest.NewRequest("POST", "/orders/1", nil)))
+		if rr.Code != tt.status {
+			t.Errorf("%q: expected status code %v, got %v: %s", tt.who, tt.status, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetOrders(rr, as("ann", httptest.NewRequest("GET", "/orders", nil)))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected a customer listing every order to answer %v, got %v", http.StatusForbidden, rr.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type PlayController struct {
+	Service services.PlayServiceInterface
+	// Customers signs in the listeners.
+	Customers services.CustomerServiceInterface
+}
+
+// RecordPlay handles the signed-in customer reporting a play. A play sent again answers 200 with the stored
+// play instead of 201.
+func (c *PlayController) RecordPlay(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+
+	var play models.Play
+	if err := decodeRequest(r, &play); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	created, err := c.Service.RecordPlay(customer.ID, &play)
+	if err != nil {
+		http.Error(w, err.Error(), playErrorStatus(err))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeResponse(w, r, status, play)
+}
+
+// RecordPlays handles the signed-in customer reporting a batch of plays, answering with the outcome of each.
+func (c *PlayController) RecordPlays(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+
+	var batch models.PlayBatch
+	if err := decodeRequest(r, &batch); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	result, err := c.Service.RecordPlays(customer.ID, batch)
+	if err != nil {
+		http.Error(w, err.Error(), playErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// GetHistory handles retrieving a page of the signed-in customer's plays. Accepts from, to, page and per_page.
+func (c *PlayController) GetHistory(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+	page, perPage, err := pageFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	history, err := c.Service.GetHistory(customer.ID, query.Get("from"), query.Get("to"), page, perPage)
+	if err != nil {
+		http.Error(w, err.Error(), playErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, history)
+}
+
+// GetMyTop handles ranking what the signed-in customer played most. Accepts from, to and limit.
+func (c *PlayController) GetMyTop(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+	c.getTop(w, r, customer.ID)
+}
+
+// GetTop handles ranking what every customer played most. Accepts from, to and limit.
+func (c *PlayController) GetTop(w http.ResponseWriter, r *http.Request) {
+	c.getTop(w, r, 0)
+}
+
+func (c *PlayController) getTop(w http.ResponseWriter, r *http.Request, customerID uint) {
+	limit, err := limitFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	stats, err := c.Service.GetTop(mux.Vars(r)["type"], customerID, query.Get("from"), query.Get("to"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), playErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, stats)
+}
+
+// playErrorStatus maps a missing album to 404 and anything else to 400.
+func playErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestPlayScrobbling(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}, AlbumRepo: albumRepo, Orders: &repositories.OrderRepository{DB: db}}
+	controller := &PlayController{Service: &services.PlayService{Repo: &repositories.PlayRepository{DB: db}}, Customers: customers}
+
+	if err := albumRepo.CreateAlbum(&models.Album{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200}); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	if _, err := customers.Register(models.Registration{Email: "ann@example.com", Name: "Ann", Password: "correct horse"}); err != nil {
+		t.Fatalf("failed to register customer: %v", err)
+	}
+	session, err := customers.Login(models.Credentials{Email: "ann@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	signedIn := func(method, url, body string) *http.Request {
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+		return req
+	}
+
+	rr := httptest.NewRecorder()
+	controller.RecordPlay(rr, httptest.NewRequest("POST", "/me/plays", bytes.NewBufferString(`{"client_id": "x", "album_id": 1}`)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %v, got %v", http.StatusUnauthorized, rr.Code)
+	}
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"client_id": "x", "album_id": 1, "played_at": "2026-01-01T10:00:00Z"}`, http.StatusCreated},
+		{`{"client_id": "x", "album_id": 1}`, http.StatusOK},
+		{`{"client_id": "y", "album_id": 99}`, http.StatusNotFound},
+		{`{"client_id": "", "album_id": 1}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		controller.RecordPlay(rr, signedIn("POST", "/me/plays", tt.body))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status code %v, got %v: %s", tt.body, tt.status, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.RecordPlays(rr, signedIn("POST", "/me/plays/batch", `{"plays": [{"client_id": "x", "album_id": 1}, {"client_id": "z", "album_id": 1, "played_at": "2026-01-02T10:00:00Z"}]}`))
+	var result models.PlayBatchResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode batch result: %v", err)
+	}
+	if rr.Code != http.StatusOK || result.Accepted != 1 || result.Duplicates != 1 {
+		t.Errorf("unexpected batch result %v %+v", rr.Code, result)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetHistory(rr, signedIn("GET", "/me/plays?per_page=1", ""))
+	var history models.PlayPage
+	if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+		t.Fatalf("failed to decode history: %v", err)
+	}
+	if history.Total != 2 || len(history.Plays) != 1 || history.Plays[0].ClientID != "z" {
+		t.Errorf("unexpected history %+v", history)
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetMyTop(rr, mux.SetURLVars(signedIn("GET", "/me/plays/top/albums?from=2026-01-02T00:00:00Z", ""), map[string]string{"type": "albums"}))
+	var stats models.PlayStats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode statistics: %v", err)
+	}
+	if stats.Plays != 1 || len(stats.Top) != 1 || stats.Top[0].Name != "Album 1" {
+		t.Errorf("unexpected statistics %+v", stats)
+	}
+
+	topTests := []struct {
+		url, topType string
+		status       int
+	}{
+		{"/plays/top/albums", "albums", http.StatusOK},
+		{"/plays/top/albums?limit=many", "albums", http.StatusBadRequest},
+		{"/plays/top/labels", "labels", http.StatusBadRequest},
+	}
+	for _, tt := range topTests {
+		rr := httptest.NewRecorder()
+		controller.GetTop(rr, mux.SetURLVars(httptest.NewRequest("GET", tt.url, nil), map[string]string{"type": tt.topType}))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status code %v, got %v", tt.url, tt.status, rr.Code)
+		}
+	}
+}
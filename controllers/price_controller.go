@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type PriceController struct {
+	Service services.PriceServiceInterface
+	// Customers signs in the admins who replace the exchange rates. Without it, the rates cannot be replaced.
+	Customers services.CustomerServiceInterface
+}
+
+// UploadExchangeRates handles replacing the exchange rate table; admins only.
+func (c *PriceController) UploadExchangeRates(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+		return
+	}
+
+	var rates []models.ExchangeRate
+	if err := decodeRequest(r, &rates); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	stored, err := c.Service.UploadExchangeRates(rates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, stored)
+}
+
+// GetExchangeRates handles retrieving the exchange rate table.
+func (c *PriceController) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := c.Service.GetExchangeRates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, rates)
+}
+
+// GetAlbumPrices handles retrieving the price list of an album.
+func (c *PriceController) GetAlbumPrices(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := c.Service.GetAlbumPrices(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), priceErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, prices)
+}
+
+// SetAlbumPrice handles setting the price of an album in one currency.
+func (c *PriceController) SetAlbumPrice(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var price models.AlbumPrice
+	if err := decodeRequest(r, &price); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	price.AlbumID = uint(albumID)
+	price.Currency = vars["currency"]
+
+	if err := c.Service.SetAlbumPrice(&price); err != nil {
+		http.Error(w, err.Error(), priceErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, price)
+}
+
+// RemoveAlbumPrice handles removing the price of an album in one currency.
+func (c *PriceController) RemoveAlbumPrice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.RemoveAlbumPrice(uint(albumID), vars["currency"]); err != nil {
+		http.Error(w, err.Error(), priceErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// priceErrorStatus maps a missing album or price to 404 and a missing exchange rate to 400.
+func priceErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNoExchangeRate):
+		return http.StatusBadRequest
+	}
+	return fallback
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestUploadExchangeRatesFormats(t *testing.T) {
+	db := setupTestDB(t)
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}}
+	controller := &PriceController{Service: &services.PriceService{Repo: &repositories.PriceRepository{DB: db}, AlbumRepo: &repositories.AlbumRepository{DB: db}},
+		Customers: customers}
+	admin := signIn(t, db, customers, "staff@example.com", true)
+	customer := signIn(t, db, customers, "ann@example.com", false)
+
+	bodies := map[string]string{
+		"application/json": `[{"currency": "eur", "rate": 0.9}, {"currency": "JPY", "rate": 150}]`,
+		"application/xml":  `<rates><rate><currency>EUR</currency><rate>0.9</rate></rate><rate><currency>JPY</currency><rate>150</rate></rate></rates>`,
+		"text/csv":         "currency,rate\nEUR,0.9\nJPY,150\n",
+	}
+
+	for contentType, body := range bodies {
+		req := httptest.NewRequest("PUT", "/exchange-rates", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+admin)
+		rr := httptest.NewRecorder()
+		controller.UploadExchangeRates(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status code %v, got %v: %s", contentType, http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var rates []models.ExchangeRate
+		if err := json.NewDecoder(rr.Body).Decode(&rates); err != nil {
+			t.Fatalf("%s: failed to decode rates: %v", contentType, err)
+		}
+		if len(rates) != 2 || rates[0] != (models.ExchangeRate{Currency: "EUR", Rate: 0.9}) || rates[1].Rate != 150 {
+			t.Errorf("%s: unexpected rates %+v", contentType, rates)
+		}
+	}
+
+	req := httptest.NewRequest("PUT", "/exchange-rates", bytes.NewBufferString(`[{"currency": "USD", "rate": 2}]`))
+	req.Header.Set("Authorization", "Bearer "+admin)
+	rr := httptest.NewRecorder()
+	controller.UploadExchangeRates(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	// Only admins may replace the rates
+	for token, expected := range map[string]int{"": http.StatusUnauthorized, customer: http.StatusForbidden} {
+		req := httptest.NewRequest("PUT", "/exchange-rates", bytes.NewBufferString(`[{"currency": "EUR", "rate": 2}]`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		controller.UploadExchangeRates(rr, req)
+		if rr.Code != expected {
+			t.Errorf("expected status code %v, got %v", expected, rr.Code)
+		}
+	}
+}
+
+func TestGetAlbumsWithCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	prices := &services.PriceService{Repo: &repositories.PriceRepository{DB: db}, AlbumRepo: albumRepo}
+	controller := &PriceController{Service: prices}
+	albumController := &AlbumController{Service: &services.AlbumService{Repo: albumRepo, Prices: prices}}
+
+	if _, err := prices.UploadExchangeRates([]models.ExchangeRate{{Currency: "GBP", Rate: 0.8}}); err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+	for _, album := range []*models.Album{
+		{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 199.99},
+		{Name: "Album 2", ReleaseDate: "2021-01-01", Price: 300},
+	} {
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest("PUT", "/albums/2/prices/gbp", bytes.NewBufferString(`{"price": 229}`)), map[string]string{"id": "2", "currency": "gbp"})
+	rr := httptest.NewRecorder()
+	controller.SetAlbumPrice(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/albums?currency=gbp", nil)
+	rr = httptest.NewRecorder()
+	albumController.GetAlbums(rr, req)
+	var albums []models.Album
+	if err := json.NewDecoder(rr.Body).Decode(&albums); err != nil {
+		t.Fatalf("failed to decode albums: %v", err)
+	}
+	// 199.99 USD at 0.8 is 159.992 GBP, rounded to the penny
+	if len(albums) != 2 || albums[0].Price != 159.99 || albums[1].Price != 229 || albums[1].Currency != "GBP" {
+		t.Errorf("expected converted and listed GBP prices, got %+v", albums)
+	}
+
+	// An unsupported currency and one without an exchange rate are both rejected
+	for _, currency := range []string{"XYZ", "EUR"} {
+		req = httptest.NewRequest("GET", "/albums?currency="+currency, nil)
+		rr = httptest.NewRecorder()
+		albumController.GetAlbums(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("currency %s: expected status code %v, got %v", currency, http.StatusBadRequest, rr.Code)
+		}
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/albums/1/prices/GBP", nil), map[string]string{"id": "1", "currency": "GBP"})
+	rr = httptest.NewRecorder()
+	controller.RemoveAlbumPrice(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/9/prices", nil), map[string]string{"id": "9"})
+	rr = httptest.NewRecorder()
+	controller.GetAlbumPrices(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type PromotionController struct {
+	Service services.PromotionServiceInterface
+}
+
+// CreatePromotion handles creating a promotion.
+func (c *PromotionController) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var promotion models.Promotion
+	if err := decodeRequest(r, &promotion); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	if err := c.Service.CreatePromotion(&promotion); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, promotion)
+}
+
+// GetPromotions handles retrieving every promotion.
+func (c *PromotionController) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := c.Service.GetPromotions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, promotions)
+}
+
+// GetPromotion handles retrieving a single promotion by ID.
+func (c *PromotionController) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	promotionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := c.Service.GetPromotion(uint(promotionID))
+	if err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, promotion)
+}
+
+// UpdatePromotion handles updating an existing promotion.
+func (c *PromotionController) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var promotion models.Promotion
+	if err := decodeRequest(r, &promotion); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+	promotion.ID = uint(promotionID)
+
+	if err := c.Service.UpdatePromotion(&promotion); err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, promotion)
+}
+
+// DeletePromotion handles deleting a promotion by ID.
+func (c *PromotionController) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	promotionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.DeletePromotion(uint(promotionID)); err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SchedulePrice handles scheduling a change of an album's price.
+func (c *PromotionController) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+
+	var schedule models.PriceSchedule
+	if err := decodeRequest(r, &schedule); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	schedule.AlbumID = uint(albumID)
+
+	if err := c.Service.SchedulePrice(&schedule); err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, schedule)
+}
+
+// GetPriceSchedules handles retrieving the scheduled price changes of an album.
+func (c *PromotionController) GetPriceSchedules(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	schedules, err := c.Service.GetPriceSchedules(uint(albumID))
+	if err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, schedules)
+}
+
+// CancelPriceSchedule handles cancelling a pending price change of an album.
+func (c *PromotionController) CancelPriceSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	scheduleID, err := strconv.ParseUint(vars["schedule_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Service.CancelPriceSchedule(uint(albumID), uint(scheduleID)); err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetEffectivePrice handles resolving what an album costs at the time given by the at query parameter,
+// or now.
+func (c *PromotionController) GetEffectivePrice(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+
+	at := time.Now()
+	if value := r.URL.Query().Get("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "at must be a time in RFC 3339 format, e.g. 2024-11-29T00:00:00Z", http.StatusBadRequest)
+			return
+		}
+	}
+
+	price, err := c.Service.GetEffectivePrice(uint(albumID), at)
+	if err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, price)
+}
+
+// promotionErrorStatus maps a missing promotion, album or pending price change to 404.
+func promotionErrorStatus(err error, fallback int) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func setupPromotionController(t *testing.T) (*PromotionController, *repositories.AlbumRepository) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	prices := &services.PriceService{Repo: &repositories.PriceRepository{DB: db}, AlbumRepo: albumRepo}
+	service := &services.PromotionService{
+		Repo:      &repositories.PromotionRepository{DB: db},
+		AlbumRepo: albumRepo,
+		Prices:    prices,
+		Editions:  &repositories.EditionRepository{DB: db},
+	}
+	return &PromotionController{Service: service}, albumRepo
+}
+
+func TestPromotionLifecycle(t *testing.T) {
+	controller, albumRepo := setupPromotionController(t)
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 200}
+	if err := albumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	body := `{"name": "Black Friday", "kind": "percentage", "percent_off": 30, "album_id": 1, "starts_at": "2024-11-29T00:00:00Z", "ends_at": "2024-11-30T00:00:00Z"}`
+	req := httptest.NewRequest("POST", "/promotions", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	controller.CreatePromotion(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	body = `{"name": "Black Friday", "kind": "percentage", "percent_off": 40, "album_id": 1, "starts_at": "2024-11-29T00:00:00Z", "ends_at": "2024-12-02T00:00:00Z"}`
+	req = mux.SetURLVars(httptest.NewRequest("PUT", "/promotions/1", bytes.NewBufferString(body)), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.UpdatePromotion(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/effective-price?at=2024-12-01T12:00:00Z", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetEffectivePrice(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var price models.EffectivePrice
+	if err := json.NewDecoder(rr.Body).Decode(&price); err != nil {
+		t.Fatalf("failed to decode price: %v", err)
+	}
+	if price.Price != 200 || price.EffectivePrice != 120 || price.PromotionID != 1 || price.At != "2024-12-01T12:00:00Z" {
+		t.Errorf("unexpected effective price %+v", price)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/promotions/1", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.DeletePromotion(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status code %v, got %v", http.StatusNoContent, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/promotions/1", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetPromotion(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestPriceScheduleEndpoints(t *testing.T) {
+	controller, albumRepo := setupPromotionController(t)
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 200}
+	if err := albumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest("POST", "/albums/1/price-schedules", bytes.NewBufferString(`{"price": 250, "starts_at": "2024-01-01T00:00:00Z"}`)), map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+	controller.SchedulePrice(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("POST", "/albums/99/price-schedules", bytes.NewBufferString(`{"price": 250, "starts_at": "2024-01-01T00:00:00Z"}`)), map[string]string{"id": "99"})
+	rr = httptest.NewRecorder()
+	controller.SchedulePrice(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/price-schedules", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetPriceSchedules(rr, req)
+	var schedules []models.PriceSchedule
+	if err := json.NewDecoder(rr.Body).Decode(&schedules); err != nil {
+		t.Fatalf("failed to decode schedules: %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].Price != 250 {
+		t.Errorf("unexpected schedules %+v", schedules)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/effective-price?at=2024-01-02T00:00:00Z", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetEffectivePrice(rr, req)
+	var price models.EffectivePrice
+	if err := json.NewDecoder(rr.Body).Decode(&price); err != nil {
+		t.Fatalf("failed to decode price: %v", err)
+	}
+	if price.Price != 250 || price.EffectivePrice != 250 {
+		t.Errorf("expected the scheduled price to apply, got %+v", price)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/effective-price?at=tomorrow", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetEffectivePrice(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/albums/1/price-schedules/1", nil), map[string]string{"id": "1", "schedule_id": "1"})
+	rr = httptest.NewRecorder()
+	controller.CancelPriceSchedule(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/albums/1/price-schedules/1", nil), map[string]string{"id": "1", "schedule_id": "1"})
+	rr = httptest.NewRecorder()
+	controller.CancelPriceSchedule(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
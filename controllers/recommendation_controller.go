@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type RecommendationController struct {
+	Service services.RecommendationServiceInterface
+}
+
+// GetRelatedAlbums handles retrieving the albums listeners of an album also like. Accepts limit and currency.
+func (c *RecommendationController) GetRelatedAlbums(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	limit, err := limitFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	currency, err := currencyFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	related, err := c.Service.GetRelatedAlbums(uint(albumID), limit, currency)
+	if err != nil {
+		http.Error(w, err.Error(), recommendationErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, related)
+}
+
+// GetRelatedMusicians handles retrieving the musicians listeners of a musician also like. Accepts limit.
+func (c *RecommendationController) GetRelatedMusicians(w http.ResponseWriter, r *http.Request) {
+	musicianID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid musician ID", http.StatusBadRequest)
+		return
+	}
+	limit, err := limitFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	related, err := c.Service.GetRelatedMusicians(uint(musicianID), limit)
+	if err != nil {
+		http.Error(w, err.Error(), recommendationErrorStatus(err))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, related)
+}
+
+// limitFromQuery parses the optional limit query parameter; 0 leaves the limit to the service.
+func limitFromQuery(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errors.New("Invalid limit")
+	}
+	return limit, nil
+}
+
+// recommendationErrorStatus maps a missing album or musician to 404 and anything else to 400.
+func recommendationErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
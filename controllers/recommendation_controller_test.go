@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGetRelatedAlbums(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	service := &services.RecommendationService{Repo: &repositories.RecommendationRepository{DB: db}, AlbumRepo: albumRepo,
+		MusicianRepo: &repositories.MusicianRepository{DB: db}, Albums: &services.AlbumService{Repo: albumRepo}}
+	controller := &RecommendationController{Service: service}
+
+	if _, err := db.Exec("INSERT INTO musicians (id, name, musician_type) VALUES (1, 'Ann', 'Singer')"); err != nil {
+		t.Fatalf("failed to create musician: %v", err)
+	}
+	for _, date := range []string{"2020-01-01", "2021-01-01"} {
+		album := &models.Album{Name: "Album " + date, ReleaseDate: date, Price: 10}
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+		if err := albumRepo.LinkMusiciansToAlbum(album.ID, services.PerformerCredits([]uint{1})); err != nil {
+			t.Fatalf("failed to link musician: %v", err)
+		}
+	}
+	if _, err := service.Refresh(time.Now()); err != nil {
+		t.Fatalf("failed to refresh recommendations: %v", err)
+	}
+
+	tests := []struct {
+		id     string
+		query  string
+		status int
+	}{
+		{"1", "", http.StatusOK},
+		{"1", "limit=many", http.StatusBadRequest},
+		{"1", "limit=0", http.StatusOK},
+		{"1", "limit=1000", http.StatusBadRequest},
+		{"99", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/albums/"+tt.id+"/related?"+tt.query, nil), map[string]string{"id": tt.id})
+		rr := httptest.NewRecorder()
+		controller.GetRelatedAlbums(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s?%s: expected status code %v, got %v", tt.id, tt.query, tt.status, rr.Code)
+		}
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/albums/1/related", nil), map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+	controller.GetRelatedAlbums(rr, req)
+	var related []models.RelatedAlbum
+	if err := json.NewDecoder(rr.Body).Decode(&related); err != nil {
+		t.Fatalf("failed to decode related albums: %v", err)
+	}
+	if len(related) != 1 || related[0].ID != 2 || related[0].Score <= 0 {
+		t.Errorf("expected album 2 to be related through its musician, got %+v", related)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/musicians/1/related", nil), map[string]string{"id": "1"})
+	rr = httptest.NewRecorder()
+	controller.GetRelatedMusicians(rr, req)
+	if rr.Code != http.StatusOK || rr.Body.String() != "[]\n" {
+		t.Errorf("expected a musician without peers to have no related musicians, got %v %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ReviewController struct {
+	Service services.ReviewServiceInterface
+	// Customers signs in the authors of reviews and the admins who moderate them.
+	Customers services.CustomerServiceInterface
+}
+
+// GetAlbumReviews handles retrieving a page of an album's approved reviews.
+func (c *ReviewController) GetAlbumReviews(w http.ResponseWriter, r *http.Request) {
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	page, perPage, err := pageFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := c.Service.GetAlbumReviews(uint(albumID), page, perPage)
+	if err != nil {
+		http.Error(w, err.Error(), reviewErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, reviews)
+}
+
+// CreateReview handles the signed-in customer reviewing an album.
+func (c *ReviewController) CreateReview(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+
+	var review models.Review
+	if err := decodeRequest(r, &review); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return
+	}
+	review.AlbumID = uint(albumID)
+	review.CustomerID = customer.ID
+
+	if err := c.Service.CreateReview(&review); err != nil {
+		http.Error(w, err.Error(), reviewErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, review)
+}
+
+// UpdateReview handles the signed-in customer changing their review of an album.
+func (c *ReviewController) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+
+	var review models.Review
+	if err := decodeRequest(r, &review); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	albumID, reviewID, ok := reviewFromPath(w, r)
+	if !ok {
+		return
+	}
+	review.AlbumID = albumID
+	review.ID = reviewID
+	review.CustomerID = customer.ID
+
+	if err := c.Service.UpdateReview(&review); err != nil {
+		http.Error(w, err.Error(), reviewErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, review)
+}
+
+// DeleteReview handles the signed-in customer deleting their review of an album.
+func (c *ReviewController) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	customer, ok := authenticate(w, r, c.Customers)
+	if !ok {
+		return
+	}
+	albumID, reviewID, ok := reviewFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteReview(albumID, reviewID, customer.ID); err != nil {
+		http.Error(w, err.Error(), reviewErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReviews handles an admin retrieving a page of every review, optionally only those with the status query
+// parameter.
+func (c *ReviewController) GetReviews(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+		return
+	}
+	page, perPage, err := pageFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := c.Service.GetReviews(r.URL.Query().Get("status"), page, perPage)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, reviews)
+}
+
+// ModerateReview handles an admin approving or rejecting a review.
+func (c *ReviewController) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	if !acceptable(w, r) {
+		return
+	}
+	if _, ok := authorizeAdmin(w, r, c.Customers); !ok {
+		return
+	}
+
+	var moderation models.ReviewModeration
+	if err := decodeRequest(r, &moderation); err != nil {
+		http.Error(w, err.Error(), decodeStatus(err))
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return
+	}
+
+	review, err := c.Service.ModerateReview(uint(reviewID), moderation.Status)
+	if err != nil {
+		http.Error(w, err.Error(), reviewErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, review)
+}
+
+// reviewFromPath parses the album and review IDs of a review's URL, answering 400 Bad Request when either is
+// invalid.
+func reviewFromPath(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	vars := mux.Vars(r)
+	albumID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	reviewID, err := strconv.ParseUint(vars["review_id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return uint(albumID), uint(reviewID), true
+}
+
+// pageFromQuery parses the page and per_page query parameters. page defaults to 1 and per_page to 0, which
+// leaves the page size to the service.
+func pageFromQuery(r *http.Request) (int, int, error) {
+	page, perPage := 1, 0
+	query := r.URL.Query()
+	if raw := query.Get("page"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, 0, errors.New("Invalid page")
+		}
+		page = parsed
+	}
+	if raw := query.Get("per_page"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, 0, errors.New("Invalid per_page")
+		}
+		perPage = parsed
+	}
+	return page, perPage, nil
+}
+
+// reviewErrorStatus maps a missing album or review to 404 and a second review of an album to 409.
+func reviewErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrAlreadyReviewed):
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestReviewLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	customers := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: db}, AlbumRepo: albumRepo, Orders: &repositories.OrderRepository{DB: db}}
+	controller := &ReviewController{Service: &services.ReviewService{Repo: &repositories.ReviewRepository{DB: db}, AlbumRepo: albumRepo}, Customers: customers}
+
+	if err := albumRepo.CreateAlbum(&models.Album{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200}); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	if _, err := customers.Register(models.Registration{Email: "ann@example.com", Name: "Ann", Password: "correct horse"}); err != nil {
+		t.Fatalf("failed to register customer: %v", err)
+	}
+	session, err := customers.Login(models.Credentials{Email: "ann@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	albumReviews := func(method, body string, signedIn bool) *http.Request {
+		req := mux.SetURLVars(httptest.NewRequest(method, "/albums/1/reviews", bytes.NewBufferString(body)), map[string]string{"id": "1"})
+		if signedIn {
+			req.Header.Set("Authorization", "Bearer "+session.Token)
+		}
+		return req
+	}
+
+	rr := httptest.NewRecorder()
+	controller.CreateReview(rr, albumReviews("POST", `{"rating": 4, "text": "Lovely"}`, false))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %v, got %v", http.StatusUnauthorized, rr.Code)
+	}
+
+	var review models.Review
+	for _, status := range []int{http.StatusCreated, http.StatusConflict} {
+		rr = httptest.NewRecorder()
+		controller.CreateReview(rr, albumReviews("POST", `{"rating": 4, "text": "Lovely"}`, true))
+		if rr.Code != status {
+			t.Fatalf("expected status code %v, got %v: %s", status, rr.Code, rr.Body.String())
+		}
+		if status == http.StatusCreated {
+			if err := json.NewDecoder(rr.Body).Decode(&review); err != nil {
+				t.Fatalf("failed to decode review: %v", err)
+			}
+		}
+	}
+	if review.Author != "Ann" || review.Status != models.ReviewPending {
+		t.Errorf("unexpected review %+v", review)
+	}
+
+	admin := signIn(t, db, customers, "staff@example.com", true)
+	for token, status := range map[string]int{"": http.StatusUnauthorized, session.Token: http.StatusForbidden, admin: http.StatusOK} {
+		rr = httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest("PUT", "/reviews/1/status", bytes.NewBufferString(`{"status": "approved"}`)), map[string]string{"id": "1"})
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		controller.ModerateReview(rr, req)
+		if rr.Code != status {
+			t.Fatalf("expected status code %v, got %v: %s", status, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	req := albumReviews("GET", "", false)
+	req.URL.RawQuery = "page=1&per_page=10"
+	controller.GetAlbumReviews(rr, req)
+	var page models.ReviewPage
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode reviews: %v", err)
+	}
+	if page.Total != 1 || page.PerPage != 10 || len(page.Reviews) != 1 {
+		t.Errorf("unexpected review page %+v", page)
+	}
+
+	for _, query := range []string{"page=first", "page=0", "per_page=1000"} {
+		rr = httptest.NewRecorder()
+		req = albumReviews("GET", "", false)
+		req.URL.RawQuery = query
+		controller.GetAlbumReviews(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %v for %q, got %v", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	controller.GetReviews(rr, albumReviews("GET", "", true))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected status code %v, got %v", http.StatusForbidden, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/reviews?status=pending", nil)
+	req.Header.Set("Authorization", "Bearer "+admin)
+	controller.GetReviews(rr, req)
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode moderation queue: %v", err)
+	}
+	if page.Total != 0 {
+		t.Errorf("expected an empty moderation queue, got %+v", page)
+	}
+
+	for _, status := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req := mux.SetURLVars(httptest.NewRequest("DELETE", "/albums/1/reviews/1", nil), map[string]string{"id": "1", "review_id": "1"})
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+		rr = httptest.NewRecorder()
+		controller.DeleteReview(rr, req)
+		if rr.Code != status {
+			t.Errorf("expected status code %v, got %v", status, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+-- Domain events written in the same transaction as the change that produced them and delivered
+-- by the outbox dispatcher. Databases created from schema.sql since the outbox was added already
+-- have the table.
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id INTEGER NOT NULL,
+  event_type TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  dispatched_at TEXT,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (dispatched_at, id);
@@ -0,0 +1,15 @@
+-- Distinguish groups from individual musicians and record band membership over time.
+ALTER TABLE musicians ADD COLUMN kind TEXT NOT NULL DEFAULT 'person' CHECK (kind IN ('person', 'group'));
+
+CREATE TABLE group_memberships (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  group_id INTEGER NOT NULL,
+  member_id INTEGER NOT NULL,
+  instrument TEXT NOT NULL DEFAULT '',
+  joined_on DATE NOT NULL,
+  left_on DATE,
+  FOREIGN KEY (group_id) REFERENCES musicians(id),
+  FOREIGN KEY (member_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_group_memberships_group ON group_memberships (group_id, joined_on);
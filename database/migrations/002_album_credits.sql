@@ -0,0 +1,24 @@
+-- Credit musicians on albums with a role, an optional instrument and an optional track.
+-- SQLite cannot change a primary key in place, so the table is rebuilt and every
+-- existing link becomes a whole-album performer credit.
+BEGIN TRANSACTION;
+
+CREATE TABLE album_musicians_new (
+  album_id INTEGER,
+  musician_id INTEGER,
+  role TEXT NOT NULL DEFAULT 'performer' CHECK (role IN ('performer', 'producer', 'composer', 'engineer', 'featured')),
+  instrument TEXT NOT NULL DEFAULT '',
+  -- 0 credits the whole album
+  track INTEGER NOT NULL DEFAULT 0,
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (musician_id) REFERENCES musicians(id),
+  PRIMARY KEY (album_id, musician_id, role, instrument, track)
+);
+
+INSERT INTO album_musicians_new (album_id, musician_id)
+SELECT album_id, musician_id FROM album_musicians;
+
+DROP TABLE album_musicians;
+ALTER TABLE album_musicians_new RENAME TO album_musicians;
+
+COMMIT;
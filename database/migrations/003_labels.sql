@@ -0,0 +1,15 @@
+-- Record labels, with imprints pointing at their parent label, and the label and
+-- catalog number of each album.
+CREATE TABLE labels (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  country TEXT NOT NULL DEFAULT '',
+  parent_id INTEGER,
+  FOREIGN KEY (parent_id) REFERENCES labels(id)
+);
+
+ALTER TABLE albums ADD COLUMN label_id INTEGER REFERENCES labels(id);
+ALTER TABLE albums ADD COLUMN catalog_number TEXT;
+
+-- A catalog number identifies one release within its label
+CREATE UNIQUE INDEX idx_albums_label_catalog_number ON albums (label_id, catalog_number);
@@ -0,0 +1,73 @@
+-- Hierarchical genres with aliases, and albums tagged with any number of them.
+-- Existing albums.genre text is matched against names and aliases and tagged by the
+-- application on startup (GenreService.BackfillAlbumGenres), since matching ignores
+-- case and punctuation in ways SQLite cannot express.
+CREATE TABLE genres (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  -- Name normalised by models.GenreKey; unique together with genre_aliases.key
+  key TEXT NOT NULL UNIQUE,
+  parent_id INTEGER,
+  FOREIGN KEY (parent_id) REFERENCES genres(id)
+);
+
+CREATE TABLE genre_aliases (
+  key TEXT PRIMARY KEY,
+  alias TEXT NOT NULL,
+  genre_id INTEGER NOT NULL,
+  FOREIGN KEY (genre_id) REFERENCES genres(id)
+);
+
+CREATE TABLE album_genres (
+  album_id INTEGER NOT NULL,
+  genre_id INTEGER NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (genre_id) REFERENCES genres(id),
+  PRIMARY KEY (album_id, genre_id)
+);
+
+CREATE INDEX idx_genres_parent ON genres (parent_id);
+
+-- Starter taxonomy
+INSERT INTO genres (id, name, key, parent_id) VALUES
+  (1, 'Rock', 'rock', NULL),
+  (2, 'Rock & Roll', 'rock and roll', 1),
+  (3, 'Hard Rock', 'hard rock', 1),
+  (4, 'Metal', 'metal', 1),
+  (5, 'Punk', 'punk', 1),
+  (6, 'Alternative', 'alternative', 1),
+  (7, 'Indie', 'indie', 6),
+  (8, 'Pop', 'pop', NULL),
+  (9, 'Synth-pop', 'synth pop', 8),
+  (10, 'Jazz', 'jazz', NULL),
+  (11, 'Bebop', 'bebop', 10),
+  (12, 'Fusion', 'fusion', 10),
+  (13, 'Blues', 'blues', NULL),
+  (14, 'R&B', 'r and b', NULL),
+  (15, 'Soul', 'soul', 14),
+  (16, 'Funk', 'funk', 14),
+  (17, 'Hip Hop', 'hip hop', NULL),
+  (18, 'Electronic', 'electronic', NULL),
+  (19, 'House', 'house', 18),
+  (20, 'Techno', 'techno', 18),
+  (21, 'Classical', 'classical', NULL),
+  (22, 'Country', 'country', NULL),
+  (23, 'Folk', 'folk', NULL),
+  (24, 'Reggae', 'reggae', NULL);
+
+INSERT INTO genre_aliases (key, alias, genre_id) VALUES
+  ('rock n roll', 'Rock n Roll', 2),
+  ('heavy metal', 'Heavy Metal', 4),
+  ('punk rock', 'Punk Rock', 5),
+  ('alternative rock', 'Alternative Rock', 6),
+  ('alt rock', 'Alt-Rock', 6),
+  ('indie rock', 'Indie Rock', 7),
+  ('synthpop', 'Synthpop', 9),
+  ('jazz fusion', 'Jazz Fusion', 12),
+  ('rnb', 'RnB', 14),
+  ('rhythm and blues', 'Rhythm and Blues', 14),
+  ('hiphop', 'Hiphop', 17),
+  ('rap', 'Rap', 17),
+  ('electronica', 'Electronica', 18),
+  ('edm', 'EDM', 18),
+  ('country and western', 'Country & Western', 22);
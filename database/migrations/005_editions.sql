@@ -0,0 +1,15 @@
+-- Editions of an album in different formats, each with its own barcode, release date
+-- and price. albums.price and albums.release_date are kept at the lowest price and
+-- earliest release date of an album's editions.
+CREATE TABLE editions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  format TEXT NOT NULL CHECK (format IN ('vinyl', 'cd', 'cassette', 'digital')),
+  name TEXT NOT NULL DEFAULT '',
+  barcode TEXT UNIQUE,
+  release_date DATE NOT NULL,
+  price REAL NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_editions_album ON editions (album_id, release_date);
@@ -0,0 +1,25 @@
+-- Prices as integer minor units with an ISO 4217 currency, exchange rates against the
+-- base currency and per-currency album price lists. Existing prices are taken to be USD.
+ALTER TABLE albums ADD COLUMN price_minor INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE albums ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+UPDATE albums SET price_minor = CAST(ROUND(price * 100) AS INTEGER);
+ALTER TABLE albums DROP COLUMN price;
+
+ALTER TABLE editions ADD COLUMN price_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE editions SET price_minor = CAST(ROUND(price * 100) AS INTEGER);
+ALTER TABLE editions DROP COLUMN price;
+
+-- Units of each currency one unit of the base currency (USD) buys
+CREATE TABLE exchange_rates (
+  currency TEXT PRIMARY KEY,
+  rate REAL NOT NULL CHECK (rate > 0)
+);
+
+-- Explicit album prices in other currencies, used instead of converting the album's own price
+CREATE TABLE album_prices (
+  album_id INTEGER NOT NULL,
+  currency TEXT NOT NULL,
+  price_minor INTEGER NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  PRIMARY KEY (album_id, currency)
+);
@@ -0,0 +1,37 @@
+-- Time-boxed discounts on an album or on every album of a genre, label or musician,
+-- and scheduled changes of an album's own price. Times are UTC in RFC 3339 format
+-- (2024-11-29T00:00:00Z) so they compare as text.
+CREATE TABLE promotions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+  percent_off REAL,
+  -- In minor units of currency
+  amount_off_minor INTEGER,
+  currency TEXT,
+  album_id INTEGER,
+  genre_id INTEGER,
+  label_id INTEGER,
+  musician_id INTEGER,
+  starts_at TEXT NOT NULL,
+  ends_at TEXT NOT NULL CHECK (ends_at > starts_at),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (genre_id) REFERENCES genres(id),
+  FOREIGN KEY (label_id) REFERENCES labels(id),
+  FOREIGN KEY (musician_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_promotions_period ON promotions (starts_at, ends_at);
+
+CREATE TABLE price_schedules (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  -- In minor units of the album's currency
+  price_minor INTEGER NOT NULL,
+  starts_at TEXT NOT NULL,
+  -- Set once the album's price has been changed
+  applied_at TEXT,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_price_schedules_album ON price_schedules (album_id, starts_at);
@@ -0,0 +1,36 @@
+-- Stock of physical copies per album, or per edition for albums with editions, and the
+-- append-only ledger of movements that changed it.
+CREATE TABLE stock_levels (
+  album_id INTEGER NOT NULL,
+  -- 0 for albums stocked as a whole
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+  low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+  PRIMARY KEY (album_id, edition_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE stock_movements (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  kind TEXT NOT NULL CHECK (kind IN ('receive', 'sell', 'adjust', 'return')),
+  -- Signed change of the stock level
+  quantity_change INTEGER NOT NULL,
+  balance INTEGER NOT NULL CHECK (balance >= 0),
+  note TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_stock_movements_item ON stock_movements (album_id, edition_id, id);
+
+CREATE TRIGGER stock_movements_no_update BEFORE UPDATE ON stock_movements
+BEGIN
+  SELECT RAISE(ABORT, 'stock movements are append-only');
+END;
+
+CREATE TRIGGER stock_movements_no_delete BEFORE DELETE ON stock_movements
+BEGIN
+  SELECT RAISE(ABORT, 'stock movements are append-only');
+END;
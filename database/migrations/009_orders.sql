@@ -0,0 +1,60 @@
+-- Shopping carts, the orders they are checked out into with prices fixed at checkout, and
+-- the sales tax rate charged per country. Amounts are in minor units of the order's currency.
+CREATE TABLE carts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  currency TEXT NOT NULL DEFAULT 'USD',
+  created_at TEXT NOT NULL,
+  -- Set once the cart has become an order; it can no longer change
+  checked_out_at TEXT
+);
+
+CREATE TABLE cart_items (
+  cart_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  -- 0 for albums sold as a whole
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (cart_id, album_id, edition_id),
+  FOREIGN KEY (cart_id) REFERENCES carts(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  cart_id INTEGER NOT NULL UNIQUE,
+  status TEXT NOT NULL CHECK (status IN ('pending', 'paid', 'shipped', 'cancelled')),
+  country TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  subtotal_minor INTEGER NOT NULL,
+  tax_rate REAL NOT NULL,
+  tax_minor INTEGER NOT NULL,
+  total_minor INTEGER NOT NULL,
+  payment_reference TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  FOREIGN KEY (cart_id) REFERENCES carts(id)
+);
+
+CREATE INDEX idx_orders_status ON orders (status, id);
+
+CREATE TABLE order_lines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  order_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  name TEXT NOT NULL,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  unit_price_minor INTEGER NOT NULL,
+  total_minor INTEGER NOT NULL,
+  -- Whether the copies were taken from tracked stock
+  stock_tracked INTEGER NOT NULL DEFAULT 0,
+  FOREIGN KEY (order_id) REFERENCES orders(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_order_lines_order ON order_lines (order_id, id);
+
+CREATE TABLE tax_rates (
+  country TEXT PRIMARY KEY,
+  rate REAL NOT NULL CHECK (rate >= 0 AND rate < 1)
+);
@@ -0,0 +1,40 @@
+-- Customer accounts, their login sessions and the library of albums each customer owns.
+-- Carts and orders record the customer who placed them; anonymous carts have none.
+CREATE TABLE customers (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  -- Lower-cased, so an address registers once whatever its case
+  email TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  -- bcrypt hash; the password itself is never stored
+  password_hash TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE TABLE sessions (
+  -- SHA-256 of the bearer token, so a leaked table cannot be replayed
+  token_hash TEXT PRIMARY KEY,
+  customer_id INTEGER NOT NULL,
+  created_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX idx_sessions_expires_at ON sessions (expires_at);
+
+CREATE TABLE library_entries (
+  customer_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  source TEXT NOT NULL CHECK (source IN ('order', 'manual')),
+  -- The paid order the album came from, for source 'order'
+  order_id INTEGER,
+  added_at TEXT NOT NULL,
+  PRIMARY KEY (customer_id, album_id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+ALTER TABLE carts ADD COLUMN customer_id INTEGER REFERENCES customers(id);
+ALTER TABLE orders ADD COLUMN customer_id INTEGER REFERENCES customers(id);
+
+CREATE INDEX idx_orders_customer ON orders (customer_id, id);
@@ -0,0 +1,19 @@
+-- Customer ratings and reviews of albums. Reviews wait for moderation before they are
+-- shown or counted towards an album's rating.
+CREATE TABLE reviews (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  customer_id INTEGER NOT NULL,
+  rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  -- One review per customer per album
+  UNIQUE (album_id, customer_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX idx_reviews_album_status ON reviews (album_id, status, created_at);
+CREATE INDEX idx_reviews_status ON reviews (status, created_at);
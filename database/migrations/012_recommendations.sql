@@ -0,0 +1,27 @@
+-- Precomputed "listeners also like" scores, rebuilt in the background from shared musicians,
+-- genres, release eras and co-purchases or co-ratings. Only each subject's best matches are kept.
+CREATE TABLE album_recommendations (
+  album_id INTEGER NOT NULL,
+  related_album_id INTEGER NOT NULL,
+  score REAL NOT NULL,
+  -- Comma-separated signals the albums share, e.g. 'musicians,genre'
+  reasons TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  PRIMARY KEY (album_id, related_album_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (related_album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE musician_recommendations (
+  musician_id INTEGER NOT NULL,
+  related_musician_id INTEGER NOT NULL,
+  score REAL NOT NULL,
+  reasons TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  PRIMARY KEY (musician_id, related_musician_id),
+  FOREIGN KEY (musician_id) REFERENCES musicians(id),
+  FOREIGN KEY (related_musician_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_album_recommendations_score ON album_recommendations (album_id, score DESC);
+CREATE INDEX idx_musician_recommendations_score ON musician_recommendations (musician_id, score DESC);
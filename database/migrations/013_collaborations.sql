@@ -0,0 +1,3 @@
+-- Musicians collaborate when they are credited on the same album. The collaboration graph is
+-- walked from musicians to their albums and back, which needs album_musicians indexed by musician.
+CREATE INDEX idx_album_musicians_musician ON album_musicians (musician_id, album_id);
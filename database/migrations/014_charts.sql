@@ -0,0 +1,39 @@
+-- Weekly charts. Counters bucket plays, purchased copies and approved ratings by ISO week
+-- (e.g. '2026-W41'); charts rank albums on them and are stored once their week is over.
+CREATE TABLE chart_counters (
+  period TEXT NOT NULL,
+  metric TEXT NOT NULL CHECK (metric IN ('plays', 'purchases', 'ratings')),
+  album_id INTEGER NOT NULL,
+  -- Plays, copies or ratings counted
+  count INTEGER NOT NULL DEFAULT 0,
+  -- Sum of the stars of the ratings; 0 for the other metrics
+  total INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (period, metric, album_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE charts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  chart_type TEXT NOT NULL,
+  -- Genre key the chart is limited to; '' for the overall chart
+  genre TEXT NOT NULL DEFAULT '',
+  period TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  UNIQUE (chart_type, genre, period)
+);
+
+CREATE TABLE chart_entries (
+  chart_id INTEGER NOT NULL,
+  position INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  value REAL NOT NULL,
+  count INTEGER NOT NULL,
+  previous_position INTEGER,
+  movement TEXT NOT NULL CHECK (movement IN ('new', 'up', 'down', 'same')),
+  PRIMARY KEY (chart_id, position),
+  FOREIGN KEY (chart_id) REFERENCES charts(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+-- Purchases are counted by checkout time
+CREATE INDEX idx_orders_created_at ON orders (created_at);
@@ -0,0 +1,19 @@
+-- Play history. Clients send each play with their own identifier so that retried
+-- submissions are stored once.
+CREATE TABLE plays (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  customer_id INTEGER NOT NULL,
+  client_id TEXT NOT NULL,
+  album_id INTEGER NOT NULL,
+  -- Track number played; 0 for the whole album
+  track INTEGER NOT NULL DEFAULT 0,
+  duration_seconds INTEGER NOT NULL DEFAULT 0,
+  played_at TEXT NOT NULL,
+  received_at TEXT NOT NULL,
+  UNIQUE (customer_id, client_id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_plays_customer ON plays (customer_id, played_at);
+CREATE INDEX idx_plays_played_at ON plays (played_at);
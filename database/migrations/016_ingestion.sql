@@ -0,0 +1,32 @@
+-- Tracks of albums, filled in from the tags of ingested audio files, and the conflicts
+-- ingestion finds between the tags and the catalog, kept for review.
+CREATE TABLE tracks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  disc INTEGER NOT NULL DEFAULT 1,
+  number INTEGER NOT NULL,
+  title TEXT NOT NULL,
+  duration_seconds INTEGER NOT NULL DEFAULT 0,
+  UNIQUE (album_id, disc, number),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE ingest_conflicts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  file_name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('album', 'musician', 'track')),
+  -- The catalog row the file disagrees with; 0 when several rows match it
+  entity_id INTEGER NOT NULL DEFAULT 0,
+  field TEXT NOT NULL,
+  existing TEXT NOT NULL,
+  incoming TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+  created_at TEXT NOT NULL,
+  reviewed_at TEXT
+);
+
+CREATE INDEX idx_ingest_conflicts_status ON ingest_conflicts (status, id);
+
+-- Ingestion matches albums and musicians by name, ignoring case
+CREATE INDEX idx_albums_name ON albums (name COLLATE NOCASE);
+CREATE INDEX idx_musicians_name ON musicians (name COLLATE NOCASE);
@@ -0,0 +1,20 @@
+-- Audio files found by the library scanner, so later scans only re-read files whose size or
+-- modification time changed, and only re-ingest files whose content hash changed.
+CREATE TABLE library_files (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  path TEXT NOT NULL UNIQUE,
+  size INTEGER NOT NULL,
+  modified_ns INTEGER NOT NULL,
+  -- SHA-256 of the file's content, hex encoded
+  hash TEXT NOT NULL,
+  -- Outcome of ingesting the file: created, matched, conflict or failed
+  status TEXT NOT NULL CHECK (status IN ('created', 'matched', 'conflict', 'failed')),
+  album_id INTEGER NOT NULL DEFAULT 0,
+  track_id INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  scanned_at TEXT NOT NULL,
+  -- Set while the file is no longer found under its root
+  missing_since TEXT
+);
+
+CREATE INDEX idx_library_files_hash ON library_files (hash);
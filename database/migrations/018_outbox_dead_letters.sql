@@ -0,0 +1,2 @@
+-- Park outbox events that keep failing, so one bad event cannot be retried forever or stall delivery.
+ALTER TABLE outbox_events ADD COLUMN dead_at TEXT;
@@ -0,0 +1,3 @@
+-- Let staff accounts list every order and ship or cancel them. Accounts are promoted by hand, e.g.
+-- UPDATE customers SET is_admin = 1 WHERE email = 'staff@example.com';
+ALTER TABLE customers ADD COLUMN is_admin INTEGER NOT NULL DEFAULT 0;
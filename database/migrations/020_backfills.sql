@@ -0,0 +1,9 @@
+-- Record the one-off data backfills run at startup, so each runs once and later edits are left alone.
+CREATE TABLE backfills (
+  name TEXT PRIMARY KEY,
+  completed_at TEXT NOT NULL
+);
+
+-- Databases whose albums are already tagged have had their genres backfilled
+INSERT INTO backfills (name, completed_at)
+SELECT 'album_genres', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE EXISTS (SELECT 1 FROM album_genres);
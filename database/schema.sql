@@ -1,7 +1,16 @@
 CREATE TABLE musicians (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
   name TEXT NOT NULL,
-  musician_type TEXT NOT NULL
+  musician_type TEXT NOT NULL,
+  kind TEXT NOT NULL DEFAULT 'person' CHECK (kind IN ('person', 'group'))
+);
+
+CREATE TABLE labels (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  country TEXT NOT NULL DEFAULT '',
+  parent_id INTEGER,
+  FOREIGN KEY (parent_id) REFERENCES labels(id)
 );
 
 CREATE TABLE albums (
@@ -9,14 +18,495 @@ CREATE TABLE albums (
   name TEXT NOT NULL,
   release_date DATE NOT NULL,
   genre TEXT,
-  price REAL NOT NULL,
-  description TEXT
+  -- In minor units of currency, e.g. cents
+  price_minor INTEGER NOT NULL,
+  -- ISO 4217 code
+  currency TEXT NOT NULL DEFAULT 'USD',
+  description TEXT,
+  label_id INTEGER,
+  catalog_number TEXT,
+  FOREIGN KEY (label_id) REFERENCES labels(id)
 );
 
+-- A catalog number identifies one release within its label
+CREATE UNIQUE INDEX idx_albums_label_catalog_number ON albums (label_id, catalog_number);
+
 CREATE TABLE album_musicians (
   album_id INTEGER,
   musician_id INTEGER,
+  role TEXT NOT NULL DEFAULT 'performer' CHECK (role IN ('performer', 'producer', 'composer', 'engineer', 'featured')),
+  instrument TEXT NOT NULL DEFAULT '',
+  -- 0 credits the whole album
+  track INTEGER NOT NULL DEFAULT 0,
   FOREIGN KEY (album_id) REFERENCES albums(id),
   FOREIGN KEY (musician_id) REFERENCES musicians(id),
-  PRIMARY KEY (album_id, musician_id)
+  PRIMARY KEY (album_id, musician_id, role, instrument, track)
+);
+
+-- Walks the collaboration graph from musicians to their albums
+CREATE INDEX idx_album_musicians_musician ON album_musicians (musician_id, album_id);
+
+CREATE TABLE outbox_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id INTEGER NOT NULL,
+  event_type TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  dispatched_at TEXT,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  -- Set when the event failed too often and was parked instead of retried
+  dead_at TEXT
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events (dispatched_at, id);
+
+CREATE TABLE group_memberships (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  group_id INTEGER NOT NULL,
+  member_id INTEGER NOT NULL,
+  instrument TEXT NOT NULL DEFAULT '',
+  joined_on DATE NOT NULL,
+  left_on DATE,
+  FOREIGN KEY (group_id) REFERENCES musicians(id),
+  FOREIGN KEY (member_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_group_memberships_group ON group_memberships (group_id, joined_on);
+
+CREATE TABLE genres (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  -- Name normalised by models.GenreKey; unique together with genre_aliases.key
+  key TEXT NOT NULL UNIQUE,
+  parent_id INTEGER,
+  FOREIGN KEY (parent_id) REFERENCES genres(id)
+);
+
+CREATE TABLE genre_aliases (
+  key TEXT PRIMARY KEY,
+  alias TEXT NOT NULL,
+  genre_id INTEGER NOT NULL,
+  FOREIGN KEY (genre_id) REFERENCES genres(id)
+);
+
+CREATE TABLE album_genres (
+  album_id INTEGER NOT NULL,
+  genre_id INTEGER NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (genre_id) REFERENCES genres(id),
+  PRIMARY KEY (album_id, genre_id)
+);
+
+CREATE INDEX idx_genres_parent ON genres (parent_id);
+
+-- Starter taxonomy
+INSERT INTO genres (id, name, key, parent_id) VALUES
+  (1, 'Rock', 'rock', NULL),
+  (2, 'Rock & Roll', 'rock and roll', 1),
+  (3, 'Hard Rock', 'hard rock', 1),
+  (4, 'Metal', 'metal', 1),
+  (5, 'Punk', 'punk', 1),
+  (6, 'Alternative', 'alternative', 1),
+  (7, 'Indie', 'indie', 6),
+  (8, 'Pop', 'pop', NULL),
+  (9, 'Synth-pop', 'synth pop', 8),
+  (10, 'Jazz', 'jazz', NULL),
+  (11, 'Bebop', 'bebop', 10),
+  (12, 'Fusion', 'fusion', 10),
+  (13, 'Blues', 'blues', NULL),
+  (14, 'R&B', 'r and b', NULL),
+  (15, 'Soul', 'soul', 14),
+  (16, 'Funk', 'funk', 14),
+  (17, 'Hip Hop', 'hip hop', NULL),
+  (18, 'Electronic', 'electronic', NULL),
+  (19, 'House', 'house', 18),
+  (20, 'Techno', 'techno', 18),
+  (21, 'Classical', 'classical', NULL),
+  (22, 'Country', 'country', NULL),
+  (23, 'Folk', 'folk', NULL),
+  (24, 'Reggae', 'reggae', NULL);
+
+INSERT INTO genre_aliases (key, alias, genre_id) VALUES
+  ('rock n roll', 'Rock n Roll', 2),
+  ('heavy metal', 'Heavy Metal', 4),
+  ('punk rock', 'Punk Rock', 5),
+  ('alternative rock', 'Alternative Rock', 6),
+  ('alt rock', 'Alt-Rock', 6),
+  ('indie rock', 'Indie Rock', 7),
+  ('synthpop', 'Synthpop', 9),
+  ('jazz fusion', 'Jazz Fusion', 12),
+  ('rnb', 'RnB', 14),
+  ('rhythm and blues', 'Rhythm and Blues', 14),
+  ('hiphop', 'Hiphop', 17),
+  ('rap', 'Rap', 17),
+  ('electronica', 'Electronica', 18),
+  ('edm', 'EDM', 18),
+  ('country and western', 'Country & Western', 22);
+
+CREATE TABLE editions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  format TEXT NOT NULL CHECK (format IN ('vinyl', 'cd', 'cassette', 'digital')),
+  name TEXT NOT NULL DEFAULT '',
+  barcode TEXT UNIQUE,
+  release_date DATE NOT NULL,
+  -- In minor units of the album's currency
+  price_minor INTEGER NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_editions_album ON editions (album_id, release_date);
+
+-- Units of each currency one unit of the base currency (USD) buys
+CREATE TABLE exchange_rates (
+  currency TEXT PRIMARY KEY,
+  rate REAL NOT NULL CHECK (rate > 0)
+);
+
+-- Explicit album prices in other currencies, used instead of converting the album's own price
+CREATE TABLE album_prices (
+  album_id INTEGER NOT NULL,
+  currency TEXT NOT NULL,
+  price_minor INTEGER NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  PRIMARY KEY (album_id, currency)
+);
+
+-- Time-boxed discounts on an album or on every album of a genre, label or musician.
+-- Times are UTC in RFC 3339 format so they compare as text.
+CREATE TABLE promotions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+  percent_off REAL,
+  -- In minor units of currency
+  amount_off_minor INTEGER,
+  currency TEXT,
+  album_id INTEGER,
+  genre_id INTEGER,
+  label_id INTEGER,
+  musician_id INTEGER,
+  starts_at TEXT NOT NULL,
+  ends_at TEXT NOT NULL CHECK (ends_at > starts_at),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (genre_id) REFERENCES genres(id),
+  FOREIGN KEY (label_id) REFERENCES labels(id),
+  FOREIGN KEY (musician_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_promotions_period ON promotions (starts_at, ends_at);
+
+-- Scheduled changes of an album's own price, applied by the price scheduler
+CREATE TABLE price_schedules (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  -- In minor units of the album's currency
+  price_minor INTEGER NOT NULL,
+  starts_at TEXT NOT NULL,
+  -- Set once the album's price has been changed
+  applied_at TEXT,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_price_schedules_album ON price_schedules (album_id, starts_at);
+
+-- Stock per album, or per edition, and the append-only ledger of stock movements
+CREATE TABLE stock_levels (
+  album_id INTEGER NOT NULL,
+  -- 0 for albums stocked as a whole
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+  low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+  PRIMARY KEY (album_id, edition_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE stock_movements (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  kind TEXT NOT NULL CHECK (kind IN ('receive', 'sell', 'adjust', 'return')),
+  -- Signed change of the stock level
+  quantity_change INTEGER NOT NULL,
+  balance INTEGER NOT NULL CHECK (balance >= 0),
+  note TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL,
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_stock_movements_item ON stock_movements (album_id, edition_id, id);
+
+CREATE TRIGGER stock_movements_no_update BEFORE UPDATE ON stock_movements
+BEGIN
+  SELECT RAISE(ABORT, 'stock movements are append-only');
+END;
+
+CREATE TRIGGER stock_movements_no_delete BEFORE DELETE ON stock_movements
+BEGIN
+  SELECT RAISE(ABORT, 'stock movements are append-only');
+END;
+
+-- Customer accounts and their login sessions
+CREATE TABLE customers (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  -- Lower-cased, so an address registers once whatever its case
+  email TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  -- bcrypt hash; the password itself is never stored
+  password_hash TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  -- Staff accounts, which may list every order and ship or cancel them
+  is_admin INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE sessions (
+  -- SHA-256 of the bearer token, so a leaked table cannot be replayed
+  token_hash TEXT PRIMARY KEY,
+  customer_id INTEGER NOT NULL,
+  created_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX idx_sessions_expires_at ON sessions (expires_at);
+
+-- Shopping carts, orders with prices fixed at checkout, and sales tax rates per country
+CREATE TABLE carts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  currency TEXT NOT NULL DEFAULT 'USD',
+  created_at TEXT NOT NULL,
+  -- Set once the cart has become an order; it can no longer change
+  checked_out_at TEXT,
+  -- The signed-in customer who created the cart, if any
+  customer_id INTEGER,
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE TABLE cart_items (
+  cart_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  -- 0 for albums sold as a whole
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (cart_id, album_id, edition_id),
+  FOREIGN KEY (cart_id) REFERENCES carts(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  cart_id INTEGER NOT NULL UNIQUE,
+  status TEXT NOT NULL CHECK (status IN ('pending', 'paid', 'shipped', 'cancelled')),
+  country TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  subtotal_minor INTEGER NOT NULL,
+  tax_rate REAL NOT NULL,
+  tax_minor INTEGER NOT NULL,
+  total_minor INTEGER NOT NULL,
+  payment_reference TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  customer_id INTEGER,
+  FOREIGN KEY (cart_id) REFERENCES carts(id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX idx_orders_status ON orders (status, id);
+CREATE INDEX idx_orders_customer ON orders (customer_id, id);
+CREATE INDEX idx_orders_created_at ON orders (created_at);
+
+CREATE TABLE order_lines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  order_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  edition_id INTEGER NOT NULL DEFAULT 0,
+  name TEXT NOT NULL,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  unit_price_minor INTEGER NOT NULL,
+  total_minor INTEGER NOT NULL,
+  -- Whether the copies were taken from tracked stock
+  stock_tracked INTEGER NOT NULL DEFAULT 0,
+  FOREIGN KEY (order_id) REFERENCES orders(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_order_lines_order ON order_lines (order_id, id);
+
+CREATE TABLE tax_rates (
+  country TEXT PRIMARY KEY,
+  rate REAL NOT NULL CHECK (rate >= 0 AND rate < 1)
+);
+
+-- The albums each customer owns, from paid orders or added by hand
+CREATE TABLE library_entries (
+  customer_id INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  source TEXT NOT NULL CHECK (source IN ('order', 'manual')),
+  -- The paid order the album came from, for source 'order'
+  order_id INTEGER,
+  added_at TEXT NOT NULL,
+  PRIMARY KEY (customer_id, album_id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+-- Ratings and reviews of albums, shown once approved by a moderator
+CREATE TABLE reviews (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  customer_id INTEGER NOT NULL,
+  rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  -- One review per customer per album
+  UNIQUE (album_id, customer_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX idx_reviews_album_status ON reviews (album_id, status, created_at);
+CREATE INDEX idx_reviews_status ON reviews (status, created_at);
+
+-- Precomputed "listeners also like" scores, rebuilt in the background
+CREATE TABLE album_recommendations (
+  album_id INTEGER NOT NULL,
+  related_album_id INTEGER NOT NULL,
+  score REAL NOT NULL,
+  -- Comma-separated signals the albums share, e.g. 'musicians,genre'
+  reasons TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  PRIMARY KEY (album_id, related_album_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id),
+  FOREIGN KEY (related_album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE musician_recommendations (
+  musician_id INTEGER NOT NULL,
+  related_musician_id INTEGER NOT NULL,
+  score REAL NOT NULL,
+  reasons TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  PRIMARY KEY (musician_id, related_musician_id),
+  FOREIGN KEY (musician_id) REFERENCES musicians(id),
+  FOREIGN KEY (related_musician_id) REFERENCES musicians(id)
+);
+
+CREATE INDEX idx_album_recommendations_score ON album_recommendations (album_id, score DESC);
+CREATE INDEX idx_musician_recommendations_score ON musician_recommendations (musician_id, score DESC);
+
+-- Weekly chart counters by ISO week, and the charts ranked from them
+CREATE TABLE chart_counters (
+  period TEXT NOT NULL,
+  metric TEXT NOT NULL CHECK (metric IN ('plays', 'purchases', 'ratings')),
+  album_id INTEGER NOT NULL,
+  -- Plays, copies or ratings counted
+  count INTEGER NOT NULL DEFAULT 0,
+  -- Sum of the stars of the ratings; 0 for the other metrics
+  total INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (period, metric, album_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE charts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  chart_type TEXT NOT NULL,
+  -- Genre key the chart is limited to; '' for the overall chart
+  genre TEXT NOT NULL DEFAULT '',
+  period TEXT NOT NULL,
+  computed_at TEXT NOT NULL,
+  UNIQUE (chart_type, genre, period)
+);
+
+CREATE TABLE chart_entries (
+  chart_id INTEGER NOT NULL,
+  position INTEGER NOT NULL,
+  album_id INTEGER NOT NULL,
+  value REAL NOT NULL,
+  count INTEGER NOT NULL,
+  previous_position INTEGER,
+  movement TEXT NOT NULL CHECK (movement IN ('new', 'up', 'down', 'same')),
+  PRIMARY KEY (chart_id, position),
+  FOREIGN KEY (chart_id) REFERENCES charts(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE plays (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  customer_id INTEGER NOT NULL,
+  client_id TEXT NOT NULL,
+  album_id INTEGER NOT NULL,
+  -- Track number played; 0 for the whole album
+  track INTEGER NOT NULL DEFAULT 0,
+  duration_seconds INTEGER NOT NULL DEFAULT 0,
+  played_at TEXT NOT NULL,
+  received_at TEXT NOT NULL,
+  UNIQUE (customer_id, client_id),
+  FOREIGN KEY (customer_id) REFERENCES customers(id),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE INDEX idx_plays_customer ON plays (customer_id, played_at);
+CREATE INDEX idx_plays_played_at ON plays (played_at);
+
+CREATE TABLE tracks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  album_id INTEGER NOT NULL,
+  disc INTEGER NOT NULL DEFAULT 1,
+  number INTEGER NOT NULL,
+  title TEXT NOT NULL,
+  duration_seconds INTEGER NOT NULL DEFAULT 0,
+  UNIQUE (album_id, disc, number),
+  FOREIGN KEY (album_id) REFERENCES albums(id)
+);
+
+CREATE TABLE ingest_conflicts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  file_name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('album', 'musician', 'track')),
+  -- The catalog row the file disagrees with; 0 when several rows match it
+  entity_id INTEGER NOT NULL DEFAULT 0,
+  field TEXT NOT NULL,
+  existing TEXT NOT NULL,
+  incoming TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+  created_at TEXT NOT NULL,
+  reviewed_at TEXT
+);
+
+CREATE INDEX idx_ingest_conflicts_status ON ingest_conflicts (status, id);
+
+-- Ingestion matches albums and musicians by name, ignoring case
+CREATE INDEX idx_albums_name ON albums (name COLLATE NOCASE);
+CREATE INDEX idx_musicians_name ON musicians (name COLLATE NOCASE);
+
+-- Audio files found by the library scanner, so later scans only re-read files whose size or
+-- modification time changed, and only re-ingest files whose content hash changed.
+CREATE TABLE library_files (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  path TEXT NOT NULL UNIQUE,
+  size INTEGER NOT NULL,
+  modified_ns INTEGER NOT NULL,
+  -- SHA-256 of the file's content, hex encoded
+  hash TEXT NOT NULL,
+  -- Outcome of ingesting the file: created, matched, conflict or failed
+  status TEXT NOT NULL CHECK (status IN ('created', 'matched', 'conflict', 'failed')),
+  album_id INTEGER NOT NULL DEFAULT 0,
+  track_id INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  scanned_at TEXT NOT NULL,
+  -- Set while the file is no longer found under its root
+  missing_since TEXT
+);
+
+CREATE INDEX idx_library_files_hash ON library_files (hash);
+
+-- One-off data backfills run at startup, so each runs once and later edits are left alone.
+CREATE TABLE backfills (
+  name TEXT PRIMARY KEY,
+  completed_at TEXT NOT NULL
 );
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openSchema creates an in-memory database from SQL scripts applied in order.
+func openSchema(t *testing.T, scripts ...string) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	for _, script := range scripts {
+		data, err := os.ReadFile(script)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", script, err)
+		}
+		if _, err := db.Exec(string(data)); err != nil {
+			t.Fatalf("failed to apply %s: %v", script, err)
+		}
+	}
+	return db
+}
+
+// describeSchema lists every table's columns and every index's columns, sorted so that columns added by
+// ALTER TABLE compare equal to the same columns declared in CREATE TABLE.
+func describeSchema(t *testing.T, db *sql.DB) map[string][]string {
+	rows, err := db.Query("SELECT type, name, tbl_name FROM sqlite_master WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		t.Fatalf("failed to list schema: %v", err)
+	}
+	type object struct{ kind, name, table string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.kind, &o.name, &o.table); err != nil {
+			t.Fatalf("failed to scan schema: %v", err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+
+	schema := make(map[string][]string)
+	for _, o := range objects {
+		var described []string
+		if o.kind == "table" {
+			described = query(t, db, fmt.Sprintf("SELECT name || ' ' || type || ' notnull=' || \"notnull\" || ' default=' || COALESCE(dflt_value, '') || ' pk=' || pk FROM pragma_table_info('%s')", o.name))
+			sort.Strings(described)
+		} else {
+			described = append([]string{"on " + o.table}, query(t, db, fmt.Sprintf("SELECT COALESCE(name, 'expression') FROM pragma_index_info('%s') ORDER BY seqno", o.name))...)
+		}
+		schema[o.kind+" "+o.name] = described
+	}
+	return schema
+}
+
+func query(t *testing.T, db *sql.DB, q string) []string {
+	rows, err := db.Query(q)
+	if err != nil {
+		t.Fatalf("failed to query %q: %v", q, err)
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// addedColumnDefaults are NOT NULL columns a migration added with ALTER TABLE, which SQLite only allows
+// with a default that schema.sql does not need.
+var addedColumnDefaults = map[string]string{
+	"table albums":   "price_minor INTEGER notnull=1 default=0 pk=0",
+	"table editions": "price_minor INTEGER notnull=1 default=0 pk=0",
+}
+
+func TestMigrationsMatchSchema(t *testing.T) {
+	migrations, err := filepath.Glob(filepath.Join("migrations", "*.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	sort.Strings(migrations)
+
+	migrated := describeSchema(t, openSchema(t, append([]string{filepath.Join("testdata", "baseline_schema.sql")}, migrations...)...))
+	fresh := describeSchema(t, openSchema(t, "schema.sql"))
+	for name, column := range addedColumnDefaults {
+		for i, described := range migrated[name] {
+			if described == column {
+				migrated[name][i] = strings.Replace(column, "default=0", "default=", 1)
+			}
+		}
+	}
+
+	for name, want := range fresh {
+		if got, ok := migrated[name]; !ok {
+			t.Errorf("%s is in schema.sql but no migration creates it", name)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s differs after migrating:\n got  %v\n want %v", name, got, want)
+		}
+	}
+	for name := range migrated {
+		if _, ok := fresh[name]; !ok {
+			t.Errorf("%s is created by a migration but missing from schema.sql", name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"jukebox/models"
+	"jukebox/services"
+	"sync"
+)
+
+// albumBatch holds every album resolved at one level of a query. The first
+// album to ask for its musicians loads them for all siblings in a single query,
+// so a nested query reads album_musicians once per level instead of once per album.
+type albumBatch struct {
+	ids       []uint
+	albums    services.AlbumServiceInterface
+	musicians services.MusicianServiceInterface
+
+	once   sync.Once
+	result map[uint][]models.Musician
+	next   *musicianBatch
+	err    error
+}
+
+// newAlbumResolvers wraps albums resolved at the same level in resolvers sharing one batch.
+func newAlbumResolvers(albums []models.Album, albumService services.AlbumServiceInterface, musicianService services.MusicianServiceInterface) []*albumResolver {
+	batch := &albumBatch{albums: albumService, musicians: musicianService}
+	resolvers := make([]*albumResolver, len(albums))
+	for i, album := range albums {
+		batch.ids = append(batch.ids, album.ID)
+		resolvers[i] = &albumResolver{album: album, batch: batch}
+	}
+	return resolvers
+}
+
+// musiciansOf returns the musicians of one album together with the batch they belong to.
+func (b *albumBatch) musiciansOf(albumID uint) ([]models.Musician, *musicianBatch, error) {
+	b.once.Do(func() {
+		b.result, b.err = b.musicians.GetMusiciansByAlbums(b.ids)
+		if b.err != nil {
+			return
+		}
+
+		// The musicians of every album in this level form the next level
+		seen := make(map[uint]bool)
+		b.next = &musicianBatch{albums: b.albums, musicians: b.musicians}
+		for _, id := range b.ids {
+			for _, musician := range b.result[id] {
+				if !seen[musician.ID] {
+					seen[musician.ID] = true
+					b.next.ids = append(b.next.ids, musician.ID)
+				}
+			}
+		}
+	})
+	return b.result[albumID], b.next, b.err
+}
+
+// musicianBatch is the musician counterpart of albumBatch.
+type musicianBatch struct {
+	ids       []uint
+	albums    services.AlbumServiceInterface
+	musicians services.MusicianServiceInterface
+
+	once   sync.Once
+	result map[uint][]models.Album
+	next   *albumBatch
+	err    error
+}
+
+// newMusicianResolvers wraps musicians resolved at the same level in resolvers sharing one batch.
+func newMusicianResolvers(musicians []models.Musician, albumService services.AlbumServiceInterface, musicianService services.MusicianServiceInterface) []*musicianResolver {
+	batch := &musicianBatch{albums: albumService, musicians: musicianService}
+	resolvers := make([]*musicianResolver, len(musicians))
+	for i, musician := range musicians {
+		batch.ids = append(batch.ids, musician.ID)
+		resolvers[i] = &musicianResolver{musician: musician, batch: batch}
+	}
+	return resolvers
+}
+
+// albumsOf returns the albums of one musician together with the batch they belong to.
+func (b *musicianBatch) albumsOf(musicianID uint) ([]models.Album, *albumBatch, error) {
+	b.once.Do(func() {
+		b.result, b.err = b.albums.GetAlbumsByMusicians(b.ids)
+		if b.err != nil {
+			return
+		}
+
+		seen := make(map[uint]bool)
+		b.next = &albumBatch{albums: b.albums, musicians: b.musicians}
+		for _, id := range b.ids {
+			for _, album := range b.result[id] {
+				if !seen[album.ID] {
+					seen[album.ID] = true
+					b.next.ids = append(b.next.ids, album.ID)
+				}
+			}
+		}
+	})
+	return b.result[musicianID], b.next, b.err
+}
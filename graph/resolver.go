@@ -0,0 +1,396 @@
+package graph
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/services"
+	"net/http"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// Resolver is the root resolver for queries and mutations. It is backed by the
+// same service interfaces as the REST controllers.
+type Resolver struct {
+	AlbumService    services.AlbumServiceInterface
+	MusicianService services.MusicianServiceInterface
+}
+
+// NewHandler parses the schema and returns the handler for POST /graphql.
+func NewHandler(albumService services.AlbumServiceInterface, musicianService services.MusicianServiceInterface) http.Handler {
+	resolver := &Resolver{AlbumService: albumService, MusicianService: musicianService}
+	schema := graphql.MustParseSchema(Schema, resolver)
+	return &relay.Handler{Schema: schema}
+}
+
+// Albums resolves the albums query.
+func (r *Resolver) Albums(args struct {
+	Genre      *string
+	MusicianID *graphql.ID
+	Currency   *string
+}) ([]*albumResolver, error) {
+	filter := models.AlbumFilter{Genre: stringValue(args.Genre), Currency: stringValue(args.Currency)}
+	if args.MusicianID != nil {
+		musicianID, err := parseID(*args.MusicianID)
+		if err != nil {
+			return nil, err
+		}
+		filter.MusicianID = musicianID
+	}
+
+	albums, err := r.AlbumService.GetAlbums(filter)
+	if err != nil {
+		return nil, err
+	}
+	return newAlbumResolvers(albums, r.AlbumService, r.MusicianService), nil
+}
+
+// Musicians resolves the musicians query.
+func (r *Resolver) Musicians() ([]*musicianResolver, error) {
+	musicians, err := r.MusicianService.GetMusicians()
+	if err != nil {
+		return nil, err
+	}
+	return newMusicianResolvers(musicians, r.AlbumService, r.MusicianService), nil
+}
+
+type albumInput struct {
+	Name          string
+	ReleaseDate   string
+	Genre         *string
+	Price         float64
+	Currency      *string
+	Description   *string
+	LabelID       *graphql.ID
+	CatalogNumber *string
+	MusicianIDs   *[]graphql.ID
+	Credits       *[]creditInput
+}
+
+type creditInput struct {
+	MusicianID graphql.ID
+	Role       *string
+	Instrument *string
+	Track      *int32
+}
+
+type albumUpdate struct {
+	Name          string
+	ReleaseDate   string
+	Genre         *string
+	Price         float64
+	Currency      *string
+	Description   *string
+	LabelID       *graphql.ID
+	CatalogNumber *string
+}
+
+type musicianInput struct {
+	Name         string
+	MusicianType string
+	Kind         *string
+}
+
+// CreateAlbum validates and creates an album, then links it to the given musicians.
+func (r *Resolver) CreateAlbum(args struct{ Input albumInput }) (*albumResolver, error) {
+	album := models.Album{
+		Name:        args.Input.Name,
+		ReleaseDate: args.Input.ReleaseDate,
+		Genre:       stringValue(args.Input.Genre),
+		Price:       args.Input.Price,
+		Currency:    stringValue(args.Input.Currency),
+		Description: stringValue(args.Input.Description),
+	}
+	if err := setLabel(&album, args.Input.LabelID, args.Input.CatalogNumber); err != nil {
+		return nil, err
+	}
+	credits, err := parseCredits(args.Input.MusicianIDs, args.Input.Credits)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.AlbumService.CreateAlbum(&album); err != nil {
+		return nil, err
+	}
+
+	if len(credits) > 0 {
+		if err := r.AlbumService.LinkMusiciansToAlbum(album.ID, credits); err != nil {
+			return nil, err
+		}
+	}
+
+	return newAlbumResolvers([]models.Album{album}, r.AlbumService, r.MusicianService)[0], nil
+}
+
+// UpdateAlbum updates an existing album.
+func (r *Resolver) UpdateAlbum(args struct {
+	ID    graphql.ID
+	Input albumUpdate
+}) (*albumResolver, error) {
+	albumID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	album := models.Album{
+		ID:          albumID,
+		Name:        args.Input.Name,
+		ReleaseDate: args.Input.ReleaseDate,
+		Genre:       stringValue(args.Input.Genre),
+		Price:       args.Input.Price,
+		Currency:    stringValue(args.Input.Currency),
+		Description: stringValue(args.Input.Description),
+	}
+	if err := setLabel(&album, args.Input.LabelID, args.Input.CatalogNumber); err != nil {
+		return nil, err
+	}
+	if err := r.AlbumService.UpdateAlbum(&album); err != nil {
+		return nil, err
+	}
+
+	return newAlbumResolvers([]models.Album{album}, r.AlbumService, r.MusicianService)[0], nil
+}
+
+// DeleteAlbum deletes an album by ID.
+func (r *Resolver) DeleteAlbum(args struct{ ID graphql.ID }) (bool, error) {
+	albumID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.AlbumService.DeleteAlbum(albumID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateMusician validates and creates a musician.
+func (r *Resolver) CreateMusician(args struct{ Input musicianInput }) (*musicianResolver, error) {
+	musician := models.Musician{Name: args.Input.Name, MusicianType: args.Input.MusicianType, Kind: stringValue(args.Input.Kind)}
+	if err := r.MusicianService.CreateMusician(&musician); err != nil {
+		return nil, err
+	}
+
+	return newMusicianResolvers([]models.Musician{musician}, r.AlbumService, r.MusicianService)[0], nil
+}
+
+// UpdateMusician updates an existing musician.
+func (r *Resolver) UpdateMusician(args struct {
+	ID    graphql.ID
+	Input musicianInput
+}) (*musicianResolver, error) {
+	musicianID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	musician := models.Musician{ID: musicianID, Name: args.Input.Name, MusicianType: args.Input.MusicianType, Kind: stringValue(args.Input.Kind)}
+	if err := r.MusicianService.UpdateMusician(&musician); err != nil {
+		return nil, err
+	}
+
+	return newMusicianResolvers([]models.Musician{musician}, r.AlbumService, r.MusicianService)[0], nil
+}
+
+// DeleteMusician deletes a musician by ID.
+func (r *Resolver) DeleteMusician(args struct{ ID graphql.ID }) (bool, error) {
+	musicianID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.MusicianService.DeleteMusician(musicianID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LinkMusicians credits musicians on an existing album.
+func (r *Resolver) LinkMusicians(args struct {
+	AlbumID     graphql.ID
+	MusicianIDs *[]graphql.ID
+	Credits     *[]creditInput
+}) (bool, error) {
+	albumID, err := parseID(args.AlbumID)
+	if err != nil {
+		return false, err
+	}
+	credits, err := parseCredits(args.MusicianIDs, args.Credits)
+	if err != nil {
+		return false, err
+	}
+	if err := r.AlbumService.LinkMusiciansToAlbum(albumID, credits); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type albumResolver struct {
+	album models.Album
+	batch *albumBatch
+}
+
+func (a *albumResolver) ID() graphql.ID {
+	return formatID(a.album.ID)
+}
+
+func (a *albumResolver) Name() string {
+	return a.album.Name
+}
+
+func (a *albumResolver) ReleaseDate() string {
+	return a.album.ReleaseDate
+}
+
+func (a *albumResolver) Genre() string {
+	return a.album.Genre
+}
+
+func (a *albumResolver) Price() float64 {
+	return a.album.Price
+}
+
+func (a *albumResolver) Currency() string {
+	return a.album.Currency
+}
+
+func (a *albumResolver) Description() string {
+	return a.album.Description
+}
+
+func (a *albumResolver) LabelID() *graphql.ID {
+	if a.album.LabelID == 0 {
+		return nil
+	}
+	id := formatID(a.album.LabelID)
+	return &id
+}
+
+func (a *albumResolver) CatalogNumber() *string {
+	if a.album.CatalogNumber == "" {
+		return nil
+	}
+	return &a.album.CatalogNumber
+}
+
+func (a *albumResolver) Musicians() ([]*musicianResolver, error) {
+	musicians, next, err := a.batch.musiciansOf(a.album.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*musicianResolver, len(musicians))
+	for i, musician := range musicians {
+		resolvers[i] = &musicianResolver{musician: musician, batch: next}
+	}
+	return resolvers, nil
+}
+
+type musicianResolver struct {
+	musician models.Musician
+	batch    *musicianBatch
+}
+
+func (m *musicianResolver) ID() graphql.ID {
+	return formatID(m.musician.ID)
+}
+
+func (m *musicianResolver) Name() string {
+	return m.musician.Name
+}
+
+func (m *musicianResolver) MusicianType() string {
+	return m.musician.MusicianType
+}
+
+func (m *musicianResolver) Kind() string {
+	return m.musician.Kind
+}
+
+func (m *musicianResolver) Albums() ([]*albumResolver, error) {
+	albums, next, err := m.batch.albumsOf(m.musician.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*albumResolver, len(albums))
+	for i, album := range albums {
+		resolvers[i] = &albumResolver{album: album, batch: next}
+	}
+	return resolvers, nil
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	value, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid ID " + string(id))
+	}
+	return uint(value), nil
+}
+
+func parseIDs(ids []graphql.ID) ([]uint, error) {
+	values := make([]uint, len(ids))
+	for i, id := range ids {
+		value, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// setLabel copies the optional label reference and catalog number of an album input.
+func setLabel(album *models.Album, labelID *graphql.ID, catalogNumber *string) error {
+	if labelID != nil {
+		id, err := parseID(*labelID)
+		if err != nil {
+			return err
+		}
+		album.LabelID = id
+	}
+	album.CatalogNumber = stringValue(catalogNumber)
+	return nil
+}
+
+// parseCredits combines performer musician IDs and explicit credits into validated credits.
+func parseCredits(musicianIDs *[]graphql.ID, inputs *[]creditInput) ([]models.Credit, error) {
+	var credits []models.Credit
+	if musicianIDs != nil {
+		ids, err := parseIDs(*musicianIDs)
+		if err != nil {
+			return nil, err
+		}
+		credits = services.PerformerCredits(ids)
+	}
+	if inputs != nil {
+		for _, input := range *inputs {
+			musicianID, err := parseID(input.MusicianID)
+			if err != nil {
+				return nil, err
+			}
+			credit := models.Credit{MusicianID: musicianID, Role: stringValue(input.Role), Instrument: stringValue(input.Instrument)}
+			if input.Track != nil {
+				credit.Track = int(*input.Track)
+			}
+			credits = append(credits, credit)
+		}
+	}
+
+	for i := range credits {
+		if err := services.ValidateCredit(&credits[i]); err != nil {
+			return nil, err
+		}
+	}
+	return credits, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
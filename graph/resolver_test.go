@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Helper function to set up the test database and tables
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// Resolvers run concurrently; keep every query on the same in-memory database
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		CREATE TABLE album_musicians (
+			album_id INTEGER,
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE exchange_rates (
+			currency TEXT PRIMARY KEY,
+			rate REAL NOT NULL CHECK (rate > 0)
+		);
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return db
+}
+
+// countingMusicianService counts batched musician lookups.
+type countingMusicianService struct {
+	services.MusicianServiceInterface
+	batches int32
+}
+
+func (s *countingMusicianService) GetMusiciansByAlbums(albumIDs []uint) (map[uint][]models.Musician, error) {
+	atomic.AddInt32(&s.batches, 1)
+	return s.MusicianServiceInterface.GetMusiciansByAlbums(albumIDs)
+}
+
+// countingAlbumService counts batched album lookups.
+type countingAlbumService struct {
+	services.AlbumServiceInterface
+	batches int32
+}
+
+func (s *countingAlbumService) GetAlbumsByMusicians(musicianIDs []uint) (map[uint][]models.Album, error) {
+	atomic.AddInt32(&s.batches, 1)
+	return s.AlbumServiceInterface.GetAlbumsByMusicians(musicianIDs)
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, handler http.Handler, query string, variables map[string]interface{}) graphQLResponse {
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	return response
+}
+
+func TestNestedQueryBatchesLinkLookups(t *testing.T) {
+	db := setupTestDB(t)
+	albumService := &countingAlbumService{AlbumServiceInterface: &services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}}}
+	musicianService := &countingMusicianService{MusicianServiceInterface: &services.MusicianService{Repo: &repositories.MusicianRepository{DB: db}}}
+	handler := NewHandler(albumService, musicianService)
+
+	_, err := db.Exec(`
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'First Album', '2020-01-01', 'Rock', 20000, ''),
+		(2, 'Second Album', '2021-01-01', 'Pop', 30000, ''),
+		(3, 'Third Album', '2022-01-01', 'Jazz', 40000, '');
+		INSERT INTO musicians (id, name, musician_type) VALUES
+		(1, 'Alice', 'Vocalist'),
+		(2, 'Bob', 'Drummer');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES
+		(1, 1), (1, 2), (2, 1), (3, 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	response := execute(t, handler, `{ albums { name musicians { name albums { name } } } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", response.Errors)
+	}
+
+	var data struct {
+		Albums []struct {
+			Name      string
+			Musicians []struct {
+				Name   string
+				Albums []struct{ Name string }
+			}
+		}
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+
+	if len(data.Albums) != 3 {
+		t.Fatalf("expected 3 albums, got %d", len(data.Albums))
+	}
+	first := data.Albums[0]
+	if first.Name != "First Album" || len(first.Musicians) != 2 || first.Musicians[0].Name != "Alice" {
+		t.Errorf("expected First Album with Alice and Bob, got %+v", first)
+	}
+	if len(first.Musicians[0].Albums) != 2 {
+		t.Errorf("expected Alice to have 2 albums, got %d", len(first.Musicians[0].Albums))
+	}
+
+	if musicianService.batches != 1 {
+		t.Errorf("expected 1 musician lookup for the second level, got %d", musicianService.batches)
+	}
+	if albumService.batches != 1 {
+		t.Errorf("expected 1 album lookup for the third level, got %d", albumService.batches)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	db := setupTestDB(t)
+	albumService := &services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}}
+	musicianService := &services.MusicianService{Repo: &repositories.MusicianRepository{DB: db}}
+	handler := NewHandler(albumService, musicianService)
+
+	response := execute(t, handler, `mutation { createMusician(input: {name: "Alice", musicianType: "Vocalist"}) { id } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", response.Errors)
+	}
+
+	response = execute(t, handler, `mutation($input: AlbumInput!) { createAlbum(input: $input) { id name musicians { name } } }`,
+		map[string]interface{}{"input": map[string]interface{}{
+			"name": "Test Album", "releaseDate": "2022-01-01", "price": 150, "musicianIds": []string{"1"},
+		}})
+	if len(response.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", response.Errors)
+	}
+
+	var created struct {
+		CreateAlbum struct {
+			ID        string
+			Name      string
+			Musicians []struct{ Name string }
+		}
+	}
+	if err := json.Unmarshal(response.Data, &created); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+	if created.CreateAlbum.ID != "1" || len(created.CreateAlbum.Musicians) != 1 {
+		t.Errorf("expected album 1 linked to Alice, got %+v", created.CreateAlbum)
+	}
+
+	// Service validation errors are reported as GraphQL errors
+	response = execute(t, handler, `mutation { createAlbum(input: {name: "Tiny", releaseDate: "2022-01-01", price: 150}) { id } }`, nil)
+	if len(response.Errors) != 1 || response.Errors[0].Message != "album name must be at least 5 characters long" {
+		t.Errorf("expected album name validation error, got %+v", response.Errors)
+	}
+
+	response = execute(t, handler, `mutation { deleteAlbum(id: "1") }`, nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", response.Errors)
+	}
+
+	albums, err := albumService.GetAlbums(models.AlbumFilter{})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if len(albums) != 0 {
+		t.Errorf("expected 0 albums after delete, got %d", len(albums))
+	}
+}
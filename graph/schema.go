@@ -0,0 +1,98 @@
+package graph
+
+// Schema is the GraphQL schema served at POST /graphql.
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	# Albums sorted by release date, oldest first. genre also matches albums tagged with any
+	# descendant genre and may be a genre name or alias. currency reports prices in that
+	# ISO 4217 currency.
+	albums(genre: String, musicianId: ID, currency: String): [Album!]!
+	# All musicians.
+	musicians: [Musician!]!
+}
+
+type Mutation {
+	createAlbum(input: AlbumInput!): Album!
+	updateAlbum(id: ID!, input: AlbumUpdate!): Album!
+	deleteAlbum(id: ID!): Boolean!
+	createMusician(input: MusicianInput!): Musician!
+	updateMusician(id: ID!, input: MusicianInput!): Musician!
+	deleteMusician(id: ID!): Boolean!
+	# Musicians in musicianIds are credited as performers on the whole album.
+	linkMusicians(albumId: ID!, musicianIds: [ID!], credits: [CreditInput!]): Boolean!
+}
+
+type Album {
+	id: ID!
+	name: String!
+	releaseDate: String!
+	genre: String!
+	price: Float!
+	# ISO 4217 code the price is in.
+	currency: String!
+	description: String!
+	# Null for albums without a label.
+	labelId: ID
+	catalogNumber: String
+	# Musicians on the album sorted by name.
+	musicians: [Musician!]!
+}
+
+type Musician {
+	id: ID!
+	name: String!
+	musicianType: String!
+	# Either "person" or "group".
+	kind: String!
+	# Albums of the musician sorted by price, lowest first.
+	albums: [Album!]!
+}
+
+input AlbumInput {
+	name: String!
+	releaseDate: String!
+	genre: String
+	price: Float!
+	# ISO 4217 code; defaults to USD.
+	currency: String
+	description: String
+	labelId: ID
+	# Unique within the label; requires labelId.
+	catalogNumber: String
+	musicianIds: [ID!]
+	credits: [CreditInput!]
+}
+
+input AlbumUpdate {
+	name: String!
+	releaseDate: String!
+	genre: String
+	price: Float!
+	# ISO 4217 code; defaults to USD.
+	currency: String
+	description: String
+	labelId: ID
+	catalogNumber: String
+}
+
+input CreditInput {
+	musicianId: ID!
+	# One of performer, producer, composer, engineer or featured; defaults to performer.
+	role: String
+	instrument: String
+	# Track number the credit is limited to; omit for the whole album.
+	track: Int
+}
+
+input MusicianInput {
+	name: String!
+	musicianType: String!
+	# Defaults to "person" on creation and is left unchanged on update.
+	kind: String
+}
+`
@@ -0,0 +1,298 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"jukebox/catalogpb"
+	"jukebox/models"
+	"jukebox/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CatalogServer implements the Catalog gRPC service on top of the same
+// services as the REST controllers.
+type CatalogServer struct {
+	catalogpb.UnimplementedCatalogServer
+
+	AlbumService    services.AlbumServiceInterface
+	MusicianService services.MusicianServiceInterface
+}
+
+// NewServer returns a gRPC server with the Catalog service registered.
+func NewServer(albumService services.AlbumServiceInterface, musicianService services.MusicianServiceInterface) *grpc.Server {
+	server := grpc.NewServer()
+	catalogpb.RegisterCatalogServer(server, &CatalogServer{AlbumService: albumService, MusicianService: musicianService})
+	return server
+}
+
+// CreateAlbum validates and creates an album, then links it to the requested musicians.
+func (s *CatalogServer) CreateAlbum(ctx context.Context, req *catalogpb.CreateAlbumRequest) (*catalogpb.Album, error) {
+	credits, err := creditsFromProto(req.GetMusicianIds(), req.GetCredits())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	album := albumFromProto(req.GetAlbum())
+	if err := s.AlbumService.CreateAlbum(&album); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.AlbumService.LinkMusiciansToAlbum(album.ID, credits); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return albumToProto(album), nil
+}
+
+// UpdateAlbum updates an existing album.
+func (s *CatalogServer) UpdateAlbum(ctx context.Context, req *catalogpb.UpdateAlbumRequest) (*catalogpb.Album, error) {
+	album := albumFromProto(req.GetAlbum())
+	if album.ID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "album ID is required")
+	}
+
+	if err := s.AlbumService.UpdateAlbum(&album); err != nil {
+		return nil, statusError(err)
+	}
+
+	return albumToProto(album), nil
+}
+
+// DeleteAlbum deletes an album by ID.
+func (s *CatalogServer) DeleteAlbum(ctx context.Context, req *catalogpb.DeleteAlbumRequest) (*catalogpb.DeleteAlbumResponse, error) {
+	if err := s.AlbumService.DeleteAlbum(uint(req.GetId())); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &catalogpb.DeleteAlbumResponse{}, nil
+}
+
+// ListAlbums streams the albums matching the request's filters sorted by release date.
+func (s *CatalogServer) ListAlbums(req *catalogpb.ListAlbumsRequest, stream grpc.ServerStreamingServer[catalogpb.Album]) error {
+	filter := models.AlbumFilter{MusicianID: uint(req.GetMusicianId()), Genre: req.GetGenre(), Currency: req.GetCurrency()}
+	albums, err := s.AlbumService.GetAlbums(filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return sendAlbums(stream, albums)
+}
+
+// LinkMusiciansToAlbum credits musicians on an existing album.
+func (s *CatalogServer) LinkMusiciansToAlbum(ctx context.Context, req *catalogpb.LinkMusiciansToAlbumRequest) (*catalogpb.LinkMusiciansToAlbumResponse, error) {
+	credits, err := creditsFromProto(req.GetMusicianIds(), req.GetCredits())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.AlbumService.LinkMusiciansToAlbum(uint(req.GetAlbumId()), credits); err != nil {
+		return nil, statusError(err)
+	}
+	return &catalogpb.LinkMusiciansToAlbumResponse{}, nil
+}
+
+// ListAlbumsByMusician streams the albums of a musician sorted by price, with the musician's credits.
+func (s *CatalogServer) ListAlbumsByMusician(req *catalogpb.ListAlbumsByMusicianRequest, stream grpc.ServerStreamingServer[catalogpb.Album]) error {
+	if req.GetRole() != "" {
+		if err := services.ValidateCreditRole(req.GetRole()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	albums, err := s.AlbumService.GetAlbumsByMusician(uint(req.GetMusicianId()), req.GetRole(), req.GetCurrency())
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	for _, album := range albums {
+		message := albumToProto(album.Album)
+		message.Credits = creditsToProto(album.Credits)
+		if err := stream.Send(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateMusician validates and creates a musician.
+func (s *CatalogServer) CreateMusician(ctx context.Context, req *catalogpb.CreateMusicianRequest) (*catalogpb.Musician, error) {
+	musician := musicianFromProto(req.GetMusician())
+	if err := s.MusicianService.CreateMusician(&musician); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return musicianToProto(musician), nil
+}
+
+// UpdateMusician updates an existing musician.
+func (s *CatalogServer) UpdateMusician(ctx context.Context, req *catalogpb.UpdateMusicianRequest) (*catalogpb.Musician, error) {
+	musician := musicianFromProto(req.GetMusician())
+	if musician.ID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "musician ID is required")
+	}
+
+	if err := s.MusicianService.UpdateMusician(&musician); err != nil {
+		return nil, statusError(err)
+	}
+	return musicianToProto(musician), nil
+}
+
+// DeleteMusician deletes a musician by ID.
+func (s *CatalogServer) DeleteMusician(ctx context.Context, req *catalogpb.DeleteMusicianRequest) (*catalogpb.DeleteMusicianResponse, error) {
+	if err := s.MusicianService.DeleteMusician(uint(req.GetId())); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &catalogpb.DeleteMusicianResponse{}, nil
+}
+
+// ListMusicians streams every musician.
+func (s *CatalogServer) ListMusicians(req *catalogpb.ListMusiciansRequest, stream grpc.ServerStreamingServer[catalogpb.Musician]) error {
+	musicians, err := s.MusicianService.GetMusicians()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return sendMusicians(stream, musicians)
+}
+
+// ListMusiciansByAlbum streams the musicians on an album sorted by name, with their credits.
+func (s *CatalogServer) ListMusiciansByAlbum(req *catalogpb.ListMusiciansByAlbumRequest, stream grpc.ServerStreamingServer[catalogpb.Musician]) error {
+	if req.GetRole() != "" {
+		if err := services.ValidateCreditRole(req.GetRole()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	musicians, err := s.MusicianService.GetMusiciansByAlbum(uint(req.GetAlbumId()), req.GetRole())
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	for _, musician := range musicians {
+		message := musicianToProto(musician.Musician)
+		message.Credits = creditsToProto(musician.Credits)
+		if err := stream.Send(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusError maps invalid input to InvalidArgument, a missing album or musician to NotFound, a catalog number
+// already in use to AlreadyExists, a locked currency to FailedPrecondition, and anything else to Internal.
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, services.ErrInvalidAlbum), errors.Is(err, services.ErrInvalidMusician), errors.Is(err, services.ErrInvalidCredit):
+		code = codes.InvalidArgument
+	case errors.Is(err, sql.ErrNoRows):
+		code = codes.NotFound
+	case errors.Is(err, services.ErrCatalogNumberTaken):
+		code = codes.AlreadyExists
+	case errors.Is(err, services.ErrCurrencyLocked):
+		code = codes.FailedPrecondition
+	}
+	return status.Error(code, err.Error())
+}
+
+func sendAlbums(stream grpc.ServerStreamingServer[catalogpb.Album], albums []models.Album) error {
+	for _, album := range albums {
+		if err := stream.Send(albumToProto(album)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendMusicians(stream grpc.ServerStreamingServer[catalogpb.Musician], musicians []models.Musician) error {
+	for _, musician := range musicians {
+		if err := stream.Send(musicianToProto(musician)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func albumFromProto(album *catalogpb.Album) models.Album {
+	return models.Album{
+		ID:            uint(album.GetId()),
+		Name:          album.GetName(),
+		ReleaseDate:   album.GetReleaseDate(),
+		Genre:         album.GetGenre(),
+		Price:         album.GetPrice(),
+		Currency:      album.GetCurrency(),
+		Description:   album.GetDescription(),
+		LabelID:       uint(album.GetLabelId()),
+		CatalogNumber: album.GetCatalogNumber(),
+	}
+}
+
+func albumToProto(album models.Album) *catalogpb.Album {
+	return &catalogpb.Album{
+		Id:            uint32(album.ID),
+		Name:          album.Name,
+		ReleaseDate:   album.ReleaseDate,
+		Genre:         album.Genre,
+		Price:         album.Price,
+		Currency:      album.Currency,
+		Description:   album.Description,
+		LabelId:       uint32(album.LabelID),
+		CatalogNumber: album.CatalogNumber,
+	}
+}
+
+func musicianFromProto(musician *catalogpb.Musician) models.Musician {
+	return models.Musician{
+		ID:           uint(musician.GetId()),
+		Name:         musician.GetName(),
+		MusicianType: musician.GetMusicianType(),
+		Kind:         musician.GetKind(),
+	}
+}
+
+func musicianToProto(musician models.Musician) *catalogpb.Musician {
+	return &catalogpb.Musician{
+		Id:           uint32(musician.ID),
+		Name:         musician.Name,
+		MusicianType: musician.MusicianType,
+		Kind:         musician.Kind,
+	}
+}
+
+// creditsFromProto combines performer musician IDs and explicit credits into validated credits.
+func creditsFromProto(musicianIDs []uint32, messages []*catalogpb.Credit) ([]models.Credit, error) {
+	credits := services.PerformerCredits(uintIDs(musicianIDs))
+	for _, message := range messages {
+		credits = append(credits, models.Credit{
+			MusicianID: uint(message.GetMusicianId()),
+			Role:       message.GetRole(),
+			Instrument: message.GetInstrument(),
+			Track:      int(message.GetTrack()),
+		})
+	}
+
+	for i := range credits {
+		if err := services.ValidateCredit(&credits[i]); err != nil {
+			return nil, err
+		}
+	}
+	return credits, nil
+}
+
+func creditsToProto(credits []models.Credit) []*catalogpb.Credit {
+	messages := make([]*catalogpb.Credit, len(credits))
+	for i, credit := range credits {
+		messages[i] = &catalogpb.Credit{
+			MusicianId: uint32(credit.MusicianID),
+			Role:       credit.Role,
+			Instrument: credit.Instrument,
+			Track:      uint32(credit.Track),
+		}
+	}
+	return messages
+}
+
+func uintIDs(ids []uint32) []uint {
+	values := make([]uint, len(ids))
+	for i, id := range ids {
+		values[i] = uint(id)
+	}
+	return values
+}
@@ -0,0 +1,220 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"jukebox/catalogpb"
+	"jukebox/repositories"
+	"jukebox/services"
+	"net"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Helper function to set up the test database and tables
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// Requests are served on separate goroutines; keep them on the same in-memory database
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		CREATE TABLE album_musicians (
+			album_id INTEGER,
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE editions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			format TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			barcode TEXT UNIQUE,
+			release_date TEXT NOT NULL,
+			price_minor INTEGER NOT NULL
+		);
+		CREATE TABLE price_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			price_minor INTEGER NOT NULL,
+			starts_at TEXT NOT NULL,
+			applied_at TEXT
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return db
+}
+
+// setupTestClient serves the Catalog service on a database over an in-memory listener and returns a client for it.
+func setupTestClient(t *testing.T, db *sql.DB) catalogpb.CatalogClient {
+	server := NewServer(&services.AlbumService{Repo: &repositories.AlbumRepository{DB: db}},
+		&services.MusicianService{Repo: &repositories.MusicianRepository{DB: db}})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return catalogpb.NewCatalogClient(conn)
+}
+
+func TestCreateAndStreamAlbums(t *testing.T) {
+	client := setupTestClient(t, setupTestDB(t))
+	ctx := context.Background()
+
+	musician, err := client.CreateMusician(ctx, &catalogpb.CreateMusicianRequest{Musician: &catalogpb.Musician{Name: "John Doe", MusicianType: "Guitarist"}})
+	if err != nil {
+		t.Fatalf("failed to create musician: %v", err)
+	}
+
+	for _, album := range []*catalogpb.Album{
+		{Name: "Second Album", ReleaseDate: "2022-02-01", Genre: "Pop", Price: 300},
+		{Name: "First Album", ReleaseDate: "2022-01-01", Genre: "Rock", Price: 200},
+	} {
+		created, err := client.CreateAlbum(ctx, &catalogpb.CreateAlbumRequest{Album: album, MusicianIds: []uint32{musician.Id}})
+		if err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+		if created.Id == 0 {
+			t.Errorf("expected album to have an ID, got %v", created.Id)
+		}
+	}
+
+	stream, err := client.ListAlbums(ctx, &catalogpb.ListAlbumsRequest{})
+	if err != nil {
+		t.Fatalf("failed to list albums: %v", err)
+	}
+
+	var names []string
+	for {
+		album, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to receive album: %v", err)
+		}
+		names = append(names, album.Name)
+	}
+
+	if len(names) != 2 || names[0] != "First Album" {
+		t.Errorf("expected albums sorted by release date, got %v", names)
+	}
+
+	musicians, err := client.ListMusiciansByAlbum(ctx, &catalogpb.ListMusiciansByAlbumRequest{AlbumId: 1})
+	if err != nil {
+		t.Fatalf("failed to list musicians: %v", err)
+	}
+	linked, err := musicians.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive musician: %v", err)
+	}
+	if linked.Name != "John Doe" {
+		t.Errorf("expected John Doe on album 1, got %s", linked.Name)
+	}
+}
+
+func TestCreateAlbumValidationError(t *testing.T) {
+	client := setupTestClient(t, setupTestDB(t))
+
+	_, err := client.CreateAlbum(context.Background(), &catalogpb.CreateAlbumRequest{Album: &catalogpb.Album{Name: "Tiny", Price: 200}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestUpdateErrorCodes(t *testing.T) {
+	db := setupTestDB(t)
+	client := setupTestClient(t, db)
+	ctx := context.Background()
+
+	album, err := client.CreateAlbum(ctx, &catalogpb.CreateAlbumRequest{Album: &catalogpb.Album{Name: "First Album", ReleaseDate: "2022-01-01", Price: 200}})
+	if err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	musician, err := client.CreateMusician(ctx, &catalogpb.CreateMusicianRequest{Musician: &catalogpb.Musician{Name: "John Doe", MusicianType: "Guitarist"}})
+	if err != nil {
+		t.Fatalf("failed to create musician: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO editions (album_id, format, release_date, price_minor) VALUES (?, 'cd', '2022-01-01', 20000)", album.Id); err != nil {
+		t.Fatalf("failed to insert edition: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{"invalid currency", func() error {
+			_, err := client.UpdateAlbum(ctx, &catalogpb.UpdateAlbumRequest{Album: &catalogpb.Album{Id: album.Id, Name: "First Album", Price: 200, Currency: "XYZ"}})
+			return err
+		}, codes.InvalidArgument},
+		{"missing album", func() error {
+			_, err := client.UpdateAlbum(ctx, &catalogpb.UpdateAlbumRequest{Album: &catalogpb.Album{Id: 99, Name: "First Album", Price: 200}})
+			return err
+		}, codes.NotFound},
+		{"locked currency", func() error {
+			_, err := client.UpdateAlbum(ctx, &catalogpb.UpdateAlbumRequest{Album: &catalogpb.Album{Id: album.Id, Name: "First Album", Price: 200, Currency: "EUR"}})
+			return err
+		}, codes.FailedPrecondition},
+		{"invalid kind", func() error {
+			_, err := client.UpdateMusician(ctx, &catalogpb.UpdateMusicianRequest{Musician: &catalogpb.Musician{Id: musician.Id, Name: "John Doe", Kind: "band"}})
+			return err
+		}, codes.InvalidArgument},
+		{"missing musician", func() error {
+			_, err := client.UpdateMusician(ctx, &catalogpb.UpdateMusicianRequest{Musician: &catalogpb.Musician{Id: 99, Name: "John Doe"}})
+			return err
+		}, codes.NotFound},
+		{"invalid role", func() error {
+			_, err := client.LinkMusiciansToAlbum(ctx, &catalogpb.LinkMusiciansToAlbumRequest{AlbumId: album.Id, Credits: []*catalogpb.Credit{{MusicianId: musician.Id, Role: "drummer"}}})
+			return err
+		}, codes.InvalidArgument},
+		{"link to missing album", func() error {
+			_, err := client.LinkMusiciansToAlbum(ctx, &catalogpb.LinkMusiciansToAlbumRequest{AlbumId: 99, MusicianIds: []uint32{musician.Id}})
+			return err
+		}, codes.NotFound},
+	}
+	for _, tt := range tests {
+		if code := status.Code(tt.call()); code != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, code)
+		}
+	}
+}
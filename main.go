@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"net"
 	"net/http"
+	"time"
 
-	"github.com/gorilla/mux"
 	_ "github.com/mattn/go-sqlite3"
 
 	"jukebox/controllers"
+	"jukebox/grpcapi"
 	"jukebox/repositories"
 	"jukebox/routes"
 	"jukebox/services"
@@ -23,20 +26,136 @@ func main() {
 	defer db.Close()
 
 	// Set up Repositories, Services, and Controllers
-	albumRepo := &repositories.AlbumRepository{DB: db}
-	musicianRepo := &repositories.MusicianRepository{DB: db}
+	outboxRepo := &repositories.OutboxRepository{DB: db}
+	albumRepo := &repositories.AlbumRepository{DB: db, Outbox: outboxRepo}
+	musicianRepo := &repositories.MusicianRepository{DB: db, Outbox: outboxRepo}
+	membershipRepo := &repositories.MembershipRepository{DB: db}
+	labelRepo := &repositories.LabelRepository{DB: db}
+	genreRepo := &repositories.GenreRepository{DB: db}
+	editionRepo := &repositories.EditionRepository{DB: db, Outbox: outboxRepo}
+	priceRepo := &repositories.PriceRepository{DB: db}
+	promotionRepo := &repositories.PromotionRepository{DB: db, Outbox: outboxRepo}
+	inventoryRepo := &repositories.InventoryRepository{DB: db}
+	orderRepo := &repositories.OrderRepository{DB: db}
+	customerRepo := &repositories.CustomerRepository{DB: db}
+	reviewRepo := &repositories.ReviewRepository{DB: db}
+	recommendationRepo := &repositories.RecommendationRepository{DB: db}
+	collaborationRepo := &repositories.CollaborationRepository{DB: db}
+	chartRepo := &repositories.ChartRepository{DB: db}
+	playRepo := &repositories.PlayRepository{DB: db}
+	trackRepo := &repositories.TrackRepository{DB: db}
+	ingestRepo := &repositories.IngestRepository{DB: db}
 
-	albumService := &services.AlbumService{Repo: albumRepo}
+	genreService := &services.GenreService{Repo: genreRepo, AlbumRepo: albumRepo}
+	priceService := &services.PriceService{Repo: priceRepo, AlbumRepo: albumRepo}
+	promotionService := &services.PromotionService{Repo: promotionRepo, AlbumRepo: albumRepo, Prices: priceService, Editions: editionRepo}
+	reviewService := &services.ReviewService{Repo: reviewRepo, AlbumRepo: albumRepo}
+	albumService := &services.AlbumService{Repo: albumRepo, Labels: labelRepo, Genres: genreService, Prices: priceService, Editions: editionRepo,
+		Promotions: promotionService, Reviews: reviewService}
 	musicianService := &services.MusicianService{Repo: musicianRepo}
+	importService := &services.ImportService{AlbumRepo: albumRepo, MusicianRepo: musicianRepo, Genres: genreService}
+	exportService := &services.ExportService{Repo: albumRepo, Prices: priceService}
+	membershipService := &services.MembershipService{Repo: membershipRepo, MusicianRepo: musicianRepo, AlbumRepo: albumRepo}
+	labelService := &services.LabelService{Repo: labelRepo}
+	editionService := &services.EditionService{Repo: editionRepo, AlbumRepo: albumRepo, Prices: priceService}
+	inventoryService := &services.InventoryService{Repo: inventoryRepo, AlbumRepo: albumRepo, Editions: editionRepo}
+	// The fake provider accepts every payment; swap in a real PaymentProvider to take money
+	orderService := &services.OrderService{Repo: orderRepo, AlbumRepo: albumRepo, Editions: editionRepo, Prices: priceService,
+		Promotions: promotionService, Payments: &services.FakePaymentProvider{}}
+	customerService := &services.CustomerService{Repo: customerRepo, AlbumRepo: albumRepo, Orders: orderRepo}
+	recommendationService := &services.RecommendationService{Repo: recommendationRepo, AlbumRepo: albumRepo, MusicianRepo: musicianRepo,
+		Albums: albumService}
+	collaborationService := &services.CollaborationService{Repo: collaborationRepo, MusicianRepo: musicianRepo, AlbumRepo: albumRepo}
+	chartService := &services.ChartService{Repo: chartRepo, Genres: genreRepo}
+	playService := &services.PlayService{Repo: playRepo}
+	ingestService := &services.IngestService{Repo: ingestRepo, Tracks: trackRepo, Albums: albumService, Musicians: musicianService}
+
+	// Tag albums created before the genre taxonomy existed with the genre their genre text names
+	tagged, err := genreService.BackfillAlbumGenres()
+	if err != nil {
+		log.Fatal("Error tagging album genres:", err)
+	}
+	if tagged > 0 {
+		log.Printf("Tagged %d albums with genres", tagged)
+	}
 
 	albumController := &controllers.AlbumController{Service: albumService}
 	musicianController := &controllers.MusicianController{Service: musicianService}
+	importController := &controllers.ImportController{Service: importService}
+	exportController := &controllers.ExportController{Service: exportService}
+	membershipController := &controllers.MembershipController{Service: membershipService}
+	labelController := &controllers.LabelController{Service: labelService}
+	genreController := &controllers.GenreController{Service: genreService}
+	editionController := &controllers.EditionController{Service: editionService}
+	priceController := &controllers.PriceController{Service: priceService, Customers: customerService}
+	promotionController := &controllers.PromotionController{Service: promotionService}
+	inventoryController := &controllers.InventoryController{Service: inventoryService}
+	orderController := &controllers.OrderController{Service: orderService, Customers: customerService}
+	customerController := &controllers.CustomerController{Service: customerService}
+	reviewController := &controllers.ReviewController{Service: reviewService, Customers: customerService}
+	recommendationController := &controllers.RecommendationController{Service: recommendationService}
+	collaborationController := &controllers.CollaborationController{Service: collaborationService}
+	chartController := &controllers.ChartController{Service: chartService}
+	playController := &controllers.PlayController{Service: playService, Customers: customerService}
+	ingestController := &controllers.IngestController{Service: ingestService}
+
+	// Deliver domain events from the outbox in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := &services.OutboxDispatcher{Repo: outboxRepo, Interval: time.Second}
+	// Log every event; subscribe further handlers here to notify other systems
+	dispatcher.Subscribe(services.AllEvents, services.LogEvent)
+	go dispatcher.Run(ctx)
 
-	// Use mux for routing
-	r := mux.NewRouter()
+	// Apply scheduled price changes as they fall due
+	scheduler := &services.PriceScheduler{Service: promotionService, Interval: time.Minute}
+	go scheduler.Run(ctx)
+
+	// Recount the running week's chart purchases and ratings so chart requests only read them
+	chartScheduler := &services.ChartScheduler{Service: chartService, Interval: 5 * time.Minute}
+	go chartScheduler.Run(ctx)
+
+	// Precompute related albums and musicians so requests only read stored scores
+	refresher := &services.RecommendationRefresher{Service: recommendationService, Interval: time.Hour}
+	go refresher.Run(ctx)
+
+	// Serve the gRPC Catalog service on its own port, sharing the service layer
+	listener, err := net.Listen("tcp", ":9090")
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := grpcapi.NewServer(albumService, musicianService)
+	go func() {
+		log.Println("gRPC server starting on port 9090")
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	defer grpcServer.GracefulStop()
 
 	// Set up the routes
-	r = routes.SetupRoutes(albumController, musicianController)
+	r := routes.NewRouter(routes.Controllers{
+		Album:          albumController,
+		Musician:       musicianController,
+		Import:         importController,
+		Export:         exportController,
+		Membership:     membershipController,
+		Label:          labelController,
+		Genre:          genreController,
+		Edition:        editionController,
+		Price:          priceController,
+		Promotion:      promotionController,
+		Inventory:      inventoryController,
+		Order:          orderController,
+		Customer:       customerController,
+		Review:         reviewController,
+		Recommendation: recommendationController,
+		Collaboration:  collaborationController,
+		Chart:          chartController,
+		Play:           playController,
+		Ingest:         ingestController,
+	})
 
 	// Start the server
 	log.Println("Server starting on port 8080")
@@ -1,10 +1,22 @@
 package models
 
 type Album struct {
-    ID          uint   `json:"id"`
-    Name        string `json:"name"`
-    ReleaseDate string `json:"release_date"`
-    Genre       string `json:"genre"`
-    Price       float64 `json:"price"`
-    Description string `json:"description"`
+    ID          uint   `json:"id" xml:"id"`
+    Name        string `json:"name" xml:"name"`
+    ReleaseDate string `json:"release_date" xml:"release_date"`
+    Genre       string `json:"genre" xml:"genre"`
+    Price       float64 `json:"price" xml:"price"`
+    // Currency is the ISO 4217 code Price is in; it defaults to BaseCurrency.
+    Currency    string `json:"currency" xml:"currency"`
+    // EffectivePrice is Price once scheduled changes and the best running promotion are applied.
+    // It is only reported when albums are listed.
+    EffectivePrice float64 `json:"effective_price,omitempty" xml:"effective_price,omitempty"`
+    // AverageRating and ReviewCount summarise the album's approved reviews. They are only reported
+    // when albums are listed, and are absent for albums without approved reviews.
+    AverageRating float64 `json:"average_rating,omitempty" xml:"average_rating,omitempty"`
+    ReviewCount   int     `json:"review_count,omitempty" xml:"review_count,omitempty"`
+    Description string `json:"description" xml:"description"`
+    // LabelID is 0 for albums without a label.
+    LabelID       uint   `json:"label_id,omitempty" xml:"label_id,omitempty"`
+    CatalogNumber string `json:"catalog_number,omitempty" xml:"catalog_number,omitempty"`
 }
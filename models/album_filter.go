@@ -0,0 +1,21 @@
+package models
+
+// AlbumFilter narrows album listings. Zero values mean "no restriction".
+type AlbumFilter struct {
+	// MusicianID restricts the listing to albums the musician is linked to.
+	MusicianID uint
+	// Genre restricts the listing to albums tagged with the genre, one of its aliases or any descendant genre.
+	Genre string
+	// InStock, when set, restricts the listing to albums with (true) or without (false) copies in stock.
+	InStock *bool
+	// CustomerID restricts the listing to albums in the customer's library.
+	CustomerID uint
+	// Currency, when set, reports prices in this ISO 4217 currency instead of each album's own.
+	Currency string
+}
+
+// AlbumWithMusicians is an album together with every musician linked to it.
+type AlbumWithMusicians struct {
+	Album
+	Musicians []Musician `json:"musicians"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// AlbumMusician links a musician to an album.
+type AlbumMusician struct {
+	AlbumID    uint `json:"album_id"`
+	MusicianID uint `json:"musician_id"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "encoding/xml"
+
+// Chart types, which are also the metrics their counters hold.
+const (
+	ChartPlays     = "plays"
+	ChartPurchases = "purchases"
+	ChartRatings   = "ratings"
+)
+
+// ChartTypes lists every valid chart type.
+var ChartTypes = []string{ChartPlays, ChartPurchases, ChartRatings}
+
+// How an album moved since the previous period's chart.
+const (
+	MovementNew  = "new"
+	MovementUp   = "up"
+	MovementDown = "down"
+	MovementSame = "same"
+)
+
+// ChartEntry is an album's place on a chart.
+type ChartEntry struct {
+	Position int   `json:"position" xml:"position"`
+	Album    Album `json:"album" xml:"album"`
+	// Value is what the chart ranks on: plays, copies sold or the average rating.
+	Value float64 `json:"value" xml:"value"`
+	// Count is the plays, copies or ratings the value comes from.
+	Count int `json:"count" xml:"count"`
+	// PreviousPosition is 0 for albums that were not on the previous period's chart.
+	PreviousPosition int    `json:"previous_position,omitempty" xml:"previous_position,omitempty"`
+	Movement         string `json:"movement" xml:"movement"`
+}
+
+// Chart ranks albums on one metric over one ISO week.
+type Chart struct {
+	XMLName xml.Name `json:"-" xml:"chart"`
+	Type    string   `json:"type" xml:"type"`
+	// Genre is empty for the overall chart.
+	Genre  string `json:"genre,omitempty" xml:"genre,omitempty"`
+	Period string `json:"period" xml:"period"`
+	// Final is set once the period is over and the chart is stored; charts of the running week change.
+	Final      bool         `json:"final" xml:"final"`
+	ComputedAt string       `json:"computed_at" xml:"computed_at"`
+	Entries    []ChartEntry `json:"entries" xml:"entries>entry"`
+}
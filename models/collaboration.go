@@ -0,0 +1,51 @@
+package models
+
+import "encoding/xml"
+
+// Collaborator is a musician credited on albums together with another.
+type Collaborator struct {
+	XMLName xml.Name `json:"-" xml:"musician"`
+	Musician
+	SharedAlbums int `json:"shared_albums" xml:"shared_albums"`
+}
+
+// Collaboration links two musicians through an album they are both credited on.
+type Collaboration struct {
+	MusicianID     uint
+	CollaboratorID uint
+	AlbumID        uint
+}
+
+// PathStep is one musician on a collaboration path, reached through an album shared with the previous one.
+type PathStep struct {
+	Musician Musician `json:"musician" xml:"musician"`
+	// Via is nil for the first musician.
+	Via *Album `json:"via,omitempty" xml:"via,omitempty"`
+}
+
+// CollaborationPath is a shortest chain of collaborations between two musicians.
+type CollaborationPath struct {
+	XMLName xml.Name `json:"-" xml:"path"`
+	// Degrees is the number of collaborations separating the musicians.
+	Degrees int        `json:"degrees" xml:"degrees"`
+	Steps   []PathStep `json:"steps" xml:"steps>step"`
+}
+
+// ComponentSize counts the groups of connected musicians of one size.
+type ComponentSize struct {
+	Size  int `json:"size" xml:"size"`
+	Count int `json:"count" xml:"count"`
+}
+
+// CollaborationStats summarises the connected components of the collaboration graph.
+type CollaborationStats struct {
+	XMLName        xml.Name `json:"-" xml:"stats"`
+	Musicians      int      `json:"musicians" xml:"musicians"`
+	Collaborations int      `json:"collaborations" xml:"collaborations"`
+	// Components counts the groups of two or more musicians connected by collaborations.
+	Components       int `json:"components" xml:"components"`
+	LargestComponent int `json:"largest_component" xml:"largest_component"`
+	// Isolated counts musicians without collaborators.
+	Isolated       int             `json:"isolated" xml:"isolated"`
+	ComponentSizes []ComponentSize `json:"component_sizes" xml:"component_sizes>component_size"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// Roles a musician can be credited with on an album.
+const (
+	CreditPerformer = "performer"
+	CreditProducer  = "producer"
+	CreditComposer  = "composer"
+	CreditEngineer  = "engineer"
+	CreditFeatured  = "featured"
+)
+
+// CreditRoles lists every valid credit role.
+var CreditRoles = []string{CreditPerformer, CreditProducer, CreditComposer, CreditEngineer, CreditFeatured}
+
+// Credit records what a musician did on an album.
+type Credit struct {
+	MusicianID uint   `json:"musician_id" xml:"musician_id"`
+	Role       string `json:"role" xml:"role"`
+	Instrument string `json:"instrument,omitempty" xml:"instrument,omitempty"`
+	// Track limits the credit to one track number; 0 means the whole album.
+	Track int `json:"track,omitempty" xml:"track,omitempty"`
+}
+
+// String describes the credit in one line, e.g. "performer (guitar, track 3)".
+func (c Credit) String() string {
+	details := c.Instrument
+	if c.Track != 0 {
+		if details != "" {
+			details += ", "
+		}
+		details += fmt.Sprintf("track %d", c.Track)
+	}
+	if details == "" {
+		return c.Role
+	}
+	return c.Role + " (" + details + ")"
+}
+
+// CreditedMusician is a musician together with their credits on one album.
+type CreditedMusician struct {
+	XMLName xml.Name `json:"-" xml:"musician"`
+	Musician
+	// Credits have no CSV form; CSV lists the musician only.
+	Credits []Credit `json:"credits" xml:"credits>credit" csv:"-"`
+}
+
+// CreditedAlbum is an album together with one musician's credits on it.
+type CreditedAlbum struct {
+	XMLName xml.Name `json:"-" xml:"album"`
+	Album
+	// Credits have no CSV form; CSV lists the album only.
+	Credits []Credit `json:"credits" xml:"credits>credit" csv:"-"`
+}
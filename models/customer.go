@@ -0,0 +1,51 @@
+package models
+
+// Sources of library entries: albums bought in a paid order, or added by the customer by hand.
+const (
+	LibraryFromOrder = "order"
+	LibraryManual    = "manual"
+)
+
+// Customer is a registered account. Its password hash never leaves the repository layer.
+type Customer struct {
+	ID        uint   `json:"id" xml:"id"`
+	Email     string `json:"email" xml:"email"`
+	Name      string `json:"name" xml:"name"`
+	CreatedAt string `json:"created_at" xml:"created_at"`
+	// Admin marks staff accounts, which may list every order and ship or cancel them.
+	Admin bool `json:"admin,omitempty" xml:"admin,omitempty"`
+}
+
+// Registration is the request to create a customer account.
+type Registration struct {
+	Email    string `json:"email" xml:"email"`
+	Name     string `json:"name" xml:"name"`
+	Password string `json:"password" xml:"password"`
+}
+
+// Credentials are the email and password a customer signs in with.
+type Credentials struct {
+	Email    string `json:"email" xml:"email"`
+	Password string `json:"password" xml:"password"`
+}
+
+// Session is a signed-in customer's bearer token. The token is only ever reported when the session is created.
+type Session struct {
+	Token     string   `json:"token" xml:"token"`
+	ExpiresAt string   `json:"expires_at" xml:"expires_at"`
+	Customer  Customer `json:"customer" xml:"customer"`
+}
+
+// LibraryEntry is an album a customer owns.
+type LibraryEntry struct {
+	Album  Album  `json:"album" xml:"album"`
+	Source string `json:"source" xml:"source"`
+	// OrderID is the paid order the album came from, for entries from orders.
+	OrderID uint   `json:"order_id,omitempty" xml:"order_id,omitempty"`
+	AddedAt string `json:"added_at" xml:"added_at"`
+}
+
+// LibraryAdd is the request to add an album to a customer's library by hand.
+type LibraryAdd struct {
+	AlbumID uint `json:"album_id" xml:"album_id"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// Release formats an edition can be sold in.
+const (
+	FormatVinyl    = "vinyl"
+	FormatCD       = "cd"
+	FormatCassette = "cassette"
+	FormatDigital  = "digital"
+)
+
+// EditionFormats lists the valid edition formats.
+var EditionFormats = []string{FormatVinyl, FormatCD, FormatCassette, FormatDigital}
+
+// Edition is one release of an album in a given format, e.g. the deluxe vinyl.
+type Edition struct {
+	ID      uint   `json:"id" xml:"id"`
+	AlbumID uint   `json:"album_id" xml:"album_id"`
+	Format  string `json:"format" xml:"format"`
+	// Name distinguishes editions in the same format, e.g. "Deluxe" or "180g Remaster".
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	// Barcode is an EAN-13, EAN-8 or UPC-A code.
+	Barcode     string `json:"barcode,omitempty" xml:"barcode,omitempty"`
+	ReleaseDate string `json:"release_date" xml:"release_date"`
+	// Price is in the album's currency.
+	Price float64 `json:"price" xml:"price"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Event types written to the outbox when catalog entities change.
+const (
+	EventAlbumCreated    = "album.created"
+	EventAlbumUpdated    = "album.updated"
+	EventAlbumDeleted    = "album.deleted"
+	EventMusicianCreated = "musician.created"
+	EventMusicianUpdated = "musician.updated"
+	EventMusicianDeleted = "musician.deleted"
+)
+
+// Event is a domain event stored in the outbox until it has been delivered.
+type Event struct {
+	ID            uint   `json:"id"`
+	AggregateType string `json:"aggregate_type"`
+	AggregateID   uint   `json:"aggregate_id"`
+	Type          string `json:"type"`
+	Payload       string `json:"payload"`
+	CreatedAt     string `json:"created_at"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error"`
+}
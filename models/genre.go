@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Genre is a node in the genre tree. Albums tagged with a genre also belong to its ancestors.
+type Genre struct {
+	ID   uint   `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+	// ParentID is 0 for top-level genres.
+	ParentID uint `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	// Aliases are alternative spellings that resolve to this genre, e.g. "Rock'n'Roll".
+	Aliases []string `json:"aliases" xml:"aliases>alias"`
+}
+
+// GenreKey normalises a genre name or alias for matching: case, punctuation and
+// the spelling of "and" are ignored, so "Rock & Roll" and "rock and roll" match.
+func GenreKey(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "&", " and ")
+	name = strings.ReplaceAll(name, "'n'", " and ")
+
+	var key strings.Builder
+	gap := false
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			gap = true
+			continue
+		}
+		if gap && key.Len() > 0 {
+			key.WriteByte(' ')
+		}
+		gap = false
+		key.WriteRune(r)
+	}
+	return key.String()
+}
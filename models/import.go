@@ -0,0 +1,24 @@
+package models
+
+// Outcomes recorded for each row of a bulk import.
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportRowResult reports what happened to a single imported row.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport summarises a bulk import.
+type ImportReport struct {
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,72 @@
+package models
+
+// Outcomes of ingesting an audio file.
+const (
+	// IngestCreated means the file added an album, musician or track to the catalog.
+	IngestCreated = "created"
+	// IngestMatched means everything the file describes was already in the catalog.
+	IngestMatched = "matched"
+	// IngestConflicted means the file disagrees with the catalog and was left for review.
+	IngestConflicted = "conflict"
+	IngestFailed     = "failed"
+)
+
+// What an ingestion conflict concerns.
+const (
+	ConflictAlbum    = "album"
+	ConflictMusician = "musician"
+	ConflictTrack    = "track"
+)
+
+// Review statuses of ingestion conflicts.
+const (
+	ConflictOpen      = "open"
+	ConflictResolved  = "resolved"
+	ConflictDismissed = "dismissed"
+)
+
+// ConflictStatuses lists the valid conflict statuses.
+var ConflictStatuses = []string{ConflictOpen, ConflictResolved, ConflictDismissed}
+
+// IngestConflict is a disagreement between an ingested file's tags and the catalog, kept for review
+// instead of changing or duplicating the catalog.
+type IngestConflict struct {
+	ID       uint   `json:"id" xml:"id"`
+	FileName string `json:"file_name" xml:"file_name"`
+	Kind     string `json:"kind" xml:"kind"`
+	// EntityID is the album, musician or track the file disagrees with, or 0 when several match it.
+	EntityID   uint   `json:"entity_id,omitempty" xml:"entity_id,omitempty"`
+	Field      string `json:"field" xml:"field"`
+	Existing   string `json:"existing" xml:"existing"`
+	Incoming   string `json:"incoming" xml:"incoming"`
+	Status     string `json:"status" xml:"status"`
+	CreatedAt  string `json:"created_at" xml:"created_at"`
+	ReviewedAt string `json:"reviewed_at,omitempty" xml:"reviewed_at,omitempty"`
+}
+
+// ConflictReview is the request to resolve or dismiss a conflict.
+type ConflictReview struct {
+	Status string `json:"status" xml:"status"`
+}
+
+// IngestFileResult reports what became of one ingested file.
+type IngestFileResult struct {
+	FileName string `json:"file_name" xml:"file_name"`
+	// Format is the tag format read, such as "id3v2".
+	Format      string           `json:"format,omitempty" xml:"format,omitempty"`
+	Status      string           `json:"status" xml:"status"`
+	AlbumID     uint             `json:"album_id,omitempty" xml:"album_id,omitempty"`
+	TrackID     uint             `json:"track_id,omitempty" xml:"track_id,omitempty"`
+	MusicianIDs []uint           `json:"musician_ids,omitempty" xml:"musician_ids>id,omitempty"`
+	Conflicts   []IngestConflict `json:"conflicts,omitempty" xml:"conflicts>conflict,omitempty"`
+	Error       string           `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// IngestReport summarises an upload of audio files, reporting each file in upload order.
+type IngestReport struct {
+	Created   int                `json:"created" xml:"created"`
+	Matched   int                `json:"matched" xml:"matched"`
+	Conflicts int                `json:"conflicts" xml:"conflicts"`
+	Failed    int                `json:"failed" xml:"failed"`
+	Files     []IngestFileResult `json:"files" xml:"files>file"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// Kinds of stock movement.
+const (
+	MovementReceive = "receive"
+	MovementSell    = "sell"
+	MovementAdjust  = "adjust"
+	MovementReturn  = "return"
+)
+
+// MovementKinds lists the valid stock movement kinds.
+var MovementKinds = []string{MovementReceive, MovementSell, MovementAdjust, MovementReturn}
+
+// StockLevel is the number of copies in stock of an album, or of one of its editions.
+type StockLevel struct {
+	AlbumID uint `json:"album_id" xml:"album_id"`
+	// EditionID is absent for albums stocked as a whole.
+	EditionID uint `json:"edition_id,omitempty" xml:"edition_id,omitempty"`
+	Quantity  int  `json:"quantity" xml:"quantity"`
+	// LowStockThreshold flags the stock as low once the quantity falls to it; 0 disables the alert.
+	LowStockThreshold int  `json:"low_stock_threshold" xml:"low_stock_threshold"`
+	LowStock          bool `json:"low_stock" xml:"low_stock"`
+}
+
+// StockMovement is an entry of the append-only stock ledger.
+type StockMovement struct {
+	ID        uint   `json:"id" xml:"id"`
+	AlbumID   uint   `json:"album_id" xml:"album_id"`
+	EditionID uint   `json:"edition_id,omitempty" xml:"edition_id,omitempty"`
+	Kind      string `json:"kind" xml:"kind"`
+	// Quantity is the number of copies received, sold or returned; adjustments are negative to remove copies.
+	Quantity int `json:"quantity" xml:"quantity"`
+	// Balance is the stock level after the movement.
+	Balance   int    `json:"balance" xml:"balance"`
+	Note      string `json:"note,omitempty" xml:"note,omitempty"`
+	CreatedAt string `json:"created_at" xml:"created_at"`
+}
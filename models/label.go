@@ -0,0 +1,10 @@
+package models
+
+// Label is a record label. Imprints point at the label that owns them.
+type Label struct {
+	ID      uint   `json:"id" xml:"id"`
+	Name    string `json:"name" xml:"name"`
+	Country string `json:"country,omitempty" xml:"country,omitempty"`
+	// ParentID is 0 for labels without a parent label.
+	ParentID uint `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+}
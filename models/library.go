@@ -0,0 +1,68 @@
+package models
+
+// LibraryFile is an audio file found by the library scanner.
+type LibraryFile struct {
+	ID         uint   `json:"id"`
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	ModifiedNS int64  `json:"modified_ns"`
+	// Hash is the hex encoded SHA-256 of the file's content.
+	Hash string `json:"hash"`
+	// Status is the outcome of ingesting the file, one of the Ingest statuses.
+	Status    string `json:"status"`
+	AlbumID   uint   `json:"album_id,omitempty"`
+	TrackID   uint   `json:"track_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ScannedAt string `json:"scanned_at"`
+	// MissingSince is set while the file is no longer found under its root.
+	MissingSince string `json:"missing_since,omitempty"`
+}
+
+// What a library scan did, or in a dry run would do, with a file.
+const (
+	// ScanAdded means a new file was ingested.
+	ScanAdded = "added"
+	// ScanChanged means a known file's content changed and the catalog was updated from its tags, or a
+	// conflict was recorded where they disagree.
+	ScanChanged = "changed"
+	// ScanMoved means a known file was found at a new path with the same content.
+	ScanMoved = "moved"
+	// ScanMissing means a known file is no longer found under its root.
+	ScanMissing = "missing"
+	// ScanRetried means a file left in conflict was ingested again with a different outcome.
+	ScanRetried = "retried"
+	// ScanFailed means a file could not be read.
+	ScanFailed = "failed"
+)
+
+// ScanChange is one change a library scan made or plans.
+type ScanChange struct {
+	Action string `json:"action"`
+	Path   string `json:"path"`
+	// From is the previous path of a moved file.
+	From string `json:"from,omitempty"`
+	// Details describes the change, such as the tags of an added file or the fields updated.
+	Details string `json:"details,omitempty"`
+	// Result is the outcome of ingesting the file; it is absent in dry runs and for in-place updates.
+	Result *IngestFileResult `json:"result,omitempty"`
+	// AlbumID and TrackID are the album and track a missing file was ingested as.
+	AlbumID uint `json:"album_id,omitempty"`
+	TrackID uint `json:"track_id,omitempty"`
+}
+
+// ScanReport summarises a library scan.
+type ScanReport struct {
+	Roots     []string `json:"roots"`
+	DryRun    bool     `json:"dry_run"`
+	Scanned   int      `json:"scanned"`
+	Unchanged int      `json:"unchanged"`
+	Added     int      `json:"added"`
+	Changed   int      `json:"changed"`
+	Moved     int      `json:"moved"`
+	Missing   int      `json:"missing"`
+	Retried   int      `json:"retried"`
+	Failed    int      `json:"failed"`
+	// Conflicts counts the files ingested in this scan that were left in conflict.
+	Conflicts int          `json:"conflicts"`
+	Changes   []ScanChange `json:"changes"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Membership records that a person belonged to a group over a period of time.
+// A person who left and rejoined has one membership per stint.
+type Membership struct {
+	ID         uint     `json:"id" xml:"id"`
+	GroupID    uint     `json:"group_id" xml:"group_id"`
+	MemberID   uint     `json:"member_id" xml:"member_id"`
+	Member     Musician `json:"member" xml:"member"`
+	Instrument string   `json:"instrument" xml:"instrument"`
+	JoinedOn   string   `json:"joined_on" xml:"joined_on"`
+	// LeftOn is empty while the member is still in the group.
+	LeftOn string `json:"left_on,omitempty" xml:"left_on,omitempty"`
+}
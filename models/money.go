@@ -0,0 +1,64 @@
+package models
+
+import "math"
+
+// BaseCurrency is the currency exchange rates are quoted against and the album price limits apply to.
+const BaseCurrency = "USD"
+
+// CurrencyExponents maps the supported ISO 4217 currency codes to the number of digits of their minor unit.
+var CurrencyExponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+// ExchangeRate is how much of a currency one unit of BaseCurrency buys.
+type ExchangeRate struct {
+	Currency string  `json:"currency" xml:"currency"`
+	Rate     float64 `json:"rate" xml:"rate"`
+}
+
+// AlbumPrice is an explicit price of an album in one currency, used instead of converting its own price.
+type AlbumPrice struct {
+	AlbumID  uint    `json:"album_id" xml:"album_id"`
+	Currency string  `json:"currency" xml:"currency"`
+	Price    float64 `json:"price" xml:"price"`
+}
+
+// ToMinor converts an amount in major units, e.g. 12.99, to the currency's minor units, e.g. 1299,
+// rounding half away from zero.
+func ToMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * scale(currency)))
+}
+
+// FromMinor converts an amount in the currency's minor units to major units.
+func FromMinor(minor int64, currency string) float64 {
+	return float64(minor) / scale(currency)
+}
+
+// ConvertMinor converts an amount in minor units between currencies given both currencies' exchange
+// rates, rounding half away from zero to the minor unit of the target currency.
+func ConvertMinor(minor int64, from string, fromRate float64, to string, toRate float64) int64 {
+	return int64(math.Round(float64(minor) / scale(from) / fromRate * toRate * scale(to)))
+}
+
+func scale(currency string) float64 {
+	return math.Pow10(CurrencyExponents[currency])
+}
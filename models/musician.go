@@ -1,7 +1,14 @@
 package models
 
+// Kinds of musician: an individual person or a group such as a band.
+const (
+    MusicianKindPerson = "person"
+    MusicianKindGroup  = "group"
+)
+
 type Musician struct {
-    ID           uint   `json:"id"`
-    Name         string `json:"name"`
-    MusicianType string `json:"musician_type"`
+    ID           uint   `json:"id" xml:"id"`
+    Name         string `json:"name" xml:"name"`
+    MusicianType string `json:"musician_type" xml:"musician_type"`
+    Kind         string `json:"kind" xml:"kind"`
 }
@@ -0,0 +1,78 @@
+package models
+
+// Order statuses. Orders start pending and move on to paid, then shipped; pending and paid orders can be
+// cancelled.
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderShipped   = "shipped"
+	OrderCancelled = "cancelled"
+)
+
+// OrderStatuses lists the valid order statuses.
+var OrderStatuses = []string{OrderPending, OrderPaid, OrderShipped, OrderCancelled}
+
+// Cart holds the albums and editions a customer intends to buy.
+type Cart struct {
+	ID uint `json:"id" xml:"id"`
+	// Currency the cart is checked out in; defaults to BaseCurrency.
+	Currency  string     `json:"currency" xml:"currency"`
+	Items     []CartItem `json:"items" xml:"items>item"`
+	CreatedAt string     `json:"created_at" xml:"created_at"`
+	// CheckedOutAt is absent until the cart has become an order.
+	CheckedOutAt string `json:"checked_out_at,omitempty" xml:"checked_out_at,omitempty"`
+	// CustomerID is the signed-in customer who created the cart, if any.
+	CustomerID uint `json:"customer_id,omitempty" xml:"customer_id,omitempty"`
+}
+
+// CartItem is a quantity of an album, or of one of its editions, in a cart.
+type CartItem struct {
+	AlbumID   uint `json:"album_id" xml:"album_id"`
+	EditionID uint `json:"edition_id,omitempty" xml:"edition_id,omitempty"`
+	Quantity  int  `json:"quantity" xml:"quantity"`
+}
+
+// Checkout is the request to turn a cart into an order.
+type Checkout struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country whose tax rate applies.
+	Country string `json:"country" xml:"country"`
+}
+
+// Order is a checked out cart with the prices, tax and total fixed at the time of purchase.
+type Order struct {
+	ID       uint        `json:"id" xml:"id"`
+	CartID   uint        `json:"cart_id" xml:"cart_id"`
+	Status   string      `json:"status" xml:"status"`
+	Country  string      `json:"country" xml:"country"`
+	Currency string      `json:"currency" xml:"currency"`
+	Lines    []OrderLine `json:"lines" xml:"lines>line"`
+	Subtotal float64     `json:"subtotal" xml:"subtotal"`
+	TaxRate  float64     `json:"tax_rate" xml:"tax_rate"`
+	Tax      float64     `json:"tax" xml:"tax"`
+	Total    float64     `json:"total" xml:"total"`
+	// PaymentReference is the payment provider's reference for the charge, once paid.
+	PaymentReference string `json:"payment_reference,omitempty" xml:"payment_reference,omitempty"`
+	CreatedAt        string `json:"created_at" xml:"created_at"`
+	UpdatedAt        string `json:"updated_at" xml:"updated_at"`
+	// CustomerID is the customer whose cart was checked out, if any.
+	CustomerID uint `json:"customer_id,omitempty" xml:"customer_id,omitempty"`
+}
+
+// OrderLine is an album or edition bought in an order at the price it had at checkout.
+type OrderLine struct {
+	AlbumID   uint `json:"album_id" xml:"album_id"`
+	EditionID uint `json:"edition_id,omitempty" xml:"edition_id,omitempty"`
+	// Name is the album's name at checkout, with the edition's format and name for editions.
+	Name      string  `json:"name" xml:"name"`
+	Quantity  int     `json:"quantity" xml:"quantity"`
+	UnitPrice float64 `json:"unit_price" xml:"unit_price"`
+	Total     float64 `json:"total" xml:"total"`
+	// StockTracked records whether the copies were taken from tracked stock, so cancelling returns them.
+	StockTracked bool `json:"-" xml:"-"`
+}
+
+// TaxRate is the sales tax charged on orders to a country, as a fraction of the subtotal.
+type TaxRate struct {
+	Country string  `json:"country" xml:"country"`
+	Rate    float64 `json:"rate" xml:"rate"`
+}
@@ -0,0 +1,95 @@
+package models
+
+// Outcomes of recording a play in a batch.
+const (
+	PlayAccepted  = "accepted"
+	PlayDuplicate = "duplicate"
+	PlayRejected  = "rejected"
+)
+
+// What play statistics rank.
+const (
+	TopAlbums    = "albums"
+	TopMusicians = "musicians"
+	TopGenres    = "genres"
+)
+
+// TopTypes lists every valid kind of play statistics.
+var TopTypes = []string{TopAlbums, TopMusicians, TopGenres}
+
+// Play is a customer listening to an album, or to one of its tracks.
+type Play struct {
+	ID uint `json:"id" xml:"id"`
+	// ClientID is the client's own identifier for the play; a play sent again with the same ClientID is
+	// stored once.
+	ClientID   string `json:"client_id" xml:"client_id"`
+	CustomerID uint   `json:"customer_id" xml:"customer_id"`
+	AlbumID    uint   `json:"album_id" xml:"album_id"`
+	// AlbumName is reported in play history.
+	AlbumName string `json:"album_name,omitempty" xml:"album_name,omitempty"`
+	// Track is the track number played; 0 means the whole album.
+	Track           int    `json:"track,omitempty" xml:"track,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" xml:"duration_seconds,omitempty"`
+	PlayedAt        string `json:"played_at" xml:"played_at"`
+}
+
+// PlayBatch is the request to record several plays at once.
+type PlayBatch struct {
+	Plays []Play `json:"plays" xml:"plays>play"`
+}
+
+// PlayResult is what became of one play of a batch.
+type PlayResult struct {
+	ClientID string `json:"client_id" xml:"client_id"`
+	Status   string `json:"status" xml:"status"`
+	// PlayID is the stored play, for accepted and duplicate plays.
+	PlayID uint   `json:"play_id,omitempty" xml:"play_id,omitempty"`
+	Error  string `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// PlayBatchResult reports the outcome of every play of a batch, in the order they were sent.
+type PlayBatchResult struct {
+	Accepted   int          `json:"accepted" xml:"accepted"`
+	Duplicates int          `json:"duplicates" xml:"duplicates"`
+	Rejected   int          `json:"rejected" xml:"rejected"`
+	Results    []PlayResult `json:"results" xml:"results>result"`
+}
+
+// PlayFilter narrows play listings and statistics. Zero values mean "no restriction".
+type PlayFilter struct {
+	CustomerID uint
+	// From and To bound the time played, from From up to but not including To, in TimeFormat.
+	From string
+	To   string
+	// Limit and Offset select one page of the listing; a zero Limit lists everything.
+	Limit  int
+	Offset int
+}
+
+// PlayPage is one page of a customer's play history.
+type PlayPage struct {
+	Plays   []Play `json:"plays" xml:"plays>play"`
+	Page    int    `json:"page" xml:"page"`
+	PerPage int    `json:"per_page" xml:"per_page"`
+	// Total is the number of plays on every page.
+	Total int `json:"total" xml:"total"`
+}
+
+// PlayCount is how often an album, musician or genre was played.
+type PlayCount struct {
+	ID    uint   `json:"id" xml:"id"`
+	Name  string `json:"name" xml:"name"`
+	Plays int    `json:"plays" xml:"plays"`
+	// Listeners is the number of customers who played it.
+	Listeners int `json:"listeners" xml:"listeners"`
+}
+
+// PlayStats ranks the most played albums, musicians or genres over a time range.
+type PlayStats struct {
+	Type string `json:"type" xml:"type"`
+	From string `json:"from,omitempty" xml:"from,omitempty"`
+	To   string `json:"to" xml:"to"`
+	// Plays is the number of plays in the range.
+	Plays int         `json:"plays" xml:"plays"`
+	Top   []PlayCount `json:"top" xml:"top>entry"`
+}
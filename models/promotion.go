@@ -0,0 +1,58 @@
+package models
+
+// Promotion kinds.
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+)
+
+// PromotionKinds lists the supported promotion kinds.
+var PromotionKinds = []string{PromotionPercentage, PromotionFixed}
+
+// TimeFormat is the layout promotion and price schedule times are stored in. Times are kept in UTC
+// so they compare as text.
+const TimeFormat = "2006-01-02T15:04:05Z"
+
+// Promotion is a time-boxed discount on an album or on every album of a genre, label or musician.
+// Exactly one of AlbumID, GenreID, LabelID and MusicianID is set.
+type Promotion struct {
+	ID   uint   `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+	Kind string `json:"kind" xml:"kind"`
+	// PercentOff is the discount of percentage promotions.
+	PercentOff float64 `json:"percent_off,omitempty" xml:"percent_off,omitempty"`
+	// AmountOff is the discount of fixed promotions, in Currency.
+	AmountOff float64 `json:"amount_off,omitempty" xml:"amount_off,omitempty"`
+	Currency  string  `json:"currency,omitempty" xml:"currency,omitempty"`
+	// GenreID also covers albums tagged with any descendant genre.
+	AlbumID    uint `json:"album_id,omitempty" xml:"album_id,omitempty"`
+	GenreID    uint `json:"genre_id,omitempty" xml:"genre_id,omitempty"`
+	LabelID    uint `json:"label_id,omitempty" xml:"label_id,omitempty"`
+	MusicianID uint `json:"musician_id,omitempty" xml:"musician_id,omitempty"`
+	// The promotion runs from StartsAt, inclusive, to EndsAt, exclusive.
+	StartsAt string `json:"starts_at" xml:"starts_at"`
+	EndsAt   string `json:"ends_at" xml:"ends_at"`
+}
+
+// PriceSchedule changes an album's own price at a point in time.
+type PriceSchedule struct {
+	ID      uint `json:"id" xml:"id"`
+	AlbumID uint `json:"album_id" xml:"album_id"`
+	// Price is in the album's currency.
+	Price    float64 `json:"price" xml:"price"`
+	StartsAt string  `json:"starts_at" xml:"starts_at"`
+	// AppliedAt is when the album's price was changed; it is empty while the change is pending.
+	AppliedAt string `json:"applied_at,omitempty" xml:"applied_at,omitempty"`
+}
+
+// EffectivePrice is what an album costs at a point in time, in the album's currency.
+type EffectivePrice struct {
+	AlbumID  uint   `json:"album_id" xml:"album_id"`
+	At       string `json:"at" xml:"at"`
+	Currency string `json:"currency" xml:"currency"`
+	// Price is the album's own price at At, including scheduled changes due by then.
+	Price          float64 `json:"price" xml:"price"`
+	EffectivePrice float64 `json:"effective_price" xml:"effective_price"`
+	// PromotionID is the promotion giving EffectivePrice, or 0 when none applies.
+	PromotionID uint `json:"promotion_id,omitempty" xml:"promotion_id,omitempty"`
+}
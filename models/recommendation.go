@@ -0,0 +1,53 @@
+package models
+
+import "encoding/xml"
+
+// Signals a recommendation can be based on.
+const (
+	// ReasonMusicians marks albums sharing musicians, or musicians who played together or in the same group.
+	ReasonMusicians = "musicians"
+	ReasonGenre     = "genre"
+	ReasonEra       = "era"
+	// ReasonListeners marks albums or musicians bought or rated highly by the same customers.
+	ReasonListeners = "listeners"
+)
+
+// RelatedAlbum is an album recommended alongside another, with how strongly and why.
+type RelatedAlbum struct {
+	XMLName xml.Name `json:"-" xml:"album"`
+	Album
+	// Score is from 0 to 1.
+	Score   float64  `json:"score" xml:"score"`
+	Reasons []string `json:"reasons" xml:"reasons>reason"`
+}
+
+// RelatedMusician is a musician recommended alongside another, with how strongly and why.
+type RelatedMusician struct {
+	XMLName xml.Name `json:"-" xml:"musician"`
+	Musician
+	Score   float64  `json:"score" xml:"score"`
+	Reasons []string `json:"reasons" xml:"reasons>reason"`
+}
+
+// Recommendation is a precomputed score of one album or musician for another.
+type Recommendation struct {
+	SubjectID uint
+	RelatedID uint
+	Score     float64
+	Reasons   []string
+}
+
+// RecommendationSignals is everything recommendations are computed from, loaded in bulk.
+type RecommendationSignals struct {
+	// ReleaseDates holds every album's release date by album ID.
+	ReleaseDates map[uint]string
+	// AlbumMusicians holds the musicians credited on each album.
+	AlbumMusicians map[uint][]uint
+	// AlbumGenres holds each album's genres together with their ancestors.
+	AlbumGenres map[uint][]uint
+	// AlbumListeners holds the customers, or for guest orders the orders, that bought each album or
+	// rated it highly, e.g. "customer:3" or "order:7".
+	AlbumListeners map[uint][]string
+	// MusicianGroups holds the groups each person has been a member of.
+	MusicianGroups map[uint][]uint
+}
@@ -0,0 +1,55 @@
+package models
+
+// Review moderation statuses. New and edited reviews are pending until a moderator approves or rejects them;
+// only approved reviews are listed and rated.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// ReviewStatuses lists the valid review statuses.
+var ReviewStatuses = []string{ReviewPending, ReviewApproved, ReviewRejected}
+
+// Review is a customer's rating of an album with an optional text.
+type Review struct {
+	ID         uint `json:"id" xml:"id"`
+	AlbumID    uint `json:"album_id" xml:"album_id"`
+	CustomerID uint `json:"customer_id" xml:"customer_id"`
+	// Author is the reviewing customer's name.
+	Author    string `json:"author" xml:"author"`
+	Rating    int    `json:"rating" xml:"rating"`
+	Text      string `json:"text" xml:"text"`
+	Status    string `json:"status" xml:"status"`
+	CreatedAt string `json:"created_at" xml:"created_at"`
+	UpdatedAt string `json:"updated_at" xml:"updated_at"`
+}
+
+// ReviewModeration is the request to change a review's status.
+type ReviewModeration struct {
+	Status string `json:"status" xml:"status"`
+}
+
+// ReviewFilter narrows review listings. Zero values mean "no restriction".
+type ReviewFilter struct {
+	AlbumID uint
+	Status  string
+	// Limit and Offset select one page of the listing; a zero Limit lists everything.
+	Limit  int
+	Offset int
+}
+
+// ReviewPage is one page of a review listing.
+type ReviewPage struct {
+	Reviews []Review `json:"reviews" xml:"reviews>review"`
+	Page    int      `json:"page" xml:"page"`
+	PerPage int      `json:"per_page" xml:"per_page"`
+	// Total is the number of reviews on every page.
+	Total int `json:"total" xml:"total"`
+}
+
+// RatingSummary is the average rating and number of approved reviews of an album.
+type RatingSummary struct {
+	AverageRating float64
+	ReviewCount   int
+}
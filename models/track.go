@@ -0,0 +1,14 @@
+package models
+
+// Track is one track of an album, on one of its discs.
+type Track struct {
+	ID      uint   `json:"id" xml:"id"`
+	AlbumID uint   `json:"album_id" xml:"album_id"`
+	Disc    int    `json:"disc" xml:"disc"`
+	Number  int    `json:"number" xml:"number"`
+	Title   string `json:"title" xml:"title"`
+	// DurationSeconds is 0 when the length is not known.
+	DurationSeconds int `json:"duration_seconds,omitempty" xml:"duration_seconds,omitempty"`
+	// MissingSince is set once every library file of the track is no longer found, to when the last went missing.
+	MissingSince string `json:"missing_since,omitempty" xml:"missing_since,omitempty"`
+}
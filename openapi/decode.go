@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlNode is an element of an XML body with its text and child elements.
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+}
+
+// parseXML reads an XML document into a tree of elements, returning its root.
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var open []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local}
+			if len(open) == 0 {
+				if root != nil {
+					return nil, errors.New("must contain a single root element")
+				}
+				root = node
+			} else {
+				parent := open[len(open)-1]
+				parent.children = append(parent.children, node)
+			}
+			open = append(open, node)
+		case xml.EndElement:
+			open = open[:len(open)-1]
+		case xml.CharData:
+			if len(open) > 0 {
+				open[len(open)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("has no root element")
+	}
+	return root, nil
+}
+
+// xmlValue converts an element to the JSON value its schema describes, the way the controllers
+// decode it: objects from their child elements, arrays from the children of a wrapping element
+// and scalars from their text. Values that do not convert are left as strings for validateValue
+// to report.
+func (d *Document) xmlValue(node *xmlNode, schema *Schema) interface{} {
+	schema = d.resolve(schema)
+	if schema == nil {
+		return strings.TrimSpace(node.text)
+	}
+
+	switch schema.Type {
+	case "object":
+		object := make(map[string]interface{})
+		for _, child := range node.children {
+			property := d.resolve(schema.Properties[child.name])
+			if property == nil || property.Type != "array" {
+				object[child.name] = d.xmlValue(child, property)
+				continue
+			}
+			// Lists are either repeated elements or the children of one wrapping element
+			items, _ := object[child.name].([]interface{})
+			if d.wrapsItems(child, property.Items) {
+				for _, item := range child.children {
+					items = append(items, d.xmlValue(item, property.Items))
+				}
+			} else {
+				items = append(items, d.xmlValue(child, property.Items))
+			}
+			object[child.name] = items
+		}
+		return object
+
+	case "array":
+		items := make([]interface{}, 0, len(node.children))
+		for _, child := range node.children {
+			items = append(items, d.xmlValue(child, schema.Items))
+		}
+		return items
+	}
+	return scalarValue(strings.TrimSpace(node.text), schema)
+}
+
+// wrapsItems reports whether an element holds list items, e.g. <items><item>...</item></items>,
+// rather than being one item itself.
+func (d *Document) wrapsItems(node *xmlNode, items *Schema) bool {
+	if len(node.children) == 0 {
+		return false
+	}
+	items = d.resolve(items)
+	if items == nil || items.Type != "object" {
+		return true
+	}
+	for _, child := range node.children {
+		if _, ok := items.Properties[child.name]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// csvValue converts a CSV body, a header row followed by data rows, to the JSON value its schema
+// describes: one object from the first data row, or an array with an object per row.
+func (d *Document) csvValue(data []byte, schema *Schema) (interface{}, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, errors.New("needs a header row and a data row")
+	}
+	header, rows := records[0], records[1:]
+	for i, record := range rows {
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("row %d has %d fields, expected %d", i+1, len(record), len(header))
+		}
+	}
+
+	schema = d.resolve(schema)
+	if schema != nil && schema.Type == "array" {
+		items := make([]interface{}, 0, len(rows))
+		for _, record := range rows {
+			items = append(items, d.csvRow(header, record, schema.Items))
+		}
+		return items, nil
+	}
+	return d.csvRow(header, rows[0], schema), nil
+}
+
+// csvRow converts one CSV record to an object. Empty cells are left out, as the controllers leave
+// their fields unset, and lists are semicolon-separated.
+func (d *Document) csvRow(header, record []string, schema *Schema) map[string]interface{} {
+	schema = d.resolve(schema)
+	object := make(map[string]interface{})
+	for i, column := range header {
+		name, raw := strings.TrimSpace(column), strings.TrimSpace(record[i])
+		if raw == "" {
+			continue
+		}
+		var property *Schema
+		if schema != nil {
+			property = d.resolve(schema.Properties[name])
+		}
+		if property != nil && property.Type == "array" {
+			items := []interface{}{}
+			for _, item := range strings.Split(raw, ";") {
+				items = append(items, scalarValue(strings.TrimSpace(item), d.resolve(property.Items)))
+			}
+			object[name] = items
+			continue
+		}
+		object[name] = scalarValue(raw, property)
+	}
+	return object
+}
+
+// scalarValue converts text to the number or boolean its schema expects, leaving it a string when
+// it does not parse.
+func scalarValue(raw string, schema *Schema) interface{} {
+	if schema == nil {
+		return raw
+	}
+	if value, ok := parseParameter(raw, schema); ok {
+		return value
+	}
+	return raw
+}
@@ -0,0 +1,2370 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"jukebox/models"
+	"jukebox/services"
+	"jukebox/tags"
+	"net/http"
+	"sort"
+)
+
+// Spec builds the OpenAPI document for every route registered in routes.SetupRoutes.
+// Validation constraints are taken from the service layer so the document cannot
+// drift from the rules the API actually enforces.
+func Spec() *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Jukebox API",
+			Description: "Manage music albums, musicians and the links between them.",
+			Version:     "1.0.0",
+		},
+		Paths: map[string]*PathItem{
+			"/": {
+				"get": {
+					OperationID: "getRoot",
+					Summary:     "Check that the server is running",
+					Responses: map[string]*Response{
+						"200": textResponse("Welcome message"),
+					},
+				},
+			},
+			"/openapi.json": {
+				"get": {
+					OperationID: "getOpenAPI",
+					Summary:     "Retrieve this OpenAPI document",
+					Responses: map[string]*Response{
+						"200": {Description: "OpenAPI 3 document", Content: jsonContent(&Schema{Type: "object"})},
+					},
+				},
+			},
+			"/graphql": {
+				"post": {
+					OperationID: "postGraphQL",
+					Summary:     "Query and mutate albums, musicians and their links with GraphQL",
+					Tags:        []string{"graphql"},
+					RequestBody: &RequestBody{Required: true, Content: jsonContent(ref("GraphQLRequest"))},
+					Responses: map[string]*Response{
+						"200": {Description: "GraphQL result, including any field errors", Content: jsonContent(ref("GraphQLResponse"))},
+						"400": errorResponse("Malformed request"),
+					},
+				},
+			},
+			"/import": {
+				"post": {
+					OperationID: "importCatalog",
+					Summary:     "Bulk import albums, musicians or album–musician links from CSV or JSON Lines",
+					Tags:        []string{"import"},
+					Parameters: []*Parameter{
+						{Name: "entity", In: "query", Required: true, Description: "What the rows describe", Schema: &Schema{Type: "string", Enum: []string{services.ImportAlbums, services.ImportMusicians, services.ImportLinks}}},
+						{Name: "format", In: "query", Description: "Upload format; defaults to the one implied by Content-Type", Schema: &Schema{Type: "string", Enum: []string{services.FormatCSV, services.FormatJSONL}}},
+					},
+					RequestBody: &RequestBody{Required: true, Content: map[string]*MediaType{
+						"text/csv":             {Schema: &Schema{Type: "string", Description: "Header row naming the columns, then one row per record"}},
+						"application/x-ndjson": {Schema: &Schema{Type: "string", Description: "One JSON object per line"}},
+					}},
+					Responses: map[string]*Response{
+						"200": {Description: "Per-row import report", Content: jsonContent(ref("ImportReport"))},
+						"400": errorResponse("Unknown entity or unreadable upload"),
+						"415": errorResponse("Unsupported content type"),
+					},
+				},
+			},
+			"/export": {
+				"get": {
+					OperationID: "exportCatalog",
+					Summary:     "Download every album with its musicians joined, one row per album",
+					Tags:        []string{"export"},
+					Parameters: []*Parameter{
+						{Name: "format", In: "query", Description: "Download format; defaults to csv", Schema: &Schema{Type: "string", Enum: []string{services.FormatCSV, services.FormatJSONL, services.FormatODS}}},
+						{Name: "musician_id", In: "query", Description: "Only albums linked to this musician", Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}},
+						genreParameter(),
+						inStockParameter(),
+						currencyQueryParameter(),
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Catalog export", Content: map[string]*MediaType{
+							"text/csv":             {Schema: &Schema{Type: "string"}},
+							"application/x-ndjson": {Schema: ref("AlbumWithMusicians")},
+							"application/vnd.oasis.opendocument.spreadsheet": {Schema: &Schema{Type: "string", Format: "binary"}},
+						}},
+						"400": errorResponse("Unsupported format, invalid filter or a currency without an exchange rate"),
+					},
+				},
+			},
+			"/genres": {
+				"get": {
+					OperationID: "getGenres",
+					Summary:     "List genres sorted by name",
+					Tags:        []string{"genres"},
+					Responses: map[string]*Response{
+						"200": {Description: "Genres", Content: negotiatedContent(arrayOf("Genre"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "createGenre",
+					Summary:     "Create a genre",
+					Tags:        []string{"genres"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("GenreInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created genre", Content: negotiatedContent(ref("Genre"))},
+						"400": errorResponse("Malformed body or validation failure"),
+						"404": errorResponse("Parent genre not found"),
+					},
+				},
+			},
+			"/genres/{id}": {
+				"get": {
+					OperationID: "getGenre",
+					Summary:     "Retrieve a genre",
+					Tags:        []string{"genres"},
+					Parameters:  []*Parameter{idParameter("Genre ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Genre", Content: negotiatedContent(ref("Genre"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Genre not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": {
+					OperationID: "updateGenre",
+					Summary:     "Update a genre, replacing its aliases",
+					Tags:        []string{"genres"},
+					Parameters:  []*Parameter{idParameter("Genre ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("GenreInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated genre", Content: negotiatedContent(ref("Genre"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Genre or parent genre not found"),
+					},
+				},
+				"delete": {
+					OperationID: "deleteGenre",
+					Summary:     "Delete a genre without child genres, removing it from every album",
+					Tags:        []string{"genres"},
+					Parameters:  []*Parameter{idParameter("Genre ID")},
+					Responses: map[string]*Response{
+						"204": {Description: "Genre deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Genre not found"),
+						"409": errorResponse("Genre still has child genres"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/genres": {
+				"get": {
+					OperationID: "getAlbumGenres",
+					Summary:     "List the genres an album is tagged with",
+					Tags:        []string{"albums", "genres"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Genres", Content: negotiatedContent(arrayOf("Genre"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": {
+					OperationID: "setAlbumGenres",
+					Summary:     "Replace the genres an album is tagged with",
+					Tags:        []string{"albums", "genres"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("AlbumGenresInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Genres of the album", Content: negotiatedContent(arrayOf("Genre"))},
+						"400": errorResponse("Malformed body, invalid ID or unknown genre"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+			},
+			"/labels": {
+				"get": {
+					OperationID: "getLabels",
+					Summary:     "List record labels sorted by name",
+					Tags:        []string{"labels"},
+					Responses: map[string]*Response{
+						"200": {Description: "Labels", Content: negotiatedContent(arrayOf("Label"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "createLabel",
+					Summary:     "Create a record label",
+					Tags:        []string{"labels"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("LabelInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created label", Content: negotiatedContent(ref("Label"))},
+						"400": errorResponse("Malformed body or validation failure"),
+						"404": errorResponse("Parent label not found"),
+					},
+				},
+			},
+			"/labels/{id}": {
+				"get": {
+					OperationID: "getLabel",
+					Summary:     "Retrieve a record label",
+					Tags:        []string{"labels"},
+					Parameters:  []*Parameter{idParameter("Label ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Label", Content: negotiatedContent(ref("Label"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Label not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": {
+					OperationID: "updateLabel",
+					Summary:     "Update a record label",
+					Tags:        []string{"labels"},
+					Parameters:  []*Parameter{idParameter("Label ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("LabelInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated label", Content: negotiatedContent(ref("Label"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Label or parent label not found"),
+					},
+				},
+				"delete": {
+					OperationID: "deleteLabel",
+					Summary:     "Delete a record label that no album or imprint refers to",
+					Tags:        []string{"labels"},
+					Parameters:  []*Parameter{idParameter("Label ID")},
+					Responses: map[string]*Response{
+						"204": {Description: "Label deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Label not found"),
+						"409": errorResponse("Label still has albums or imprints"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/labels/{id}/albums": {
+				"get": {
+					OperationID: "getAlbumsByLabel",
+					Summary:     "List the albums of a label sorted by release date, oldest first",
+					Tags:        []string{"albums", "labels"},
+					Parameters:  []*Parameter{idParameter("Label ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Albums", Content: negotiatedContent(arrayOf("Album"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Label not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums": {
+				"get": {
+					OperationID: "getAlbums",
+					Summary:     "List albums sorted by release date, oldest first",
+					Tags:        []string{"albums"},
+					Parameters: []*Parameter{
+						{Name: "musician_id", In: "query", Description: "Only albums linked to this musician", Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}},
+						genreParameter(),
+						inStockParameter(),
+						currencyQueryParameter(),
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Albums", Content: negotiatedContent(arrayOf("Album"))},
+						"400": errorResponse("Invalid filter, unsupported currency or missing exchange rate"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "createAlbum",
+					Summary:     "Create an album and link it to musicians",
+					Tags:        []string{"albums"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("AlbumInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created album", Content: negotiatedContent(ref("Album"))},
+						"400": errorResponse("Malformed body or validation failure"),
+						"500": errorResponse("Linking musicians failed"),
+					},
+				},
+			},
+			"/albums/{id}": {
+				"put": {
+					OperationID: "updateAlbum",
+					Summary:     "Update an album",
+					Tags:        []string{"albums"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("Album"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated album", Content: negotiatedContent(ref("Album"))},
+						"400": errorResponse("Malformed body, invalid ID, invalid currency or price, or missing label"),
+						"404": errorResponse("Album not found"),
+						"409": errorResponse("Currency changed while the album has editions or pending scheduled prices, or catalog number already used by the label"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"delete": {
+					OperationID: "deleteAlbum",
+					Summary:     "Delete an album",
+					Tags:        []string{"albums"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"204": {Description: "Album deleted"},
+						"400": errorResponse("Invalid ID"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/musicians": {
+				"get": {
+					OperationID: "getMusiciansByAlbum",
+					Summary:     "List the musicians on an album sorted by name, with their credits",
+					Tags:        []string{"albums", "musicians"},
+					Parameters:  []*Parameter{idParameter("Album ID"), roleParameter()},
+					Responses: map[string]*Response{
+						"200": {Description: "Musicians with their credits", Content: negotiatedContent(arrayOf("CreditedMusician"))},
+						"400": errorResponse("Invalid ID or role"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/musicians": {
+				"get": {
+					OperationID: "getMusicians",
+					Summary:     "List musicians",
+					Tags:        []string{"musicians"},
+					Responses: map[string]*Response{
+						"200": {Description: "Musicians", Content: negotiatedContent(arrayOf("Musician"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "createMusician",
+					Summary:     "Create a musician",
+					Tags:        []string{"musicians"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("MusicianInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created musician", Content: negotiatedContent(ref("Musician"))},
+						"400": errorResponse("Malformed body or validation failure"),
+					},
+				},
+			},
+			"/musicians/{id}": {
+				"put": {
+					OperationID: "updateMusician",
+					Summary:     "Update a musician",
+					Tags:        []string{"musicians"},
+					Parameters:  []*Parameter{idParameter("Musician ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("Musician"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated musician", Content: negotiatedContent(ref("Musician"))},
+						"400": errorResponse("Malformed body, invalid ID or invalid kind"),
+						"404": errorResponse("Musician not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"delete": {
+					OperationID: "deleteMusician",
+					Summary:     "Delete a musician",
+					Tags:        []string{"musicians"},
+					Parameters:  []*Parameter{idParameter("Musician ID")},
+					Responses: map[string]*Response{
+						"204": {Description: "Musician deleted"},
+						"400": errorResponse("Invalid ID"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/musicians/{id}/albums": {
+				"get": {
+					OperationID: "getAlbumsByMusician",
+					Summary:     "List the albums of a musician sorted by price, lowest first, with the musician's credits",
+					Tags:        []string{"albums", "musicians"},
+					Parameters:  []*Parameter{idParameter("Musician ID"), roleParameter(), currencyQueryParameter()},
+					Responses: map[string]*Response{
+						"200": {Description: "Albums with the musician's credits", Content: negotiatedContent(arrayOf("CreditedAlbum"))},
+						"400": errorResponse("Invalid ID, role, unsupported currency or missing exchange rate"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/musicians/{id}/members": {
+				"get": {
+					OperationID: "getMembers",
+					Summary:     "List the members of a group sorted by join date",
+					Tags:        []string{"musicians"},
+					Parameters: []*Parameter{
+						idParameter("Group ID"),
+						{Name: "at", In: "query", Description: "Only members who were in the group on this date", Schema: &Schema{Type: "string", Format: "date"}},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Memberships", Content: negotiatedContent(arrayOf("Membership"))},
+						"400": errorResponse("Invalid ID or date"),
+						"404": errorResponse("Group not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "addMember",
+					Summary:     "Add a person to a group",
+					Tags:        []string{"musicians"},
+					Parameters:  []*Parameter{idParameter("Group ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("MembershipInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created membership", Content: negotiatedContent(ref("Membership"))},
+						"400": errorResponse("Malformed body or validation failure"),
+						"404": errorResponse("Group or member not found"),
+					},
+				},
+			},
+			"/musicians/{id}/members/{membership_id}": {
+				"put": {
+					OperationID: "updateMembership",
+					Summary:     "Update a membership of a group",
+					Tags:        []string{"musicians"},
+					Parameters:  []*Parameter{idParameter("Group ID"), membershipIDParameter()},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("MembershipInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated membership", Content: negotiatedContent(ref("Membership"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Group, member or membership not found"),
+					},
+				},
+				"delete": {
+					OperationID: "removeMember",
+					Summary:     "Delete a membership of a group",
+					Tags:        []string{"musicians"},
+					Parameters:  []*Parameter{idParameter("Group ID"), membershipIDParameter()},
+					Responses: map[string]*Response{
+						"204": {Description: "Membership deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Membership not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/lineup": {
+				"get": {
+					OperationID: "getAlbumLineup",
+					Summary:     "List the members of the album's groups on its release date",
+					Tags:        []string{"albums", "musicians"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Memberships", Content: negotiatedContent(arrayOf("Membership"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/editions": {
+				"get": {
+					OperationID: "getEditions",
+					Summary:     "List the editions of an album sorted by release date, then price",
+					Tags:        []string{"albums", "editions"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Editions", Content: negotiatedContent(arrayOf("Edition"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "addEdition",
+					Summary:     "Add an edition to an album",
+					Tags:        []string{"albums", "editions"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("EditionInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created edition", Content: negotiatedContent(ref("Edition"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+			},
+			"/albums/{id}/editions/{edition_id}": {
+				"put": {
+					OperationID: "updateEdition",
+					Summary:     "Update an edition of an album",
+					Tags:        []string{"albums", "editions"},
+					Parameters:  []*Parameter{idParameter("Album ID"), editionIDParameter()},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("EditionInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated edition", Content: negotiatedContent(ref("Edition"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album or edition not found"),
+					},
+				},
+				"delete": {
+					OperationID: "removeEdition",
+					Summary:     "Delete an edition of an album",
+					Tags:        []string{"albums", "editions"},
+					Parameters:  []*Parameter{idParameter("Album ID"), editionIDParameter()},
+					Responses: map[string]*Response{
+						"204": {Description: "Edition deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album or edition not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/prices": {
+				"get": {
+					OperationID: "getAlbumPrices",
+					Summary:     "List the price list of an album sorted by currency",
+					Tags:        []string{"albums", "prices"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Album prices", Content: negotiatedContent(arrayOf("AlbumPrice"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/prices/{currency}": {
+				"put": {
+					OperationID: "setAlbumPrice",
+					Summary:     "Set the price of an album in one currency, used instead of converting its own price",
+					Tags:        []string{"albums", "prices"},
+					Parameters:  []*Parameter{idParameter("Album ID"), currencyPathParameter()},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("AlbumPriceInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Album price", Content: negotiatedContent(ref("AlbumPrice"))},
+						"400": errorResponse("Malformed body, invalid ID, unsupported currency or validation failure"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+				"delete": {
+					OperationID: "removeAlbumPrice",
+					Summary:     "Remove the price of an album in one currency",
+					Tags:        []string{"albums", "prices"},
+					Parameters:  []*Parameter{idParameter("Album ID"), currencyPathParameter()},
+					Responses: map[string]*Response{
+						"204": {Description: "Album price removed"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album has no price in the currency"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/price-schedules": {
+				"get": {
+					OperationID: "getPriceSchedules",
+					Summary:     "List the scheduled price changes of an album sorted by start time",
+					Tags:        []string{"albums", "promotions"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Price schedules", Content: negotiatedContent(arrayOf("PriceSchedule"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "schedulePrice",
+					Summary:     "Schedule a change of an album's price; albums with editions cannot be scheduled",
+					Tags:        []string{"albums", "promotions"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("PriceScheduleInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created price schedule", Content: negotiatedContent(ref("PriceSchedule"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+			},
+			"/albums/{id}/price-schedules/{schedule_id}": {
+				"delete": {
+					OperationID: "cancelPriceSchedule",
+					Summary:     "Cancel a pending price change of an album",
+					Tags:        []string{"albums", "promotions"},
+					Parameters:  []*Parameter{idParameter("Album ID"), scheduleIDParameter()},
+					Responses: map[string]*Response{
+						"204": {Description: "Price change cancelled"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album has no such pending price change"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/effective-price": {
+				"get": {
+					OperationID: "getEffectivePrice",
+					Summary:     "Resolve what an album costs at a point in time once scheduled changes and promotions apply",
+					Tags:        []string{"albums", "promotions"},
+					Parameters: []*Parameter{
+						idParameter("Album ID"),
+						{Name: "at", In: "query", Description: "Point in time; defaults to now", Schema: &Schema{Type: "string", Format: "date-time"}},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Effective price", Content: negotiatedContent(ref("EffectivePrice"))},
+						"400": errorResponse("Invalid ID or time"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/stock": {
+				"get": {
+					OperationID: "getStock",
+					Summary:     "List the stock levels of an album, or of each of its editions",
+					Tags:        []string{"albums", "inventory"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Stock levels", Content: negotiatedContent(arrayOf("StockLevel"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": {
+					OperationID: "setLowStockThreshold",
+					Summary:     "Set the low-stock threshold of an album or one of its editions",
+					Tags:        []string{"albums", "inventory"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("StockLevelInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Stock level", Content: negotiatedContent(ref("StockLevel"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album or edition not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/albums/{id}/stock/movements": {
+				"get": {
+					OperationID: "getStockMovements",
+					Summary:     "List the stock ledger of an album and its editions, oldest first",
+					Tags:        []string{"albums", "inventory"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Stock movements", Content: negotiatedContent(arrayOf("StockMovement"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "recordStockMovement",
+					Summary:     "Receive, sell, adjust or return copies of an album or one of its editions",
+					Tags:        []string{"albums", "inventory"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("StockMovementInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Recorded movement", Content: negotiatedContent(ref("StockMovement"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album or edition not found"),
+						"409": errorResponse("Not enough copies in stock"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/stock/low": {
+				"get": {
+					OperationID: "getLowStock",
+					Summary:     "List the stock levels at or below their low-stock threshold, emptiest first",
+					Tags:        []string{"inventory"},
+					Responses: map[string]*Response{
+						"200": {Description: "Stock levels", Content: negotiatedContent(arrayOf("StockLevel"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/carts": {
+				"post": {
+					OperationID: "createCart",
+					Summary:     "Create an empty cart",
+					Tags:        []string{"orders"},
+					RequestBody: &RequestBody{Content: negotiatedContent(ref("CartInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created cart", Content: negotiatedContent(ref("Cart"))},
+						"400": errorResponse("Malformed body or unsupported currency"),
+						"401": errorResponse("Invalid or expired bearer token"),
+					},
+					// Signing in is optional; a signed-in customer's cart and its order are theirs
+					Security: []SecurityRequirement{{}, {"bearer": {}}},
+				},
+			},
+			"/carts/{id}": {
+				"get": ownedOperation(&Operation{
+					OperationID: "getCart",
+					Summary:     "Get a cart with its items",
+					Tags:        []string{"orders"},
+					Parameters:  []*Parameter{idParameter("Cart ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Cart", Content: negotiatedContent(ref("Cart"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Cart not found"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/carts/{id}/items": {
+				"put": ownedOperation(&Operation{
+					OperationID: "setCartItem",
+					Summary:     "Set the quantity of an album or edition in a cart; 0 removes it",
+					Tags:        []string{"orders"},
+					Parameters:  []*Parameter{idParameter("Cart ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("CartItem"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated cart", Content: negotiatedContent(ref("Cart"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Cart, album or edition not found"),
+						"409": errorResponse("Cart already checked out"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/carts/{id}/checkout": {
+				"post": ownedOperation(&Operation{
+					OperationID: "checkout",
+					Summary:     "Turn a cart into a pending order at the current prices, taking tracked stock",
+					Tags:        []string{"orders"},
+					Parameters:  []*Parameter{idParameter("Cart ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("Checkout"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created order", Content: negotiatedContent(ref("Order"))},
+						"400": errorResponse("Malformed body, empty cart, no tax rate for the country or missing exchange rate"),
+						"404": errorResponse("Cart, album or edition not found"),
+						"409": errorResponse("Cart already checked out or not enough copies in stock"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/orders": {
+				"get": adminOperation(&Operation{
+					OperationID: "getOrders",
+					Summary:     "List orders, newest first",
+					Tags:        []string{"orders"},
+					Parameters: []*Parameter{
+						{Name: "status", In: "query", Description: "Only orders with this status", Schema: orderStatusSchema()},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Orders", Content: negotiatedContent(arrayOf("Order"))},
+						"400": errorResponse("Invalid status"),
+					},
+				}),
+			},
+			"/orders/{id}": {
+				"get": ownedOperation(&Operation{
+					OperationID: "getOrder",
+					Summary:     "Get an order with its lines",
+					Tags:        []string{"orders"},
+					Parameters:  []*Parameter{idParameter("Order ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Order", Content: negotiatedContent(ref("Order"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Order not found"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/orders/{id}/pay": {
+				"post": ownedOperation(orderTransitionOperation("payOrder", "Charge a pending order through the payment provider", errorResponse("Payment declined"))),
+			},
+			"/orders/{id}/ship": {
+				"post": adminOperation(orderTransitionOperation("shipOrder", "Mark a paid order shipped", nil)),
+			},
+			"/orders/{id}/cancel": {
+				"post": adminOperation(orderTransitionOperation("cancelOrder", "Cancel a pending or paid order, returning its copies to stock and refunding it", nil)),
+			},
+			"/tax-rates": {
+				"get": {
+					OperationID: "getTaxRates",
+					Summary:     "List the tax rates sorted by country",
+					Tags:        []string{"orders"},
+					Responses: map[string]*Response{
+						"200": {Description: "Tax rates", Content: negotiatedContent(arrayOf("TaxRate"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": adminOperation(&Operation{
+					OperationID: "uploadTaxRates",
+					Summary:     "Replace every tax rate",
+					Tags:        []string{"orders"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(arrayOf("TaxRate"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Stored tax rates", Content: negotiatedContent(arrayOf("TaxRate"))},
+						"400": errorResponse("Malformed body or validation failure"),
+					},
+				}),
+			},
+			"/customers": {
+				"post": {
+					OperationID: "register",
+					Summary:     "Create a customer account",
+					Tags:        []string{"customers"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("Registration"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created customer", Content: negotiatedContent(ref("Customer"))},
+						"400": errorResponse("Malformed body or validation failure"),
+						"409": errorResponse("Email already registered"),
+					},
+				},
+			},
+			"/sessions": {
+				"post": {
+					OperationID: "login",
+					Summary:     "Sign in, answering with a bearer token",
+					Tags:        []string{"customers"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("Credentials"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Session", Content: negotiatedContent(ref("Session"))},
+						"400": errorResponse("Malformed body"),
+						"401": errorResponse("Wrong email or password"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"delete": signedInOperation("customers", &Operation{
+					OperationID: "logout",
+					Summary:     "Sign out, ending the session of the bearer token",
+					Responses: map[string]*Response{
+						"204": {Description: "Signed out"},
+					},
+				}),
+			},
+			"/me": {
+				"get": signedInOperation("customers", &Operation{
+					OperationID: "getMe",
+					Summary:     "Get the signed-in customer",
+					Responses: map[string]*Response{
+						"200": {Description: "Customer", Content: negotiatedContent(ref("Customer"))},
+					},
+				}),
+			},
+			"/me/library": {
+				"get": signedInOperation("customers", &Operation{
+					OperationID: "getLibrary",
+					Summary:     "List the albums the signed-in customer owns, most recently added first",
+					Responses: map[string]*Response{
+						"200": {Description: "Library", Content: negotiatedContent(arrayOf("LibraryEntry"))},
+						"500": errorResponse("Database error"),
+					},
+				}),
+				"post": signedInOperation("customers", &Operation{
+					OperationID: "addToLibrary",
+					Summary:     "Add an album to the signed-in customer's library",
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("LibraryAdd"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated library", Content: negotiatedContent(arrayOf("LibraryEntry"))},
+						"400": errorResponse("Malformed body"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/me/library/{album_id}": {
+				"delete": signedInOperation("customers", &Operation{
+					OperationID: "removeFromLibrary",
+					Summary:     "Remove an album from the signed-in customer's library",
+					Parameters: []*Parameter{
+						{Name: "album_id", In: "path", Description: "Album ID", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}},
+					},
+					Responses: map[string]*Response{
+						"204": {Description: "Removed"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not in the library"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/me/plays": {
+				"get": signedInOperation("plays", &Operation{
+					OperationID: "getPlayHistory",
+					Summary:     "List the signed-in customer's plays, most recently played first",
+					Parameters:  append(playRangeParameters(), playPageParameters()...),
+					Responses: map[string]*Response{
+						"200": {Description: "Play history", Content: negotiatedContent(ref("PlayPage"))},
+						"400": errorResponse("Invalid range, page or per_page"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+				"post": signedInOperation("plays", &Operation{
+					OperationID: "recordPlay",
+					Summary:     "Record that the signed-in customer played an album; a client_id sent before answers 200 with the stored play",
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("PlayInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Play already recorded", Content: negotiatedContent(ref("Play"))},
+						"201": {Description: "Recorded play", Content: negotiatedContent(ref("Play"))},
+						"400": errorResponse("Malformed body or invalid play"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/me/plays/batch": {
+				"post": signedInOperation("plays", &Operation{
+					OperationID: "recordPlays",
+					Summary:     "Record several plays of the signed-in customer at once, reporting which were accepted, duplicates or rejected",
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("PlayBatch"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Outcome of each play", Content: negotiatedContent(ref("PlayBatchResult"))},
+						"400": errorResponse("Malformed body, or no or too many plays"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/me/plays/top/{type}": {
+				"get": signedInOperation("plays", &Operation{
+					OperationID: "getMyTopPlays",
+					Summary:     "Rank the albums, musicians or genres the signed-in customer played most over a time range",
+					Parameters:  append([]*Parameter{topTypeParameter()}, topParameters()...),
+					Responses: map[string]*Response{
+						"200": {Description: "Listening statistics", Content: negotiatedContent(ref("PlayStats"))},
+						"400": errorResponse("Invalid type, range or limit"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/me/orders": {
+				"get": signedInOperation("customers", &Operation{
+					OperationID: "getMyOrders",
+					Summary:     "List the signed-in customer's orders, newest first",
+					Responses: map[string]*Response{
+						"200": {Description: "Orders", Content: negotiatedContent(arrayOf("Order"))},
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/albums/{id}/reviews": {
+				"get": {
+					OperationID: "getAlbumReviews",
+					Summary:     "List a page of an album's approved reviews, newest first",
+					Tags:        []string{"reviews"},
+					Parameters:  append([]*Parameter{idParameter("Album ID")}, reviewPageParameters()...),
+					Responses: map[string]*Response{
+						"200": {Description: "Page of reviews", Content: negotiatedContent(ref("ReviewPage"))},
+						"400": errorResponse("Invalid ID, page or per_page"),
+						"404": errorResponse("Album not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": signedInOperation("reviews", &Operation{
+					OperationID: "createReview",
+					Summary:     "Review an album as the signed-in customer; the review awaits moderation",
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("ReviewInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created review", Content: negotiatedContent(ref("Review"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album not found"),
+						"409": errorResponse("Album already reviewed by the customer"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/albums/{id}/reviews/{review_id}": {
+				"put": signedInOperation("reviews", &Operation{
+					OperationID: "updateReview",
+					Summary:     "Change the signed-in customer's review; it goes back to moderation",
+					Parameters:  []*Parameter{idParameter("Album ID"), reviewIDParameter()},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("ReviewInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated review", Content: negotiatedContent(ref("Review"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Album or review not found, or not the customer's"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+				"delete": signedInOperation("reviews", &Operation{
+					OperationID: "deleteReview",
+					Summary:     "Delete the signed-in customer's review",
+					Parameters:  []*Parameter{idParameter("Album ID"), reviewIDParameter()},
+					Responses: map[string]*Response{
+						"204": {Description: "Deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Review not found, or not the customer's"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/reviews": {
+				"get": adminOperation(&Operation{
+					OperationID: "getReviews",
+					Summary:     "List a page of reviews of every status for moderation, newest first",
+					Tags:        []string{"reviews"},
+					Parameters: append([]*Parameter{
+						{Name: "status", In: "query", Description: "Only reviews with this status", Schema: reviewStatusSchema()},
+					}, reviewPageParameters()...),
+					Responses: map[string]*Response{
+						"200": {Description: "Page of reviews", Content: negotiatedContent(ref("ReviewPage"))},
+						"400": errorResponse("Invalid status, page or per_page"),
+					},
+				}),
+			},
+			"/reviews/{id}/status": {
+				"put": adminOperation(&Operation{
+					OperationID: "moderateReview",
+					Summary:     "Approve or reject a review",
+					Tags:        []string{"reviews"},
+					Parameters:  []*Parameter{idParameter("Review ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("ReviewModeration"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Moderated review", Content: negotiatedContent(ref("Review"))},
+						"400": errorResponse("Malformed body, invalid ID or status"),
+						"404": errorResponse("Review not found"),
+						"500": errorResponse("Database error"),
+					},
+				}),
+			},
+			"/albums/{id}/related": {
+				"get": {
+					OperationID: "getRelatedAlbums",
+					Summary:     "List the albums listeners of an album also like, best match first, from scores refreshed periodically",
+					Tags:        []string{"albums", "recommendations"},
+					Parameters:  []*Parameter{idParameter("Album ID"), relatedLimitParameter(), currencyQueryParameter()},
+					Responses: map[string]*Response{
+						"200": {Description: "Related albums", Content: negotiatedContent(arrayOf("RelatedAlbum"))},
+						"400": errorResponse("Invalid ID, limit, unsupported currency or missing exchange rate"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+			},
+			"/musicians/{id}/related": {
+				"get": {
+					OperationID: "getRelatedMusicians",
+					Summary:     "List the musicians listeners of a musician also like, best match first, from scores refreshed periodically",
+					Tags:        []string{"musicians", "recommendations"},
+					Parameters:  []*Parameter{idParameter("Musician ID"), relatedLimitParameter()},
+					Responses: map[string]*Response{
+						"200": {Description: "Related musicians", Content: negotiatedContent(arrayOf("RelatedMusician"))},
+						"400": errorResponse("Invalid ID or limit"),
+						"404": errorResponse("Musician not found"),
+					},
+				},
+			},
+			"/musicians/{id}/collaborators": {
+				"get": {
+					OperationID: "getCollaborators",
+					Summary:     "List the musicians credited on albums with a musician, most shared albums first",
+					Tags:        []string{"musicians", "collaborations"},
+					Parameters:  []*Parameter{idParameter("Musician ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Collaborators", Content: negotiatedContent(arrayOf("Collaborator"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Musician not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/musicians/{id}/path/{to_id}": {
+				"get": {
+					OperationID: "getCollaborationPath",
+					Summary:     "Find a shortest chain of collaborations between two musicians",
+					Tags:        []string{"musicians", "collaborations"},
+					Parameters: []*Parameter{
+						idParameter("Musician ID"),
+						{Name: "to_id", In: "path", Description: "ID of the musician to reach", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Collaboration path", Content: negotiatedContent(ref("CollaborationPath"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Musician not found, or no chain of collaborations links the musicians"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/collaborations/components": {
+				"get": {
+					OperationID: "getCollaborationStats",
+					Summary:     "Summarise the groups of musicians connected by collaborations",
+					Tags:        []string{"collaborations"},
+					Responses: map[string]*Response{
+						"200": {Description: "Connected component statistics", Content: negotiatedContent(ref("CollaborationStats"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/charts/{type}": {
+				"get": {
+					OperationID: "getChart",
+					Summary:     "Rank albums by plays, copies sold or average rating over an ISO week, with movement since the week before",
+					Tags:        []string{"charts"},
+					Parameters: []*Parameter{
+						{Name: "type", In: "path", Description: "What the chart ranks on", Required: true, Schema: &Schema{Type: "string", Enum: models.ChartTypes}},
+						{Name: "period", In: "query", Description: "ISO week, e.g. 2026-W41; defaults to the last finished week",
+							Schema: &Schema{Type: "string", Pattern: `^[0-9]{4}-W[0-9]{2}$`}},
+						genreParameter(),
+						{Name: "limit", In: "query", Description: fmt.Sprintf("Most albums to return; defaults to %d", services.DefaultChartSize),
+							Schema: &Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(services.MaxChartSize)}},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Chart", Content: negotiatedContent(ref("Chart"))},
+						"400": errorResponse("Invalid type, period or limit, or a period that has not started"),
+						"404": errorResponse("Genre not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/plays/top/{type}": {
+				"get": {
+					OperationID: "getTopPlays",
+					Summary:     "Rank the albums, musicians or genres every customer played most over a time range",
+					Tags:        []string{"plays"},
+					Parameters:  append([]*Parameter{topTypeParameter()}, topParameters()...),
+					Responses: map[string]*Response{
+						"200": {Description: "Listening statistics", Content: negotiatedContent(ref("PlayStats"))},
+						"400": errorResponse("Invalid type, range or limit"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/ingest": {
+				"post": {
+					OperationID: "ingestAudio",
+					Summary:     "Add albums, musicians and tracks from the tags of uploaded MP3, FLAC or M4A files",
+					Tags:        []string{"ingest"},
+					Parameters: []*Parameter{
+						{Name: "price", In: "query", Description: fmt.Sprintf("Price of albums created from the files; defaults to %d", services.MinAlbumPrice), Schema: &Schema{Type: "number", Minimum: floatPtr(0)}},
+						currencyQueryParameter(),
+					},
+					RequestBody: &RequestBody{Required: true, Content: map[string]*MediaType{
+						"multipart/form-data": {Schema: &Schema{
+							Type:     "object",
+							Required: []string{"files"},
+							Properties: map[string]*Schema{
+								"files": {Type: "array", Description: fmt.Sprintf("From 1 to %d audio files, at most %d bytes in all", services.MaxIngestFiles, services.MaxIngestUploadBytes), Items: &Schema{Type: "string", Format: "binary"}},
+							},
+						}},
+					}},
+					Responses: map[string]*Response{
+						"200": {Description: "Per-file ingestion report", Content: negotiatedContent(ref("IngestReport"))},
+						"400": errorResponse("Unreadable upload, no or too many files, invalid price or unsupported currency"),
+						"415": errorResponse("Upload is not multipart/form-data"),
+					},
+				},
+			},
+			"/ingest/conflicts": {
+				"get": {
+					OperationID: "getIngestConflicts",
+					Summary:     "List the disagreements between ingested files and the catalog, oldest first",
+					Tags:        []string{"ingest"},
+					Parameters: []*Parameter{
+						{Name: "status", In: "query", Description: "Conflict status; defaults to open", Schema: conflictStatusSchema()},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Conflicts", Content: negotiatedContent(arrayOf("IngestConflict"))},
+						"400": errorResponse("Invalid status"),
+					},
+				},
+			},
+			"/ingest/conflicts/{id}": {
+				"put": {
+					OperationID: "reviewIngestConflict",
+					Summary:     "Resolve, dismiss or reopen an ingestion conflict",
+					Tags:        []string{"ingest"},
+					Parameters:  []*Parameter{idParameter("Conflict ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("ConflictReview"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Reviewed conflict", Content: negotiatedContent(ref("IngestConflict"))},
+						"400": errorResponse("Malformed body, invalid ID or status"),
+						"404": errorResponse("Conflict not found"),
+					},
+				},
+			},
+			"/albums/{id}/tracks": {
+				"get": {
+					OperationID: "getTracks",
+					Summary:     "List the tracks of an album in disc and track order",
+					Tags:        []string{"albums", "ingest"},
+					Parameters:  []*Parameter{idParameter("Album ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Tracks", Content: negotiatedContent(arrayOf("Track"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Album not found"),
+					},
+				},
+			},
+			"/promotions": {
+				"get": {
+					OperationID: "getPromotions",
+					Summary:     "List promotions sorted by start time",
+					Tags:        []string{"promotions"},
+					Responses: map[string]*Response{
+						"200": {Description: "Promotions", Content: negotiatedContent(arrayOf("Promotion"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"post": {
+					OperationID: "createPromotion",
+					Summary:     "Create a promotion",
+					Tags:        []string{"promotions"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("PromotionInput"))},
+					Responses: map[string]*Response{
+						"201": {Description: "Created promotion", Content: negotiatedContent(ref("Promotion"))},
+						"400": errorResponse("Malformed body or validation failure"),
+					},
+				},
+			},
+			"/promotions/{id}": {
+				"get": {
+					OperationID: "getPromotion",
+					Summary:     "Retrieve a promotion",
+					Tags:        []string{"promotions"},
+					Parameters:  []*Parameter{idParameter("Promotion ID")},
+					Responses: map[string]*Response{
+						"200": {Description: "Promotion", Content: negotiatedContent(ref("Promotion"))},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Promotion not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": {
+					OperationID: "updatePromotion",
+					Summary:     "Update a promotion",
+					Tags:        []string{"promotions"},
+					Parameters:  []*Parameter{idParameter("Promotion ID")},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(ref("PromotionInput"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Updated promotion", Content: negotiatedContent(ref("Promotion"))},
+						"400": errorResponse("Malformed body, invalid ID or validation failure"),
+						"404": errorResponse("Promotion not found"),
+					},
+				},
+				"delete": {
+					OperationID: "deletePromotion",
+					Summary:     "Delete a promotion",
+					Tags:        []string{"promotions"},
+					Parameters:  []*Parameter{idParameter("Promotion ID")},
+					Responses: map[string]*Response{
+						"204": {Description: "Promotion deleted"},
+						"400": errorResponse("Invalid ID"),
+						"404": errorResponse("Promotion not found"),
+						"500": errorResponse("Database error"),
+					},
+				},
+			},
+			"/exchange-rates": {
+				"get": {
+					OperationID: "getExchangeRates",
+					Summary:     "List the exchange rates sorted by currency",
+					Tags:        []string{"prices"},
+					Responses: map[string]*Response{
+						"200": {Description: "Exchange rates", Content: negotiatedContent(arrayOf("ExchangeRate"))},
+						"500": errorResponse("Database error"),
+					},
+				},
+				"put": adminOperation(&Operation{
+					OperationID: "uploadExchangeRates",
+					Summary:     "Replace every exchange rate",
+					Tags:        []string{"prices"},
+					RequestBody: &RequestBody{Required: true, Content: negotiatedContent(arrayOf("ExchangeRate"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Stored exchange rates", Content: negotiatedContent(arrayOf("ExchangeRate"))},
+						"400": errorResponse("Malformed body or validation failure"),
+					},
+				}),
+			},
+		},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Album": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":              {Type: "integer", ReadOnly: true},
+						"name":            {Type: "string"},
+						"release_date":    {Type: "string", Format: "date", Description: "Earliest edition's release date for albums with editions"},
+						"genre":           {Type: "string"},
+						"price":           {Type: "number", Description: "Lowest edition price for albums with editions; converted when currency is requested"},
+						"currency":        currencySchema(),
+						"effective_price": {Type: "number", ReadOnly: true, Description: "Price once scheduled changes and the best running promotion apply; only reported when albums are listed"},
+						"average_rating":  {Type: "number", ReadOnly: true, Description: "Average stars of the approved reviews; only reported when albums are listed"},
+						"review_count":    {Type: "integer", ReadOnly: true, Description: "Number of approved reviews; only reported when albums are listed"},
+						"description":     {Type: "string"},
+						"label_id":        {Type: "integer", Minimum: floatPtr(0), Description: "Absent for albums without a label"},
+						"catalog_number":  {Type: "string", Description: "Unique within the label"},
+					},
+				},
+				"AlbumInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"name", "price"},
+					Properties: map[string]*Schema{
+						"name":           {Type: "string", MinLength: intPtr(services.MinAlbumNameLength)},
+						"release_date":   {Type: "string", Format: "date"},
+						"genre":          {Type: "string"},
+						"price":          {Type: "number", Description: fmt.Sprintf("Between %d and %d %s once converted", services.MinAlbumPrice, services.MaxAlbumPrice, models.BaseCurrency)},
+						"currency":       {Type: "string", Enum: currencyCodes(), Description: "Defaults to " + models.BaseCurrency},
+						"description":    {Type: "string"},
+						"label_id":       {Type: "integer", Minimum: floatPtr(0)},
+						"catalog_number": {Type: "string", Description: "Unique within the label; requires label_id"},
+						"musician_ids":   {Type: "array", Description: "Credited as performers on the whole album", Items: &Schema{Type: "integer", Minimum: floatPtr(0)}},
+						"credits":        arrayOf("Credit"),
+					},
+				},
+				"Genre": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":        {Type: "integer", ReadOnly: true},
+						"name":      {Type: "string"},
+						"parent_id": {Type: "integer", Description: "Absent for top-level genres"},
+						"aliases":   {Type: "array", Items: &Schema{Type: "string"}},
+					},
+				},
+				"GenreInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"name"},
+					Properties: map[string]*Schema{
+						"name":      {Type: "string", Description: "Unique among genre names and aliases, ignoring case and punctuation"},
+						"parent_id": {Type: "integer", Minimum: floatPtr(0)},
+						"aliases":   {Type: "array", Description: "Alternative names the genre is matched by", Items: &Schema{Type: "string"}},
+					},
+				},
+				"AlbumGenresInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"genres"},
+					Properties: map[string]*Schema{
+						"genres": {Type: "array", Description: "Genre names or aliases; empty removes every genre", Items: &Schema{Type: "string"}},
+					},
+				},
+				"Edition": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":           {Type: "integer", ReadOnly: true},
+						"album_id":     {Type: "integer", ReadOnly: true},
+						"format":       editionFormatSchema(),
+						"name":         {Type: "string", Description: "Edition name, e.g. Deluxe"},
+						"barcode":      {Type: "string", Pattern: "^([0-9]{8}|[0-9]{12,13})$"},
+						"release_date": {Type: "string", Format: "date"},
+						"price":        {Type: "number", Description: "In the album's currency"},
+					},
+				},
+				"EditionInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"format", "price"},
+					Properties: map[string]*Schema{
+						"format":       editionFormatSchema(),
+						"name":         {Type: "string", Description: "Edition name, e.g. Deluxe"},
+						"barcode":      {Type: "string", Description: "EAN-13, EAN-8 or UPC-A code with a valid check digit; spaces and hyphens are ignored. Unique across editions"},
+						"release_date": {Type: "string", Format: "date", Description: "Defaults to the album's release date"},
+						"price":        {Type: "number", Description: fmt.Sprintf("In the album's currency; between %d and %d %s once converted", services.MinAlbumPrice, services.MaxAlbumPrice, models.BaseCurrency)},
+					},
+				},
+				"ExchangeRate": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"currency", "rate"},
+					Properties: map[string]*Schema{
+						"currency": currencySchema(),
+						"rate":     {Type: "number", Description: "Units of the currency per unit of " + models.BaseCurrency + "; must be positive, and 1 for " + models.BaseCurrency},
+					},
+				},
+				"AlbumPrice": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"album_id": {Type: "integer", ReadOnly: true},
+						"currency": currencySchema(),
+						"price":    {Type: "number"},
+					},
+				},
+				"AlbumPriceInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"price"},
+					Properties: map[string]*Schema{
+						"price": {Type: "number", Description: "Positive, with no more decimals than the currency's minor unit allows"},
+					},
+				},
+				"Promotion": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties:           promotionProperties(true),
+				},
+				"PromotionInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"name", "kind", "starts_at", "ends_at"},
+					Description:          "Exactly one of album_id, genre_id, label_id or musician_id is required",
+					Properties:           promotionProperties(false),
+				},
+				"PriceSchedule": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":         {Type: "integer", ReadOnly: true},
+						"album_id":   {Type: "integer", ReadOnly: true},
+						"price":      {Type: "number", Description: "In the album's currency"},
+						"starts_at":  {Type: "string", Format: "date-time"},
+						"applied_at": {Type: "string", Format: "date-time", Description: "Absent while the change is pending"},
+					},
+				},
+				"PriceScheduleInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"price", "starts_at"},
+					Properties: map[string]*Schema{
+						"price":     {Type: "number", Description: fmt.Sprintf("In the album's currency; between %d and %d %s once converted", services.MinAlbumPrice, services.MaxAlbumPrice, models.BaseCurrency)},
+						"starts_at": {Type: "string", Format: "date-time"},
+					},
+				},
+				"EffectivePrice": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"album_id":        {Type: "integer"},
+						"at":              {Type: "string", Format: "date-time"},
+						"currency":        currencySchema(),
+						"price":           {Type: "number", Description: "The album's own price, including scheduled changes due by then"},
+						"effective_price": {Type: "number"},
+						"promotion_id":    {Type: "integer", Description: "Absent when no promotion applies"},
+					},
+				},
+				"StockLevel": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"album_id":            {Type: "integer"},
+						"edition_id":          {Type: "integer", Description: "Absent for albums stocked as a whole"},
+						"quantity":            {Type: "integer", Minimum: floatPtr(0)},
+						"low_stock_threshold": {Type: "integer", Minimum: floatPtr(0)},
+						"low_stock":           {Type: "boolean", Description: "Whether the quantity is at or below a non-zero threshold"},
+					},
+				},
+				"StockLevelInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"low_stock_threshold"},
+					Properties: map[string]*Schema{
+						"edition_id":          {Type: "integer", Minimum: floatPtr(0), Description: "Required for albums with editions"},
+						"low_stock_threshold": {Type: "integer", Minimum: floatPtr(0), Description: "0 disables the alert"},
+					},
+				},
+				"StockMovement": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":         {Type: "integer", ReadOnly: true},
+						"album_id":   {Type: "integer", ReadOnly: true},
+						"edition_id": {Type: "integer"},
+						"kind":       movementKindSchema(),
+						"quantity":   {Type: "integer"},
+						"balance":    {Type: "integer", ReadOnly: true, Description: "Stock level after the movement"},
+						"note":       {Type: "string"},
+						"created_at": {Type: "string", Format: "date-time", ReadOnly: true},
+					},
+				},
+				"StockMovementInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"kind", "quantity"},
+					Properties: map[string]*Schema{
+						"edition_id": {Type: "integer", Minimum: floatPtr(0), Description: "Required for albums with editions"},
+						"kind":       movementKindSchema(),
+						"quantity":   {Type: "integer", Description: "Copies received, sold or returned; adjustments are negative to remove copies"},
+						"note":       {Type: "string"},
+					},
+				},
+				"Cart": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":             {Type: "integer", ReadOnly: true},
+						"currency":       currencySchema(),
+						"items":          arrayOf("CartItem"),
+						"created_at":     {Type: "string", Format: "date-time"},
+						"checked_out_at": {Type: "string", Format: "date-time", Description: "Absent until the cart has become an order"},
+						"customer_id":    {Type: "integer", Description: "Absent for carts created without signing in"},
+					},
+				},
+				"CartInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"currency": {Type: "string", Enum: currencyCodes(), Description: "Currency the cart is checked out in; defaults to " + models.BaseCurrency},
+					},
+				},
+				"CartItem": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"album_id", "quantity"},
+					Properties: map[string]*Schema{
+						"album_id":   {Type: "integer", Minimum: floatPtr(0)},
+						"edition_id": {Type: "integer", Minimum: floatPtr(0), Description: "Required for albums with editions"},
+						"quantity":   {Type: "integer", Minimum: floatPtr(0)},
+					},
+				},
+				"Checkout": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"country"},
+					Properties: map[string]*Schema{
+						"country": {Type: "string", Pattern: "^[A-Za-z]{2}$", Description: "Country whose tax rate applies"},
+					},
+				},
+				"Order": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":                {Type: "integer"},
+						"cart_id":           {Type: "integer"},
+						"status":            orderStatusSchema(),
+						"country":           {Type: "string"},
+						"currency":          currencySchema(),
+						"lines":             arrayOf("OrderLine"),
+						"subtotal":          {Type: "number"},
+						"tax_rate":          {Type: "number"},
+						"tax":               {Type: "number"},
+						"total":             {Type: "number"},
+						"payment_reference": {Type: "string", Description: "Absent until paid"},
+						"created_at":        {Type: "string", Format: "date-time"},
+						"updated_at":        {Type: "string", Format: "date-time"},
+						"customer_id":       {Type: "integer", Description: "Absent for orders from carts created without signing in"},
+					},
+				},
+				"OrderLine": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"album_id":   {Type: "integer"},
+						"edition_id": {Type: "integer"},
+						"name":       {Type: "string"},
+						"quantity":   {Type: "integer"},
+						"unit_price": {Type: "number", Description: "Price at checkout, after promotions"},
+						"total":      {Type: "number"},
+					},
+				},
+				"TaxRate": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"country", "rate"},
+					Properties: map[string]*Schema{
+						"country": {Type: "string", Pattern: "^[A-Za-z]{2}$"},
+						"rate":    {Type: "number", Minimum: floatPtr(0), Maximum: floatPtr(1), Description: "Fraction of the subtotal, e.g. 0.2 for 20%"},
+					},
+				},
+				"Customer": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":         {Type: "integer"},
+						"email":      {Type: "string", Format: "email"},
+						"name":       {Type: "string"},
+						"created_at": {Type: "string", Format: "date-time"},
+						"admin":      {Type: "boolean", Description: "Staff accounts, which may list every order and ship or cancel them"},
+					},
+				},
+				"Registration": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"email", "name", "password"},
+					Properties: map[string]*Schema{
+						"email":    {Type: "string", Format: "email"},
+						"name":     {Type: "string", MinLength: intPtr(1)},
+						"password": {Type: "string", MinLength: intPtr(services.MinPasswordLength), Description: fmt.Sprintf("At most %d bytes", services.MaxPasswordBytes)},
+					},
+				},
+				"Credentials": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"email", "password"},
+					Properties: map[string]*Schema{
+						"email":    {Type: "string"},
+						"password": {Type: "string"},
+					},
+				},
+				"Session": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"token":      {Type: "string", Description: "Bearer token for the Authorization header; only reported here"},
+						"expires_at": {Type: "string", Format: "date-time"},
+						"customer":   ref("Customer"),
+					},
+				},
+				"LibraryEntry": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"album":    ref("Album"),
+						"source":   {Type: "string", Enum: []string{models.LibraryFromOrder, models.LibraryManual}},
+						"order_id": {Type: "integer", Description: "The paid order the album came from, for source order"},
+						"added_at": {Type: "string", Format: "date-time"},
+					},
+				},
+				"LibraryAdd": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"album_id"},
+					Properties: map[string]*Schema{
+						"album_id": {Type: "integer", Minimum: floatPtr(0)},
+					},
+				},
+				"Review": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":          {Type: "integer"},
+						"album_id":    {Type: "integer"},
+						"customer_id": {Type: "integer"},
+						"author":      {Type: "string", Description: "The reviewing customer's name"},
+						"rating":      {Type: "integer", Minimum: floatPtr(services.MinRating), Maximum: floatPtr(services.MaxRating)},
+						"text":        {Type: "string"},
+						"status":      reviewStatusSchema(),
+						"created_at":  {Type: "string", Format: "date-time"},
+						"updated_at":  {Type: "string", Format: "date-time"},
+					},
+				},
+				"ReviewInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"rating"},
+					Properties: map[string]*Schema{
+						"rating": {Type: "integer", Minimum: floatPtr(services.MinRating), Maximum: floatPtr(services.MaxRating), Description: "Stars"},
+						"text":   {Type: "string", Description: fmt.Sprintf("At most %d characters", services.MaxReviewLength)},
+					},
+				},
+				"ReviewModeration": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"status"},
+					Properties: map[string]*Schema{
+						"status": reviewStatusSchema(),
+					},
+				},
+				"ReviewPage": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"reviews":  arrayOf("Review"),
+						"page":     {Type: "integer"},
+						"per_page": {Type: "integer"},
+						"total":    {Type: "integer", Description: "Reviews on every page"},
+					},
+				},
+				"Label": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":        {Type: "integer", ReadOnly: true},
+						"name":      {Type: "string"},
+						"country":   {Type: "string", Pattern: "^[A-Za-z]{2}$"},
+						"parent_id": {Type: "integer", Description: "Absent for labels without a parent label"},
+					},
+				},
+				"LabelInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"name"},
+					Properties: map[string]*Schema{
+						"name":      {Type: "string", MinLength: intPtr(services.MinLabelNameLength)},
+						"country":   {Type: "string", Description: "ISO 3166-1 alpha-2 code", Pattern: "^[A-Za-z]{2}$"},
+						"parent_id": {Type: "integer", Minimum: floatPtr(0)},
+					},
+				},
+				"Credit": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"musician_id"},
+					Properties: map[string]*Schema{
+						"musician_id": {Type: "integer", Minimum: floatPtr(1)},
+						"role":        creditRoleSchema(),
+						"instrument":  {Type: "string"},
+						"track":       {Type: "integer", Minimum: floatPtr(0), Description: "Track number the credit is limited to; absent or 0 for the whole album"},
+					},
+				},
+				"CreditedAlbum": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":             {Type: "integer"},
+						"name":           {Type: "string"},
+						"release_date":   {Type: "string", Format: "date"},
+						"genre":          {Type: "string"},
+						"price":          {Type: "number"},
+						"description":    {Type: "string"},
+						"label_id":       {Type: "integer"},
+						"catalog_number": {Type: "string"},
+						"credits":        arrayOf("Credit"),
+					},
+				},
+				"CreditedMusician": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":            {Type: "integer"},
+						"name":          {Type: "string"},
+						"musician_type": {Type: "string"},
+						"kind":          musicianKindSchema(),
+						"credits":       arrayOf("Credit"),
+					},
+				},
+				"RelatedAlbum": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":              {Type: "integer"},
+						"name":            {Type: "string"},
+						"release_date":    {Type: "string", Format: "date"},
+						"genre":           {Type: "string"},
+						"price":           {Type: "number"},
+						"currency":        currencySchema(),
+						"effective_price": {Type: "number"},
+						"average_rating":  {Type: "number"},
+						"review_count":    {Type: "integer"},
+						"description":     {Type: "string"},
+						"label_id":        {Type: "integer"},
+						"catalog_number":  {Type: "string"},
+						"score":           recommendationScoreSchema(),
+						"reasons":         recommendationReasonsSchema(),
+					},
+				},
+				"RelatedMusician": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":            {Type: "integer"},
+						"name":          {Type: "string"},
+						"musician_type": {Type: "string"},
+						"kind":          musicianKindSchema(),
+						"score":         recommendationScoreSchema(),
+						"reasons":       recommendationReasonsSchema(),
+					},
+				},
+				"Collaborator": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":            {Type: "integer"},
+						"name":          {Type: "string"},
+						"musician_type": {Type: "string"},
+						"kind":          musicianKindSchema(),
+						"shared_albums": {Type: "integer", Description: "Albums both musicians are credited on"},
+					},
+				},
+				"CollaborationPath": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"degrees": {Type: "integer", Description: "Collaborations separating the musicians"},
+						"steps": {
+							Type:        "array",
+							Description: "Musicians from the first to the second, each with the album shared with the one before",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"musician": ref("Musician"),
+									"via":      ref("Album"),
+								},
+							},
+						},
+					},
+				},
+				"CollaborationStats": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"musicians":         {Type: "integer"},
+						"collaborations":    {Type: "integer", Description: "Distinct pairs of musicians credited on the same album"},
+						"components":        {Type: "integer", Description: "Groups of two or more connected musicians"},
+						"largest_component": {Type: "integer"},
+						"isolated":          {Type: "integer", Description: "Musicians without collaborators"},
+						"component_sizes": {
+							Type: "array",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"size":  {Type: "integer"},
+									"count": {Type: "integer"},
+								},
+							},
+						},
+					},
+				},
+				"Chart": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"type":        {Type: "string", Enum: models.ChartTypes},
+						"genre":       {Type: "string", Description: "Absent for the overall chart"},
+						"period":      {Type: "string"},
+						"final":       {Type: "boolean", Description: "Whether the week is over and the chart is stored"},
+						"computed_at": {Type: "string", Format: "date-time"},
+						"entries": {
+							Type: "array",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"position":          {Type: "integer"},
+									"album":             ref("Album"),
+									"value":             {Type: "number", Description: "Plays, copies sold or average rating"},
+									"count":             {Type: "integer", Description: "Plays, copies or ratings the value comes from"},
+									"previous_position": {Type: "integer", Description: "Absent for albums new to the chart"},
+									"movement":          {Type: "string", Enum: []string{models.MovementNew, models.MovementUp, models.MovementDown, models.MovementSame}},
+								},
+							},
+						},
+					},
+				},
+				"Play": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":               {Type: "integer"},
+						"client_id":        {Type: "string"},
+						"customer_id":      {Type: "integer"},
+						"album_id":         {Type: "integer"},
+						"album_name":       {Type: "string"},
+						"track":            {Type: "integer", Description: "Track number played; absent for the whole album"},
+						"duration_seconds": {Type: "integer"},
+						"played_at":        {Type: "string", Format: "date-time"},
+					},
+				},
+				"PlayInput": playInputSchema(),
+				"PlayBatch": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"plays"},
+					Properties: map[string]*Schema{
+						"plays": {Type: "array", Description: fmt.Sprintf("From 1 to %d plays", services.MaxPlayBatch), Items: playInputSchema()},
+					},
+				},
+				"PlayBatchResult": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"accepted":   {Type: "integer"},
+						"duplicates": {Type: "integer"},
+						"rejected":   {Type: "integer"},
+						"results": {
+							Type:        "array",
+							Description: "Outcome of each play, in the order they were sent",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"client_id": {Type: "string"},
+									"status":    {Type: "string", Enum: []string{models.PlayAccepted, models.PlayDuplicate, models.PlayRejected}},
+									"play_id":   {Type: "integer", Description: "Stored play; absent for rejected plays"},
+									"error":     {Type: "string", Description: "Why the play was rejected"},
+								},
+							},
+						},
+					},
+				},
+				"PlayPage": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"plays":    arrayOf("Play"),
+						"page":     {Type: "integer"},
+						"per_page": {Type: "integer"},
+						"total":    {Type: "integer", Description: "Plays on every page"},
+					},
+				},
+				"PlayStats": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"type":  {Type: "string", Enum: models.TopTypes},
+						"from":  {Type: "string", Format: "date-time", Description: "Absent when the range has no start"},
+						"to":    {Type: "string", Format: "date-time"},
+						"plays": {Type: "integer", Description: "Plays in the range"},
+						"top": {
+							Type: "array",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"id":        {Type: "integer"},
+									"name":      {Type: "string"},
+									"plays":     {Type: "integer"},
+									"listeners": {Type: "integer", Description: "Customers who played it"},
+								},
+							},
+						},
+					},
+				},
+				"Track": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":               {Type: "integer"},
+						"album_id":         {Type: "integer"},
+						"disc":             {Type: "integer"},
+						"number":           {Type: "integer"},
+						"title":            {Type: "string"},
+						"duration_seconds": {Type: "integer", Description: "Absent when the file's metadata does not record it"},
+						"missing_since":    {Type: "string", Format: "date-time", Description: "When the last library file of the track went missing; absent while one is found"},
+					},
+				},
+				"IngestConflict": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":          {Type: "integer"},
+						"file_name":   {Type: "string"},
+						"kind":        {Type: "string", Enum: []string{models.ConflictAlbum, models.ConflictMusician, models.ConflictTrack}},
+						"entity_id":   {Type: "integer", Description: "Album, musician or track the file disagrees with; absent when several match it"},
+						"field":       {Type: "string"},
+						"existing":    {Type: "string"},
+						"incoming":    {Type: "string"},
+						"status":      conflictStatusSchema(),
+						"created_at":  {Type: "string", Format: "date-time"},
+						"reviewed_at": {Type: "string", Format: "date-time"},
+					},
+				},
+				"ConflictReview": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"status"},
+					Properties: map[string]*Schema{
+						"status": conflictStatusSchema(),
+					},
+				},
+				"IngestReport": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"created":   {Type: "integer"},
+						"matched":   {Type: "integer"},
+						"conflicts": {Type: "integer"},
+						"failed":    {Type: "integer"},
+						"files": {Type: "array", Items: &Schema{
+							Type:                 "object",
+							AdditionalProperties: boolPtr(false),
+							Properties: map[string]*Schema{
+								"file_name":    {Type: "string"},
+								"format":       {Type: "string", Enum: []string{tags.FormatID3, tags.FormatFLAC, tags.FormatMP4}},
+								"status":       {Type: "string", Enum: []string{models.IngestCreated, models.IngestMatched, models.IngestConflicted, models.IngestFailed}},
+								"album_id":     {Type: "integer"},
+								"track_id":     {Type: "integer"},
+								"musician_ids": {Type: "array", Items: &Schema{Type: "integer"}},
+								"conflicts":    arrayOf("IngestConflict"),
+								"error":        {Type: "string"},
+							},
+						}},
+					},
+				},
+				"Musician": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":            {Type: "integer", ReadOnly: true},
+						"name":          {Type: "string"},
+						"musician_type": {Type: "string"},
+						"kind":          musicianKindSchema(),
+					},
+				},
+				"MusicianInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"name"},
+					Properties: map[string]*Schema{
+						"name":          {Type: "string", MinLength: intPtr(services.MinMusicianNameLength)},
+						"musician_type": {Type: "string"},
+						"kind":          musicianKindSchema(),
+					},
+				},
+				"Membership": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Properties: map[string]*Schema{
+						"id":         {Type: "integer", ReadOnly: true},
+						"group_id":   {Type: "integer", ReadOnly: true},
+						"member_id":  {Type: "integer"},
+						"member":     ref("Musician"),
+						"instrument": {Type: "string"},
+						"joined_on":  {Type: "string", Format: "date"},
+						"left_on":    {Type: "string", Format: "date", Description: "Absent while the member is still in the group"},
+					},
+				},
+				"MembershipInput": {
+					Type:                 "object",
+					AdditionalProperties: boolPtr(false),
+					Required:             []string{"member_id", "joined_on"},
+					Properties: map[string]*Schema{
+						"member_id":  {Type: "integer", Minimum: floatPtr(0)},
+						"instrument": {Type: "string"},
+						"joined_on":  {Type: "string", Format: "date"},
+						"left_on":    {Type: "string", Format: "date"},
+					},
+				},
+				"GraphQLRequest": {
+					Type:     "object",
+					Required: []string{"query"},
+					Properties: map[string]*Schema{
+						"query":         {Type: "string"},
+						"operationName": {Type: "string"},
+						"variables":     {Type: "object"},
+					},
+				},
+				"GraphQLResponse": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"data":   {Type: "object"},
+						"errors": {Type: "array", Items: &Schema{Type: "object"}},
+					},
+				},
+				"ImportReport": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"created": {Type: "integer"},
+						"skipped": {Type: "integer"},
+						"failed":  {Type: "integer"},
+						"rows": {Type: "array", Items: &Schema{
+							Type: "object",
+							Properties: map[string]*Schema{
+								"row":    {Type: "integer"},
+								"status": {Type: "string", Enum: []string{models.ImportCreated, models.ImportSkipped, models.ImportFailed}},
+								"id":     {Type: "integer"},
+								"reason": {Type: "string"},
+							},
+						}},
+					},
+				},
+				"AlbumWithMusicians": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"id":             {Type: "integer"},
+						"name":           {Type: "string"},
+						"release_date":   {Type: "string", Format: "date"},
+						"genre":          {Type: "string"},
+						"price":          {Type: "number"},
+						"description":    {Type: "string"},
+						"label_id":       {Type: "integer"},
+						"catalog_number": {Type: "string"},
+						"musicians":      arrayOf("Musician"),
+					},
+				},
+				"Error": {Type: "string", Description: "Plain-text error message"},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer", Description: "Session token from POST /sessions"},
+			},
+		},
+	}
+
+	addCSVSchemas(doc, "AlbumInput", "CreditedAlbum", "CreditedMusician")
+	addNegotiationResponses(doc)
+	return doc
+}
+
+// addCSVSchemas gives the CSV bodies of the named schemas a variant without credits, which have no CSV
+// form, named after the schema with a CSV suffix.
+func addCSVSchemas(doc *Document, names ...string) {
+	variants := make(map[string]*Schema)
+	for _, name := range names {
+		schema := *doc.Components.Schemas[name]
+		schema.Properties = make(map[string]*Schema)
+		for property, value := range doc.Components.Schemas[name].Properties {
+			if property != "credits" {
+				schema.Properties[property] = value
+			}
+		}
+		doc.Components.Schemas[name+"CSV"] = &schema
+		variants[ref(name).Ref] = ref(name + "CSV")
+	}
+
+	csvSchema := func(schema *Schema) *Schema {
+		if variant, ok := variants[schema.Ref]; ok {
+			return variant
+		}
+		if schema.Type == "array" && schema.Items != nil {
+			if variant, ok := variants[schema.Items.Ref]; ok {
+				return &Schema{Type: "array", Items: variant}
+			}
+		}
+		return schema
+	}
+	for _, item := range doc.Paths {
+		for _, op := range *item {
+			var contents []map[string]*MediaType
+			if op.RequestBody != nil {
+				contents = append(contents, op.RequestBody.Content)
+			}
+			for _, response := range op.Responses {
+				contents = append(contents, response.Content)
+			}
+			for _, content := range contents {
+				if media, ok := content[mediaCSV]; ok && media.Schema != nil {
+					content[mediaCSV] = &MediaType{Schema: csvSchema(media.Schema)}
+				}
+			}
+		}
+	}
+}
+
+// addNegotiationResponses documents the 406 and 415 responses of every operation
+// whose body is negotiated between JSON, XML and CSV.
+func addNegotiationResponses(doc *Document) {
+	for _, item := range doc.Paths {
+		for _, op := range *item {
+			negotiated := false
+			for _, response := range op.Responses {
+				if _, ok := response.Content["application/xml"]; ok {
+					negotiated = true
+				}
+			}
+			if !negotiated {
+				continue
+			}
+
+			op.Responses["406"] = errorResponse("None of the representations in Accept is supported")
+			if op.RequestBody != nil {
+				op.Responses["415"] = errorResponse("Unsupported request Content-Type")
+			}
+		}
+	}
+}
+
+// ServeSpec handles requests for the OpenAPI document.
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Spec())
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func arrayOf(name string) *Schema {
+	return &Schema{Type: "array", Items: ref(name)}
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+// negotiatedContent lists the representations the album and musician endpoints read and write.
+func negotiatedContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: schema},
+		"application/xml":  {Schema: schema},
+		"text/csv":         {Schema: schema},
+	}
+}
+
+func textResponse(description string) *Response {
+	return &Response{Description: description, Content: map[string]*MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}}
+}
+
+func errorResponse(description string) *Response {
+	return &Response{Description: description, Content: map[string]*MediaType{"text/plain": {Schema: ref("Error")}}}
+}
+
+func idParameter(description string) *Parameter {
+	return &Parameter{Name: "id", In: "path", Description: description, Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}}
+}
+
+func membershipIDParameter() *Parameter {
+	return &Parameter{Name: "membership_id", In: "path", Description: "Membership ID", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}}
+}
+
+func roleParameter() *Parameter {
+	return &Parameter{Name: "role", In: "query", Description: "Only credits with this role", Schema: creditRoleSchema()}
+}
+
+func reviewIDParameter() *Parameter {
+	return &Parameter{Name: "review_id", In: "path", Description: "Review ID", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}}
+}
+
+// reviewPageParameters describes the page and per_page query parameters of review listings.
+func reviewPageParameters() []*Parameter {
+	return []*Parameter{
+		{Name: "page", In: "query", Description: "Page number, from 1", Schema: &Schema{Type: "integer", Minimum: floatPtr(1)}},
+		{Name: "per_page", In: "query", Description: fmt.Sprintf("Reviews per page; defaults to %d", services.DefaultReviewsPage),
+			Schema: &Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(services.MaxReviewsPage)}},
+	}
+}
+
+func reviewStatusSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.ReviewStatuses}
+}
+
+// relatedLimitParameter describes the limit query parameter of related album and musician listings.
+func relatedLimitParameter() *Parameter {
+	return &Parameter{Name: "limit", In: "query", Description: fmt.Sprintf("Most results to return; defaults to %d", services.DefaultRelated),
+		Schema: &Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(services.MaxRelated)}}
+}
+
+func recommendationScoreSchema() *Schema {
+	return &Schema{Type: "number", Minimum: floatPtr(0), Maximum: floatPtr(1), Description: "How strongly the result is related, from 0 to 1"}
+}
+
+func recommendationReasonsSchema() *Schema {
+	return &Schema{Type: "array", Description: "Signals the result shares with the album or musician",
+		Items: &Schema{Type: "string", Enum: []string{models.ReasonMusicians, models.ReasonGenre, models.ReasonEra, models.ReasonListeners}}}
+}
+
+func playInputSchema() *Schema {
+	return &Schema{
+		Type:                 "object",
+		AdditionalProperties: boolPtr(false),
+		Required:             []string{"client_id", "album_id"},
+		Properties: map[string]*Schema{
+			"client_id": {Type: "string", MinLength: intPtr(1),
+				Description: fmt.Sprintf("The client's identifier for the play, at most %d characters; plays sent again with it are stored once", services.MaxClientIDLength)},
+			"album_id":         {Type: "integer", Minimum: floatPtr(1)},
+			"track":            {Type: "integer", Minimum: floatPtr(0), Description: "Track number played; 0 or absent for the whole album"},
+			"duration_seconds": {Type: "integer", Minimum: floatPtr(0)},
+			"played_at":        {Type: "string", Format: "date-time", Description: "Defaults to now"},
+		},
+	}
+}
+
+// playRangeParameters describes the from and to query parameters bounding plays by when they were played.
+func playRangeParameters() []*Parameter {
+	return []*Parameter{
+		{Name: "from", In: "query", Description: "Only plays at or after this time", Schema: &Schema{Type: "string", Format: "date-time"}},
+		{Name: "to", In: "query", Description: "Only plays before this time", Schema: &Schema{Type: "string", Format: "date-time"}},
+	}
+}
+
+func playPageParameters() []*Parameter {
+	return []*Parameter{
+		{Name: "page", In: "query", Description: "Page number, from 1", Schema: &Schema{Type: "integer", Minimum: floatPtr(1)}},
+		{Name: "per_page", In: "query", Description: fmt.Sprintf("Plays per page; defaults to %d", services.DefaultPlaysPage),
+			Schema: &Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(services.MaxPlaysPage)}},
+	}
+}
+
+func topTypeParameter() *Parameter {
+	return &Parameter{Name: "type", In: "path", Description: "What to rank", Required: true, Schema: &Schema{Type: "string", Enum: models.TopTypes}}
+}
+
+// topParameters describes the range and limit query parameters of listening statistics; the range ends now
+// unless to is given.
+func topParameters() []*Parameter {
+	return append(playRangeParameters(), &Parameter{Name: "limit", In: "query", Description: fmt.Sprintf("Most results to return; defaults to %d", services.DefaultTopPlays),
+		Schema: &Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(services.MaxTopPlays)}})
+}
+
+// conflictStatusSchema describes the review status of an ingestion conflict.
+func conflictStatusSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.ConflictStatuses}
+}
+
+func editionIDParameter() *Parameter {
+	return &Parameter{Name: "edition_id", In: "path", Description: "Edition ID", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}}
+}
+
+func editionFormatSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.EditionFormats}
+}
+
+func genreParameter() *Parameter {
+	return &Parameter{Name: "genre", In: "query", Description: "Only albums tagged with this genre or one of its descendants, by name or alias", Schema: &Schema{Type: "string"}}
+}
+
+func scheduleIDParameter() *Parameter {
+	return &Parameter{Name: "schedule_id", In: "path", Description: "Price schedule ID", Required: true, Schema: &Schema{Type: "integer", Minimum: floatPtr(0)}}
+}
+
+func inStockParameter() *Parameter {
+	return &Parameter{Name: "in_stock", In: "query", Description: "Only albums with (true) or without (false) copies of the album or an edition in stock; albums whose stock is not tracked count as in stock", Schema: &Schema{Type: "boolean"}}
+}
+
+func movementKindSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.MovementKinds}
+}
+
+func orderStatusSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.OrderStatuses}
+}
+
+// orderTransitionOperation describes an endpoint moving an order on to another status. declined, when set,
+// documents a 402 response.
+func orderTransitionOperation(operationID, summary string, declined *Response) *Operation {
+	operation := &Operation{
+		OperationID: operationID,
+		Summary:     summary,
+		Tags:        []string{"orders"},
+		Parameters:  []*Parameter{idParameter("Order ID")},
+		Responses: map[string]*Response{
+			"200": {Description: "Updated order", Content: negotiatedContent(ref("Order"))},
+			"400": errorResponse("Invalid ID"),
+			"404": errorResponse("Order not found"),
+			"409": errorResponse("The order's status does not allow the change"),
+			"500": errorResponse("Database or payment provider error"),
+		},
+	}
+	if declined != nil {
+		operation.Responses["402"] = declined
+	}
+	return operation
+}
+
+// signedInOperation completes an operation that needs a signed-in customer with its tag, bearer token
+// requirement and 401 response.
+func signedInOperation(tag string, operation *Operation) *Operation {
+	operation.Tags = []string{tag}
+	operation.Security = []SecurityRequirement{{"bearer": {}}}
+	operation.Responses["401"] = errorResponse("Missing, invalid or expired bearer token")
+	return operation
+}
+
+// ownedOperation completes an operation on a cart or order that may belong to a customer. Guest carts and
+// orders need no session; a customer's need theirs or an admin's, and are not found for anyone else.
+func ownedOperation(operation *Operation) *Operation {
+	operation.Security = []SecurityRequirement{{}, {"bearer": {}}}
+	operation.Responses["401"] = errorResponse("Invalid or expired bearer token")
+	operation.Responses["404"].Description += ", or it belongs to another customer"
+	return operation
+}
+
+// adminOperation completes an operation only admins may use with its bearer token requirement and 401 and 403
+// responses.
+func adminOperation(operation *Operation) *Operation {
+	operation.Security = []SecurityRequirement{{"bearer": {}}}
+	operation.Responses["401"] = errorResponse("Missing, invalid or expired bearer token")
+	operation.Responses["403"] = errorResponse("The customer is not an admin")
+	return operation
+}
+
+// promotionProperties describes a promotion; readOnly adds the fields only present in responses.
+func promotionProperties(readOnly bool) map[string]*Schema {
+	properties := map[string]*Schema{
+		"name":        {Type: "string", MinLength: intPtr(1)},
+		"kind":        {Type: "string", Enum: models.PromotionKinds},
+		"percent_off": {Type: "number", Minimum: floatPtr(0), Maximum: floatPtr(100), Description: "Discount of percentage promotions"},
+		"amount_off":  {Type: "number", Minimum: floatPtr(0), Description: "Discount of fixed promotions, in currency"},
+		"currency":    {Type: "string", Enum: currencyCodes(), Description: "Currency of amount_off; defaults to " + models.BaseCurrency},
+		"album_id":    {Type: "integer", Minimum: floatPtr(0)},
+		"genre_id":    {Type: "integer", Minimum: floatPtr(0), Description: "Also covers descendant genres"},
+		"label_id":    {Type: "integer", Minimum: floatPtr(0)},
+		"musician_id": {Type: "integer", Minimum: floatPtr(0)},
+		"starts_at":   {Type: "string", Format: "date-time", Description: "Inclusive"},
+		"ends_at":     {Type: "string", Format: "date-time", Description: "Exclusive"},
+	}
+	if readOnly {
+		properties["id"] = &Schema{Type: "integer", ReadOnly: true}
+	}
+	return properties
+}
+
+func currencySchema() *Schema {
+	return &Schema{Type: "string", Enum: currencyCodes()}
+}
+
+func currencyQueryParameter() *Parameter {
+	return &Parameter{Name: "currency", In: "query", Description: "Report prices in this currency, from the album's price list or converted through the exchange rates", Schema: currencySchema()}
+}
+
+func currencyPathParameter() *Parameter {
+	return &Parameter{Name: "currency", In: "path", Description: "ISO 4217 currency code", Required: true, Schema: currencySchema()}
+}
+
+// currencyCodes lists the supported currency codes sorted alphabetically.
+func currencyCodes() []string {
+	codes := make([]string, 0, len(models.CurrencyExponents))
+	for code := range models.CurrencyExponents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func creditRoleSchema() *Schema {
+	return &Schema{Type: "string", Enum: models.CreditRoles}
+}
+
+func musicianKindSchema() *Schema {
+	return &Schema{Type: "string", Enum: []string{models.MusicianKindPerson, models.MusicianKindGroup}}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
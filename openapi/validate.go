@@ -0,0 +1,364 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Violation is a single way in which a request does not match the spec.
+type Violation struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// ValidationError is the body returned when a request is rejected.
+type ValidationError struct {
+	Error      string      `json:"error"`
+	Violations []Violation `json:"violations"`
+}
+
+// Strip mux regular expressions so "/albums/{id:[0-9]+}" matches the spec path "/albums/{id}".
+var muxVariable = regexp.MustCompile(`\{([^}:]+):[^}]+\}`)
+
+// SpecPath converts a mux path template to the matching OpenAPI path.
+func SpecPath(template string) string {
+	return muxVariable.ReplaceAllString(template, "{$1}")
+}
+
+// ValidateRequests returns middleware that checks path and query parameters and
+// JSON, XML and CSV request bodies decoded into objects or arrays against the document before the controller runs.
+// Requests that do not match are rejected with 400 and a list of every violation.
+func ValidateRequests(doc *Document) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			op := doc.Operation(SpecPath(template), r.Method)
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			violations := doc.validateParameters(op, r)
+
+			// Only bodies the controllers decode are buffered; uploads such as imports are streamed to the controller untouched
+			if mediaType := bodyMediaType(r.Header.Get("Content-Type")); op.RequestBody != nil && doc.decoded(op.RequestBody.Content[mediaType]) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				violations = append(violations, doc.validateBody(op.RequestBody, mediaType, body)...)
+			}
+
+			if len(violations) > 0 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(ValidationError{Error: "request does not match the API specification", Violations: violations})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (d *Document) validateParameters(op *Operation, r *http.Request) []Violation {
+	var violations []Violation
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	for _, param := range op.Parameters {
+		var raw string
+		var present bool
+		switch param.In {
+		case "path":
+			raw, present = vars[param.Name]
+		case "query":
+			present = query.Has(param.Name)
+			raw = query.Get(param.Name)
+		default:
+			continue
+		}
+
+		location := param.In + "." + param.Name
+		if !present {
+			if param.Required {
+				violations = append(violations, Violation{location, "is required"})
+			}
+			continue
+		}
+
+		value, ok := parseParameter(raw, param.Schema)
+		if !ok {
+			violations = append(violations, Violation{location, fmt.Sprintf("must be of type %s", param.Schema.Type)})
+			continue
+		}
+		violations = append(violations, d.validateValue(location, value, param.Schema)...)
+	}
+	return violations
+}
+
+// parseParameter converts a raw path or query value into the JSON value its schema expects.
+func parseParameter(raw string, schema *Schema) (interface{}, bool) {
+	switch schema.Type {
+	case "integer", "number":
+		var n json.Number
+		if err := json.Unmarshal([]byte(raw), &n); err != nil {
+			return nil, false
+		}
+		return n, true
+	case "boolean":
+		switch raw {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+		return nil, false
+	}
+	return raw, true
+}
+
+// decoded reports whether a body of a media type is decoded into the object or array its schema
+// describes, rather than being an opaque upload described as a string.
+func (d *Document) decoded(media *MediaType) bool {
+	if media == nil {
+		return false
+	}
+	schema := d.resolve(media.Schema)
+	return schema != nil && (schema.Type == "object" || schema.Type == "array")
+}
+
+// validateBody decodes a body of one of the media types the document lists for it and checks it
+// against that media type's schema.
+func (d *Document) validateBody(body *RequestBody, mediaType string, data []byte) []Violation {
+	media, ok := body.Content[mediaType]
+	if !ok {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if body.Required {
+			return []Violation{{"body", "is required"}}
+		}
+		return nil
+	}
+
+	var value interface{}
+	switch mediaType {
+	case mediaXML:
+		root, err := parseXML(data)
+		if err != nil {
+			return []Violation{{"body", "is not valid XML: " + err.Error()}}
+		}
+		value = d.xmlValue(root, media.Schema)
+	case mediaCSV:
+		var err error
+		if value, err = d.csvValue(data, media.Schema); err != nil {
+			return []Violation{{"body", "is not valid CSV: " + err.Error()}}
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return []Violation{{"body", "is not valid JSON: " + err.Error()}}
+		}
+		if decoder.More() {
+			return []Violation{{"body", "must contain a single JSON value"}}
+		}
+	}
+
+	return d.validateValue("body", value, media.Schema)
+}
+
+// validateValue checks a decoded JSON value against a schema and reports every violation found.
+func (d *Document) validateValue(location string, value interface{}, schema *Schema) []Violation {
+	schema = d.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []Violation{{location, "must be an object"}}
+		}
+		return d.validateObject(location, object, schema)
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []Violation{{location, "must be an array"}}
+		}
+		var violations []Violation
+		for i, item := range items {
+			violations = append(violations, d.validateValue(fmt.Sprintf("%s[%d]", location, i), item, schema.Items)...)
+		}
+		return violations
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []Violation{{location, "must be a string"}}
+		}
+		return validateString(location, s, schema)
+
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return []Violation{{location, "must be a number"}}
+		}
+		return validateNumber(location, n, schema)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []Violation{{location, "must be a boolean"}}
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateObject(location string, object map[string]interface{}, schema *Schema) []Violation {
+	var violations []Violation
+
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, Violation{location + "." + name, "is required"})
+		}
+	}
+
+	// Iterate in a stable order so violations are reported deterministically
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				violations = append(violations, Violation{location + "." + name, "is not a known field"})
+			}
+			continue
+		}
+		if object[name] == nil {
+			continue
+		}
+		violations = append(violations, d.validateValue(location+"."+name, object[name], property)...)
+	}
+	return violations
+}
+
+func validateString(location, s string, schema *Schema) []Violation {
+	var violations []Violation
+	if schema.MinLength != nil && len(s) < *schema.MinLength {
+		violations = append(violations, Violation{location, fmt.Sprintf("must be at least %d characters long", *schema.MinLength)})
+	}
+	if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+		violations = append(violations, Violation{location, "must be one of " + strings.Join(schema.Enum, ", ")})
+	}
+	if schema.Pattern != "" {
+		if matched, err := regexp.MatchString(schema.Pattern, s); err == nil && !matched {
+			violations = append(violations, Violation{location, "must match pattern " + schema.Pattern})
+		}
+	}
+	if schema.Format == "date" {
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			violations = append(violations, Violation{location, "must be a date in YYYY-MM-DD format"})
+		}
+	}
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			violations = append(violations, Violation{location, "must be a date-time in RFC 3339 format"})
+		}
+	}
+	return violations
+}
+
+func validateNumber(location string, n json.Number, schema *Schema) []Violation {
+	if schema.Type == "integer" {
+		if _, err := n.Int64(); err != nil {
+			return []Violation{{location, "must be an integer"}}
+		}
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return []Violation{{location, "must be a number"}}
+	}
+
+	var violations []Violation
+	if schema.Minimum != nil && f < *schema.Minimum {
+		violations = append(violations, Violation{location, fmt.Sprintf("must be at least %v", *schema.Minimum)})
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		violations = append(violations, Violation{location, fmt.Sprintf("must be at most %v", *schema.Maximum)})
+	}
+	return violations
+}
+
+// Media types of the request bodies the controllers decode.
+const (
+	mediaJSON = "application/json"
+	mediaXML  = "application/xml"
+	mediaCSV  = "text/csv"
+)
+
+// bodyMediaType returns the media type a request body with a Content-Type is validated as, or ""
+// for bodies the controllers do not decode. A missing Content-Type is treated as JSON, and text/xml
+// as application/xml.
+func bodyMediaType(contentType string) string {
+	if contentType == "" {
+		return mediaJSON
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case mediaJSON, mediaXML, mediaCSV:
+		return mediaType
+	case "text/xml":
+		return mediaXML
+	}
+	return ""
+}
+
+// resolve follows a component reference to the schema it names.
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// setupValidatedRouter registers stub handlers behind the validation middleware
+// and records the body each handler received.
+func setupValidatedRouter(received *string) *mux.Router {
+	r := mux.NewRouter()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}
+	r.HandleFunc("/albums", handler).Methods("POST")
+	r.HandleFunc("/albums/{id:[0-9]+}", handler).Methods("PUT")
+	r.Use(ValidateRequests(Spec()))
+	return r
+}
+
+func TestValidateRequestsAcceptsValidBody(t *testing.T) {
+	var received string
+	router := setupValidatedRouter(&received)
+
+	payload := `{"name": "Test Album", "release_date": "2022-01-01", "genre": "Rock", "price": 150, "musician_ids": [1, 2]}`
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if received != payload {
+		t.Errorf("expected controller to receive the original body, got %q", received)
+	}
+}
+
+func TestValidateRequestsListsEveryViolation(t *testing.T) {
+	var received string
+	router := setupValidatedRouter(&received)
+
+	payload := `{"name": "Tiny", "release_date": "01/01/2022", "price": "cheap", "musician_ids": [1, 2.5], "label": "Acme"}`
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(payload))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+	if received != "" {
+		t.Error("expected controller not to run for an invalid request")
+	}
+
+	var response ValidationError
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+
+	expected := map[string]bool{
+		"body.name":            true,
+		"body.release_date":    true,
+		"body.price":           true,
+		"body.musician_ids[1]": true,
+		"body.label":           true,
+	}
+	if len(response.Violations) != len(expected) {
+		t.Errorf("expected %d violations, got %+v", len(expected), response.Violations)
+	}
+	for _, violation := range response.Violations {
+		if !expected[violation.Location] {
+			t.Errorf("unexpected violation %+v", violation)
+		}
+	}
+}
+
+func TestValidateRequestsRequiredFields(t *testing.T) {
+	var received string
+	router := setupValidatedRouter(&received)
+
+	req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"genre": "Rock"}`))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	var response ValidationError
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if len(response.Violations) != 2 || response.Violations[0].Location != "body.name" || response.Violations[1].Location != "body.price" {
+		t.Errorf("expected name and price to be reported missing, got %+v", response.Violations)
+	}
+}
+
+func TestValidateRequestsPathParameter(t *testing.T) {
+	var received string
+	router := setupValidatedRouter(&received)
+
+	// The mux pattern accepts the digits, but the value overflows an integer
+	req := httptest.NewRequest("PUT", "/albums/99999999999999999999", bytes.NewBufferString(`{"name": "Test Album"}`))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+	}
+	if !bytes.Contains(rr.Body.Bytes(), []byte("path.id")) {
+		t.Errorf("expected path.id violation, got %s", rr.Body.String())
+	}
+}
+
+func TestValidateRequestsXMLAndCSVBodies(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		violations  []string
+	}{
+		{
+			name:        "valid XML",
+			contentType: "application/xml",
+			body:        `<album><name>Test Album</name><release_date>2022-01-01</release_date><price>150</price><musician_ids><id>1</id><id>2</id></musician_ids></album>`,
+		},
+		{
+			name:        "invalid XML",
+			contentType: "text/xml; charset=utf-8",
+			body:        `<album><name>Tiny</name><price>cheap</price><musician_ids><id>1</id><id>2.5</id></musician_ids><label>Acme</label></album>`,
+			violations:  []string{"body.label", "body.musician_ids[1]", "body.name", "body.price"},
+		},
+		{
+			name:        "malformed XML",
+			contentType: "application/xml",
+			body:        `<album><name>Test Album</album>`,
+			violations:  []string{"body"},
+		},
+		{
+			name:        "valid CSV",
+			contentType: "text/csv",
+			body:        "name,release_date,price,musician_ids\nTest Album,2022-01-01,150,1;2\n",
+		},
+		{
+			name:        "invalid CSV",
+			contentType: "text/csv",
+			body:        "name,release_date,musician_ids\nTiny,01/01/2022,1;x\n",
+			violations:  []string{"body.price", "body.musician_ids[1]", "body.name", "body.release_date"},
+		},
+		{
+			name:        "CSV credits",
+			contentType: "text/csv",
+			body:        "name,release_date,price,credits\nTest Album,2022-01-01,150,1\n",
+			violations:  []string{"body.credits"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			router := setupValidatedRouter(&received)
+
+			req := httptest.NewRequest("POST", "/albums", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if len(tt.violations) == 0 {
+				if rr.Code != http.StatusOK || received != tt.body {
+					t.Errorf("expected the body to reach the controller untouched, got %v: %s", rr.Code, rr.Body.String())
+				}
+				return
+			}
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status code %v, got %v", http.StatusBadRequest, rr.Code)
+			}
+			var response ValidationError
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response body: %v", err)
+			}
+			var locations []string
+			for _, violation := range response.Violations {
+				locations = append(locations, violation.Location)
+			}
+			if !slices.Equal(locations, tt.violations) {
+				t.Errorf("expected violations at %v, got %+v", tt.violations, response.Violations)
+			}
+		})
+	}
+}
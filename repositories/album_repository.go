@@ -2,18 +2,109 @@ package repositories
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"jukebox/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
 )
 
+// ErrCurrencyLocked is returned when an album's currency is changed while editions or pending scheduled prices
+// are stored in its current currency.
+var ErrCurrencyLocked = errors.New("album currency cannot change while it has editions or pending scheduled prices")
+
+// albumColumns selects every album column from the table aliased as a, in the order albumFields scans them.
+// The currency comes before the price so the price can be converted from its minor units.
+const albumColumns = "a.id, a.name, a.release_date, a.genre, a.currency, a.price_minor, a.description, COALESCE(a.label_id, 0), COALESCE(a.catalog_number, '')"
+
+// basePriceOrder orders albums by price in models.BaseCurrency, so albums priced in different currencies compare
+// by what they cost rather than by their minor units. It needs the album's exchange rate joined as er; albums in
+// a currency without one sort after the rest.
+var basePriceOrder = func() string {
+	currencies := make([]string, 0, len(models.CurrencyExponents))
+	for currency := range models.CurrencyExponents {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	scale := "CASE a.currency"
+	for _, currency := range currencies {
+		scale += fmt.Sprintf(" WHEN '%s' THEN %g", currency, math.Pow10(models.CurrencyExponents[currency]))
+	}
+	scale += " END"
+	rate := fmt.Sprintf("CASE WHEN a.currency = '%s' THEN 1 ELSE er.rate END", models.BaseCurrency)
+	price := "a.price_minor / (" + scale + ") / (" + rate + ")"
+	return "(" + price + ") IS NULL, " + price
+}()
+
+// albumFields returns the scan destinations for albumColumns.
+func albumFields(album *models.Album) []interface{} {
+	return []interface{}{&album.ID, &album.Name, dateOnly{&album.ReleaseDate}, &album.Genre, &album.Currency, minorUnits{&album.Price, &album.Currency}, &album.Description, &album.LabelID, &album.CatalogNumber}
+}
+
+// minorUnits scans a price stored in minor units into a price in major units. The currency
+// must be scanned first, which holds as database/sql assigns columns in order.
+type minorUnits struct {
+	price    *float64
+	currency *string
+}
+
+func (m minorUnits) Scan(src interface{}) error {
+	var minor sql.NullInt64
+	if err := minor.Scan(src); err != nil {
+		return err
+	}
+	*m.price = models.FromMinor(minor.Int64, *m.currency)
+	return nil
+}
+
+// dateOnly scans a DATE column, which the driver reads back as a timestamp such as 1957-01-01T00:00:00Z,
+// into a YYYY-MM-DD string.
+type dateOnly struct {
+	date *string
+}
+
+func (d dateOnly) Scan(src interface{}) error {
+	if t, ok := src.(time.Time); ok {
+		*d.date = t.Format(time.DateOnly)
+		return nil
+	}
+	var date sql.NullString
+	if err := date.Scan(src); err != nil {
+		return err
+	}
+	*d.date = date.String
+	return nil
+}
+
+// defaultCurrency prices albums without a currency in BaseCurrency.
+func defaultCurrency(album *models.Album) {
+	if album.Currency == "" {
+		album.Currency = models.BaseCurrency
+	}
+}
+
 type AlbumRepository struct {
 	DB *sql.DB
+	// Outbox, when set, receives a domain event for every album change in the same transaction.
+	Outbox *OutboxRepository
 }
 
 // CreateAlbum inserts a new album into the database and returns the inserted album with the correct ID.
 func (r *AlbumRepository) CreateAlbum(album *models.Album) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	defaultCurrency(album)
+
 	// Check if the albums table is empty by seeing if any row exists
 	var exists bool
-	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM albums LIMIT 1)").Scan(&exists)
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM albums LIMIT 1)").Scan(&exists)
 	if err != nil {
 		return err
 	}
@@ -21,15 +112,15 @@ func (r *AlbumRepository) CreateAlbum(album *models.Album) error {
 	if !exists {
 		// If the table is empty, explicitly set the album ID to 1
 		album.ID = 1
-		_, err = r.DB.Exec("INSERT INTO albums (id, name, release_date, genre, price, description) VALUES (?, ?, ?, ?, ?, ?)",
-			album.ID, album.Name, album.ReleaseDate, album.Genre, album.Price, album.Description)
+		_, err = tx.Exec("INSERT INTO albums (id, name, release_date, genre, price_minor, currency, description, label_id, catalog_number) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			album.ID, album.Name, album.ReleaseDate, album.Genre, models.ToMinor(album.Price, album.Currency), album.Currency, album.Description, nullableID(album.LabelID), nullableString(album.CatalogNumber))
 		if err != nil {
 			return err
 		}
 	} else {
 		// Insert the album into the database (ID will be auto-generated)
-		result, err := r.DB.Exec("INSERT INTO albums (name, release_date, genre, price, description) VALUES (?, ?, ?, ?, ?)",
-			album.Name, album.ReleaseDate, album.Genre, album.Price, album.Description)
+		result, err := tx.Exec("INSERT INTO albums (name, release_date, genre, price_minor, currency, description, label_id, catalog_number) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			album.Name, album.ReleaseDate, album.Genre, models.ToMinor(album.Price, album.Currency), album.Currency, album.Description, nullableID(album.LabelID), nullableString(album.CatalogNumber))
 		if err != nil {
 			return err
 		}
@@ -44,12 +135,17 @@ func (r *AlbumRepository) CreateAlbum(album *models.Album) error {
 		album.ID = uint(id)
 	}
 
-	return nil
+	if err := r.appendEvent(tx, album.ID, models.EventAlbumCreated, album); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetAlbums retrieves all albums from the database.
-func (r *AlbumRepository) GetAlbums() ([]models.Album, error) {
-	rows, err := r.DB.Query("SELECT id, name, release_date, genre, price, description FROM albums ORDER BY release_date ASC")
+// GetAlbums retrieves the albums matching a filter sorted by release date.
+func (r *AlbumRepository) GetAlbums(filter models.AlbumFilter) ([]models.Album, error) {
+	where, args := albumFilterClause(filter)
+	rows, err := r.DB.Query("SELECT "+albumColumns+" FROM albums a"+where+" ORDER BY a.release_date ASC", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +154,7 @@ func (r *AlbumRepository) GetAlbums() ([]models.Album, error) {
 	var albums []models.Album
 	for rows.Next() {
 		var album models.Album
-		if err := rows.Scan(&album.ID, &album.Name, &album.ReleaseDate, &album.Genre, &album.Price, &album.Description); err != nil {
+		if err := rows.Scan(albumFields(&album)...); err != nil {
 			return nil, err
 		}
 		albums = append(albums, album)
@@ -66,51 +162,386 @@ func (r *AlbumRepository) GetAlbums() ([]models.Album, error) {
 	return albums, nil
 }
 
-// UpdateAlbum updates an existing album in the database.
+// UpdateAlbum updates an existing album in the database. It returns sql.ErrNoRows if the album does not exist.
 func (r *AlbumRepository) UpdateAlbum(album *models.Album) error {
-	_, err := r.DB.Exec("UPDATE albums SET name = ?, release_date = ?, genre = ?, price = ?, description = ? WHERE id = ?",
-		album.Name, album.ReleaseDate, album.Genre, album.Price, album.Description, album.ID)
-	return err
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	defaultCurrency(album)
+	if err := checkCurrencyChange(tx, album); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("UPDATE albums SET name = ?, release_date = ?, genre = ?, price_minor = ?, currency = ?, description = ?, label_id = ?, catalog_number = ? WHERE id = ?",
+		album.Name, album.ReleaseDate, album.Genre, models.ToMinor(album.Price, album.Currency), album.Currency, album.Description, nullableID(album.LabelID), nullableString(album.CatalogNumber), album.ID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := r.appendEvent(tx, album.ID, models.EventAlbumUpdated, album); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkCurrencyChange returns ErrCurrencyLocked if an update changes the currency of an album with editions or
+// pending scheduled prices, whose prices are stored in its current currency and would silently be repriced.
+func checkCurrencyChange(tx *sql.Tx, album *models.Album) error {
+	var currency string
+	err := tx.QueryRow("SELECT currency FROM albums WHERE id = ?", album.ID).Scan(&currency)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && currency == album.Currency {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var locked bool
+	err = tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM editions WHERE album_id = ?)
+            OR EXISTS (SELECT 1 FROM price_schedules WHERE album_id = ? AND applied_at IS NULL)`,
+		album.ID, album.ID).Scan(&locked)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return fmt.Errorf("album %d: %w", album.ID, ErrCurrencyLocked)
+	}
+	return nil
 }
 
 // DeleteAlbum deletes an album by ID.
 func (r *AlbumRepository) DeleteAlbum(id uint) error {
-	_, err := r.DB.Exec("DELETE FROM albums WHERE id = ?", id)
-	return err
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM albums WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	if err := r.appendEvent(tx, id, models.EventAlbumDeleted, map[string]uint{"id": id}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// appendEvent records a domain event in the outbox when one is configured.
+func (r *AlbumRepository) appendEvent(tx *sql.Tx, albumID uint, eventType string, payload interface{}) error {
+	if r.Outbox == nil {
+		return nil
+	}
+	return r.Outbox.Append(tx, "album", albumID, eventType, payload)
 }
 
-func (r *AlbumRepository) LinkMusiciansToAlbum(albumID uint, musicianIDs []uint) error {
-	// Insert each musician into album_musicians table
-	for _, musicianID := range musicianIDs {
-		_, err := r.DB.Exec("INSERT INTO album_musicians (album_id, musician_id) VALUES (?, ?)", albumID, musicianID)
+// LinkMusiciansToAlbum credits musicians on an album. Credits the album already has are skipped.
+func (r *AlbumRepository) LinkMusiciansToAlbum(albumID uint, credits []models.Credit) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, credit := range credits {
+		_, err := tx.Exec(`
+            INSERT INTO album_musicians (album_id, musician_id, role, instrument, track)
+            SELECT ?, ?, ?, ?, ?
+            WHERE NOT EXISTS (
+                SELECT 1 FROM album_musicians
+                WHERE album_id = ? AND musician_id = ? AND role = ? AND instrument = ? AND track = ?
+            )`,
+			albumID, credit.MusicianID, credit.Role, credit.Instrument, credit.Track,
+			albumID, credit.MusicianID, credit.Role, credit.Instrument, credit.Track)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	return tx.Commit()
 }
 
-func (r *AlbumRepository) GetAlbumsByMusician(musicianID uint) ([]models.Album, error) {
-	rows, err := r.DB.Query(`
-        SELECT a.id, a.name, a.release_date, a.genre, a.price, a.description
+// GetAlbumsByMusician retrieves the albums a musician is credited on sorted by price, each with the
+// musician's credits. When role is set, only credits with that role are returned.
+func (r *AlbumRepository) GetAlbumsByMusician(musicianID uint, role string) ([]models.CreditedAlbum, error) {
+	query := `
+        SELECT ` + albumColumns + `, am.role, am.instrument, am.track
         FROM albums a
         JOIN album_musicians am ON a.id = am.album_id
-        WHERE am.musician_id = ?
-        ORDER BY a.price ASC
-    `, musicianID)
+        LEFT JOIN exchange_rates er ON er.currency = a.currency
+        WHERE am.musician_id = ?`
+	args := []interface{}{musicianID}
+	if role != "" {
+		query += ` AND am.role = ?`
+		args = append(args, role)
+	}
+	query += `
+        ORDER BY ` + basePriceOrder + `, a.id ASC, am.track ASC, am.role ASC`
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var albums []models.Album
+	albums := []models.CreditedAlbum{}
 	for rows.Next() {
 		var album models.Album
-		if err := rows.Scan(&album.ID, &album.Name, &album.ReleaseDate, &album.Genre, &album.Price, &album.Description); err != nil {
+		credit := models.Credit{MusicianID: musicianID}
+		if err := rows.Scan(append(albumFields(&album), &credit.Role, &credit.Instrument, &credit.Track)...); err != nil {
 			return nil, err
 		}
-		albums = append(albums, album)
+
+		// Credits on the same album are adjacent
+		if n := len(albums); n == 0 || albums[n-1].ID != album.ID {
+			albums = append(albums, models.CreditedAlbum{Album: album})
+		}
+		last := &albums[len(albums)-1]
+		last.Credits = append(last.Credits, credit)
 	}
 
-	return albums, nil
+	return albums, rows.Err()
+}
+
+// GetAlbumsByMusicians retrieves the albums of several musicians, one query per chunk of IDs, keyed by musician ID
+// and sorted by price.
+func (r *AlbumRepository) GetAlbumsByMusicians(musicianIDs []uint) (map[uint][]models.Album, error) {
+	albumsByMusician := make(map[uint][]models.Album)
+	for start := 0; start < len(musicianIDs); start += maxInClause {
+		placeholders, args := inClause(musicianIDs[start:min(start+maxInClause, len(musicianIDs))])
+		rows, err := r.DB.Query(`
+            SELECT DISTINCT am.musician_id, `+albumColumns+`
+            FROM albums a
+            JOIN album_musicians am ON a.id = am.album_id
+            LEFT JOIN exchange_rates er ON er.currency = a.currency
+            WHERE am.musician_id IN (`+placeholders+`)
+            ORDER BY `+basePriceOrder+`, a.id ASC
+        `, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var musicianID uint
+			var album models.Album
+			if err := rows.Scan(append([]interface{}{&musicianID}, albumFields(&album)...)...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			albumsByMusician[musicianID] = append(albumsByMusician[musicianID], album)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return albumsByMusician, nil
+}
+
+// albumFilterClause builds the WHERE clause, if any, that applies a filter to the albums table aliased as a.
+func albumFilterClause(filter models.AlbumFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.MusicianID != 0 {
+		conditions = append(conditions, "a.id IN (SELECT album_id FROM album_musicians WHERE musician_id = ?)")
+		args = append(args, filter.MusicianID)
+	}
+	if filter.Genre != "" {
+		// Walk down the genre tree from the genre the name or alias resolves to
+		conditions = append(conditions, `a.id IN (
+            WITH RECURSIVE tree(id) AS (
+                SELECT id FROM genres WHERE key = ? OR id IN (SELECT genre_id FROM genre_aliases WHERE key = ?)
+                UNION
+                SELECT g.id FROM genres g JOIN tree ON g.parent_id = tree.id
+            )
+            SELECT ag.album_id FROM album_genres ag JOIN tree ON ag.genre_id = tree.id)`)
+		key := models.GenreKey(filter.Genre)
+		args = append(args, key, key)
+	}
+	if filter.InStock != nil {
+		// An album is in stock when it, or any of its editions, has copies, or when its stock is not
+		// tracked at all, as checkout then sells it without limit
+		inStock := "(a.id NOT IN (SELECT album_id FROM stock_levels) OR a.id IN (SELECT album_id FROM stock_levels WHERE quantity > 0))"
+		if !*filter.InStock {
+			inStock = "a.id IN (SELECT album_id FROM stock_levels) AND a.id NOT IN (SELECT album_id FROM stock_levels WHERE quantity > 0)"
+		}
+		conditions = append(conditions, inStock)
+	}
+	if filter.CustomerID != 0 {
+		conditions = append(conditions, "a.id IN (SELECT album_id FROM library_entries WHERE customer_id = ?)")
+		args = append(args, filter.CustomerID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// inClause builds the placeholders and arguments for an SQL IN list.
+func inClause(ids []uint) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// GetAlbumByID retrieves a single album. It returns sql.ErrNoRows if the album does not exist.
+func (r *AlbumRepository) GetAlbumByID(id uint) (*models.Album, error) {
+	var album models.Album
+	err := r.DB.QueryRow("SELECT "+albumColumns+" FROM albums a WHERE a.id = ?", id).Scan(albumFields(&album)...)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// AlbumExists reports whether an album with the same name and release date is already in the catalog.
+func (r *AlbumRepository) AlbumExists(name, releaseDate string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM albums WHERE name = ? AND release_date = ?)", name, releaseDate).Scan(&exists)
+	return exists, err
+}
+
+// CreateAlbums inserts several albums in a single transaction and sets their IDs.
+func (r *AlbumRepository) CreateAlbums(albums []*models.Album) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO albums (name, release_date, genre, price_minor, currency, description, label_id, catalog_number) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, album := range albums {
+		defaultCurrency(album)
+		result, err := stmt.Exec(album.Name, album.ReleaseDate, album.Genre, models.ToMinor(album.Price, album.Currency), album.Currency, album.Description, nullableID(album.LabelID), nullableString(album.CatalogNumber))
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		album.ID = uint(id)
+
+		if err := r.appendEvent(tx, album.ID, models.EventAlbumCreated, album); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HasLink reports whether a musician is already linked to an album.
+func (r *AlbumRepository) HasLink(albumID, musicianID uint) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM album_musicians WHERE album_id = ? AND musician_id = ?)", albumID, musicianID).Scan(&exists)
+	return exists, err
+}
+
+// CreateLinks inserts several album–musician links in a single transaction.
+func (r *AlbumRepository) CreateLinks(links []models.AlbumMusician) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, link := range links {
+		if _, err := tx.Exec("INSERT INTO album_musicians (album_id, musician_id) VALUES (?, ?)", link.AlbumID, link.MusicianID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StreamAlbumsWithMusicians calls fn for each page of up to maxInClause albums matching the filter,
+// sorted by release date, with their musicians attached. Pages are read one query at a time and
+// handed over once the query is done, so the full result is never held in memory and fn may query
+// the database itself, e.g. to look up the page's prices by album ID.
+func (r *AlbumRepository) StreamAlbumsWithMusicians(filter models.AlbumFilter, fn func([]models.AlbumWithMusicians) error) error {
+	where, args := albumFilterClause(filter)
+	for offset := 0; ; offset += maxInClause {
+		page, err := r.albumsWithMusiciansPage(where, args, offset)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < maxInClause {
+			return nil
+		}
+	}
+}
+
+// albumsWithMusiciansPage retrieves up to maxInClause albums matching a WHERE clause from an offset, sorted
+// by release date, with their musicians attached.
+func (r *AlbumRepository) albumsWithMusiciansPage(where string, args []interface{}, offset int) ([]models.AlbumWithMusicians, error) {
+	rows, err := r.DB.Query(`
+        SELECT `+albumColumns+`, m.id, m.name, m.musician_type, m.kind
+        FROM albums a
+        LEFT JOIN (SELECT DISTINCT album_id, musician_id FROM album_musicians) am ON a.id = am.album_id
+        LEFT JOIN musicians m ON m.id = am.musician_id
+        WHERE a.id IN (SELECT a.id FROM albums a`+where+` ORDER BY a.release_date ASC, a.id ASC LIMIT ? OFFSET ?)
+        ORDER BY a.release_date ASC, a.id ASC, m.name ASC`, append(append([]interface{}{}, args...), maxInClause, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := []models.AlbumWithMusicians{}
+	for rows.Next() {
+		var album models.Album
+		var musicianID sql.NullInt64
+		var musicianName, musicianType, musicianKind sql.NullString
+		if err := rows.Scan(append(albumFields(&album), &musicianID, &musicianName, &musicianType, &musicianKind)...); err != nil {
+			return nil, err
+		}
+
+		// Rows for the same album are adjacent; start a new album when the ID changes
+		if len(page) == 0 || page[len(page)-1].ID != album.ID {
+			page = append(page, models.AlbumWithMusicians{Album: album, Musicians: []models.Musician{}})
+		}
+		if musicianID.Valid {
+			current := &page[len(page)-1]
+			current.Musicians = append(current.Musicians, models.Musician{
+				ID:           uint(musicianID.Int64),
+				Name:         musicianName.String,
+				MusicianType: musicianType.String,
+				Kind:         musicianKind.String,
+			})
+		}
+	}
+	return page, rows.Err()
+}
+
+// CatalogNumberTaken reports whether another album of the label already uses the catalog number.
+func (r *AlbumRepository) CatalogNumberTaken(labelID uint, catalogNumber string, excludeAlbumID uint) (bool, error) {
+	var taken bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM albums WHERE label_id = ? AND catalog_number = ? AND id != ?)",
+		labelID, catalogNumber, excludeAlbumID).Scan(&taken)
+	return taken, err
 }
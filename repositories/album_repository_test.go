@@ -1,7 +1,10 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"jukebox/models"
+	"slices"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -39,15 +42,15 @@ func TestGetAlbums(t *testing.T) {
 
 	// Insert test data
 	_, err := db.Exec(`
-		INSERT INTO albums (name, release_date, genre, price, description) VALUES 
-		('Test Album 1', '2022-01-01', 'Rock', 200, 'A test album 1'),
-		('Test Album 2', '2022-02-01', 'Pop', 250, 'A test album 2');
+		INSERT INTO albums (name, release_date, genre, price_minor, description) VALUES 
+		('Test Album 1', '2022-01-01', 'Rock', 20000, 'A test album 1'),
+		('Test Album 2', '2022-02-01', 'Pop', 25000, 'A test album 2');
 	`)
 	if err != nil {
 		t.Fatalf("failed to insert test data: %v", err)
 	}
 
-	albums, err := repo.GetAlbums()
+	albums, err := repo.GetAlbums(models.AlbumFilter{})
 	if err != nil {
 		t.Fatalf("failed to get albums: %v", err)
 	}
@@ -64,7 +67,7 @@ func TestUpdateAlbum(t *testing.T) {
 	repo := AlbumRepository{DB: db}
 
 	// Insert a test album
-	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price, description) VALUES (1, 'Old Album', '2022-01-01', 'Rock', 200, 'An old album')`)
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'Old Album', '2022-01-01', 'Rock', 20000, 'An old album')`)
 	if err != nil {
 		t.Fatalf("failed to insert test album: %v", err)
 	}
@@ -95,7 +98,7 @@ func TestDeleteAlbum(t *testing.T) {
 	repo := AlbumRepository{DB: db}
 
 	// Insert a test album
-	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price, description) VALUES (1, 'Test Album', '2022-01-01', 'Rock', 200, 'A test album')`)
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'Test Album', '2022-01-01', 'Rock', 20000, 'A test album')`)
 	if err != nil {
 		t.Fatalf("failed to insert test album: %v", err)
 	}
@@ -125,13 +128,13 @@ func TestLinkMusiciansToAlbum(t *testing.T) {
 	repo := AlbumRepository{DB: db}
 
 	// Insert a test album
-	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price, description) VALUES (1, 'Test Album', '2022-01-01', 'Rock', 200, 'A test album')`)
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'Test Album', '2022-01-01', 'Rock', 20000, 'A test album')`)
 	if err != nil {
 		t.Fatalf("failed to insert test album: %v", err)
 	}
 
 	// Test LinkMusiciansToAlbum
-	err = repo.LinkMusiciansToAlbum(1, []uint{101, 102})
+	err = repo.LinkMusiciansToAlbum(1, []models.Credit{{MusicianID: 101, Role: models.CreditPerformer}, {MusicianID: 102, Role: models.CreditPerformer}})
 	if err != nil {
 		t.Fatalf("failed to link musicians to album: %v", err)
 	}
@@ -156,9 +159,9 @@ func TestGetAlbumsByMusician(t *testing.T) {
 
 	// Insert test albums and musicians
 	_, err := db.Exec(`
-		INSERT INTO albums (id, name, release_date, genre, price, description) VALUES 
-		(1, 'Rock Album', '2022-01-01', 'Rock', 200, 'First Album'),
-		(2, 'Pop Album', '2022-02-01', 'Pop', 300, 'Second Album');
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES 
+		(1, 'Rock Album', '2022-01-01', 'Rock', 20000, 'First Album'),
+		(2, 'Pop Album', '2022-02-01', 'Pop', 30000, 'Second Album');
 		INSERT INTO album_musicians (album_id, musician_id) VALUES 
 		(1, 101),
 		(2, 101),
@@ -169,7 +172,7 @@ func TestGetAlbumsByMusician(t *testing.T) {
 	}
 
 	// Test GetAlbumsByMusician
-	albums, err := repo.GetAlbumsByMusician(101)
+	albums, err := repo.GetAlbumsByMusician(101, "")
 	if err != nil {
 		t.Fatalf("failed to get albums by musician: %v", err)
 	}
@@ -184,3 +187,178 @@ func TestGetAlbumsByMusician(t *testing.T) {
 		t.Errorf("unexpected album names for musician 101: %v, %v", albums[0].Name, albums[1].Name)
 	}
 }
+
+func TestGetAlbumsByMusicianCredits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := AlbumRepository{DB: db}
+
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES 
+		(1, 'Rock Album', '2022-01-01', 'Rock', 20000, 'First Album'),
+		(2, 'Pop Album', '2022-02-01', 'Pop', 30000, 'Second Album')`)
+	if err != nil {
+		t.Fatalf("failed to insert test albums: %v", err)
+	}
+
+	err = repo.LinkMusiciansToAlbum(1, []models.Credit{
+		{MusicianID: 101, Role: models.CreditPerformer, Instrument: "guitar"},
+		{MusicianID: 101, Role: models.CreditProducer},
+		{MusicianID: 101, Role: models.CreditProducer},
+	})
+	if err != nil {
+		t.Fatalf("failed to link musicians to album: %v", err)
+	}
+	err = repo.LinkMusiciansToAlbum(2, []models.Credit{{MusicianID: 101, Role: models.CreditComposer, Track: 3}})
+	if err != nil {
+		t.Fatalf("failed to link musicians to album: %v", err)
+	}
+
+	t.Run("all roles", func(t *testing.T) {
+		albums, err := repo.GetAlbumsByMusician(101, "")
+		if err != nil {
+			t.Fatalf("failed to get albums by musician: %v", err)
+		}
+		if len(albums) != 2 {
+			t.Fatalf("expected 2 albums, got %d", len(albums))
+		}
+		// The repeated producer credit is stored once
+		if len(albums[0].Credits) != 2 {
+			t.Errorf("expected 2 credits on the first album, got %+v", albums[0].Credits)
+		}
+		if albums[1].Credits[0].Track != 3 {
+			t.Errorf("expected a track 3 credit, got %+v", albums[1].Credits[0])
+		}
+	})
+
+	t.Run("filtered by role", func(t *testing.T) {
+		albums, err := repo.GetAlbumsByMusician(101, models.CreditProducer)
+		if err != nil {
+			t.Fatalf("failed to get albums by musician: %v", err)
+		}
+		if len(albums) != 1 || albums[0].Name != "Rock Album" || len(albums[0].Credits) != 1 {
+			t.Errorf("expected only the produced album, got %+v", albums)
+		}
+	})
+}
+
+func TestUpdateAlbumCurrencyLocked(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := AlbumRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'Edition Album', '2022-01-01', 'Rock', 20000, ''),
+		(2, 'Scheduled Album', '2022-01-01', 'Rock', 20000, ''),
+		(3, 'Plain Album', '2022-01-01', 'Rock', 20000, '');
+		INSERT INTO editions (album_id, format, release_date, price_minor) VALUES (1, 'cd', '2022-01-01', 20000);
+		INSERT INTO price_schedules (album_id, price_minor, starts_at) VALUES (2, 15000, '2030-01-01T00:00:00Z');
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	for _, tt := range []struct {
+		id     uint
+		locked bool
+	}{
+		{1, true},
+		{2, true},
+		{3, false},
+	} {
+		err := repo.UpdateAlbum(&models.Album{ID: tt.id, Name: "Album", ReleaseDate: "2022-01-01", Currency: "EUR", Price: 180})
+		if errors.Is(err, ErrCurrencyLocked) != tt.locked {
+			t.Errorf("album %d: expected locked %v, got %v", tt.id, tt.locked, err)
+		}
+	}
+
+	if err := repo.UpdateAlbum(&models.Album{ID: 1, Name: "Album", ReleaseDate: "2022-01-01", Price: 210}); err != nil {
+		t.Errorf("expected an update keeping the currency to succeed, got %v", err)
+	}
+}
+
+func TestGetAlbumsByMusicianSortsAcrossCurrencies(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := AlbumRepository{DB: db}
+
+	// 20000 JPY is about 133 USD, 15000 GBP minor units 187.50 USD
+	_, err := db.Exec(`
+		INSERT INTO exchange_rates (currency, rate) VALUES ('JPY', 150), ('GBP', 0.8);
+		INSERT INTO albums (id, name, release_date, genre, currency, price_minor, description) VALUES
+		(1, 'Pound Album', '2022-01-01', 'Rock', 'GBP', 15000, ''),
+		(2, 'Yen Album', '2022-01-01', 'Rock', 'JPY', 20000, ''),
+		(3, 'Dollar Album', '2022-01-01', 'Rock', 'USD', 16000, ''),
+		(4, 'Krona Album', '2022-01-01', 'Rock', 'SEK', 100, '');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES (1, 101), (2, 101), (3, 101), (4, 101);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	albums, err := repo.GetAlbumsByMusician(101, "")
+	if err != nil {
+		t.Fatalf("failed to get albums by musician: %v", err)
+	}
+	var names []string
+	for _, album := range albums {
+		names = append(names, album.Name)
+	}
+	// The krona album has no exchange rate, so it sorts last
+	if want := []string{"Yen Album", "Dollar Album", "Pound Album", "Krona Album"}; !slices.Equal(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+
+	byMusician, err := repo.GetAlbumsByMusicians(chunkedIDs(101))
+	if err != nil {
+		t.Fatalf("failed to get albums by musicians: %v", err)
+	}
+	if got := byMusician[101]; len(got) != 4 || got[0].Name != "Yen Album" || got[3].Name != "Krona Album" {
+		t.Errorf("unexpected albums %+v", got)
+	}
+}
+
+func TestStreamAlbumsWithMusiciansInPages(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := AlbumRepository{DB: db}
+	prices := PriceRepository{DB: db}
+
+	// Albums are created newest first, so the stream has to reorder them
+	albums := make([]*models.Album, maxInClause+1)
+	for i := range albums {
+		albums[i] = &models.Album{Name: fmt.Sprintf("Album %d", i), ReleaseDate: fmt.Sprintf("%04d-01-01", 2600-i), Price: 200}
+	}
+	if err := repo.CreateAlbums(albums); err != nil {
+		t.Fatalf("failed to create albums: %v", err)
+	}
+
+	var pages []int
+	var previous string
+	streamed := 0
+	err := repo.StreamAlbumsWithMusicians(models.AlbumFilter{}, func(page []models.AlbumWithMusicians) error {
+		pages = append(pages, len(page))
+		ids := make([]uint, len(page))
+		for i, album := range page {
+			if album.ReleaseDate < previous {
+				t.Errorf("album %d released %s streamed after %s", album.ID, album.ReleaseDate, previous)
+			}
+			previous = album.ReleaseDate
+			ids[i] = album.ID
+		}
+		streamed += len(page)
+		// The database can be queried while streaming
+		_, err := prices.GetAlbumPricesIn(ids, "EUR")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to stream albums: %v", err)
+	}
+	if streamed != len(albums) || !slices.Equal(pages, []int{maxInClause, 1}) {
+		t.Errorf("expected %d albums in pages of %d and 1, got %v", len(albums), maxInClause, pages)
+	}
+}
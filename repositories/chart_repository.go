@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+)
+
+type ChartRepository struct {
+	DB *sql.DB
+}
+
+// countPlaysQuery adds plays of an album to its counter for a period, taking the period, metric, album ID
+// and count. Plays are counted by ChartRepository.RecordPlays and as PlayRepository stores them.
+const countPlaysQuery = `
+        INSERT INTO chart_counters (period, metric, album_id, count) VALUES (?, ?, ?, ?)
+        ON CONFLICT (period, metric, album_id) DO UPDATE SET count = count + excluded.count`
+
+// RecordPlays adds plays of an album to its counter for a period.
+func (r *ChartRepository) RecordPlays(period string, albumID uint, count int) error {
+	_, err := r.DB.Exec(countPlaysQuery, period, models.ChartPlays, albumID, count)
+	return err
+}
+
+// AggregatePeriod rebuilds the purchase and rating counters of a period from the copies of paid and shipped
+// orders checked out in it and the approved reviews written in it. Times are in models.TimeFormat, from
+// start up to but not including end.
+func (r *ChartRepository) AggregatePeriod(period, start, end string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM chart_counters WHERE period = ? AND metric IN (?, ?)", period, models.ChartPurchases, models.ChartRatings); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+        INSERT INTO chart_counters (period, metric, album_id, count)
+        SELECT ?, ?, ol.album_id, SUM(ol.quantity)
+        FROM order_lines ol
+        JOIN orders o ON o.id = ol.order_id
+        WHERE o.status IN (?, ?) AND o.created_at >= ? AND o.created_at < ?
+        GROUP BY ol.album_id`,
+		period, models.ChartPurchases, models.OrderPaid, models.OrderShipped, start, end)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+        INSERT INTO chart_counters (period, metric, album_id, count, total)
+        SELECT ?, ?, album_id, COUNT(*), SUM(rating)
+        FROM reviews
+        WHERE status = ? AND created_at >= ? AND created_at < ?
+        GROUP BY album_id`,
+		period, models.ChartRatings, models.ReviewApproved, start, end)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RankAlbums ranks the albums counted for a metric in a period, optionally only those in a genre or below
+// it, and returns up to limit entries without positions. Plays and purchases rank by count; ratings rank
+// by average rating, then by number of ratings, and need at least minRatings.
+func (r *ChartRepository) RankAlbums(metric, period, genre string, minRatings, limit int) ([]models.ChartEntry, error) {
+	where, args := albumFilterClause(models.AlbumFilter{Genre: genre})
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+	where += "c.period = ? AND c.metric = ?"
+	args = append(args, period, metric)
+
+	value := "CAST(c.count AS REAL)"
+	order := "c.count DESC, a.id ASC"
+	if metric == models.ChartRatings {
+		value = "CAST(c.total AS REAL) / c.count"
+		order = value + " DESC, c.count DESC, a.id ASC"
+		where += " AND c.count >= ?"
+		args = append(args, minRatings)
+	}
+
+	rows, err := r.DB.Query("SELECT "+albumColumns+", "+value+", c.count FROM chart_counters c JOIN albums a ON a.id = c.album_id"+
+		where+" ORDER BY "+order+" LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.ChartEntry{}
+	for rows.Next() {
+		var entry models.ChartEntry
+		if err := rows.Scan(append(albumFields(&entry.Album), &entry.Value, &entry.Count)...); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetChart retrieves a stored chart for a genre key, or "" for the overall chart. It returns sql.ErrNoRows
+// if the chart has not been stored.
+func (r *ChartRepository) GetChart(chartType, genre, period string) (*models.Chart, error) {
+	var chartID uint
+	chart := models.Chart{Type: chartType, Period: period, Final: true}
+	err := r.DB.QueryRow("SELECT id, computed_at FROM charts WHERE chart_type = ? AND genre = ? AND period = ?", chartType, genre, period).
+		Scan(&chartID, &chart.ComputedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(`
+        SELECT e.position, `+albumColumns+`, e.value, e.count, COALESCE(e.previous_position, 0), e.movement
+        FROM chart_entries e
+        JOIN albums a ON a.id = e.album_id
+        WHERE e.chart_id = ?
+        ORDER BY e.position ASC`, chartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chart.Entries = []models.ChartEntry{}
+	for rows.Next() {
+		var entry models.ChartEntry
+		fields := append([]interface{}{&entry.Position}, albumFields(&entry.Album)...)
+		if err := rows.Scan(append(fields, &entry.Value, &entry.Count, &entry.PreviousPosition, &entry.Movement)...); err != nil {
+			return nil, err
+		}
+		chart.Entries = append(chart.Entries, entry)
+	}
+	return &chart, rows.Err()
+}
+
+// SaveChart stores a chart under a genre key, or "" for the overall chart. A chart stored in the meantime
+// is kept.
+func (r *ChartRepository) SaveChart(chart *models.Chart, genre string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO charts (chart_type, genre, period, computed_at) VALUES (?, ?, ?, ?) ON CONFLICT (chart_type, genre, period) DO NOTHING",
+		chart.Type, genre, chart.Period, chart.ComputedAt)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	chartID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range chart.Entries {
+		var previous interface{}
+		if entry.PreviousPosition != 0 {
+			previous = entry.PreviousPosition
+		}
+		_, err := tx.Exec("INSERT INTO chart_entries (chart_id, position, album_id, value, count, previous_position, movement) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			chartID, entry.Position, entry.Album.ID, entry.Value, entry.Count, previous, entry.Movement)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func seedChartAlbums(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+		INSERT INTO albums (id, name, release_date, genre, description, price_minor) VALUES
+		(1, 'Album 1', '2000-01-01', '', '', 1000), (2, 'Album 2', '2000-01-01', '', '', 1000), (3, 'Album 3', '2000-01-01', '', '', 1000);
+		INSERT INTO genres (id, name, key, parent_id) VALUES (1, 'Rock', 'rock', NULL), (2, 'Heavy Metal', 'heavy metal', 1);
+		INSERT INTO album_genres (album_id, genre_id) VALUES (2, 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed albums: %v", err)
+	}
+}
+
+func TestAggregatePeriod(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &ChartRepository{DB: db}
+	seedChartAlbums(t, db)
+
+	_, err := db.Exec(`
+		INSERT INTO orders (id, cart_id, status, country, currency, subtotal_minor, tax_rate, tax_minor, total_minor, created_at, updated_at) VALUES
+		(1, 1, 'paid', 'GB', 'GBP', 0, 0, 0, 0, '2026-10-05T10:00:00Z', ''),
+		(2, 2, 'shipped', 'GB', 'GBP', 0, 0, 0, 0, '2026-10-11T23:59:59Z', ''),
+		(3, 3, 'cancelled', 'GB', 'GBP', 0, 0, 0, 0, '2026-10-06T10:00:00Z', ''),
+		(4, 4, 'paid', 'GB', 'GBP', 0, 0, 0, 0, '2026-10-12T00:00:00Z', '');
+		INSERT INTO order_lines (order_id, album_id, name, quantity, unit_price_minor, total_minor) VALUES
+		(1, 1, 'Album 1', 2, 0, 0), (2, 1, 'Album 1', 1, 0, 0), (2, 2, 'Album 2', 1, 0, 0), (3, 2, 'Album 2', 5, 0, 0), (4, 3, 'Album 3', 1, 0, 0);
+		INSERT INTO reviews (album_id, customer_id, rating, status, created_at, updated_at) VALUES
+		(1, 1, 4, 'approved', '2026-10-06T10:00:00Z', ''), (1, 2, 5, 'approved', '2026-10-07T10:00:00Z', ''), (1, 3, 1, 'rejected', '2026-10-07T10:00:00Z', '');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed orders: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.AggregatePeriod("2026-W41", "2026-10-05T00:00:00Z", "2026-10-12T00:00:00Z"); err != nil {
+			t.Fatalf("failed to aggregate: %v", err)
+		}
+	}
+
+	purchases, err := repo.RankAlbums(models.ChartPurchases, "2026-W41", "", 0, 10)
+	if err != nil {
+		t.Fatalf("failed to rank purchases: %v", err)
+	}
+	if len(purchases) != 2 || purchases[0].Album.ID != 1 || purchases[0].Count != 3 || purchases[1].Album.ID != 2 || purchases[1].Count != 1 {
+		t.Errorf("expected copies of paid and shipped orders in the week counted once, got %+v", purchases)
+	}
+
+	ratings, err := repo.RankAlbums(models.ChartRatings, "2026-W41", "", 2, 10)
+	if err != nil {
+		t.Fatalf("failed to rank ratings: %v", err)
+	}
+	if len(ratings) != 1 || ratings[0].Value != 4.5 || ratings[0].Count != 2 {
+		t.Errorf("expected the average of approved ratings, got %+v", ratings)
+	}
+	if ratings, _ := repo.RankAlbums(models.ChartRatings, "2026-W41", "", 3, 10); len(ratings) != 0 {
+		t.Errorf("expected albums with too few ratings to be left out, got %+v", ratings)
+	}
+}
+
+func TestRankAlbumPlays(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &ChartRepository{DB: db}
+	seedChartAlbums(t, db)
+
+	for _, plays := range []struct {
+		period  string
+		albumID uint
+		count   int
+	}{{"2026-W41", 1, 2}, {"2026-W41", 2, 3}, {"2026-W41", 1, 2}, {"2026-W41", 3, 4}, {"2026-W40", 1, 10}} {
+		if err := repo.RecordPlays(plays.period, plays.albumID, plays.count); err != nil {
+			t.Fatalf("failed to record plays: %v", err)
+		}
+	}
+
+	entries, err := repo.RankAlbums(models.ChartPlays, "2026-W41", "", 0, 10)
+	if err != nil {
+		t.Fatalf("failed to rank plays: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Album.ID != 1 || entries[0].Value != 4 || entries[1].Album.ID != 3 || entries[2].Album.ID != 2 {
+		t.Errorf("expected plays of the week added up and ties broken by album ID, got %+v", entries)
+	}
+
+	entries, err = repo.RankAlbums(models.ChartPlays, "2026-W41", "rock", 0, 10)
+	if err != nil {
+		t.Fatalf("failed to rank plays by genre: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Album.ID != 2 {
+		t.Errorf("expected only albums in a subgenre of rock, got %+v", entries)
+	}
+}
+
+func TestSaveChart(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &ChartRepository{DB: db}
+	seedChartAlbums(t, db)
+
+	if _, err := repo.GetChart(models.ChartPlays, "", "2026-W41"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows before the chart is stored, got %v", err)
+	}
+
+	chart := &models.Chart{Type: models.ChartPlays, Period: "2026-W41", ComputedAt: "2026-10-12T00:00:00Z", Entries: []models.ChartEntry{
+		{Position: 1, Album: models.Album{ID: 2}, Value: 5, Count: 5, PreviousPosition: 3, Movement: models.MovementUp},
+		{Position: 2, Album: models.Album{ID: 1}, Value: 4, Count: 4, Movement: models.MovementNew},
+	}}
+	if err := repo.SaveChart(chart, ""); err != nil {
+		t.Fatalf("failed to save chart: %v", err)
+	}
+	replacement := &models.Chart{Type: models.ChartPlays, Period: "2026-W41", ComputedAt: "2026-10-13T00:00:00Z"}
+	if err := repo.SaveChart(replacement, ""); err != nil {
+		t.Fatalf("failed to save chart again: %v", err)
+	}
+
+	stored, err := repo.GetChart(models.ChartPlays, "", "2026-W41")
+	if err != nil {
+		t.Fatalf("failed to get chart: %v", err)
+	}
+	if !stored.Final || stored.ComputedAt != "2026-10-12T00:00:00Z" || len(stored.Entries) != 2 {
+		t.Fatalf("expected the first stored chart to be kept, got %+v", stored)
+	}
+	first, second := stored.Entries[0], stored.Entries[1]
+	if first.Album.Name != "Album 2" || first.PreviousPosition != 3 || first.Movement != models.MovementUp {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if second.Position != 2 || second.PreviousPosition != 0 || second.Movement != models.MovementNew {
+		t.Errorf("unexpected second entry %+v", second)
+	}
+	if _, err := repo.GetChart(models.ChartPlays, "rock", "2026-W41"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected genre charts to be stored separately, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+// maxInClause bounds how many IDs go into one IN list, keeping queries within SQLite's variable limit.
+const maxInClause = 500
+
+type CollaborationRepository struct {
+	DB *sql.DB
+}
+
+// GetCollaborators retrieves the musicians credited on albums together with a musician, most shared albums
+// first and then by name.
+func (r *CollaborationRepository) GetCollaborators(musicianID uint) ([]models.Collaborator, error) {
+	rows, err := r.DB.Query(`
+        SELECT m.id, m.name, m.musician_type, m.kind, COUNT(DISTINCT other.album_id)
+        FROM album_musicians am
+        JOIN album_musicians other ON other.album_id = am.album_id AND other.musician_id != am.musician_id
+        JOIN musicians m ON m.id = other.musician_id
+        WHERE am.musician_id = ?
+        GROUP BY m.id
+        ORDER BY COUNT(DISTINCT other.album_id) DESC, m.name ASC`, musicianID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborators := []models.Collaborator{}
+	for rows.Next() {
+		var collaborator models.Collaborator
+		if err := rows.Scan(&collaborator.ID, &collaborator.Name, &collaborator.MusicianType, &collaborator.Kind, &collaborator.SharedAlbums); err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, collaborator)
+	}
+	return collaborators, rows.Err()
+}
+
+// GetCollaborations retrieves every collaboration of several musicians, one per collaborator and shared
+// album, sorted by musician, collaborator and album. Large lists are queried in chunks.
+func (r *CollaborationRepository) GetCollaborations(musicianIDs []uint) ([]models.Collaboration, error) {
+	var collaborations []models.Collaboration
+	for start := 0; start < len(musicianIDs); start += maxInClause {
+		chunk := musicianIDs[start:min(start+maxInClause, len(musicianIDs))]
+		placeholders, args := inClause(chunk)
+		rows, err := r.DB.Query(`
+            SELECT DISTINCT am.musician_id, other.musician_id, am.album_id
+            FROM album_musicians am
+            JOIN album_musicians other ON other.album_id = am.album_id AND other.musician_id != am.musician_id
+            WHERE am.musician_id IN (`+placeholders+`)
+            ORDER BY am.musician_id, other.musician_id, am.album_id`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var collaboration models.Collaboration
+			if err := rows.Scan(&collaboration.MusicianID, &collaboration.CollaboratorID, &collaboration.AlbumID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			collaborations = append(collaborations, collaboration)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return collaborations, nil
+}
+
+// GetAlbumCredits retrieves the distinct musicians credited on each album, keyed by album ID.
+func (r *CollaborationRepository) GetAlbumCredits() (map[uint][]uint, error) {
+	rows, err := r.DB.Query("SELECT DISTINCT album_id, musician_id FROM album_musicians ORDER BY album_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := make(map[uint][]uint)
+	for rows.Next() {
+		var albumID, musicianID uint
+		if err := rows.Scan(&albumID, &musicianID); err != nil {
+			return nil, err
+		}
+		credits[albumID] = append(credits[albumID], musicianID)
+	}
+	return credits, rows.Err()
+}
+
+// GetMusicianIDs retrieves the ID of every musician.
+func (r *CollaborationRepository) GetMusicianIDs() ([]uint, error) {
+	rows, err := r.DB.Query("SELECT id FROM musicians ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CountCollaborations counts the distinct pairs of musicians credited on the same album.
+func (r *CollaborationRepository) CountCollaborations() (int, error) {
+	var count int
+	err := r.DB.QueryRow(`
+        SELECT COUNT(*) FROM (
+            SELECT DISTINCT am.musician_id, other.musician_id
+            FROM album_musicians am
+            JOIN album_musicians other ON other.album_id = am.album_id AND other.musician_id > am.musician_id
+        )`).Scan(&count)
+	return count, err
+}
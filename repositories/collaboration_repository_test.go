@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"slices"
+	"testing"
+)
+
+func TestGetCollaborations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &CollaborationRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'Ann', 'Singer'), (2, 'Bob', 'Drummer'), (3, 'Cat', 'Bassist');
+		INSERT INTO album_musicians (album_id, musician_id, role) VALUES
+		(1, 1, 'performer'), (1, 1, 'producer'), (1, 2, 'performer'), (2, 2, 'performer'), (2, 3, 'performer');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed collaborations: %v", err)
+	}
+
+	// More IDs than fit in one IN list are queried in chunks
+	ids := make([]uint, maxInClause+10)
+	for i := range ids {
+		ids[i] = uint(len(ids) - i)
+	}
+	collaborations, err := repo.GetCollaborations(ids)
+	if err != nil {
+		t.Fatalf("failed to get collaborations: %v", err)
+	}
+	expected := []models.Collaboration{
+		{MusicianID: 1, CollaboratorID: 2, AlbumID: 1},
+		{MusicianID: 2, CollaboratorID: 1, AlbumID: 1},
+		{MusicianID: 2, CollaboratorID: 3, AlbumID: 2},
+		{MusicianID: 3, CollaboratorID: 2, AlbumID: 2},
+	}
+	if !slices.Equal(collaborations, expected) {
+		t.Errorf("expected %+v, got %+v", expected, collaborations)
+	}
+
+	count, err := repo.CountCollaborations()
+	if err != nil {
+		t.Fatalf("failed to count collaborations: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 pairs of collaborators, got %d", count)
+	}
+}
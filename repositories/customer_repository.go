@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+)
+
+// ErrEmailTaken is returned when registering an email address that already has an account.
+var ErrEmailTaken = errors.New("email is already registered")
+
+type CustomerRepository struct {
+	DB *sql.DB
+}
+
+// CreateCustomer inserts a customer with a password hash and sets its ID. It returns ErrEmailTaken if the
+// email address is already registered.
+func (r *CustomerRepository) CreateCustomer(customer *models.Customer, passwordHash string) error {
+	result, err := r.DB.Exec(`
+        INSERT INTO customers (email, name, password_hash, created_at) VALUES (?, ?, ?, ?)
+        ON CONFLICT (email) DO NOTHING`,
+		customer.Email, customer.Name, passwordHash, customer.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEmailTaken
+		}
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	customer.ID = uint(id)
+	return nil
+}
+
+// GetCustomerByEmail retrieves a customer with their password hash. It returns sql.ErrNoRows if no customer
+// has the email address.
+func (r *CustomerRepository) GetCustomerByEmail(email string) (*models.Customer, string, error) {
+	var customer models.Customer
+	var passwordHash string
+	err := r.DB.QueryRow("SELECT id, email, name, created_at, is_admin, password_hash FROM customers WHERE email = ?", email).
+		Scan(&customer.ID, &customer.Email, &customer.Name, &customer.CreatedAt, &customer.Admin, &passwordHash)
+	if err != nil {
+		return nil, "", err
+	}
+	return &customer, passwordHash, nil
+}
+
+// CreateSession stores a session under the hash of its token, clearing out sessions that expired before it
+// was created.
+func (r *CustomerRepository) CreateSession(tokenHash string, customerID uint, createdAt, expiresAt string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM sessions WHERE expires_at <= ?", createdAt); err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO sessions (token_hash, customer_id, created_at, expires_at) VALUES (?, ?, ?, ?)",
+		tokenHash, customerID, createdAt, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetSessionCustomer retrieves the customer of the session with a token hash that is still valid at now.
+// It returns sql.ErrNoRows if there is no such session.
+func (r *CustomerRepository) GetSessionCustomer(tokenHash, now string) (*models.Customer, error) {
+	var customer models.Customer
+	err := r.DB.QueryRow(`
+        SELECT c.id, c.email, c.name, c.created_at, c.is_admin
+        FROM sessions s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE s.token_hash = ? AND s.expires_at > ?`, tokenHash, now).
+		Scan(&customer.ID, &customer.Email, &customer.Name, &customer.CreatedAt, &customer.Admin)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// DeleteSession removes the session with a token hash. It returns sql.ErrNoRows if there is none.
+func (r *CustomerRepository) DeleteSession(tokenHash string) error {
+	result, err := r.DB.Exec("DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// AddLibraryEntry adds an album to a customer's library by hand. An album the customer already owns keeps
+// its original entry.
+func (r *CustomerRepository) AddLibraryEntry(customerID, albumID uint, addedAt string) error {
+	_, err := r.DB.Exec(`
+        INSERT INTO library_entries (customer_id, album_id, source, added_at) VALUES (?, ?, ?, ?)
+        ON CONFLICT (customer_id, album_id) DO NOTHING`,
+		customerID, albumID, models.LibraryManual, addedAt)
+	return err
+}
+
+// DeleteLibraryEntry removes an album from a customer's library. It returns sql.ErrNoRows if the customer
+// does not own the album.
+func (r *CustomerRepository) DeleteLibraryEntry(customerID, albumID uint) error {
+	result, err := r.DB.Exec("DELETE FROM library_entries WHERE customer_id = ? AND album_id = ?", customerID, albumID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetLibraryEntries retrieves the entries of a customer's library, most recently added first. Only the ID of
+// each entry's album is filled in.
+func (r *CustomerRepository) GetLibraryEntries(customerID uint) ([]models.LibraryEntry, error) {
+	rows, err := r.DB.Query(`
+        SELECT album_id, source, COALESCE(order_id, 0), added_at
+        FROM library_entries
+        WHERE customer_id = ?
+        ORDER BY added_at DESC, album_id ASC`, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LibraryEntry{}
+	for rows.Next() {
+		var entry models.LibraryEntry
+		if err := rows.Scan(&entry.Album.ID, &entry.Source, &entry.OrderID, &entry.AddedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
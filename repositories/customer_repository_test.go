@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestCustomerSessions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := CustomerRepository{DB: db}
+	customer := &models.Customer{Email: "ann@example.com", Name: "Ann", CreatedAt: "2024-01-01T00:00:00Z"}
+	if err := repo.CreateCustomer(customer, "hash"); err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+	if err := repo.CreateCustomer(&models.Customer{Email: "ann@example.com", Name: "Other Ann", CreatedAt: "2024-01-01T00:00:00Z"}, "hash"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("expected ErrEmailTaken, got %v", err)
+	}
+
+	if err := repo.CreateSession("old", customer.ID, "2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if signedIn, err := repo.GetSessionCustomer("old", "2024-01-15T00:00:00Z"); err != nil || signedIn.ID != customer.ID {
+		t.Errorf("expected the session to be valid, got %+v, %v", signedIn, err)
+	}
+	if _, err := repo.GetSessionCustomer("old", "2024-02-01T00:00:00Z"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected an expired session to be refused, got %v", err)
+	}
+
+	// Creating a session clears out the expired ones
+	if err := repo.CreateSession("new", customer.ID, "2024-02-01T00:00:00Z", "2024-03-02T00:00:00Z"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	var sessions int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&sessions); err != nil {
+		t.Fatalf("failed to count sessions: %v", err)
+	}
+	if sessions != 1 {
+		t.Errorf("expected only the new session to be left, got %d", sessions)
+	}
+	if err := repo.DeleteSession("old"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
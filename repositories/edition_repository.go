@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type EditionRepository struct {
+	DB *sql.DB
+	// Outbox, when set, receives an album-updated event whenever editions change an album's price or release
+	// date, in the same transaction.
+	Outbox *OutboxRepository
+}
+
+// CreateEdition adds an edition to an album, sets its ID and updates the album's price and release date.
+// Pending price changes of the album are cancelled, as the album now takes its price from its editions.
+func (r *EditionRepository) CreateEdition(edition *models.Edition) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	currency, err := albumCurrency(tx, edition.AlbumID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("INSERT INTO editions (album_id, format, name, barcode, release_date, price_minor) VALUES (?, ?, ?, ?, ?, ?)",
+		edition.AlbumID, edition.Format, edition.Name, nullableString(edition.Barcode), edition.ReleaseDate, models.ToMinor(edition.Price, currency))
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	edition.ID = uint(id)
+
+	if _, err := tx.Exec("DELETE FROM price_schedules WHERE album_id = ? AND applied_at IS NULL", edition.AlbumID); err != nil {
+		return err
+	}
+	if err := r.syncAlbum(tx, edition.AlbumID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateEdition updates an edition of an album. It returns sql.ErrNoRows if the album has no such edition.
+func (r *EditionRepository) UpdateEdition(edition *models.Edition) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	currency, err := albumCurrency(tx, edition.AlbumID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("UPDATE editions SET format = ?, name = ?, barcode = ?, release_date = ?, price_minor = ? WHERE id = ? AND album_id = ?",
+		edition.Format, edition.Name, nullableString(edition.Barcode), edition.ReleaseDate, models.ToMinor(edition.Price, currency), edition.ID, edition.AlbumID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := r.syncAlbum(tx, edition.AlbumID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteEdition removes an edition from an album. It returns sql.ErrNoRows if the album has no such edition.
+func (r *EditionRepository) DeleteEdition(albumID, editionID uint) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM editions WHERE id = ? AND album_id = ?", editionID, albumID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := r.syncAlbum(tx, albumID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetEditionsByAlbum retrieves the editions of an album sorted by release date, then price.
+func (r *EditionRepository) GetEditionsByAlbum(albumID uint) ([]models.Edition, error) {
+	rows, err := r.DB.Query(`
+        SELECT e.id, e.album_id, e.format, e.name, COALESCE(e.barcode, ''), e.release_date, e.price_minor, a.currency
+        FROM editions e
+        JOIN albums a ON a.id = e.album_id
+        WHERE e.album_id = ?
+        ORDER BY e.release_date ASC, e.price_minor ASC, e.id ASC
+    `, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	editions := []models.Edition{}
+	for rows.Next() {
+		var edition models.Edition
+		var priceMinor int64
+		var currency string
+		if err := rows.Scan(&edition.ID, &edition.AlbumID, &edition.Format, &edition.Name, &edition.Barcode, dateOnly{&edition.ReleaseDate}, &priceMinor, &currency); err != nil {
+			return nil, err
+		}
+		edition.Price = models.FromMinor(priceMinor, currency)
+		editions = append(editions, edition)
+	}
+	return editions, rows.Err()
+}
+
+// BarcodeTaken reports whether another edition than excludeEditionID already has the barcode.
+func (r *EditionRepository) BarcodeTaken(barcode string, excludeEditionID uint) (bool, error) {
+	var taken bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM editions WHERE barcode = ? AND id != ?)", barcode, excludeEditionID).Scan(&taken)
+	return taken, err
+}
+
+// SyncAlbum sets an album's price and release date to those of its cheapest and earliest editions.
+func (r *EditionRepository) SyncAlbum(albumID uint) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.syncAlbum(tx, albumID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// albumCurrency returns the currency an album and its editions are priced in.
+func albumCurrency(tx *sql.Tx, albumID uint) (string, error) {
+	var currency string
+	err := tx.QueryRow("SELECT currency FROM albums WHERE id = ?", albumID).Scan(&currency)
+	return currency, err
+}
+
+// syncAlbum keeps the album row, which GET /albums reports, in line with its editions. Albums
+// without editions keep the price and release date they were given. An album that changes is
+// announced like any other album update.
+func (r *EditionRepository) syncAlbum(tx *sql.Tx, albumID uint) error {
+	result, err := tx.Exec(`
+        UPDATE albums
+        SET price_minor = e.price_minor, release_date = e.release_date
+        FROM (SELECT MIN(price_minor) AS price_minor, MIN(release_date) AS release_date FROM editions WHERE album_id = ?) e
+        WHERE albums.id = ? AND e.price_minor IS NOT NULL
+            AND (albums.price_minor IS NOT e.price_minor OR albums.release_date IS NOT e.release_date)`,
+		albumID, albumID)
+	if err != nil {
+		return err
+	}
+	changed, err := result.RowsAffected()
+	if err != nil || changed == 0 || r.Outbox == nil {
+		return err
+	}
+
+	var album models.Album
+	if err := tx.QueryRow("SELECT "+albumColumns+" FROM albums a WHERE a.id = ?", albumID).Scan(albumFields(&album)...); err != nil {
+		return err
+	}
+	return r.Outbox.Append(tx, "album", albumID, models.EventAlbumUpdated, &album)
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestEditionsSetAlbumPriceAndReleaseDate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	repo := EditionRepository{DB: db}
+
+	album := &models.Album{Name: "Blue Train", ReleaseDate: "1958-01-01", Price: 500}
+	if err := albums.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	vinyl := &models.Edition{AlbumID: album.ID, Format: models.FormatVinyl, ReleaseDate: "1957-09-01", Price: 300}
+	cd := &models.Edition{AlbumID: album.ID, Format: models.FormatCD, Barcode: "4006381333931", ReleaseDate: "1990-01-01", Price: 150}
+	for _, edition := range []*models.Edition{vinyl, cd} {
+		if err := repo.CreateEdition(edition); err != nil {
+			t.Fatalf("failed to create edition: %v", err)
+		}
+	}
+
+	stored, err := albums.GetAlbumByID(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if stored.Price != 150 || stored.ReleaseDate != "1957-09-01" {
+		t.Errorf("expected lowest price and earliest date, got %v and %s", stored.Price, stored.ReleaseDate)
+	}
+
+	if err := repo.DeleteEdition(album.ID, cd.ID); err != nil {
+		t.Fatalf("failed to delete edition: %v", err)
+	}
+	stored, err = albums.GetAlbumByID(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if stored.Price != 300 {
+		t.Errorf("expected price to follow the remaining edition, got %v", stored.Price)
+	}
+
+	editions, err := repo.GetEditionsByAlbum(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get editions: %v", err)
+	}
+	if len(editions) != 1 || editions[0].Format != models.FormatVinyl {
+		t.Errorf("unexpected editions: %+v", editions)
+	}
+
+	if err := repo.DeleteEdition(99, vinyl.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestEditionsAnnounceAlbumChanges(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	outbox := &OutboxRepository{DB: db}
+	albums := AlbumRepository{DB: db}
+	repo := EditionRepository{DB: db, Outbox: outbox}
+
+	album := &models.Album{Name: "Blue Train", ReleaseDate: "1958-01-01", Price: 500}
+	if err := albums.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	vinyl := &models.Edition{AlbumID: album.ID, Format: models.FormatVinyl, ReleaseDate: "1957-09-01", Price: 300}
+	reissue := &models.Edition{AlbumID: album.ID, Format: models.FormatVinyl, ReleaseDate: "2003-01-01", Price: 400}
+	for _, edition := range []*models.Edition{vinyl, reissue} {
+		if err := repo.CreateEdition(edition); err != nil {
+			t.Fatalf("failed to create edition: %v", err)
+		}
+	}
+
+	events, err := outbox.GetPendingEvents(10)
+	if err != nil {
+		t.Fatalf("failed to get events: %v", err)
+	}
+	// The reissue changes neither the lowest price nor the earliest date
+	if len(events) != 1 || events[0].Type != models.EventAlbumUpdated || events[0].AggregateID != album.ID {
+		t.Fatalf("expected one album-updated event, got %+v", events)
+	}
+	var payload models.Album
+	if err := json.Unmarshal([]byte(events[0].Payload), &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Price != 300 || payload.ReleaseDate != "1957-09-01" {
+		t.Errorf("expected the synced price and date in the event, got %+v", payload)
+	}
+}
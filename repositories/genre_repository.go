@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type GenreRepository struct {
+	DB *sql.DB
+}
+
+// CreateGenre inserts a genre with its aliases and sets its ID.
+func (r *GenreRepository) CreateGenre(genre *models.Genre) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO genres (name, key, parent_id) VALUES (?, ?, ?)",
+		genre.Name, models.GenreKey(genre.Name), nullableID(genre.ParentID))
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	genre.ID = uint(id)
+
+	if err := insertAliases(tx, genre); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateGenre replaces a genre's name, parent and aliases. It returns sql.ErrNoRows if the genre does not exist.
+func (r *GenreRepository) UpdateGenre(genre *models.Genre) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE genres SET name = ?, key = ?, parent_id = ? WHERE id = ?",
+		genre.Name, models.GenreKey(genre.Name), nullableID(genre.ParentID), genre.ID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM genre_aliases WHERE genre_id = ?", genre.ID); err != nil {
+		return err
+	}
+	if err := insertAliases(tx, genre); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertAliases(tx *sql.Tx, genre *models.Genre) error {
+	for _, alias := range genre.Aliases {
+		if _, err := tx.Exec("INSERT INTO genre_aliases (key, alias, genre_id) VALUES (?, ?, ?)", models.GenreKey(alias), alias, genre.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteGenre deletes a genre, its aliases and its album tags. It returns sql.ErrNoRows if the genre does not exist.
+func (r *GenreRepository) DeleteGenre(id uint) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM album_genres WHERE genre_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM genre_aliases WHERE genre_id = ?", id); err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM genres WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetGenres retrieves every genre with its aliases sorted by name.
+func (r *GenreRepository) GetGenres() ([]models.Genre, error) {
+	rows, err := r.DB.Query("SELECT id, name, COALESCE(parent_id, 0) FROM genres ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	index := make(map[uint]int)
+	for rows.Next() {
+		genre := models.Genre{Aliases: []string{}}
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.ParentID); err != nil {
+			return nil, err
+		}
+		index[genre.ID] = len(genres)
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	aliases, err := r.DB.Query("SELECT genre_id, alias FROM genre_aliases ORDER BY alias ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer aliases.Close()
+
+	for aliases.Next() {
+		var genreID uint
+		var alias string
+		if err := aliases.Scan(&genreID, &alias); err != nil {
+			return nil, err
+		}
+		if i, ok := index[genreID]; ok {
+			genres[i].Aliases = append(genres[i].Aliases, alias)
+		}
+	}
+	return genres, aliases.Err()
+}
+
+// GetGenreByID retrieves a genre with its aliases. It returns sql.ErrNoRows if the genre does not exist.
+func (r *GenreRepository) GetGenreByID(id uint) (*models.Genre, error) {
+	genre := models.Genre{Aliases: []string{}}
+	err := r.DB.QueryRow("SELECT id, name, COALESCE(parent_id, 0) FROM genres WHERE id = ?", id).
+		Scan(&genre.ID, &genre.Name, &genre.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query("SELECT alias FROM genre_aliases WHERE genre_id = ? ORDER BY alias ASC", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		genre.Aliases = append(genre.Aliases, alias)
+	}
+	return &genre, rows.Err()
+}
+
+// FindGenreID resolves a genre name or alias to a genre ID. It returns sql.ErrNoRows if nothing matches.
+func (r *GenreRepository) FindGenreID(name string) (uint, error) {
+	var id uint
+	key := models.GenreKey(name)
+	err := r.DB.QueryRow("SELECT id FROM genres WHERE key = ? UNION SELECT genre_id FROM genre_aliases WHERE key = ?", key, key).Scan(&id)
+	return id, err
+}
+
+// HasChildren reports whether any genre has the genre as its parent.
+func (r *GenreRepository) HasChildren(id uint) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM genres WHERE parent_id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// GetAlbumGenres retrieves the genres an album is tagged with sorted by name.
+func (r *GenreRepository) GetAlbumGenres(albumID uint) ([]models.Genre, error) {
+	rows, err := r.DB.Query(`
+        SELECT g.id, g.name, COALESCE(g.parent_id, 0)
+        FROM genres g
+        JOIN album_genres ag ON ag.genre_id = g.id
+        WHERE ag.album_id = ?
+        ORDER BY g.name ASC
+    `, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		genre := models.Genre{Aliases: []string{}}
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.ParentID); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
+
+// SetAlbumGenres replaces the genres an album is tagged with.
+func (r *GenreRepository) SetAlbumGenres(albumID uint, genreIDs []uint) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM album_genres WHERE album_id = ?", albumID); err != nil {
+		return err
+	}
+	for _, genreID := range genreIDs {
+		if err := tagAlbum(tx, albumID, genreID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// TagAlbum adds a genre to an album unless it is already tagged with it.
+func (r *GenreRepository) TagAlbum(albumID, genreID uint) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tagAlbum(tx, albumID, genreID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UntagAlbum removes a genre from an album, if it is tagged with it.
+func (r *GenreRepository) UntagAlbum(albumID, genreID uint) error {
+	_, err := r.DB.Exec("DELETE FROM album_genres WHERE album_id = ? AND genre_id = ?", albumID, genreID)
+	return err
+}
+
+func tagAlbum(tx *sql.Tx, albumID, genreID uint) error {
+	_, err := tx.Exec(`
+        INSERT INTO album_genres (album_id, genre_id)
+        SELECT ?, ? WHERE NOT EXISTS (SELECT 1 FROM album_genres WHERE album_id = ? AND genre_id = ?)`,
+		albumID, genreID, albumID, genreID)
+	return err
+}
+
+// AlbumGenresBackfilled reports whether albums have been tagged from their genre text once already.
+func (r *GenreRepository) AlbumGenresBackfilled() (bool, error) {
+	var done bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM backfills WHERE name = 'album_genres')").Scan(&done)
+	return done, err
+}
+
+// MarkAlbumGenresBackfilled records that albums have been tagged from their genre text.
+func (r *GenreRepository) MarkAlbumGenresBackfilled(at string) error {
+	_, err := r.DB.Exec("INSERT OR IGNORE INTO backfills (name, completed_at) VALUES ('album_genres', ?)", at)
+	return err
+}
+
+// GetUntaggedAlbums retrieves the albums that have genre text but no genre tags yet.
+func (r *GenreRepository) GetUntaggedAlbums() ([]models.Album, error) {
+	rows, err := r.DB.Query(`
+        SELECT ` + albumColumns + `
+        FROM albums a
+        WHERE COALESCE(a.genre, '') != ''
+          AND NOT EXISTS (SELECT 1 FROM album_genres ag WHERE ag.album_id = a.id)
+        ORDER BY a.id ASC
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		var album models.Album
+		if err := rows.Scan(albumFields(&album)...); err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
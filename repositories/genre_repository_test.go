@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestGenreAliasesAndLookup(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := GenreRepository{DB: db}
+
+	genre := &models.Genre{Name: "Hip Hop", Aliases: []string{"Rap"}}
+	if err := repo.CreateGenre(genre); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+
+	for _, name := range []string{"hip-hop", "HIP HOP", "rap"} {
+		id, err := repo.FindGenreID(name)
+		if err != nil || id != genre.ID {
+			t.Errorf("expected %q to resolve to genre %d, got %d (%v)", name, genre.ID, id, err)
+		}
+	}
+
+	genre.Aliases = []string{"Hiphop"}
+	if err := repo.UpdateGenre(genre); err != nil {
+		t.Fatalf("failed to update genre: %v", err)
+	}
+	if _, err := repo.FindGenreID("rap"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected replaced alias to be gone, got %v", err)
+	}
+
+	stored, err := repo.GetGenreByID(genre.ID)
+	if err != nil {
+		t.Fatalf("failed to get genre: %v", err)
+	}
+	if len(stored.Aliases) != 1 || stored.Aliases[0] != "Hiphop" {
+		t.Errorf("unexpected aliases: %+v", stored.Aliases)
+	}
+}
+
+func TestGetAlbumsByGenreIncludesDescendants(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	genres := GenreRepository{DB: db}
+	albums := AlbumRepository{DB: db}
+
+	rock := &models.Genre{Name: "Rock"}
+	if err := genres.CreateGenre(rock); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	metal := &models.Genre{Name: "Metal", ParentID: rock.ID, Aliases: []string{"Heavy Metal"}}
+	if err := genres.CreateGenre(metal); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	jazz := &models.Genre{Name: "Jazz"}
+	if err := genres.CreateGenre(jazz); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+
+	for _, album := range []*models.Album{
+		{Name: "Paranoid", ReleaseDate: "1970-09-18", Price: 10},
+		{Name: "Led Zeppelin IV", ReleaseDate: "1971-11-08", Price: 10},
+		{Name: "Kind of Blue", ReleaseDate: "1959-08-17", Price: 10},
+	} {
+		if err := albums.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	if err := genres.SetAlbumGenres(1, []uint{metal.ID}); err != nil {
+		t.Fatalf("failed to tag album: %v", err)
+	}
+	if err := genres.SetAlbumGenres(2, []uint{rock.ID, metal.ID}); err != nil {
+		t.Fatalf("failed to tag album: %v", err)
+	}
+	if err := genres.TagAlbum(3, jazz.ID); err != nil {
+		t.Fatalf("failed to tag album: %v", err)
+	}
+
+	tests := []struct {
+		genre string
+		want  int
+	}{
+		{"rock", 2},
+		{"heavy metal", 2},
+		{"Jazz", 1},
+		{"polka", 0},
+	}
+	for _, tt := range tests {
+		result, err := albums.GetAlbums(models.AlbumFilter{Genre: tt.genre})
+		if err != nil {
+			t.Fatalf("failed to get albums: %v", err)
+		}
+		if len(result) != tt.want {
+			t.Errorf("genre %q: expected %d albums, got %d", tt.genre, tt.want, len(result))
+		}
+	}
+
+	tagged, err := genres.GetAlbumGenres(2)
+	if err != nil {
+		t.Fatalf("failed to get album genres: %v", err)
+	}
+	if len(tagged) != 2 || tagged[0].Name != "Metal" || tagged[1].Name != "Rock" {
+		t.Errorf("unexpected album genres: %+v", tagged)
+	}
+}
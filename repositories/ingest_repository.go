@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type IngestRepository struct {
+	DB *sql.DB
+}
+
+// FindMusicians retrieves the musicians with a name, ignoring case, oldest first.
+func (r *IngestRepository) FindMusicians(name string) ([]models.Musician, error) {
+	rows, err := r.DB.Query("SELECT id, name, musician_type, kind FROM musicians WHERE name = ? COLLATE NOCASE ORDER BY id", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var musicians []models.Musician
+	for rows.Next() {
+		var musician models.Musician
+		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind); err != nil {
+			return nil, err
+		}
+		musicians = append(musicians, musician)
+	}
+	return musicians, rows.Err()
+}
+
+// FindAlbums retrieves the albums with a name, ignoring case, oldest first.
+func (r *IngestRepository) FindAlbums(name string) ([]models.Album, error) {
+	rows, err := r.DB.Query("SELECT "+albumColumns+" FROM albums a WHERE a.name = ? COLLATE NOCASE ORDER BY a.id", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var albums []models.Album
+	for rows.Next() {
+		var album models.Album
+		if err := rows.Scan(albumFields(&album)...); err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
+
+const conflictColumns = "id, file_name, kind, entity_id, field, existing, incoming, status, created_at, COALESCE(reviewed_at, '')"
+
+func conflictFields(conflict *models.IngestConflict) []interface{} {
+	return []interface{}{&conflict.ID, &conflict.FileName, &conflict.Kind, &conflict.EntityID, &conflict.Field,
+		&conflict.Existing, &conflict.Incoming, &conflict.Status, &conflict.CreatedAt, &conflict.ReviewedAt}
+}
+
+// CreateConflict records an open conflict. When the same disagreement is already open, as when a file is
+// ingested again, the open conflict is kept and conflict is replaced by it.
+func (r *IngestRepository) CreateConflict(conflict *models.IngestConflict) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT "+conflictColumns+" FROM ingest_conflicts WHERE status = ? AND kind = ? AND entity_id = ? AND field = ? AND existing = ? AND incoming = ?",
+		models.ConflictOpen, conflict.Kind, conflict.EntityID, conflict.Field, conflict.Existing, conflict.Incoming).Scan(conflictFields(conflict)...)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	conflict.Status = models.ConflictOpen
+	result, err := tx.Exec("INSERT INTO ingest_conflicts (file_name, kind, entity_id, field, existing, incoming, status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		conflict.FileName, conflict.Kind, conflict.EntityID, conflict.Field, conflict.Existing, conflict.Incoming, conflict.Status, conflict.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	conflict.ID = uint(id)
+	return tx.Commit()
+}
+
+// GetConflicts retrieves the conflicts with a status, or every conflict for an empty status, oldest first.
+func (r *IngestRepository) GetConflicts(status string) ([]models.IngestConflict, error) {
+	query := "SELECT " + conflictColumns + " FROM ingest_conflicts"
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	rows, err := r.DB.Query(query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := []models.IngestConflict{}
+	for rows.Next() {
+		var conflict models.IngestConflict
+		if err := rows.Scan(conflictFields(&conflict)...); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, rows.Err()
+}
+
+// GetConflictByID retrieves a conflict. It returns sql.ErrNoRows if the conflict does not exist.
+func (r *IngestRepository) GetConflictByID(id uint) (*models.IngestConflict, error) {
+	var conflict models.IngestConflict
+	if err := r.DB.QueryRow("SELECT "+conflictColumns+" FROM ingest_conflicts WHERE id = ?", id).Scan(conflictFields(&conflict)...); err != nil {
+		return nil, err
+	}
+	return &conflict, nil
+}
+
+// ReviewConflict sets the status of a conflict and when it was reviewed. It returns sql.ErrNoRows if the
+// conflict does not exist.
+func (r *IngestRepository) ReviewConflict(id uint, status, reviewedAt string) error {
+	result, err := r.DB.Exec("UPDATE ingest_conflicts SET status = ?, reviewed_at = ? WHERE id = ?", status, nullableString(reviewedAt), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
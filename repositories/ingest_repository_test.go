@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestFindMusiciansAndAlbums(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &IngestRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'The Band', 'Artist'), (2, 'the band', 'Artist'), (3, 'Other Band', 'Artist');
+		INSERT INTO albums (id, name, release_date, genre, description, price_minor) VALUES
+		(1, 'Big Album', '2001-01-01', '', '', 1000), (2, 'BIG ALBUM', '2002-01-01', '', '', 1000), (3, 'Small Album', '2003-01-01', '', '', 1000);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed catalog: %v", err)
+	}
+
+	musicians, err := repo.FindMusicians("THE BAND")
+	if err != nil {
+		t.Fatalf("failed to find musicians: %v", err)
+	}
+	if len(musicians) != 2 || musicians[0].ID != 1 || musicians[1].ID != 2 {
+		t.Errorf("expected musicians 1 and 2, got %+v", musicians)
+	}
+
+	albums, err := repo.FindAlbums("big album")
+	if err != nil {
+		t.Fatalf("failed to find albums: %v", err)
+	}
+	if len(albums) != 2 || albums[0].ID != 1 || albums[1].ReleaseDate != "2002-01-01" {
+		t.Errorf("expected albums 1 and 2, got %+v", albums)
+	}
+
+	if none, err := repo.FindAlbums("Missing Album"); err != nil || len(none) != 0 {
+		t.Errorf("expected no albums, got %v, %v", none, err)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &IngestRepository{DB: db}
+
+	first := models.IngestConflict{FileName: "a.mp3", Kind: models.ConflictTrack, EntityID: 4, Field: "title", Existing: "Old", Incoming: "New", CreatedAt: "2026-10-01T10:00:00Z"}
+	if err := repo.CreateConflict(&first); err != nil {
+		t.Fatalf("failed to create conflict: %v", err)
+	}
+	if first.ID == 0 || first.Status != models.ConflictOpen {
+		t.Errorf("expected a new open conflict, got %+v", first)
+	}
+
+	again := models.IngestConflict{FileName: "b.mp3", Kind: models.ConflictTrack, EntityID: 4, Field: "title", Existing: "Old", Incoming: "New", CreatedAt: "2026-10-02T10:00:00Z"}
+	if err := repo.CreateConflict(&again); err != nil {
+		t.Fatalf("failed to create conflict: %v", err)
+	}
+	if again.ID != first.ID || again.FileName != "a.mp3" {
+		t.Errorf("expected the open conflict to be reused, got %+v", again)
+	}
+
+	if err := repo.ReviewConflict(first.ID, models.ConflictResolved, "2026-10-03T10:00:00Z"); err != nil {
+		t.Fatalf("failed to review conflict: %v", err)
+	}
+	reviewed, err := repo.GetConflictByID(first.ID)
+	if err != nil {
+		t.Fatalf("failed to get conflict: %v", err)
+	}
+	if reviewed.Status != models.ConflictResolved || reviewed.ReviewedAt != "2026-10-03T10:00:00Z" {
+		t.Errorf("unexpected reviewed conflict: %+v", reviewed)
+	}
+
+	// Once the first is resolved the same disagreement opens a new conflict
+	third := again
+	third.ID = 0
+	if err := repo.CreateConflict(&third); err != nil {
+		t.Fatalf("failed to create conflict: %v", err)
+	}
+	if third.ID == first.ID {
+		t.Error("expected a new conflict once the first was resolved")
+	}
+
+	open, err := repo.GetConflicts(models.ConflictOpen)
+	if err != nil {
+		t.Fatalf("failed to get conflicts: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != third.ID {
+		t.Errorf("expected only the new conflict to be open, got %+v", open)
+	}
+	all, err := repo.GetConflicts("")
+	if err != nil || len(all) != 2 {
+		t.Errorf("expected 2 conflicts in all, got %v, %v", all, err)
+	}
+
+	if err := repo.ReviewConflict(99, models.ConflictDismissed, "2026-10-03T10:00:00Z"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing conflict, got %v", err)
+	}
+}
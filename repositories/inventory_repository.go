@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+)
+
+// ErrInsufficientStock is returned when a movement would take the stock level below zero.
+var ErrInsufficientStock = errors.New("not enough copies in stock")
+
+type InventoryRepository struct {
+	DB *sql.DB
+}
+
+const stockLevelColumns = "album_id, edition_id, quantity, low_stock_threshold"
+
+func scanStockLevels(rows *sql.Rows) ([]models.StockLevel, error) {
+	defer rows.Close()
+
+	levels := []models.StockLevel{}
+	for rows.Next() {
+		var level models.StockLevel
+		if err := rows.Scan(&level.AlbumID, &level.EditionID, &level.Quantity, &level.LowStockThreshold); err != nil {
+			return nil, err
+		}
+		level.LowStock = level.LowStockThreshold > 0 && level.Quantity <= level.LowStockThreshold
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+// RecordMovement changes a stock level and appends the movement to the ledger in one transaction, setting the
+// movement's ID and balance. A movement that would take the level below zero is rejected with
+// ErrInsufficientStock.
+func (r *InventoryRepository) RecordMovement(movement *models.StockMovement) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Writing first takes the database's write lock before anything is read
+	if _, err := tx.Exec("INSERT INTO stock_levels (album_id, edition_id) VALUES (?, ?) ON CONFLICT DO NOTHING", movement.AlbumID, movement.EditionID); err != nil {
+		return err
+	}
+	if err := applyMovement(tx, movement); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyMovement changes a stock level inside the caller's transaction and appends the movement to the ledger.
+// The level is changed by a single conditional UPDATE, so concurrent sales cannot take it below zero. It
+// returns sql.ErrNoRows when the stock of the album or edition is not tracked, and ErrInsufficientStock when
+// the movement would take the level below zero.
+func applyMovement(tx *sql.Tx, movement *models.StockMovement) error {
+	change := movement.Quantity
+	if movement.Kind == models.MovementSell {
+		change = -change
+	}
+
+	result, err := tx.Exec("UPDATE stock_levels SET quantity = quantity + ? WHERE album_id = ? AND edition_id = ? AND quantity + ? >= 0",
+		change, movement.AlbumID, movement.EditionID, change)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		var tracked bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM stock_levels WHERE album_id = ? AND edition_id = ?)", movement.AlbumID, movement.EditionID).Scan(&tracked); err != nil {
+			return err
+		}
+		if tracked {
+			return ErrInsufficientStock
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := tx.QueryRow("SELECT quantity FROM stock_levels WHERE album_id = ? AND edition_id = ?", movement.AlbumID, movement.EditionID).Scan(&movement.Balance); err != nil {
+		return err
+	}
+	result, err = tx.Exec("INSERT INTO stock_movements (album_id, edition_id, kind, quantity_change, balance, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		movement.AlbumID, movement.EditionID, movement.Kind, change, movement.Balance, movement.Note, movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	movement.ID = uint(id)
+	return nil
+}
+
+// GetMovements retrieves the ledger of an album and its editions in the order the movements were recorded.
+func (r *InventoryRepository) GetMovements(albumID uint) ([]models.StockMovement, error) {
+	rows, err := r.DB.Query(`
+        SELECT id, album_id, edition_id, kind, quantity_change, balance, note, created_at
+        FROM stock_movements
+        WHERE album_id = ?
+        ORDER BY id ASC
+    `, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []models.StockMovement{}
+	for rows.Next() {
+		var movement models.StockMovement
+		if err := rows.Scan(&movement.ID, &movement.AlbumID, &movement.EditionID, &movement.Kind, &movement.Quantity, &movement.Balance, &movement.Note, &movement.CreatedAt); err != nil {
+			return nil, err
+		}
+		// Sales are reported as the number of copies sold
+		if movement.Kind == models.MovementSell {
+			movement.Quantity = -movement.Quantity
+		}
+		movements = append(movements, movement)
+	}
+	return movements, rows.Err()
+}
+
+// SetLowStockThreshold sets the threshold of a stock level, creating the level empty if needed, and fills in
+// its quantity.
+func (r *InventoryRepository) SetLowStockThreshold(level *models.StockLevel) error {
+	_, err := r.DB.Exec(`
+        INSERT INTO stock_levels (album_id, edition_id, low_stock_threshold) VALUES (?, ?, ?)
+        ON CONFLICT (album_id, edition_id) DO UPDATE SET low_stock_threshold = excluded.low_stock_threshold
+    `, level.AlbumID, level.EditionID, level.LowStockThreshold)
+	if err != nil {
+		return err
+	}
+
+	err = r.DB.QueryRow("SELECT quantity FROM stock_levels WHERE album_id = ? AND edition_id = ?", level.AlbumID, level.EditionID).Scan(&level.Quantity)
+	level.LowStock = level.LowStockThreshold > 0 && level.Quantity <= level.LowStockThreshold
+	return err
+}
+
+// GetStockLevels retrieves the stock levels of an album and its editions sorted by edition.
+func (r *InventoryRepository) GetStockLevels(albumID uint) ([]models.StockLevel, error) {
+	rows, err := r.DB.Query("SELECT "+stockLevelColumns+" FROM stock_levels WHERE album_id = ? ORDER BY edition_id ASC", albumID)
+	if err != nil {
+		return nil, err
+	}
+	return scanStockLevels(rows)
+}
+
+// GetLowStockLevels retrieves the stock levels at or below their threshold, emptiest first.
+func (r *InventoryRepository) GetLowStockLevels() ([]models.StockLevel, error) {
+	rows, err := r.DB.Query(`
+        SELECT ` + stockLevelColumns + `
+        FROM stock_levels
+        WHERE low_stock_threshold > 0 AND quantity <= low_stock_threshold
+        ORDER BY quantity ASC, album_id ASC, edition_id ASC
+    `)
+	if err != nil {
+		return nil, err
+	}
+	return scanStockLevels(rows)
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestRecordMovement(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	repo := InventoryRepository{DB: db}
+
+	stocked := &models.Album{Name: "Stocked Album", ReleaseDate: "2020-01-01", Price: 200}
+	empty := &models.Album{Name: "Empty Album", ReleaseDate: "2021-01-01", Price: 200}
+	untracked := &models.Album{Name: "Untracked Album", ReleaseDate: "2022-01-01", Price: 200}
+	for _, album := range []*models.Album{stocked, empty, untracked} {
+		if err := albums.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	movements := []*models.StockMovement{
+		{AlbumID: stocked.ID, Kind: models.MovementReceive, Quantity: 5},
+		{AlbumID: stocked.ID, Kind: models.MovementSell, Quantity: 2},
+		{AlbumID: stocked.ID, Kind: models.MovementReturn, Quantity: 1},
+		{AlbumID: stocked.ID, Kind: models.MovementAdjust, Quantity: -1, Note: "damaged"},
+		{AlbumID: empty.ID, Kind: models.MovementReceive, Quantity: 1},
+		{AlbumID: empty.ID, Kind: models.MovementSell, Quantity: 1},
+	}
+	for _, movement := range movements {
+		movement.CreatedAt = "2024-01-01T00:00:00Z"
+		if err := repo.RecordMovement(movement); err != nil {
+			t.Fatalf("failed to record movement: %v", err)
+		}
+	}
+	if movements[3].Balance != 3 {
+		t.Errorf("expected a balance of 3, got %d", movements[3].Balance)
+	}
+
+	oversold := &models.StockMovement{AlbumID: stocked.ID, Kind: models.MovementSell, Quantity: 4, CreatedAt: "2024-01-01T00:00:00Z"}
+	if err := repo.RecordMovement(oversold); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("expected ErrInsufficientStock, got %v", err)
+	}
+
+	ledger, err := repo.GetMovements(stocked.ID)
+	if err != nil {
+		t.Fatalf("failed to get movements: %v", err)
+	}
+	if len(ledger) != 4 || ledger[1].Quantity != 2 || ledger[1].Balance != 3 || ledger[3].Quantity != -1 || ledger[3].Note != "damaged" {
+		t.Errorf("unexpected ledger: %+v", ledger)
+	}
+
+	if _, err := db.Exec("DELETE FROM stock_movements"); err == nil {
+		t.Error("expected the ledger to be append-only")
+	}
+
+	inStock := true
+	listed, err := albums.GetAlbums(models.AlbumFilter{InStock: &inStock})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != stocked.ID || listed[1].ID != untracked.ID {
+		t.Errorf("expected the stocked and the untracked album, got %+v", listed)
+	}
+	inStock = false
+	listed, err = albums.GetAlbums(models.AlbumFilter{InStock: &inStock})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != empty.ID {
+		t.Errorf("expected only the sold out album, got %+v", listed)
+	}
+}
+
+func TestLowStockLevels(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := InventoryRepository{DB: db}
+	for _, level := range []*models.StockLevel{
+		{AlbumID: 1, LowStockThreshold: 2},
+		{AlbumID: 2, EditionID: 1, LowStockThreshold: 2},
+		{AlbumID: 2, EditionID: 2},
+	} {
+		if err := repo.SetLowStockThreshold(level); err != nil {
+			t.Fatalf("failed to set threshold: %v", err)
+		}
+	}
+	for _, movement := range []*models.StockMovement{
+		{AlbumID: 1, Kind: models.MovementReceive, Quantity: 3},
+		{AlbumID: 2, EditionID: 1, Kind: models.MovementReceive, Quantity: 2},
+	} {
+		if err := repo.RecordMovement(movement); err != nil {
+			t.Fatalf("failed to record movement: %v", err)
+		}
+	}
+
+	low, err := repo.GetLowStockLevels()
+	if err != nil {
+		t.Fatalf("failed to get low stock: %v", err)
+	}
+	if len(low) != 1 || low[0].AlbumID != 2 || low[0].EditionID != 1 || !low[0].LowStock {
+		t.Errorf("expected only the edition at its threshold, got %+v", low)
+	}
+}
+
+func TestRecordMovementConcurrentSales(t *testing.T) {
+	// In-memory databases are private to each connection, so concurrent transactions need a file
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "stock.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TABLE stock_levels (
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+			low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (album_id, edition_id)
+		);
+		CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			quantity_change INTEGER NOT NULL,
+			balance INTEGER NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	repo := InventoryRepository{DB: db}
+	if err := repo.RecordMovement(&models.StockMovement{AlbumID: 1, Kind: models.MovementReceive, Quantity: 3}); err != nil {
+		t.Fatalf("failed to receive stock: %v", err)
+	}
+
+	const buyers = 10
+	errs := make(chan error, buyers)
+	var wg sync.WaitGroup
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.RecordMovement(&models.StockMovement{AlbumID: 1, Kind: models.MovementSell, Quantity: 1})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	sold := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			sold++
+		case !errors.Is(err, ErrInsufficientStock):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if sold != 3 {
+		t.Errorf("expected exactly 3 copies sold, got %d", sold)
+	}
+
+	levels, err := repo.GetStockLevels(1)
+	if err != nil {
+		t.Fatalf("failed to get stock: %v", err)
+	}
+	if len(levels) != 1 || levels[0].Quantity != 0 {
+		t.Errorf("expected the stock to run out, got %+v", levels)
+	}
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type LabelRepository struct {
+	DB *sql.DB
+}
+
+// CreateLabel inserts a new label and sets its ID.
+func (r *LabelRepository) CreateLabel(label *models.Label) error {
+	result, err := r.DB.Exec("INSERT INTO labels (name, country, parent_id) VALUES (?, ?, ?)",
+		label.Name, label.Country, nullableID(label.ParentID))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	label.ID = uint(id)
+	return nil
+}
+
+// GetLabels retrieves all labels sorted by name.
+func (r *LabelRepository) GetLabels() ([]models.Label, error) {
+	rows, err := r.DB.Query("SELECT id, name, country, COALESCE(parent_id, 0) FROM labels ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := []models.Label{}
+	for rows.Next() {
+		var label models.Label
+		if err := rows.Scan(&label.ID, &label.Name, &label.Country, &label.ParentID); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// GetLabelByID retrieves a single label. It returns sql.ErrNoRows if the label does not exist.
+func (r *LabelRepository) GetLabelByID(id uint) (*models.Label, error) {
+	var label models.Label
+	err := r.DB.QueryRow("SELECT id, name, country, COALESCE(parent_id, 0) FROM labels WHERE id = ?", id).
+		Scan(&label.ID, &label.Name, &label.Country, &label.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// UpdateLabel updates an existing label. It returns sql.ErrNoRows if the label does not exist.
+func (r *LabelRepository) UpdateLabel(label *models.Label) error {
+	result, err := r.DB.Exec("UPDATE labels SET name = ?, country = ?, parent_id = ? WHERE id = ?",
+		label.Name, label.Country, nullableID(label.ParentID), label.ID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// DeleteLabel deletes a label by ID. It returns sql.ErrNoRows if the label does not exist.
+func (r *LabelRepository) DeleteLabel(id uint) error {
+	result, err := r.DB.Exec("DELETE FROM labels WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// LabelInUse reports whether any album or imprint still refers to the label.
+func (r *LabelRepository) LabelInUse(id uint) (bool, error) {
+	var inUse bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM albums WHERE label_id = ?) OR EXISTS(SELECT 1 FROM labels WHERE parent_id = ?)", id, id).Scan(&inUse)
+	return inUse, err
+}
+
+// GetAlbumsByLabel retrieves the albums released on a label sorted by release date, oldest first.
+func (r *LabelRepository) GetAlbumsByLabel(labelID uint) ([]models.Album, error) {
+	rows, err := r.DB.Query("SELECT "+albumColumns+" FROM albums a WHERE a.label_id = ? ORDER BY a.release_date ASC, a.id ASC", labelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		var album models.Album
+		if err := rows.Scan(albumFields(&album)...); err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
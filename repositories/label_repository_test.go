@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestCreateAndGetLabels(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := LabelRepository{DB: db}
+
+	parent := &models.Label{Name: "Universal", Country: "US"}
+	if err := repo.CreateLabel(parent); err != nil {
+		t.Fatalf("failed to create label: %v", err)
+	}
+	imprint := &models.Label{Name: "Island", Country: "GB", ParentID: parent.ID}
+	if err := repo.CreateLabel(imprint); err != nil {
+		t.Fatalf("failed to create label: %v", err)
+	}
+
+	labels, err := repo.GetLabels()
+	if err != nil {
+		t.Fatalf("failed to get labels: %v", err)
+	}
+	if len(labels) != 2 || labels[0].Name != "Island" || labels[0].ParentID != parent.ID || labels[1].ParentID != 0 {
+		t.Errorf("unexpected labels: %+v", labels)
+	}
+
+	inUse, err := repo.LabelInUse(parent.ID)
+	if err != nil {
+		t.Fatalf("failed to check label usage: %v", err)
+	}
+	if !inUse {
+		t.Error("expected the parent label to be in use by its imprint")
+	}
+
+	if err := repo.DeleteLabel(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestGetAlbumsByLabel(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := LabelRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO labels (id, name) VALUES (1, 'Island'), (2, 'Other');
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description, label_id, catalog_number) VALUES
+		(1, 'Later Album', '2020-01-01', 'Rock', 20000, '', 1, 'ILPS 2'),
+		(2, 'Early Album', '2010-01-01', 'Rock', 20000, '', 1, 'ILPS 1'),
+		(3, 'Other Album', '2015-01-01', 'Rock', 20000, '', 2, 'X 1');
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	albums, err := repo.GetAlbumsByLabel(1)
+	if err != nil {
+		t.Fatalf("failed to get albums by label: %v", err)
+	}
+	if len(albums) != 2 || albums[0].Name != "Early Album" || albums[0].CatalogNumber != "ILPS 1" {
+		t.Errorf("expected the label's albums oldest first, got %+v", albums)
+	}
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type LibraryRepository struct {
+	DB *sql.DB
+}
+
+const libraryFileColumns = "id, path, size, modified_ns, hash, status, album_id, track_id, error, scanned_at, COALESCE(missing_since, '')"
+
+func libraryFileFields(file *models.LibraryFile) []interface{} {
+	return []interface{}{&file.ID, &file.Path, &file.Size, &file.ModifiedNS, &file.Hash, &file.Status,
+		&file.AlbumID, &file.TrackID, &file.Error, &file.ScannedAt, &file.MissingSince}
+}
+
+// GetLibraryFiles retrieves every file the scanner has found, in path order.
+func (r *LibraryRepository) GetLibraryFiles() ([]models.LibraryFile, error) {
+	rows, err := r.DB.Query("SELECT " + libraryFileColumns + " FROM library_files ORDER BY path")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []models.LibraryFile{}
+	for rows.Next() {
+		var file models.LibraryFile
+		if err := rows.Scan(libraryFileFields(&file)...); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// SaveLibraryFile inserts a file without an ID, setting its ID, or updates the file with its ID.
+func (r *LibraryRepository) SaveLibraryFile(file *models.LibraryFile) error {
+	if file.ID != 0 {
+		result, err := r.DB.Exec("UPDATE library_files SET path = ?, size = ?, modified_ns = ?, hash = ?, status = ?, album_id = ?, track_id = ?, error = ?, scanned_at = ?, missing_since = ? WHERE id = ?",
+			file.Path, file.Size, file.ModifiedNS, file.Hash, file.Status, file.AlbumID, file.TrackID, file.Error, file.ScannedAt, nullableString(file.MissingSince), file.ID)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result)
+	}
+
+	result, err := r.DB.Exec("INSERT INTO library_files (path, size, modified_ns, hash, status, album_id, track_id, error, scanned_at, missing_since) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		file.Path, file.Size, file.ModifiedNS, file.Hash, file.Status, file.AlbumID, file.TrackID, file.Error, file.ScannedAt, nullableString(file.MissingSince))
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	file.ID = uint(id)
+	return nil
+}
+
+// MarkMissing records that files are no longer found, keeping the time they first went missing.
+func (r *LibraryRepository) MarkMissing(ids []uint, since string) error {
+	for start := 0; start < len(ids); start += maxInClause {
+		end := start + maxInClause
+		if end > len(ids) {
+			end = len(ids)
+		}
+		placeholders, args := inClause(ids[start:end])
+		_, err := r.DB.Exec("UPDATE library_files SET missing_since = COALESCE(missing_since, ?) WHERE id IN ("+placeholders+")",
+			append([]interface{}{since}, args...)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"testing"
+)
+
+func TestLibraryFiles(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &LibraryRepository{DB: db}
+
+	files := []models.LibraryFile{
+		{Path: "/music/b.mp3", Size: 10, ModifiedNS: 1, Hash: "bbb", Status: models.IngestCreated, AlbumID: 1, TrackID: 2, ScannedAt: "2026-10-01T10:00:00Z"},
+		{Path: "/music/a.flac", Size: 20, ModifiedNS: 2, Hash: "aaa", Status: models.IngestFailed, Error: "file has no album tag", ScannedAt: "2026-10-01T10:00:00Z"},
+	}
+	for i := range files {
+		if err := repo.SaveLibraryFile(&files[i]); err != nil {
+			t.Fatalf("failed to save library file: %v", err)
+		}
+	}
+	if err := repo.SaveLibraryFile(&models.LibraryFile{Path: "/music/a.flac", Hash: "ccc", Status: models.IngestMatched, ScannedAt: "2026-10-01T10:00:00Z"}); err == nil {
+		t.Error("expected a second file at the same path to be rejected")
+	}
+
+	files[0].Path, files[0].Size = "/music/moved/b.mp3", 11
+	if err := repo.SaveLibraryFile(&files[0]); err != nil {
+		t.Fatalf("failed to update library file: %v", err)
+	}
+
+	if err := repo.MarkMissing([]uint{files[1].ID}, "2026-10-02T10:00:00Z"); err != nil {
+		t.Fatalf("failed to mark missing: %v", err)
+	}
+	if err := repo.MarkMissing([]uint{files[1].ID}, "2026-10-03T10:00:00Z"); err != nil {
+		t.Fatalf("failed to mark missing: %v", err)
+	}
+
+	saved, err := repo.GetLibraryFiles()
+	if err != nil {
+		t.Fatalf("failed to get library files: %v", err)
+	}
+	if len(saved) != 2 {
+		t.Fatalf("expected 2 library files, got %+v", saved)
+	}
+	if saved[0].Path != "/music/a.flac" || saved[0].MissingSince != "2026-10-02T10:00:00Z" || saved[0].Error != "file has no album tag" {
+		t.Errorf("expected a.flac missing since it first went missing, got %+v", saved[0])
+	}
+	if saved[1].Path != "/music/moved/b.mp3" || saved[1].Size != 11 || saved[1].TrackID != 2 || saved[1].MissingSince != "" {
+		t.Errorf("unexpected moved file: %+v", saved[1])
+	}
+
+	// Saving a file found again clears its missing time
+	saved[0].MissingSince = ""
+	if err := repo.SaveLibraryFile(&saved[0]); err != nil {
+		t.Fatalf("failed to update library file: %v", err)
+	}
+	saved, _ = repo.GetLibraryFiles()
+	if saved[0].MissingSince != "" {
+		t.Errorf("expected the missing time cleared, got %+v", saved[0])
+	}
+}
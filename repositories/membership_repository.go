@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type MembershipRepository struct {
+	DB *sql.DB
+}
+
+const membershipColumns = `gm.id, gm.group_id, gm.member_id, gm.instrument, gm.joined_on, COALESCE(gm.left_on, ''),
+        m.id, m.name, m.musician_type, m.kind`
+
+// CreateMembership adds a member to a group and sets the membership ID.
+func (r *MembershipRepository) CreateMembership(membership *models.Membership) error {
+	result, err := r.DB.Exec("INSERT INTO group_memberships (group_id, member_id, instrument, joined_on, left_on) VALUES (?, ?, ?, ?, ?)",
+		membership.GroupID, membership.MemberID, membership.Instrument, membership.JoinedOn, nullableString(membership.LeftOn))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	membership.ID = uint(id)
+	return nil
+}
+
+// UpdateMembership updates a membership of a group. It returns sql.ErrNoRows if the group has no such membership.
+func (r *MembershipRepository) UpdateMembership(membership *models.Membership) error {
+	result, err := r.DB.Exec("UPDATE group_memberships SET member_id = ?, instrument = ?, joined_on = ?, left_on = ? WHERE id = ? AND group_id = ?",
+		membership.MemberID, membership.Instrument, membership.JoinedOn, nullableString(membership.LeftOn), membership.ID, membership.GroupID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// DeleteMembership removes a membership from a group. It returns sql.ErrNoRows if the group has no such membership.
+func (r *MembershipRepository) DeleteMembership(groupID, membershipID uint) error {
+	result, err := r.DB.Exec("DELETE FROM group_memberships WHERE id = ? AND group_id = ?", membershipID, groupID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetMembershipsByGroup retrieves the memberships of a group sorted by join date. When at is
+// set, only members who were in the group on that date (YYYY-MM-DD) are returned.
+func (r *MembershipRepository) GetMembershipsByGroup(groupID uint, at string) ([]models.Membership, error) {
+	query := `
+        SELECT ` + membershipColumns + `
+        FROM group_memberships gm
+        JOIN musicians m ON m.id = gm.member_id
+        WHERE gm.group_id = ?`
+	args := []interface{}{groupID}
+	if at != "" {
+		query += ` AND gm.joined_on <= ? AND (gm.left_on IS NULL OR gm.left_on >= ?)`
+		args = append(args, at, at)
+	}
+	query += `
+        ORDER BY gm.joined_on ASC, m.name ASC`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMemberships(rows)
+}
+
+// GetLineupByAlbum retrieves the members of every group linked to an album who were
+// in the group on the album's release date, sorted by group and member name.
+func (r *MembershipRepository) GetLineupByAlbum(albumID uint) ([]models.Membership, error) {
+	rows, err := r.DB.Query(`
+        SELECT DISTINCT `+membershipColumns+`
+        FROM albums a
+        JOIN album_musicians am ON am.album_id = a.id
+        JOIN group_memberships gm ON gm.group_id = am.musician_id
+        JOIN musicians m ON m.id = gm.member_id
+        WHERE a.id = ?
+          AND gm.joined_on <= a.release_date
+          AND (gm.left_on IS NULL OR gm.left_on >= a.release_date)
+        ORDER BY gm.group_id ASC, m.name ASC
+    `, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMemberships(rows)
+}
+
+func scanMemberships(rows *sql.Rows) ([]models.Membership, error) {
+	memberships := []models.Membership{}
+	for rows.Next() {
+		var membership models.Membership
+		member := &membership.Member
+		if err := rows.Scan(&membership.ID, &membership.GroupID, &membership.MemberID, &membership.Instrument, dateOnly{&membership.JoinedOn}, &membership.LeftOn,
+			&member.ID, &member.Name, &member.MusicianType, &member.Kind); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, membership)
+	}
+	return memberships, rows.Err()
+}
+
+// nullableString stores an empty string as NULL.
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullableID stores a zero ID as NULL.
+func nullableID(id uint) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// requireAffected turns an update or delete that matched no rows into sql.ErrNoRows.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
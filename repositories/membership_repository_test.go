@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func insertBandHistory(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type, kind) VALUES
+		(1, 'The Band', 'Rock', 'group'),
+		(2, 'Alice', 'Vocalist', 'person'),
+		(3, 'Bob', 'Drummer', 'person'),
+		(4, 'Carol', 'Drummer', 'person');
+		INSERT INTO group_memberships (group_id, member_id, instrument, joined_on, left_on) VALUES
+		(1, 2, 'vocals', '1990-01-01', NULL),
+		(1, 3, 'drums', '1990-01-01', '1995-06-30'),
+		(1, 4, 'drums', '1995-07-01', NULL);
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'Early Days', '1992-03-01', 'Rock', 20000, ''),
+		(2, 'Later Days', '1998-03-01', 'Rock', 20000, '');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES (1, 1), (2, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+}
+
+func TestGetMembershipsByGroup(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	insertBandHistory(t, db)
+
+	repo := MembershipRepository{DB: db}
+
+	t.Run("all members", func(t *testing.T) {
+		memberships, err := repo.GetMembershipsByGroup(1, "")
+		if err != nil {
+			t.Fatalf("failed to get memberships: %v", err)
+		}
+		if len(memberships) != 3 {
+			t.Fatalf("expected 3 memberships, got %d", len(memberships))
+		}
+		if memberships[1].Member.Name != "Bob" || memberships[1].JoinedOn != "1990-01-01" || memberships[1].LeftOn != "1995-06-30" {
+			t.Errorf("unexpected membership: %+v", memberships[1])
+		}
+		if memberships[0].LeftOn != "" {
+			t.Errorf("expected current member to have no left_on, got %q", memberships[0].LeftOn)
+		}
+	})
+
+	t.Run("members on a date", func(t *testing.T) {
+		memberships, err := repo.GetMembershipsByGroup(1, "1995-06-30")
+		if err != nil {
+			t.Fatalf("failed to get memberships: %v", err)
+		}
+		if len(memberships) != 2 || memberships[0].Member.Name != "Alice" || memberships[1].Member.Name != "Bob" {
+			t.Errorf("expected Alice and Bob, got %+v", memberships)
+		}
+	})
+}
+
+func TestGetLineupByAlbum(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	insertBandHistory(t, db)
+
+	repo := MembershipRepository{DB: db}
+
+	lineup, err := repo.GetLineupByAlbum(2)
+	if err != nil {
+		t.Fatalf("failed to get lineup: %v", err)
+	}
+	if len(lineup) != 2 || lineup[0].Member.Name != "Alice" || lineup[1].Member.Name != "Carol" {
+		t.Errorf("expected Alice and Carol, got %+v", lineup)
+	}
+}
+
+func TestDeleteMembershipMissing(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	insertBandHistory(t, db)
+
+	repo := MembershipRepository{DB: db}
+
+	// Membership 1 belongs to group 1, not group 2
+	if err := repo.DeleteMembership(2, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
@@ -8,13 +8,21 @@ import (
 
 type MusicianRepository struct {
 	DB *sql.DB
+	// Outbox, when set, receives a domain event for every musician change in the same transaction.
+	Outbox *OutboxRepository
 }
 
 // CreateMusician inserts a new musician into the database and returns the inserted musician with the correct ID.
 func (r *MusicianRepository) CreateMusician(musician *models.Musician) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// Check if the musicians table is empty by seeing if any row exists
 	var exists bool
-	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM musicians LIMIT 1)").Scan(&exists)
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM musicians LIMIT 1)").Scan(&exists)
 	if err != nil {
 		log.Println("Error checking if table is empty:", err)
 		return err
@@ -23,15 +31,15 @@ func (r *MusicianRepository) CreateMusician(musician *models.Musician) error {
 	if !exists {
 		// If the table is empty, explicitly set the musician ID to 1
 		musician.ID = 1
-		_, err = r.DB.Exec("INSERT INTO musicians (id, name, musician_type) VALUES (?, ?, ?)",
-			musician.ID, musician.Name, musician.MusicianType)
+		_, err = tx.Exec("INSERT INTO musicians (id, name, musician_type, kind) VALUES (?, ?, ?, ?)",
+			musician.ID, musician.Name, musician.MusicianType, musician.Kind)
 		if err != nil {
 			log.Println("Error inserting musician with ID 1:", err)
 			return err
 		}
 	} else {
 		// Insert the musician into the database (ID will be auto-generated)
-		result, err := r.DB.Exec("INSERT INTO musicians (name, musician_type) VALUES (?, ?)", musician.Name, musician.MusicianType)
+		result, err := tx.Exec("INSERT INTO musicians (name, musician_type, kind) VALUES (?, ?, ?)", musician.Name, musician.MusicianType, musician.Kind)
 		if err != nil {
 			log.Println("Error inserting musician:", err)
 			return err
@@ -48,6 +56,14 @@ func (r *MusicianRepository) CreateMusician(musician *models.Musician) error {
 		musician.ID = uint(id)
 	}
 
+	if err := r.appendEvent(tx, musician.ID, models.EventMusicianCreated, musician); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	log.Println("Musician created with ID:", musician.ID)
 
 	return nil
@@ -55,7 +71,7 @@ func (r *MusicianRepository) CreateMusician(musician *models.Musician) error {
 
 // GetMusicians retrieves all musicians from the database.
 func (r *MusicianRepository) GetMusicians() ([]models.Musician, error) {
-	rows, err := r.DB.Query("SELECT id, name, musician_type FROM musicians")
+	rows, err := r.DB.Query("SELECT id, name, musician_type, kind FROM musicians")
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +80,7 @@ func (r *MusicianRepository) GetMusicians() ([]models.Musician, error) {
 	var musicians []models.Musician
 	for rows.Next() {
 		var musician models.Musician
-		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType); err != nil {
+		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind); err != nil {
 			return nil, err
 		}
 		musicians = append(musicians, musician)
@@ -72,34 +88,176 @@ func (r *MusicianRepository) GetMusicians() ([]models.Musician, error) {
 	return musicians, nil
 }
 
-// UpdateMusician updates an existing musician in the database.
+// UpdateMusician updates an existing musician in the database. It returns sql.ErrNoRows if the musician does not exist.
 func (r *MusicianRepository) UpdateMusician(musician *models.Musician) error {
-	_, err := r.DB.Exec("UPDATE musicians SET name = ?, musician_type = ? WHERE id = ?", musician.Name, musician.MusicianType, musician.ID)
-	return err
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// An empty kind leaves the stored kind unchanged
+	result, err := tx.Exec("UPDATE musicians SET name = ?, musician_type = ?, kind = COALESCE(NULLIF(?, ''), kind) WHERE id = ?",
+		musician.Name, musician.MusicianType, musician.Kind, musician.ID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := r.appendEvent(tx, musician.ID, models.EventMusicianUpdated, musician); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // DeleteMusician deletes a musician by ID.
 func (r *MusicianRepository) DeleteMusician(id uint) error {
-	_, err := r.DB.Exec("DELETE FROM musicians WHERE id = ?", id)
-	return err
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM musicians WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	if err := r.appendEvent(tx, id, models.EventMusicianDeleted, map[string]uint{"id": id}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetMusiciansByAlbum retrieves musicians for a specific album sorted by musician name.
-func (r *MusicianRepository) GetMusiciansByAlbum(albumID uint) ([]models.Musician, error) {
-	rows, err := r.DB.Query("SELECT m.id, m.name, m.musician_type FROM musicians m "+
-		"JOIN album_musicians am ON m.id = am.musician_id WHERE am.album_id = ? ORDER BY m.name ASC", albumID)
+// appendEvent records a domain event in the outbox when one is configured.
+func (r *MusicianRepository) appendEvent(tx *sql.Tx, musicianID uint, eventType string, payload interface{}) error {
+	if r.Outbox == nil {
+		return nil
+	}
+	return r.Outbox.Append(tx, "musician", musicianID, eventType, payload)
+}
+
+// GetMusiciansByAlbum retrieves the musicians credited on an album sorted by musician name, each with
+// their credits. When role is set, only credits with that role are returned.
+func (r *MusicianRepository) GetMusiciansByAlbum(albumID uint, role string) ([]models.CreditedMusician, error) {
+	query := `
+        SELECT m.id, m.name, m.musician_type, m.kind, am.role, am.instrument, am.track
+        FROM musicians m
+        JOIN album_musicians am ON m.id = am.musician_id
+        WHERE am.album_id = ?`
+	args := []interface{}{albumID}
+	if role != "" {
+		query += ` AND am.role = ?`
+		args = append(args, role)
+	}
+	query += `
+        ORDER BY m.name ASC, m.id ASC, am.track ASC, am.role ASC`
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var musicians []models.Musician
+	musicians := []models.CreditedMusician{}
 	for rows.Next() {
 		var musician models.Musician
-		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType); err != nil {
+		credit := models.Credit{}
+		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind, &credit.Role, &credit.Instrument, &credit.Track); err != nil {
 			return nil, err
 		}
-		musicians = append(musicians, musician)
+		credit.MusicianID = musician.ID
+
+		// Credits of the same musician are adjacent
+		if n := len(musicians); n == 0 || musicians[n-1].ID != musician.ID {
+			musicians = append(musicians, models.CreditedMusician{Musician: musician})
+		}
+		last := &musicians[len(musicians)-1]
+		last.Credits = append(last.Credits, credit)
 	}
-	return musicians, nil
+	return musicians, rows.Err()
+}
+
+// GetMusiciansByAlbums retrieves the musicians of several albums, one query per chunk of IDs, keyed by album ID and
+// sorted by name.
+func (r *MusicianRepository) GetMusiciansByAlbums(albumIDs []uint) (map[uint][]models.Musician, error) {
+	musiciansByAlbum := make(map[uint][]models.Musician)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query("SELECT DISTINCT am.album_id, m.id, m.name, m.musician_type, m.kind FROM musicians m "+
+			"JOIN album_musicians am ON m.id = am.musician_id WHERE am.album_id IN ("+placeholders+") ORDER BY m.name ASC, m.id ASC", args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var albumID uint
+			var musician models.Musician
+			if err := rows.Scan(&albumID, &musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			musiciansByAlbum[albumID] = append(musiciansByAlbum[albumID], musician)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return musiciansByAlbum, nil
+}
+
+// GetMusicianByID retrieves a single musician. It returns sql.ErrNoRows if the musician does not exist.
+func (r *MusicianRepository) GetMusicianByID(id uint) (*models.Musician, error) {
+	var musician models.Musician
+	err := r.DB.QueryRow("SELECT id, name, musician_type, kind FROM musicians WHERE id = ?", id).
+		Scan(&musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind)
+	if err != nil {
+		return nil, err
+	}
+	return &musician, nil
+}
+
+// MusicianExists reports whether a musician with the same name and type is already in the catalog.
+func (r *MusicianRepository) MusicianExists(name, musicianType string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM musicians WHERE name = ? AND musician_type = ?)", name, musicianType).Scan(&exists)
+	return exists, err
+}
+
+// CreateMusicians inserts several musicians in a single transaction and sets their IDs.
+func (r *MusicianRepository) CreateMusicians(musicians []*models.Musician) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO musicians (name, musician_type, kind) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, musician := range musicians {
+		result, err := stmt.Exec(musician.Name, musician.MusicianType, musician.Kind)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		musician.ID = uint(id)
+
+		if err := r.appendEvent(tx, musician.ID, models.EventMusicianCreated, musician); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
@@ -121,8 +121,8 @@ func TestGetMusiciansByAlbum(t *testing.T) {
 
 	// Insert test albums and musicians
 	_, err := db.Exec(`
-		INSERT INTO albums (id, name, release_date, genre, price, description) VALUES 
-		(1, 'Rock Album', '2022-01-01', 'Rock', 200, 'First Album');
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES 
+		(1, 'Rock Album', '2022-01-01', 'Rock', 20000, 'First Album');
 		INSERT INTO musicians (id, name, musician_type) VALUES 
 		(101, 'John Doe', 'Guitarist'),
 		(102, 'Jane Smith', 'Vocalist');
@@ -136,7 +136,7 @@ func TestGetMusiciansByAlbum(t *testing.T) {
 
 	// Successful get musicians by album
 	t.Run("successful get musicians by album", func(t *testing.T) {
-		musicians, err := repo.GetMusiciansByAlbum(1)
+		musicians, err := repo.GetMusiciansByAlbum(1, "")
 		if err != nil {
 			t.Fatalf("failed to get musicians by album: %v", err)
 		}
@@ -146,9 +146,33 @@ func TestGetMusiciansByAlbum(t *testing.T) {
 		}
 	})
 
+	// Musicians with several roles are returned once, filtered by role on request
+	t.Run("credits grouped per musician", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO album_musicians (album_id, musician_id, role) VALUES (1, 101, 'producer')`)
+		if err != nil {
+			t.Fatalf("failed to insert credit: %v", err)
+		}
+
+		musicians, err := repo.GetMusiciansByAlbum(1, "")
+		if err != nil {
+			t.Fatalf("failed to get musicians by album: %v", err)
+		}
+		if len(musicians) != 2 || musicians[1].Name != "John Doe" || len(musicians[1].Credits) != 2 {
+			t.Errorf("expected John Doe with 2 credits, got %+v", musicians)
+		}
+
+		producers, err := repo.GetMusiciansByAlbum(1, models.CreditProducer)
+		if err != nil {
+			t.Fatalf("failed to get musicians by album: %v", err)
+		}
+		if len(producers) != 1 || producers[0].ID != 101 {
+			t.Errorf("expected only John Doe as producer, got %+v", producers)
+		}
+	})
+
 	// Error scenario: No musicians for album
 	t.Run("no musicians found for album", func(t *testing.T) {
-		musicians, err := repo.GetMusiciansByAlbum(999)
+		musicians, err := repo.GetMusiciansByAlbum(999, "")
 		if err != nil {
 			t.Fatalf("failed to get musicians by album: %v", err)
 		}
@@ -158,3 +182,36 @@ func TestGetMusiciansByAlbum(t *testing.T) {
 		}
 	})
 }
+
+func TestGetMusiciansByAlbums(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := MusicianRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES
+		(1, 'John Doe', 'Guitarist'),
+		(2, 'Jane Smith', 'Vocalist');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES
+		(1, 1), (1, 2), (2, 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	musiciansByAlbum, err := repo.GetMusiciansByAlbums(chunkedIDs(1, 2, 3))
+	if err != nil {
+		t.Fatalf("failed to get musicians by albums: %v", err)
+	}
+
+	if len(musiciansByAlbum[1]) != 2 || musiciansByAlbum[1][0].Name != "Jane Smith" {
+		t.Errorf("expected album 1 to have 2 musicians sorted by name, got %+v", musiciansByAlbum[1])
+	}
+	if len(musiciansByAlbum[2]) != 1 {
+		t.Errorf("expected album 2 to have 1 musician, got %d", len(musiciansByAlbum[2]))
+	}
+	if len(musiciansByAlbum[3]) != 0 {
+		t.Errorf("expected album 3 to have no musicians, got %d", len(musiciansByAlbum[3]))
+	}
+}
@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+)
+
+// ErrCartCheckedOut is returned when a cart that has already become an order is changed or checked out again.
+var ErrCartCheckedOut = errors.New("cart has already been checked out")
+
+type OrderRepository struct {
+	DB *sql.DB
+}
+
+// CreateCart inserts an empty cart and sets its ID.
+func (r *OrderRepository) CreateCart(cart *models.Cart) error {
+	result, err := r.DB.Exec("INSERT INTO carts (currency, created_at, customer_id) VALUES (?, ?, ?)", cart.Currency, cart.CreatedAt, nullableID(cart.CustomerID))
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	cart.ID = uint(id)
+	cart.Items = []models.CartItem{}
+	return nil
+}
+
+// GetCart retrieves a cart with its items sorted by album and edition. It returns sql.ErrNoRows if the cart
+// does not exist.
+func (r *OrderRepository) GetCart(cartID uint) (*models.Cart, error) {
+	cart := models.Cart{ID: cartID}
+	var checkedOutAt sql.NullString
+	err := r.DB.QueryRow("SELECT currency, created_at, checked_out_at, COALESCE(customer_id, 0) FROM carts WHERE id = ?", cartID).
+		Scan(&cart.Currency, &cart.CreatedAt, &checkedOutAt, &cart.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	cart.CheckedOutAt = checkedOutAt.String
+
+	rows, err := r.DB.Query("SELECT album_id, edition_id, quantity FROM cart_items WHERE cart_id = ? ORDER BY album_id ASC, edition_id ASC", cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cart.Items = []models.CartItem{}
+	for rows.Next() {
+		var item models.CartItem
+		if err := rows.Scan(&item.AlbumID, &item.EditionID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	return &cart, rows.Err()
+}
+
+// SetCartItem sets the quantity of an album or edition in a cart, removing it at quantity 0. It returns
+// sql.ErrNoRows if the cart does not exist and ErrCartCheckedOut if it has become an order.
+func (r *OrderRepository) SetCartItem(cartID uint, item models.CartItem) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Writing first takes the database's write lock, so a concurrent checkout cannot slip in between
+	if item.Quantity == 0 {
+		_, err = tx.Exec("DELETE FROM cart_items WHERE cart_id = ? AND album_id = ? AND edition_id = ?", cartID, item.AlbumID, item.EditionID)
+	} else {
+		_, err = tx.Exec(`
+            INSERT INTO cart_items (cart_id, album_id, edition_id, quantity) VALUES (?, ?, ?, ?)
+            ON CONFLICT (cart_id, album_id, edition_id) DO UPDATE SET quantity = excluded.quantity
+        `, cartID, item.AlbumID, item.EditionID, item.Quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	var checkedOutAt sql.NullString
+	if err := tx.QueryRow("SELECT checked_out_at FROM carts WHERE id = ?", cartID).Scan(&checkedOutAt); err != nil {
+		return err
+	}
+	if checkedOutAt.Valid {
+		return ErrCartCheckedOut
+	}
+	return tx.Commit()
+}
+
+// CreateOrder checks out a cart into an order in one transaction, setting the order's ID. Copies of albums
+// and editions whose stock is tracked are taken from stock and recorded as sales in the stock ledger; if any
+// line is not covered the whole checkout is rejected with ErrInsufficientStock. A cart that has already been
+// checked out is rejected with ErrCartCheckedOut.
+func (r *OrderRepository) CreateOrder(order *models.Order) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE carts SET checked_out_at = ? WHERE id = ? AND checked_out_at IS NULL", order.CreatedAt, order.CartID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCartCheckedOut
+		}
+		return err
+	}
+
+	result, err = tx.Exec(`
+        INSERT INTO orders (cart_id, status, country, currency, subtotal_minor, tax_rate, tax_minor, total_minor, created_at, updated_at, customer_id)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		order.CartID, order.Status, order.Country, order.Currency, models.ToMinor(order.Subtotal, order.Currency), order.TaxRate,
+		models.ToMinor(order.Tax, order.Currency), models.ToMinor(order.Total, order.Currency), order.CreatedAt, order.UpdatedAt,
+		nullableID(order.CustomerID))
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	order.ID = uint(id)
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		sale := &models.StockMovement{AlbumID: line.AlbumID, EditionID: line.EditionID, Kind: models.MovementSell, Quantity: line.Quantity,
+			Note: fmt.Sprintf("order %d", order.ID), CreatedAt: order.CreatedAt}
+		switch err := applyMovement(tx, sale); {
+		case err == nil:
+			line.StockTracked = true
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		_, err := tx.Exec(`
+            INSERT INTO order_lines (order_id, album_id, edition_id, name, quantity, unit_price_minor, total_minor, stock_tracked)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			order.ID, line.AlbumID, line.EditionID, line.Name, line.Quantity, models.ToMinor(line.UnitPrice, order.Currency),
+			models.ToMinor(line.Total, order.Currency), line.StockTracked)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateOrderStatus moves an order on from the status from to its current Status, recording its payment
+// reference and update time. Paying a customer's order adds its albums to their library; cancelling an
+// order returns the copies it took to stock and takes back the library entries it added. It returns
+// sql.ErrNoRows if the order does not exist or no longer has the status from.
+func (r *OrderRepository) UpdateOrderStatus(order *models.Order, from string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE orders SET status = ?, payment_reference = ?, updated_at = ? WHERE id = ? AND status = ?",
+		order.Status, nullableString(order.PaymentReference), order.UpdatedAt, order.ID, from)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	switch order.Status {
+	case models.OrderPaid:
+		if order.CustomerID == 0 {
+			break
+		}
+		for _, line := range order.Lines {
+			// Albums the customer already owns keep their original entry
+			_, err := tx.Exec(`
+                INSERT INTO library_entries (customer_id, album_id, source, order_id, added_at) VALUES (?, ?, ?, ?, ?)
+                ON CONFLICT (customer_id, album_id) DO NOTHING`,
+				order.CustomerID, line.AlbumID, models.LibraryFromOrder, order.ID, order.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		}
+	case models.OrderCancelled:
+		if _, err := tx.Exec("DELETE FROM library_entries WHERE order_id = ?", order.ID); err != nil {
+			return err
+		}
+		for _, line := range order.Lines {
+			if !line.StockTracked {
+				continue
+			}
+			restock := &models.StockMovement{AlbumID: line.AlbumID, EditionID: line.EditionID, Kind: models.MovementReturn, Quantity: line.Quantity,
+				Note: fmt.Sprintf("order %d cancelled", order.ID), CreatedAt: order.UpdatedAt}
+			// Stock levels are never removed, so a tracked line always has one to return to
+			if err := applyMovement(tx, restock); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+const orderColumns = "id, cart_id, status, country, currency, subtotal_minor, tax_rate, tax_minor, total_minor, COALESCE(payment_reference, ''), " +
+	"created_at, updated_at, COALESCE(customer_id, 0)"
+
+func scanOrder(scanner interface{ Scan(...interface{}) error }) (models.Order, error) {
+	var order models.Order
+	var subtotal, tax, total int64
+	err := scanner.Scan(&order.ID, &order.CartID, &order.Status, &order.Country, &order.Currency, &subtotal, &order.TaxRate, &tax, &total,
+		&order.PaymentReference, &order.CreatedAt, &order.UpdatedAt, &order.CustomerID)
+	if err != nil {
+		return order, err
+	}
+	order.Subtotal = models.FromMinor(subtotal, order.Currency)
+	order.Tax = models.FromMinor(tax, order.Currency)
+	order.Total = models.FromMinor(total, order.Currency)
+	return order, nil
+}
+
+// GetOrder retrieves an order with its lines. It returns sql.ErrNoRows if the order does not exist.
+func (r *OrderRepository) GetOrder(orderID uint) (*models.Order, error) {
+	order, err := scanOrder(r.DB.QueryRow("SELECT "+orderColumns+" FROM orders WHERE id = ?", orderID))
+	if err != nil {
+		return nil, err
+	}
+	orders := []models.Order{order}
+	if err := r.loadLines(orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// GetOrders retrieves the orders with a status, or every order for an empty status, newest first.
+func (r *OrderRepository) GetOrders(status string) ([]models.Order, error) {
+	if status == "" {
+		return r.queryOrders("")
+	}
+	return r.queryOrders(" WHERE status = ?", status)
+}
+
+// GetCustomerOrders retrieves a customer's orders, newest first.
+func (r *OrderRepository) GetCustomerOrders(customerID uint) ([]models.Order, error) {
+	return r.queryOrders(" WHERE customer_id = ?", customerID)
+}
+
+// queryOrders retrieves the orders matching a WHERE clause with their lines, newest first.
+func (r *OrderRepository) queryOrders(where string, args ...interface{}) ([]models.Order, error) {
+	rows, err := r.DB.Query("SELECT "+orderColumns+" FROM orders"+where+" ORDER BY id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.Order{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, r.loadLines(orders)
+}
+
+// loadLines fills in the lines of several orders in one query.
+func (r *OrderRepository) loadLines(orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(orders))
+	byID := make(map[uint]*models.Order, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+		byID[orders[i].ID] = &orders[i]
+		orders[i].Lines = []models.OrderLine{}
+	}
+
+	placeholders, args := inClause(ids)
+	rows, err := r.DB.Query(`
+        SELECT order_id, album_id, edition_id, name, quantity, unit_price_minor, total_minor, stock_tracked
+        FROM order_lines
+        WHERE order_id IN (`+placeholders+`)
+        ORDER BY id ASC
+    `, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID uint
+		var line models.OrderLine
+		var unitPrice, total int64
+		if err := rows.Scan(&orderID, &line.AlbumID, &line.EditionID, &line.Name, &line.Quantity, &unitPrice, &total, &line.StockTracked); err != nil {
+			return err
+		}
+		order := byID[orderID]
+		line.UnitPrice = models.FromMinor(unitPrice, order.Currency)
+		line.Total = models.FromMinor(total, order.Currency)
+		order.Lines = append(order.Lines, line)
+	}
+	return rows.Err()
+}
+
+// ReplaceTaxRates replaces every tax rate with the given ones.
+func (r *OrderRepository) ReplaceTaxRates(rates []models.TaxRate) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM tax_rates"); err != nil {
+		return err
+	}
+	for _, rate := range rates {
+		if _, err := tx.Exec("INSERT INTO tax_rates (country, rate) VALUES (?, ?)", rate.Country, rate.Rate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTaxRates retrieves every tax rate sorted by country.
+func (r *OrderRepository) GetTaxRates() ([]models.TaxRate, error) {
+	rows, err := r.DB.Query("SELECT country, rate FROM tax_rates ORDER BY country ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.TaxRate{}
+	for rows.Next() {
+		var rate models.TaxRate
+		if err := rows.Scan(&rate.Country, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// GetTaxRate retrieves the tax rate of a country. It returns sql.ErrNoRows if the country has none.
+func (r *OrderRepository) GetTaxRate(country string) (float64, error) {
+	var rate float64
+	err := r.DB.QueryRow("SELECT rate FROM tax_rates WHERE country = ?", country).Scan(&rate)
+	return rate, err
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestCreateOrderTakesTrackedStock(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	inventory := InventoryRepository{DB: db}
+	repo := OrderRepository{DB: db}
+
+	tracked := &models.Album{Name: "Tracked Album", ReleaseDate: "2020-01-01", Price: 200}
+	untracked := &models.Album{Name: "Download Only", ReleaseDate: "2021-01-01", Price: 150}
+	for _, album := range []*models.Album{tracked, untracked} {
+		if err := albums.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	if err := inventory.RecordMovement(&models.StockMovement{AlbumID: tracked.ID, Kind: models.MovementReceive, Quantity: 2}); err != nil {
+		t.Fatalf("failed to receive stock: %v", err)
+	}
+
+	newOrder := func() *models.Order {
+		cart := &models.Cart{Currency: "USD", CreatedAt: "2024-01-01T00:00:00Z"}
+		if err := repo.CreateCart(cart); err != nil {
+			t.Fatalf("failed to create cart: %v", err)
+		}
+		return &models.Order{CartID: cart.ID, Status: models.OrderPending, Country: "GB", Currency: "USD", Subtotal: 550, TaxRate: 0.2, Tax: 110, Total: 660,
+			CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-01-01T00:00:00Z",
+			Lines: []models.OrderLine{
+				{AlbumID: tracked.ID, Name: tracked.Name, Quantity: 2, UnitPrice: 200, Total: 400},
+				{AlbumID: untracked.ID, Name: untracked.Name, Quantity: 1, UnitPrice: 150, Total: 150},
+			}}
+	}
+
+	order := newOrder()
+	if err := repo.CreateOrder(order); err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	if err := repo.CreateOrder(order); !errors.Is(err, ErrCartCheckedOut) {
+		t.Errorf("expected ErrCartCheckedOut, got %v", err)
+	}
+
+	// The second order cannot be covered, so neither its order nor its cart's checkout are kept
+	second := newOrder()
+	if err := repo.CreateOrder(second); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("expected ErrInsufficientStock, got %v", err)
+	}
+	if cart, err := repo.GetCart(second.CartID); err != nil || cart.CheckedOutAt != "" {
+		t.Errorf("expected the failed checkout to be rolled back, got %+v, %v", cart, err)
+	}
+
+	stored, err := repo.GetOrder(order.ID)
+	if err != nil {
+		t.Fatalf("failed to get order: %v", err)
+	}
+	if stored.Total != 660 || len(stored.Lines) != 2 || !stored.Lines[0].StockTracked || stored.Lines[1].StockTracked {
+		t.Errorf("unexpected order: %+v", stored)
+	}
+
+	stored.Status = models.OrderCancelled
+	stored.UpdatedAt = "2024-01-02T00:00:00Z"
+	if err := repo.UpdateOrderStatus(stored, models.OrderPending); err != nil {
+		t.Fatalf("failed to cancel order: %v", err)
+	}
+	levels, err := inventory.GetStockLevels(tracked.ID)
+	if err != nil {
+		t.Fatalf("failed to get stock: %v", err)
+	}
+	if levels[0].Quantity != 2 {
+		t.Errorf("expected cancelling to return the copies, got %+v", levels)
+	}
+	movements, err := inventory.GetMovements(tracked.ID)
+	if err != nil {
+		t.Fatalf("failed to get movements: %v", err)
+	}
+	if len(movements) != 3 || movements[1].Note != "order 1" || movements[2].Kind != models.MovementReturn {
+		t.Errorf("unexpected ledger: %+v", movements)
+	}
+
+	if err := repo.UpdateOrderStatus(stored, models.OrderPending); err == nil {
+		t.Error("expected a status change from a stale status to fail")
+	}
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"jukebox/models"
+	"time"
+)
+
+// OutboxRepository stores domain events in the same transaction as the entity
+// change that produced them, so an event is never lost or published for a
+// change that was rolled back.
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+// Append writes an event to the outbox inside the caller's transaction.
+func (r *OutboxRepository) Append(tx *sql.Tx, aggregateType string, aggregateID uint, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload, created_at) VALUES (?, ?, ?, ?, ?)",
+		aggregateType, aggregateID, eventType, string(data), time.Now().UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+// GetPendingEvents retrieves undelivered events that have not been parked, in the order they were written.
+func (r *OutboxRepository) GetPendingEvents(limit int) ([]models.Event, error) {
+	rows, err := r.DB.Query(`
+        SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts, last_error
+        FROM outbox_events
+        WHERE dispatched_at IS NULL AND dead_at IS NULL
+        ORDER BY id ASC
+        LIMIT ?
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		if err := rows.Scan(&event.ID, &event.AggregateType, &event.AggregateID, &event.Type, &event.Payload, &event.CreatedAt, &event.Attempts, &event.LastError); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkDispatched records that an event has been delivered to every subscriber.
+func (r *OutboxRepository) MarkDispatched(id uint) error {
+	_, err := r.DB.Exec("UPDATE outbox_events SET dispatched_at = ?, attempts = attempts + 1, last_error = '' WHERE id = ?",
+		time.Now().UTC().Format(time.RFC3339Nano), id)
+	return err
+}
+
+// MarkFailed records a failed delivery attempt so the event is retried later.
+func (r *OutboxRepository) MarkFailed(id uint, reason string) error {
+	_, err := r.DB.Exec("UPDATE outbox_events SET attempts = attempts + 1, last_error = ? WHERE id = ?", reason, id)
+	return err
+}
+
+// MarkDead records a last failed delivery attempt and parks the event, so it is no longer retried.
+func (r *OutboxRepository) MarkDead(id uint, reason string) error {
+	_, err := r.DB.Exec("UPDATE outbox_events SET attempts = attempts + 1, last_error = ?, dead_at = ? WHERE id = ?",
+		reason, time.Now().UTC().Format(time.RFC3339Nano), id)
+	return err
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"testing"
+)
+
+func TestAlbumChangesWriteOutboxEvents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	outbox := &OutboxRepository{DB: db}
+	repo := AlbumRepository{DB: db, Outbox: outbox}
+
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2022-01-01", Genre: "Rock", Price: 200, Description: "A test album"}
+	if err := repo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	album.Name = "Updated Album"
+	if err := repo.UpdateAlbum(album); err != nil {
+		t.Fatalf("failed to update album: %v", err)
+	}
+
+	events, err := outbox.GetPendingEvents(10)
+	if err != nil {
+		t.Fatalf("failed to get pending events: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 pending events, got %d", len(events))
+	}
+	if events[0].Type != models.EventAlbumCreated || events[1].Type != models.EventAlbumUpdated {
+		t.Errorf("expected created then updated events, got %s then %s", events[0].Type, events[1].Type)
+	}
+	if events[1].AggregateType != "album" || events[1].AggregateID != album.ID {
+		t.Errorf("expected event for album %d, got %s %d", album.ID, events[1].AggregateType, events[1].AggregateID)
+	}
+}
+
+func TestAlbumChangeRolledBackWithoutOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Without an outbox table the event write fails, which must undo the album insert.
+	if _, err := db.Exec("DROP TABLE outbox_events"); err != nil {
+		t.Fatalf("failed to drop outbox table: %v", err)
+	}
+
+	repo := AlbumRepository{DB: db, Outbox: &OutboxRepository{DB: db}}
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2022-01-01", Genre: "Rock", Price: 200, Description: "A test album"}
+	if err := repo.CreateAlbum(album); err == nil {
+		t.Fatal("expected error when the outbox write fails, got nil")
+	}
+
+	albums, err := repo.GetAlbums(models.AlbumFilter{})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if len(albums) != 0 {
+		t.Errorf("expected album insert to be rolled back, got %d albums", len(albums))
+	}
+}
+
+func TestMarkDispatchedAndFailed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	outbox := &OutboxRepository{DB: db}
+	repo := MusicianRepository{DB: db, Outbox: outbox}
+
+	if err := repo.CreateMusician(&models.Musician{Name: "John Doe", MusicianType: "Guitarist"}); err != nil {
+		t.Fatalf("failed to create musician: %v", err)
+	}
+	if err := repo.CreateMusician(&models.Musician{Name: "Jane Smith", MusicianType: "Vocalist"}); err != nil {
+		t.Fatalf("failed to create musician: %v", err)
+	}
+
+	events, err := outbox.GetPendingEvents(10)
+	if err != nil {
+		t.Fatalf("failed to get pending events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 pending events, got %d", len(events))
+	}
+
+	if err := outbox.MarkDispatched(events[0].ID); err != nil {
+		t.Fatalf("failed to mark event dispatched: %v", err)
+	}
+	if err := outbox.MarkFailed(events[1].ID, "subscriber unavailable"); err != nil {
+		t.Fatalf("failed to mark event failed: %v", err)
+	}
+
+	events, err = outbox.GetPendingEvents(10)
+	if err != nil {
+		t.Fatalf("failed to get pending events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 pending event, got %d", len(events))
+	}
+	if events[0].Attempts != 1 || events[0].LastError != "subscriber unavailable" {
+		t.Errorf("expected one failed attempt to be recorded, got %d attempts and error %q", events[0].Attempts, events[0].LastError)
+	}
+}
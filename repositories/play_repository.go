@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+	"strings"
+)
+
+type PlayRepository struct {
+	DB *sql.DB
+}
+
+const playColumns = "p.id, p.client_id, p.customer_id, p.album_id, COALESCE(a.name, ''), p.track, p.duration_seconds, p.played_at"
+
+func playFields(play *models.Play) []interface{} {
+	return []interface{}{&play.ID, &play.ClientID, &play.CustomerID, &play.AlbumID, &play.AlbumName, &play.Track, &play.DurationSeconds, &play.PlayedAt}
+}
+
+// CreatePlays stores plays in one transaction and counts each new one towards the plays chart of its
+// period, given in periods at the same index. A play whose customer already sent its client ID is not
+// stored again: it is replaced by the stored play and reported false in the returned slice.
+func (r *PlayRepository) CreatePlays(plays []models.Play, periods []string, receivedAt string) ([]bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created := make([]bool, len(plays))
+	for i := range plays {
+		play := &plays[i]
+		result, err := tx.Exec(`
+            INSERT INTO plays (customer_id, client_id, album_id, track, duration_seconds, played_at, received_at)
+            VALUES (?, ?, ?, ?, ?, ?, ?)
+            ON CONFLICT (customer_id, client_id) DO NOTHING`,
+			play.CustomerID, play.ClientID, play.AlbumID, play.Track, play.DurationSeconds, play.PlayedAt, receivedAt)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if affected == 0 {
+			err := tx.QueryRow("SELECT "+playColumns+" FROM plays p LEFT JOIN albums a ON a.id = p.album_id WHERE p.customer_id = ? AND p.client_id = ?",
+				play.CustomerID, play.ClientID).Scan(playFields(play)...)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		play.ID = uint(id)
+		if _, err := tx.Exec(countPlaysQuery, periods[i], models.ChartPlays, play.AlbumID, 1); err != nil {
+			return nil, err
+		}
+		created[i] = true
+	}
+	return created, tx.Commit()
+}
+
+// GetAlbumNames retrieves the names of those of several albums that exist, keyed by album ID.
+func (r *PlayRepository) GetAlbumNames(albumIDs []uint) (map[uint]string, error) {
+	names := make(map[uint]string)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query("SELECT id, name FROM albums WHERE id IN ("+placeholders+")", args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id uint
+			var name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			names[id] = name
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// playFilterClause builds the WHERE clause selecting the plays p matching a filter, or "" for every play.
+func playFilterClause(filter models.PlayFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.CustomerID != 0 {
+		conditions = append(conditions, "p.customer_id = ?")
+		args = append(args, filter.CustomerID)
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "p.played_at >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "p.played_at < ?")
+		args = append(args, filter.To)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// GetPlays retrieves the plays matching a filter, most recently played first, and how many match in total.
+func (r *PlayRepository) GetPlays(filter models.PlayFilter) ([]models.Play, int, error) {
+	where, args := playFilterClause(filter)
+
+	var total int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM plays p"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + playColumns + " FROM plays p LEFT JOIN albums a ON a.id = p.album_id" + where + " ORDER BY p.played_at DESC, p.id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	plays := []models.Play{}
+	for rows.Next() {
+		var play models.Play
+		if err := rows.Scan(playFields(&play)...); err != nil {
+			return nil, 0, err
+		}
+		plays = append(plays, play)
+	}
+	return plays, total, rows.Err()
+}
+
+// topJoins joins the plays p to what each kind of statistics ranks, selected as t.id and t.name. A play of
+// an album counts once for each musician credited on it, whatever their roles, and once for each genre it
+// is tagged with.
+var topJoins = map[string]string{
+	models.TopAlbums:    " JOIN albums t ON t.id = p.album_id",
+	models.TopMusicians: " JOIN (SELECT DISTINCT album_id, musician_id FROM album_musicians) am ON am.album_id = p.album_id JOIN musicians t ON t.id = am.musician_id",
+	models.TopGenres:    " JOIN album_genres ag ON ag.album_id = p.album_id JOIN genres t ON t.id = ag.genre_id",
+}
+
+// GetTop ranks the albums, musicians or genres most played among the plays matching a filter, ties by
+// name, returning up to filter.Limit of them, along with the number of matching plays.
+func (r *PlayRepository) GetTop(topType string, filter models.PlayFilter) ([]models.PlayCount, int, error) {
+	where, args := playFilterClause(filter)
+
+	var total int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM plays p"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query("SELECT t.id, t.name, COUNT(*), COUNT(DISTINCT p.customer_id) FROM plays p"+topJoins[topType]+where+
+		" GROUP BY t.id ORDER BY COUNT(*) DESC, t.name ASC, t.id ASC LIMIT ?", append(args, filter.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	top := []models.PlayCount{}
+	for rows.Next() {
+		var count models.PlayCount
+		if err := rows.Scan(&count.ID, &count.Name, &count.Plays, &count.Listeners); err != nil {
+			return nil, 0, err
+		}
+		top = append(top, count)
+	}
+	return top, total, rows.Err()
+}
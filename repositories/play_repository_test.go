@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"testing"
+)
+
+func TestCreatePlays(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &PlayRepository{DB: db}
+	seedChartAlbums(t, db)
+
+	plays := []models.Play{
+		{ClientID: "a", CustomerID: 1, AlbumID: 1, PlayedAt: "2026-10-05T10:00:00Z"},
+		{ClientID: "b", CustomerID: 1, AlbumID: 2, Track: 3, PlayedAt: "2026-10-05T11:00:00Z"},
+		{ClientID: "a", CustomerID: 1, AlbumID: 2, PlayedAt: "2026-10-05T12:00:00Z"},
+		{ClientID: "a", CustomerID: 2, AlbumID: 2, PlayedAt: "2026-10-05T12:00:00Z"},
+	}
+	periods := []string{"2026-W41", "2026-W41", "2026-W41", "2026-W41"}
+	created, err := repo.CreatePlays(plays, periods, "2026-10-06T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to create plays: %v", err)
+	}
+	if !created[0] || !created[1] || created[2] || !created[3] {
+		t.Errorf("expected client IDs to be unique per customer, got %v", created)
+	}
+	if plays[2].ID != plays[0].ID || plays[2].AlbumID != 1 || plays[2].AlbumName != "Album 1" {
+		t.Errorf("expected the duplicate replaced by the stored play, got %+v", plays[2])
+	}
+
+	chart, err := (&ChartRepository{DB: db}).RankAlbums(models.ChartPlays, "2026-W41", "", 0, 10)
+	if err != nil {
+		t.Fatalf("failed to rank plays: %v", err)
+	}
+	if len(chart) != 2 || chart[0].Album.ID != 2 || chart[0].Count != 2 || chart[1].Count != 1 {
+		t.Errorf("expected only new plays counted towards the chart, got %+v", chart)
+	}
+
+	names, err := repo.GetAlbumNames([]uint{1, 3, 99})
+	if err != nil {
+		t.Fatalf("failed to get album names: %v", err)
+	}
+	if len(names) != 2 || names[3] != "Album 3" {
+		t.Errorf("expected only existing albums, got %v", names)
+	}
+}
+
+func TestGetPlaysAndTop(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &PlayRepository{DB: db}
+	seedChartAlbums(t, db)
+
+	_, err := db.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'Ann', 'Singer'), (2, 'Bob', 'Drummer');
+		INSERT INTO album_musicians (album_id, musician_id, role) VALUES (1, 1, 'performer'), (1, 1, 'producer'), (2, 1, 'performer'), (2, 2, 'performer');
+		INSERT INTO plays (customer_id, client_id, album_id, played_at, received_at) VALUES
+		(1, 'a', 1, '2026-10-01T10:00:00Z', ''), (1, 'b', 1, '2026-10-02T10:00:00Z', ''), (2, 'c', 1, '2026-10-03T10:00:00Z', ''),
+		(2, 'd', 2, '2026-10-04T10:00:00Z', ''), (2, 'e', 3, '2026-10-05T10:00:00Z', '');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed plays: %v", err)
+	}
+
+	plays, total, err := repo.GetPlays(models.PlayFilter{CustomerID: 2, From: "2026-10-03T10:00:00Z", To: "2026-10-05T10:00:00Z", Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to get plays: %v", err)
+	}
+	if total != 2 || len(plays) != 1 || plays[0].ClientID != "d" || plays[0].AlbumName != "Album 2" {
+		t.Errorf("expected the latest play in the range first, got %d %+v", total, plays)
+	}
+
+	albums, total, err := repo.GetTop(models.TopAlbums, models.PlayFilter{To: "2026-10-05T00:00:00Z", Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to get top albums: %v", err)
+	}
+	if total != 4 || len(albums) != 2 || albums[0].Name != "Album 1" || albums[0].Plays != 3 || albums[0].Listeners != 2 {
+		t.Errorf("unexpected top albums %d %+v", total, albums)
+	}
+
+	musicians, _, err := repo.GetTop(models.TopMusicians, models.PlayFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to get top musicians: %v", err)
+	}
+	if len(musicians) != 2 || musicians[0].Name != "Ann" || musicians[0].Plays != 4 || musicians[1].Plays != 1 {
+		t.Errorf("expected a play to count once per musician credited, got %+v", musicians)
+	}
+
+	genres, _, err := repo.GetTop(models.TopGenres, models.PlayFilter{CustomerID: 2, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to get top genres: %v", err)
+	}
+	if len(genres) != 1 || genres[0].Name != "Heavy Metal" || genres[0].Plays != 1 {
+		t.Errorf("unexpected top genres %+v", genres)
+	}
+}
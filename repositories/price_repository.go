@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type PriceRepository struct {
+	DB *sql.DB
+}
+
+// ReplaceExchangeRates replaces every exchange rate with the given ones.
+func (r *PriceRepository) ReplaceExchangeRates(rates []models.ExchangeRate) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM exchange_rates"); err != nil {
+		return err
+	}
+	for _, rate := range rates {
+		if _, err := tx.Exec("INSERT INTO exchange_rates (currency, rate) VALUES (?, ?)", rate.Currency, rate.Rate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetExchangeRates retrieves every exchange rate sorted by currency.
+func (r *PriceRepository) GetExchangeRates() ([]models.ExchangeRate, error) {
+	rows, err := r.DB.Query("SELECT currency, rate FROM exchange_rates ORDER BY currency ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.ExchangeRate{}
+	for rows.Next() {
+		var rate models.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// SetAlbumPrice sets the price of an album in one currency, replacing any previous price in that currency.
+func (r *PriceRepository) SetAlbumPrice(price models.AlbumPrice) error {
+	_, err := r.DB.Exec(`
+        INSERT INTO album_prices (album_id, currency, price_minor) VALUES (?, ?, ?)
+        ON CONFLICT (album_id, currency) DO UPDATE SET price_minor = excluded.price_minor`,
+		price.AlbumID, price.Currency, models.ToMinor(price.Price, price.Currency))
+	return err
+}
+
+// DeleteAlbumPrice removes the price of an album in one currency. It returns sql.ErrNoRows if there is none.
+func (r *PriceRepository) DeleteAlbumPrice(albumID uint, currency string) error {
+	result, err := r.DB.Exec("DELETE FROM album_prices WHERE album_id = ? AND currency = ?", albumID, currency)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetAlbumPrices retrieves the price list of an album sorted by currency.
+func (r *PriceRepository) GetAlbumPrices(albumID uint) ([]models.AlbumPrice, error) {
+	rows, err := r.DB.Query("SELECT album_id, currency, price_minor FROM album_prices WHERE album_id = ? ORDER BY currency ASC", albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []models.AlbumPrice{}
+	for rows.Next() {
+		var price models.AlbumPrice
+		var minor int64
+		if err := rows.Scan(&price.AlbumID, &price.Currency, &minor); err != nil {
+			return nil, err
+		}
+		price.Price = models.FromMinor(minor, price.Currency)
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+// GetAlbumPricesIn retrieves the prices several albums have in one currency, keyed by album ID.
+// Albums without a price in the currency are left out. Large lists are queried in chunks.
+func (r *PriceRepository) GetAlbumPricesIn(albumIDs []uint, currency string) (map[uint]float64, error) {
+	prices := make(map[uint]float64)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query("SELECT album_id, price_minor FROM album_prices WHERE currency = ? AND album_id IN ("+placeholders+")",
+			append([]interface{}{currency}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var albumID uint
+			var minor int64
+			if err := rows.Scan(&albumID, &minor); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			prices[albumID] = models.FromMinor(minor, currency)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestPricesAreStoredInMinorUnits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	repo := PriceRepository{DB: db}
+
+	album := &models.Album{Name: "Kind of Blue", ReleaseDate: "1959-08-17", Price: 199.99, Currency: "JPY"}
+	if err := albums.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	// JPY has no minor unit, so the price is rounded to a whole yen
+	var minor int64
+	if err := db.QueryRow("SELECT price_minor FROM albums WHERE id = ?", album.ID).Scan(&minor); err != nil {
+		t.Fatalf("failed to read price: %v", err)
+	}
+	if minor != 200 {
+		t.Errorf("expected 200 minor units, got %d", minor)
+	}
+
+	for _, price := range []models.AlbumPrice{
+		{AlbumID: album.ID, Currency: "EUR", Price: 12.5},
+		{AlbumID: album.ID, Currency: "BHD", Price: 4.125},
+		{AlbumID: album.ID, Currency: "EUR", Price: 11.99},
+	} {
+		if err := repo.SetAlbumPrice(price); err != nil {
+			t.Fatalf("failed to set price: %v", err)
+		}
+	}
+
+	prices, err := repo.GetAlbumPrices(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get prices: %v", err)
+	}
+	if len(prices) != 2 || prices[0].Currency != "BHD" || prices[0].Price != 4.125 || prices[1].Price != 11.99 {
+		t.Errorf("unexpected prices: %+v", prices)
+	}
+
+	listed, err := repo.GetAlbumPricesIn(chunkedIDs(album.ID, 99), "EUR")
+	if err != nil {
+		t.Fatalf("failed to get prices: %v", err)
+	}
+	if len(listed) != 1 || listed[album.ID] != 11.99 {
+		t.Errorf("unexpected listed prices: %v", listed)
+	}
+
+	if err := repo.DeleteAlbumPrice(album.ID, "GBP"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestReplaceExchangeRates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := PriceRepository{DB: db}
+	if err := repo.ReplaceExchangeRates([]models.ExchangeRate{{Currency: "GBP", Rate: 0.8}, {Currency: "EUR", Rate: 0.9}}); err != nil {
+		t.Fatalf("failed to store rates: %v", err)
+	}
+	if err := repo.ReplaceExchangeRates([]models.ExchangeRate{{Currency: "JPY", Rate: 150}, {Currency: "EUR", Rate: 0.92}}); err != nil {
+		t.Fatalf("failed to replace rates: %v", err)
+	}
+
+	rates, err := repo.GetExchangeRates()
+	if err != nil {
+		t.Fatalf("failed to get rates: %v", err)
+	}
+	expected := []models.ExchangeRate{{Currency: "EUR", Rate: 0.92}, {Currency: "JPY", Rate: 150}}
+	if len(rates) != len(expected) || rates[0] != expected[0] || rates[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, rates)
+	}
+}
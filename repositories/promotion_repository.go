@@ -0,0 +1,324 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+)
+
+// promotionColumns selects every promotion column from the table aliased as p, in the order promotionFields
+// scans them. The currency comes before the fixed discount so it can be converted from its minor units.
+const promotionColumns = `p.id, p.name, p.kind, COALESCE(p.percent_off, 0), COALESCE(p.currency, ''), COALESCE(p.amount_off_minor, 0),
+    COALESCE(p.album_id, 0), COALESCE(p.genre_id, 0), COALESCE(p.label_id, 0), COALESCE(p.musician_id, 0), p.starts_at, p.ends_at`
+
+// promotionFields returns the scan destinations for promotionColumns.
+func promotionFields(promotion *models.Promotion) []interface{} {
+	return []interface{}{&promotion.ID, &promotion.Name, &promotion.Kind, &promotion.PercentOff, &promotion.Currency,
+		minorUnits{&promotion.AmountOff, &promotion.Currency}, &promotion.AlbumID, &promotion.GenreID, &promotion.LabelID,
+		&promotion.MusicianID, &promotion.StartsAt, &promotion.EndsAt}
+}
+
+// promotionArgs returns the values stored for a promotion, in the order of the insert and update statements.
+func promotionArgs(promotion *models.Promotion) []interface{} {
+	var percentOff, amountOff interface{}
+	if promotion.Kind == models.PromotionFixed {
+		amountOff = models.ToMinor(promotion.AmountOff, promotion.Currency)
+	} else {
+		percentOff = promotion.PercentOff
+	}
+	return []interface{}{promotion.Name, promotion.Kind, percentOff, amountOff, nullableString(promotion.Currency),
+		nullableID(promotion.AlbumID), nullableID(promotion.GenreID), nullableID(promotion.LabelID), nullableID(promotion.MusicianID),
+		promotion.StartsAt, promotion.EndsAt}
+}
+
+// promotionTargets maps the tables a promotion can target to their existence checks.
+var promotionTargets = map[string]string{
+	"albums":    "SELECT EXISTS(SELECT 1 FROM albums WHERE id = ?)",
+	"genres":    "SELECT EXISTS(SELECT 1 FROM genres WHERE id = ?)",
+	"labels":    "SELECT EXISTS(SELECT 1 FROM labels WHERE id = ?)",
+	"musicians": "SELECT EXISTS(SELECT 1 FROM musicians WHERE id = ?)",
+}
+
+type PromotionRepository struct {
+	DB *sql.DB
+	// Outbox, when set, receives an album update event for every scheduled price change applied.
+	Outbox *OutboxRepository
+}
+
+// CreatePromotion inserts a promotion and sets its ID.
+func (r *PromotionRepository) CreatePromotion(promotion *models.Promotion) error {
+	result, err := r.DB.Exec(`
+        INSERT INTO promotions (name, kind, percent_off, amount_off_minor, currency, album_id, genre_id, label_id, musician_id, starts_at, ends_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, promotionArgs(promotion)...)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	promotion.ID = uint(id)
+	return nil
+}
+
+// UpdatePromotion updates a promotion. It returns sql.ErrNoRows if the promotion does not exist.
+func (r *PromotionRepository) UpdatePromotion(promotion *models.Promotion) error {
+	result, err := r.DB.Exec(`
+        UPDATE promotions SET name = ?, kind = ?, percent_off = ?, amount_off_minor = ?, currency = ?,
+            album_id = ?, genre_id = ?, label_id = ?, musician_id = ?, starts_at = ?, ends_at = ?
+        WHERE id = ?`, append(promotionArgs(promotion), promotion.ID)...)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// DeletePromotion deletes a promotion. It returns sql.ErrNoRows if the promotion does not exist.
+func (r *PromotionRepository) DeletePromotion(id uint) error {
+	result, err := r.DB.Exec("DELETE FROM promotions WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetPromotionByID retrieves a single promotion. It returns sql.ErrNoRows if the promotion does not exist.
+func (r *PromotionRepository) GetPromotionByID(id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	err := r.DB.QueryRow("SELECT "+promotionColumns+" FROM promotions p WHERE p.id = ?", id).Scan(promotionFields(&promotion)...)
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetPromotions retrieves every promotion sorted by start time.
+func (r *PromotionRepository) GetPromotions() ([]models.Promotion, error) {
+	rows, err := r.DB.Query("SELECT " + promotionColumns + " FROM promotions p ORDER BY p.starts_at ASC, p.id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		var promotion models.Promotion
+		if err := rows.Scan(promotionFields(&promotion)...); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+// TargetExists reports whether the album, genre, label or musician a promotion targets exists.
+// table is one of albums, genres, labels or musicians.
+func (r *PromotionRepository) TargetExists(table string, id uint) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(promotionTargets[table], id).Scan(&exists)
+	return exists, err
+}
+
+// GetRunningPromotions retrieves, keyed by album ID, the promotions running at a time that target each of
+// the albums directly or through its label, one of its musicians, or one of its genres or their ancestors.
+// Large lists are queried in chunks.
+func (r *PromotionRepository) GetRunningPromotions(albumIDs []uint, at string) (map[uint][]models.Promotion, error) {
+	promotions := make(map[uint][]models.Promotion)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query(`
+            WITH RECURSIVE ancestry(album_id, genre_id) AS (
+                SELECT album_id, genre_id FROM album_genres WHERE album_id IN (`+placeholders+`)
+                UNION
+                SELECT ancestry.album_id, g.parent_id FROM ancestry JOIN genres g ON g.id = ancestry.genre_id WHERE g.parent_id IS NOT NULL
+            )
+            SELECT a.id, `+promotionColumns+`
+            FROM albums a
+            JOIN promotions p ON p.starts_at <= ? AND p.ends_at > ? AND (
+                p.album_id = a.id
+                OR p.label_id = a.label_id
+                OR p.musician_id IN (SELECT musician_id FROM album_musicians WHERE album_id = a.id)
+                OR p.genre_id IN (SELECT genre_id FROM ancestry WHERE album_id = a.id)
+            )
+            WHERE a.id IN (`+placeholders+`)
+            ORDER BY p.id ASC
+        `, append(append(args, at, at), args...)...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var albumID uint
+			var promotion models.Promotion
+			if err := rows.Scan(append([]interface{}{&albumID}, promotionFields(&promotion)...)...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			promotions[albumID] = append(promotions[albumID], promotion)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return promotions, nil
+}
+
+// CreatePriceSchedule inserts a scheduled price change and sets its ID. The price is stored in minor units
+// of the album's currency.
+func (r *PromotionRepository) CreatePriceSchedule(schedule *models.PriceSchedule, currency string) error {
+	result, err := r.DB.Exec("INSERT INTO price_schedules (album_id, price_minor, starts_at) VALUES (?, ?, ?)",
+		schedule.AlbumID, models.ToMinor(schedule.Price, currency), schedule.StartsAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	schedule.ID = uint(id)
+	return nil
+}
+
+// DeletePriceSchedule cancels a pending price change of an album. It returns sql.ErrNoRows if the album has
+// no such pending change.
+func (r *PromotionRepository) DeletePriceSchedule(albumID, scheduleID uint) error {
+	result, err := r.DB.Exec("DELETE FROM price_schedules WHERE id = ? AND album_id = ? AND applied_at IS NULL", scheduleID, albumID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetPriceSchedules retrieves the scheduled price changes of an album sorted by start time.
+func (r *PromotionRepository) GetPriceSchedules(albumID uint) ([]models.PriceSchedule, error) {
+	rows, err := r.DB.Query(`
+        SELECT s.id, s.album_id, a.currency, s.price_minor, s.starts_at, COALESCE(s.applied_at, '')
+        FROM price_schedules s
+        JOIN albums a ON a.id = s.album_id
+        WHERE s.album_id = ?
+        ORDER BY s.starts_at ASC, s.id ASC
+    `, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.PriceSchedule{}
+	for rows.Next() {
+		var schedule models.PriceSchedule
+		var currency string
+		if err := rows.Scan(&schedule.ID, &schedule.AlbumID, &currency, minorUnits{&schedule.Price, &currency}, &schedule.StartsAt, &schedule.AppliedAt); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// GetScheduledPrices retrieves, keyed by album ID, the price of the latest pending change due by a time.
+// Albums without such a change, or with editions, are left out. Large lists are queried in chunks.
+func (r *PromotionRepository) GetScheduledPrices(albumIDs []uint, at string) (map[uint]float64, error) {
+	prices := make(map[uint]float64)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query(`
+            SELECT s.album_id, a.currency, s.price_minor
+            FROM price_schedules s
+            JOIN albums a ON a.id = s.album_id
+            WHERE s.applied_at IS NULL AND s.starts_at <= ? AND s.album_id IN (`+placeholders+`)
+                AND NOT EXISTS (SELECT 1 FROM editions e WHERE e.album_id = s.album_id)
+            ORDER BY s.starts_at ASC, s.id ASC
+        `, append([]interface{}{at}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+		// Later changes overwrite earlier ones; every change of an album is in the same chunk
+		for rows.Next() {
+			var albumID uint
+			var currency string
+			var price float64
+			if err := rows.Scan(&albumID, &currency, minorUnits{&price, &currency}); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			prices[albumID] = price
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return prices, nil
+}
+
+// ApplyDueSchedules changes the price of albums whose scheduled changes are due by now, in order, and marks
+// the changes as applied. Albums with editions take their price from their editions and are skipped. It
+// returns how many changes were applied.
+func (r *PromotionRepository) ApplyDueSchedules(now string) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT s.id, s.album_id, s.price_minor
+        FROM price_schedules s
+        WHERE s.applied_at IS NULL AND s.starts_at <= ?
+            AND NOT EXISTS (SELECT 1 FROM editions e WHERE e.album_id = s.album_id)
+        ORDER BY s.starts_at ASC, s.id ASC
+    `, now)
+	if err != nil {
+		return 0, err
+	}
+	type dueSchedule struct {
+		id, albumID uint
+		priceMinor  int64
+	}
+	var due []dueSchedule
+	for rows.Next() {
+		var schedule dueSchedule
+		if err := rows.Scan(&schedule.id, &schedule.albumID, &schedule.priceMinor); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, schedule)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, schedule := range due {
+		if _, err := tx.Exec("UPDATE albums SET price_minor = ? WHERE id = ?", schedule.priceMinor, schedule.albumID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE price_schedules SET applied_at = ? WHERE id = ?", now, schedule.id); err != nil {
+			return 0, err
+		}
+		if err := r.appendAlbumEvent(tx, schedule.albumID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(due), tx.Commit()
+}
+
+// appendAlbumEvent records the updated album in the outbox when one is configured. Albums deleted
+// since the change was scheduled have nothing to report.
+func (r *PromotionRepository) appendAlbumEvent(tx *sql.Tx, albumID uint) error {
+	if r.Outbox == nil {
+		return nil
+	}
+	var album models.Album
+	err := tx.QueryRow("SELECT "+albumColumns+" FROM albums a WHERE a.id = ?", albumID).Scan(albumFields(&album)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.Outbox.Append(tx, "album", albumID, models.EventAlbumUpdated, album)
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"testing"
+)
+
+func TestGetRunningPromotionsByTarget(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	repo := PromotionRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO labels (id, name) VALUES (1, 'Blue Note');
+		INSERT INTO genres (id, name, key, parent_id) VALUES (1, 'Jazz', 'jazz', NULL), (2, 'Bebop', 'bebop', 1);
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'John Coltrane', 'Saxophonist');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	bebop := &models.Album{Name: "Bebop Album", ReleaseDate: "1950-01-01", Price: 200}
+	labelled := &models.Album{Name: "Label Album", ReleaseDate: "1960-01-01", Price: 200, LabelID: 1}
+	credited := &models.Album{Name: "Coltrane Album", ReleaseDate: "1965-01-01", Price: 200}
+	for _, album := range []*models.Album{bebop, labelled, credited} {
+		if err := albums.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO album_genres (album_id, genre_id) VALUES (?, 2)", bebop.ID); err != nil {
+		t.Fatalf("failed to tag album: %v", err)
+	}
+	if err := albums.LinkMusiciansToAlbum(credited.ID, []models.Credit{{MusicianID: 1, Role: models.CreditPerformer}}); err != nil {
+		t.Fatalf("failed to credit album: %v", err)
+	}
+
+	promotions := []*models.Promotion{
+		{Name: "Jazz Week", Kind: models.PromotionPercentage, PercentOff: 10, GenreID: 1, StartsAt: "2024-01-01T00:00:00Z", EndsAt: "2024-01-08T00:00:00Z"},
+		{Name: "Label Sale", Kind: models.PromotionFixed, AmountOff: 12.5, Currency: "USD", LabelID: 1, StartsAt: "2024-01-01T00:00:00Z", EndsAt: "2024-01-08T00:00:00Z"},
+		{Name: "Coltrane Day", Kind: models.PromotionPercentage, PercentOff: 20, MusicianID: 1, StartsAt: "2024-01-05T00:00:00Z", EndsAt: "2024-01-06T00:00:00Z"},
+	}
+	for _, promotion := range promotions {
+		if err := repo.CreatePromotion(promotion); err != nil {
+			t.Fatalf("failed to create promotion: %v", err)
+		}
+	}
+
+	running, err := repo.GetRunningPromotions(chunkedIDs(bebop.ID, labelled.ID, credited.ID), "2024-01-02T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to get running promotions: %v", err)
+	}
+	if len(running[bebop.ID]) != 1 || running[bebop.ID][0].Name != "Jazz Week" {
+		t.Errorf("expected the parent genre's promotion on the bebop album, got %+v", running[bebop.ID])
+	}
+	if len(running[labelled.ID]) != 1 || running[labelled.ID][0].AmountOff != 12.5 {
+		t.Errorf("expected the label's promotion, got %+v", running[labelled.ID])
+	}
+	if len(running[credited.ID]) != 0 {
+		t.Errorf("expected no promotion before Coltrane Day starts, got %+v", running[credited.ID])
+	}
+
+	// Promotions end exclusively
+	running, err = repo.GetRunningPromotions([]uint{bebop.ID, credited.ID}, "2024-01-08T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to get running promotions: %v", err)
+	}
+	if len(running) != 0 {
+		t.Errorf("expected every promotion to have ended, got %+v", running)
+	}
+}
+
+func TestApplyDueSchedules(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	outbox := &OutboxRepository{DB: db}
+	albums := AlbumRepository{DB: db}
+	repo := PromotionRepository{DB: db, Outbox: outbox}
+
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 500}
+	if err := albums.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	for _, schedule := range []*models.PriceSchedule{
+		{AlbumID: album.ID, Price: 300, StartsAt: "2024-01-02T00:00:00Z"},
+		{AlbumID: album.ID, Price: 250, StartsAt: "2024-01-01T00:00:00Z"},
+		{AlbumID: album.ID, Price: 400, StartsAt: "2024-02-01T00:00:00Z"},
+	} {
+		if err := repo.CreatePriceSchedule(schedule, album.Currency); err != nil {
+			t.Fatalf("failed to schedule price: %v", err)
+		}
+	}
+
+	scheduled, err := repo.GetScheduledPrices(chunkedIDs(album.ID), "2024-01-15T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to get scheduled prices: %v", err)
+	}
+	if scheduled[album.ID] != 300 {
+		t.Errorf("expected the latest due change, got %v", scheduled)
+	}
+
+	applied, err := repo.ApplyDueSchedules("2024-01-15T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to apply schedules: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("expected 2 changes applied, got %d", applied)
+	}
+
+	stored, err := albums.GetAlbumByID(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if stored.Price != 300 {
+		t.Errorf("expected the later change to win, got %v", stored.Price)
+	}
+
+	schedules, err := repo.GetPriceSchedules(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get schedules: %v", err)
+	}
+	if len(schedules) != 3 || schedules[0].AppliedAt != "2024-01-15T00:00:00Z" || schedules[2].AppliedAt != "" {
+		t.Errorf("unexpected schedules: %+v", schedules)
+	}
+
+	events, err := outbox.GetPendingEvents(10)
+	if err != nil {
+		t.Fatalf("failed to get pending events: %v", err)
+	}
+	if len(events) != 2 || events[1].Type != models.EventAlbumUpdated {
+		t.Errorf("expected an album update event per change, got %+v", events)
+	}
+
+	// Applied changes can no longer be cancelled
+	if err := repo.DeletePriceSchedule(album.ID, schedules[0].ID); err == nil {
+		t.Error("expected an applied change not to be cancelled")
+	}
+}
+
+func TestSchedulesYieldToEditions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	albums := AlbumRepository{DB: db}
+	editions := EditionRepository{DB: db}
+	repo := PromotionRepository{DB: db}
+
+	album := &models.Album{Name: "Blue Train", ReleaseDate: "1958-01-01", Price: 500}
+	if err := albums.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	if err := repo.CreatePriceSchedule(&models.PriceSchedule{AlbumID: album.ID, Price: 300, StartsAt: "2024-01-01T00:00:00Z"}, album.Currency); err != nil {
+		t.Fatalf("failed to schedule price: %v", err)
+	}
+
+	// Adding an edition cancels the pending change
+	if err := editions.CreateEdition(&models.Edition{AlbumID: album.ID, Format: models.FormatVinyl, ReleaseDate: "1957-09-01", Price: 400}); err != nil {
+		t.Fatalf("failed to create edition: %v", err)
+	}
+	schedules, err := repo.GetPriceSchedules(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get schedules: %v", err)
+	}
+	if len(schedules) != 0 {
+		t.Errorf("expected the pending change to be cancelled, got %+v", schedules)
+	}
+
+	// A change left pending alongside editions is neither priced nor applied
+	if _, err := db.Exec("INSERT INTO price_schedules (album_id, price_minor, starts_at) VALUES (?, 30000, '2024-01-01T00:00:00Z')", album.ID); err != nil {
+		t.Fatalf("failed to insert schedule: %v", err)
+	}
+	scheduled, err := repo.GetScheduledPrices(chunkedIDs(album.ID), "2024-01-15T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to get scheduled prices: %v", err)
+	}
+	if len(scheduled) != 0 {
+		t.Errorf("expected no scheduled price for an album with editions, got %v", scheduled)
+	}
+	applied, err := repo.ApplyDueSchedules("2024-01-15T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to apply schedules: %v", err)
+	}
+	if applied != 0 {
+		t.Errorf("expected no change applied, got %d", applied)
+	}
+	stored, err := albums.GetAlbumByID(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if stored.Price != 400 {
+		t.Errorf("expected the edition price to stand, got %v", stored.Price)
+	}
+}
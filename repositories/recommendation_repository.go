@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+	"strings"
+)
+
+type RecommendationRepository struct {
+	DB *sql.DB
+}
+
+// GetSignals loads everything recommendations are computed from. Listeners are the customers, or the
+// guest orders, of paid and shipped orders, and the customers whose approved reviews rate an album at
+// least minRating.
+func (r *RecommendationRepository) GetSignals(minRating int) (*models.RecommendationSignals, error) {
+	signals := &models.RecommendationSignals{
+		ReleaseDates:   make(map[uint]string),
+		AlbumMusicians: make(map[uint][]uint),
+		AlbumGenres:    make(map[uint][]uint),
+		AlbumListeners: make(map[uint][]string),
+		MusicianGroups: make(map[uint][]uint),
+	}
+
+	rows, err := r.DB.Query("SELECT id, release_date FROM albums")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var albumID uint
+		var releaseDate string
+		if err := rows.Scan(&albumID, dateOnly{&releaseDate}); err != nil {
+			return nil, err
+		}
+		signals.ReleaseDates[albumID] = releaseDate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.scanPairs("SELECT DISTINCT album_id, musician_id FROM album_musicians", signals.AlbumMusicians); err != nil {
+		return nil, err
+	}
+	// Albums belong to the ancestors of their genres too, so "Heavy Metal" and "Punk" overlap in "Rock"
+	if err := r.scanPairs(`
+        WITH RECURSIVE tagged(album_id, genre_id) AS (
+            SELECT album_id, genre_id FROM album_genres
+            UNION
+            SELECT t.album_id, g.parent_id FROM tagged t JOIN genres g ON g.id = t.genre_id WHERE g.parent_id IS NOT NULL
+        )
+        SELECT album_id, genre_id FROM tagged`, signals.AlbumGenres); err != nil {
+		return nil, err
+	}
+	if err := r.scanPairs("SELECT DISTINCT member_id, group_id FROM group_memberships", signals.MusicianGroups); err != nil {
+		return nil, err
+	}
+
+	listeners, err := r.DB.Query(`
+        SELECT ol.album_id, CASE WHEN o.customer_id IS NULL THEN 'order:' || o.id ELSE 'customer:' || o.customer_id END
+        FROM order_lines ol
+        JOIN orders o ON o.id = ol.order_id
+        WHERE o.status IN (?, ?)
+        UNION
+        SELECT album_id, 'customer:' || customer_id FROM reviews WHERE status = ? AND rating >= ?`,
+		models.OrderPaid, models.OrderShipped, models.ReviewApproved, minRating)
+	if err != nil {
+		return nil, err
+	}
+	defer listeners.Close()
+	for listeners.Next() {
+		var albumID uint
+		var listener string
+		if err := listeners.Scan(&albumID, &listener); err != nil {
+			return nil, err
+		}
+		signals.AlbumListeners[albumID] = append(signals.AlbumListeners[albumID], listener)
+	}
+	return signals, listeners.Err()
+}
+
+// scanPairs runs a query selecting pairs of IDs and groups the second by the first.
+func (r *RecommendationRepository) scanPairs(query string, pairs map[uint][]uint) error {
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, value uint
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		pairs[key] = append(pairs[key], value)
+	}
+	return rows.Err()
+}
+
+// ReplaceAlbumRecommendations swaps every precomputed album recommendation for a new set in one transaction.
+func (r *RecommendationRepository) ReplaceAlbumRecommendations(recommendations []models.Recommendation, computedAt string) error {
+	return r.replace("album_recommendations", "album_id", "related_album_id", recommendations, computedAt)
+}
+
+// ReplaceMusicianRecommendations swaps every precomputed musician recommendation for a new set in one transaction.
+func (r *RecommendationRepository) ReplaceMusicianRecommendations(recommendations []models.Recommendation, computedAt string) error {
+	return r.replace("musician_recommendations", "musician_id", "related_musician_id", recommendations, computedAt)
+}
+
+func (r *RecommendationRepository) replace(table, subjectColumn, relatedColumn string, recommendations []models.Recommendation, computedAt string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO " + table + " (" + subjectColumn + ", " + relatedColumn + ", score, reasons, computed_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, recommendation := range recommendations {
+		if _, err := stmt.Exec(recommendation.SubjectID, recommendation.RelatedID, recommendation.Score, strings.Join(recommendation.Reasons, ","), computedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRelatedAlbums retrieves up to limit of an album's precomputed recommendations, best first.
+func (r *RecommendationRepository) GetRelatedAlbums(albumID uint, limit int) ([]models.RelatedAlbum, error) {
+	rows, err := r.DB.Query(`
+        SELECT `+albumColumns+`, rec.score, rec.reasons
+        FROM album_recommendations rec
+        JOIN albums a ON a.id = rec.related_album_id
+        WHERE rec.album_id = ?
+        ORDER BY rec.score DESC, a.id ASC
+        LIMIT ?`, albumID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []models.RelatedAlbum{}
+	for rows.Next() {
+		var album models.RelatedAlbum
+		var reasons string
+		if err := rows.Scan(append(albumFields(&album.Album), &album.Score, &reasons)...); err != nil {
+			return nil, err
+		}
+		album.Reasons = strings.Split(reasons, ",")
+		related = append(related, album)
+	}
+	return related, rows.Err()
+}
+
+// GetRelatedMusicians retrieves up to limit of a musician's precomputed recommendations, best first.
+func (r *RecommendationRepository) GetRelatedMusicians(musicianID uint, limit int) ([]models.RelatedMusician, error) {
+	rows, err := r.DB.Query(`
+        SELECT m.id, m.name, m.musician_type, m.kind, rec.score, rec.reasons
+        FROM musician_recommendations rec
+        JOIN musicians m ON m.id = rec.related_musician_id
+        WHERE rec.musician_id = ?
+        ORDER BY rec.score DESC, m.id ASC
+        LIMIT ?`, musicianID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []models.RelatedMusician{}
+	for rows.Next() {
+		var musician models.RelatedMusician
+		var reasons string
+		if err := rows.Scan(&musician.ID, &musician.Name, &musician.MusicianType, &musician.Kind, &musician.Score, &reasons); err != nil {
+			return nil, err
+		}
+		musician.Reasons = strings.Split(reasons, ",")
+		related = append(related, musician)
+	}
+	return related, rows.Err()
+}
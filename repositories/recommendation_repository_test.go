@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"slices"
+	"testing"
+)
+
+func TestGetSignals(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &RecommendationRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO albums (id, name, release_date, price_minor) VALUES (1, 'Album 1', '2000-01-01', 1000), (2, 'Album 2', '2004-01-01', 1000);
+		INSERT INTO album_musicians (album_id, musician_id, role) VALUES (1, 1, 'performer'), (1, 1, 'producer'), (2, 2, 'performer');
+		INSERT INTO genres (id, name, key, parent_id) VALUES (1, 'Rock', 'rock', NULL), (2, 'Heavy Metal', 'heavy metal', 1);
+		INSERT INTO album_genres (album_id, genre_id) VALUES (1, 2);
+		INSERT INTO group_memberships (group_id, member_id, joined_on) VALUES (3, 1, '1990-01-01');
+		INSERT INTO orders (id, cart_id, status, country, currency, subtotal_minor, tax_rate, tax_minor, total_minor, created_at, updated_at, customer_id) VALUES
+		(1, 1, 'paid', 'GB', 'GBP', 0, 0, 0, 0, '', '', 7),
+		(2, 2, 'shipped', 'GB', 'GBP', 0, 0, 0, 0, '', '', NULL),
+		(3, 3, 'pending', 'GB', 'GBP', 0, 0, 0, 0, '', '', 8);
+		INSERT INTO order_lines (order_id, album_id, name, quantity, unit_price_minor, total_minor) VALUES
+		(1, 1, 'Album 1', 1, 0, 0), (2, 1, 'Album 1', 1, 0, 0), (3, 2, 'Album 2', 1, 0, 0);
+		INSERT INTO reviews (album_id, customer_id, rating, status, created_at, updated_at) VALUES
+		(2, 7, 5, 'approved', '', ''), (2, 8, 5, 'pending', '', ''), (1, 9, 2, 'approved', '', '');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed signals: %v", err)
+	}
+
+	signals, err := repo.GetSignals(4)
+	if err != nil {
+		t.Fatalf("failed to get signals: %v", err)
+	}
+	if len(signals.ReleaseDates) != 2 || signals.ReleaseDates[2] != "2004-01-01" {
+		t.Errorf("unexpected release dates %v", signals.ReleaseDates)
+	}
+	if !slices.Equal(signals.AlbumMusicians[1], []uint{1}) {
+		t.Errorf("expected a musician credited twice to be listed once, got %v", signals.AlbumMusicians[1])
+	}
+	if genres := signals.AlbumGenres[1]; len(genres) != 2 || !slices.Contains(genres, 1) {
+		t.Errorf("expected album genres to include their ancestors, got %v", genres)
+	}
+	if !slices.Equal(signals.MusicianGroups[1], []uint{3}) {
+		t.Errorf("unexpected groups %v", signals.MusicianGroups)
+	}
+
+	listeners := signals.AlbumListeners[1]
+	slices.Sort(listeners)
+	if !slices.Equal(listeners, []string{"customer:7", "order:2"}) {
+		t.Errorf("expected paid and shipped buyers of album 1, got %v", listeners)
+	}
+	if !slices.Equal(signals.AlbumListeners[2], []string{"customer:7"}) {
+		t.Errorf("expected only the approved high rating of album 2, got %v", signals.AlbumListeners[2])
+	}
+}
+
+func TestReplaceRecommendations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &RecommendationRepository{DB: db}
+	albumRepo := &AlbumRepository{DB: db}
+	for _, name := range []string{"Album 1", "Album 2", "Album 3"} {
+		if err := albumRepo.CreateAlbum(&models.Album{Name: name, ReleaseDate: "2020-01-01", Price: 10}); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	if err := repo.ReplaceAlbumRecommendations([]models.Recommendation{
+		{SubjectID: 1, RelatedID: 2, Score: 0.3, Reasons: []string{models.ReasonGenre}},
+		{SubjectID: 1, RelatedID: 3, Score: 0.6, Reasons: []string{models.ReasonMusicians, models.ReasonEra}},
+	}, "2024-01-01T00:00:00Z"); err != nil {
+		t.Fatalf("failed to store recommendations: %v", err)
+	}
+
+	related, err := repo.GetRelatedAlbums(1, 10)
+	if err != nil {
+		t.Fatalf("failed to get related albums: %v", err)
+	}
+	if len(related) != 2 || related[0].ID != 3 || related[0].Name != "Album 3" || !slices.Equal(related[0].Reasons, []string{"musicians", "era"}) {
+		t.Errorf("expected the best match first, got %+v", related)
+	}
+	if related, _ := repo.GetRelatedAlbums(1, 1); len(related) != 1 {
+		t.Errorf("expected the limit to apply, got %+v", related)
+	}
+
+	// Refreshing replaces every earlier recommendation
+	if err := repo.ReplaceAlbumRecommendations([]models.Recommendation{
+		{SubjectID: 2, RelatedID: 1, Score: 0.3, Reasons: []string{models.ReasonGenre}},
+	}, "2024-01-02T00:00:00Z"); err != nil {
+		t.Fatalf("failed to store recommendations: %v", err)
+	}
+	if related, _ := repo.GetRelatedAlbums(1, 10); len(related) != 0 {
+		t.Errorf("expected stale recommendations to be gone, got %+v", related)
+	}
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"strings"
+)
+
+// ErrAlreadyReviewed is returned when a customer reviews an album they have already reviewed.
+var ErrAlreadyReviewed = errors.New("album has already been reviewed by this customer")
+
+type ReviewRepository struct {
+	DB *sql.DB
+}
+
+// CreateReview inserts a review and sets its ID. It returns ErrAlreadyReviewed if the customer has already
+// reviewed the album.
+func (r *ReviewRepository) CreateReview(review *models.Review) error {
+	result, err := r.DB.Exec(`
+        INSERT INTO reviews (album_id, customer_id, rating, body, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (album_id, customer_id) DO NOTHING`,
+		review.AlbumID, review.CustomerID, review.Rating, review.Text, review.Status, review.CreatedAt, review.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAlreadyReviewed
+		}
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	review.ID = uint(id)
+	return nil
+}
+
+// UpdateReview updates the rating, text and status of a review of an album by its author. It returns
+// sql.ErrNoRows if the customer has no such review.
+func (r *ReviewRepository) UpdateReview(review *models.Review) error {
+	result, err := r.DB.Exec("UPDATE reviews SET rating = ?, body = ?, status = ?, updated_at = ? WHERE id = ? AND album_id = ? AND customer_id = ?",
+		review.Rating, review.Text, review.Status, review.UpdatedAt, review.ID, review.AlbumID, review.CustomerID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// DeleteReview deletes a customer's review of an album. It returns sql.ErrNoRows if the customer has no
+// such review.
+func (r *ReviewRepository) DeleteReview(albumID, reviewID, customerID uint) error {
+	result, err := r.DB.Exec("DELETE FROM reviews WHERE id = ? AND album_id = ? AND customer_id = ?", reviewID, albumID, customerID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// SetReviewStatus moderates a review. It returns sql.ErrNoRows if the review does not exist.
+func (r *ReviewRepository) SetReviewStatus(reviewID uint, status, updatedAt string) error {
+	result, err := r.DB.Exec("UPDATE reviews SET status = ?, updated_at = ? WHERE id = ?", status, updatedAt, reviewID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+const reviewColumns = "rv.id, rv.album_id, rv.customer_id, c.name, rv.rating, rv.body, rv.status, rv.created_at, rv.updated_at"
+
+func scanReview(scanner interface{ Scan(...interface{}) error }) (models.Review, error) {
+	var review models.Review
+	err := scanner.Scan(&review.ID, &review.AlbumID, &review.CustomerID, &review.Author, &review.Rating, &review.Text, &review.Status,
+		&review.CreatedAt, &review.UpdatedAt)
+	return review, err
+}
+
+// GetReview retrieves a review. It returns sql.ErrNoRows if the review does not exist.
+func (r *ReviewRepository) GetReview(reviewID uint) (*models.Review, error) {
+	review, err := scanReview(r.DB.QueryRow("SELECT "+reviewColumns+" FROM reviews rv JOIN customers c ON c.id = rv.customer_id WHERE rv.id = ?", reviewID))
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetReviews retrieves one page of the reviews matching a filter, newest first, with the number of reviews
+// matching it on every page.
+func (r *ReviewRepository) GetReviews(filter models.ReviewFilter) ([]models.Review, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.AlbumID != 0 {
+		conditions = append(conditions, "rv.album_id = ?")
+		args = append(args, filter.AlbumID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "rv.status = ?")
+		args = append(args, filter.Status)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM reviews rv"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + reviewColumns + " FROM reviews rv JOIN customers c ON c.id = rv.customer_id" + where + " ORDER BY rv.created_at DESC, rv.id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, total, rows.Err()
+}
+
+// GetRatingSummaries works out the average rating and number of approved reviews of several albums, one
+// query per chunk of IDs. Albums without approved reviews are left out.
+func (r *ReviewRepository) GetRatingSummaries(albumIDs []uint) (map[uint]models.RatingSummary, error) {
+	summaries := make(map[uint]models.RatingSummary)
+	for start := 0; start < len(albumIDs); start += maxInClause {
+		placeholders, args := inClause(albumIDs[start:min(start+maxInClause, len(albumIDs))])
+		rows, err := r.DB.Query(`
+            SELECT album_id, AVG(rating), COUNT(*)
+            FROM reviews
+            WHERE status = ? AND album_id IN (`+placeholders+`)
+            GROUP BY album_id`, append([]interface{}{models.ReviewApproved}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var albumID uint
+			var summary models.RatingSummary
+			if err := rows.Scan(&albumID, &summary.AverageRating, &summary.ReviewCount); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			summaries[albumID] = summary
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"jukebox/models"
+	"testing"
+)
+
+func TestGetRatingSummaries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := ReviewRepository{DB: db}
+
+	_, err := db.Exec(`
+		INSERT INTO reviews (album_id, customer_id, rating, status, created_at, updated_at) VALUES
+		(1, 1, 5, 'approved', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z'),
+		(1, 2, 2, 'approved', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z'),
+		(1, 3, 1, 'pending', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z'),
+		(2, 1, 1, 'rejected', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	// More IDs than fit in one IN list are queried in chunks
+	summaries, err := repo.GetRatingSummaries(chunkedIDs(1, 2))
+	if err != nil {
+		t.Fatalf("failed to get rating summaries: %v", err)
+	}
+	if len(summaries) != 1 || summaries[1] != (models.RatingSummary{AverageRating: 3.5, ReviewCount: 2}) {
+		t.Errorf("expected only the approved reviews of album 1, got %+v", summaries)
+	}
+}
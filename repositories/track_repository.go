@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"database/sql"
+	"jukebox/models"
+)
+
+type TrackRepository struct {
+	DB *sql.DB
+}
+
+const trackColumns = "id, album_id, disc, number, title, duration_seconds"
+
+func trackFields(track *models.Track) []interface{} {
+	return []interface{}{&track.ID, &track.AlbumID, &track.Disc, &track.Number, &track.Title, &track.DurationSeconds}
+}
+
+// CreateTrack inserts a track, setting its ID.
+func (r *TrackRepository) CreateTrack(track *models.Track) error {
+	result, err := r.DB.Exec("INSERT INTO tracks (album_id, disc, number, title, duration_seconds) VALUES (?, ?, ?, ?, ?)",
+		track.AlbumID, track.Disc, track.Number, track.Title, track.DurationSeconds)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	track.ID = uint(id)
+	return nil
+}
+
+// GetTrackByID retrieves a track. It returns sql.ErrNoRows if the track does not exist.
+func (r *TrackRepository) GetTrackByID(id uint) (*models.Track, error) {
+	var track models.Track
+	if err := r.DB.QueryRow("SELECT "+trackColumns+" FROM tracks WHERE id = ?", id).Scan(trackFields(&track)...); err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// UpdateTrack updates the position, title and duration of a track. It returns sql.ErrNoRows if the track does
+// not exist.
+func (r *TrackRepository) UpdateTrack(track *models.Track) error {
+	result, err := r.DB.Exec("UPDATE tracks SET disc = ?, number = ?, title = ?, duration_seconds = ? WHERE id = ?",
+		track.Disc, track.Number, track.Title, track.DurationSeconds, track.ID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetTrack retrieves the track at a position of an album. It returns sql.ErrNoRows if there is none.
+func (r *TrackRepository) GetTrack(albumID uint, disc, number int) (*models.Track, error) {
+	var track models.Track
+	err := r.DB.QueryRow("SELECT "+trackColumns+" FROM tracks WHERE album_id = ? AND disc = ? AND number = ?", albumID, disc, number).
+		Scan(trackFields(&track)...)
+	if err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// GetTracks retrieves the tracks of an album in disc and track order, with the time their library files went
+// missing when none of them is found any more.
+func (r *TrackRepository) GetTracks(albumID uint) ([]models.Track, error) {
+	rows, err := r.DB.Query(`
+		SELECT `+trackColumns+`,
+			COALESCE((SELECT CASE WHEN COUNT(*) = COUNT(missing_since) THEN MAX(missing_since) END
+				FROM library_files WHERE track_id = tracks.id), '')
+		FROM tracks WHERE album_id = ? ORDER BY disc, number`, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracks := []models.Track{}
+	for rows.Next() {
+		var track models.Track
+		if err := rows.Scan(append(trackFields(&track), &track.MissingSince)...); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"testing"
+)
+
+func TestTracks(t *testing.T) {
+	db := setupTestDB(t)
+	repo := &TrackRepository{DB: db}
+
+	tracks := []models.Track{
+		{AlbumID: 1, Disc: 2, Number: 1, Title: "Side B"},
+		{AlbumID: 1, Disc: 1, Number: 2, Title: "Second", DurationSeconds: 200},
+		{AlbumID: 1, Disc: 1, Number: 1, Title: "First", DurationSeconds: 180},
+		{AlbumID: 2, Disc: 1, Number: 1, Title: "Elsewhere"},
+	}
+	for i := range tracks {
+		if err := repo.CreateTrack(&tracks[i]); err != nil {
+			t.Fatalf("failed to create track: %v", err)
+		}
+	}
+	if err := repo.CreateTrack(&models.Track{AlbumID: 1, Disc: 1, Number: 1, Title: "Duplicate"}); err == nil {
+		t.Error("expected a second track at the same position to be rejected")
+	}
+
+	track, err := repo.GetTrack(1, 1, 2)
+	if err != nil {
+		t.Fatalf("failed to get track: %v", err)
+	}
+	if track.ID != tracks[1].ID || track.Title != "Second" || track.DurationSeconds != 200 {
+		t.Errorf("unexpected track: %+v", track)
+	}
+	if _, err := repo.GetTrack(1, 3, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for an empty position, got %v", err)
+	}
+
+	byID, err := repo.GetTrackByID(tracks[2].ID)
+	if err != nil || byID.Title != "First" {
+		t.Fatalf("expected the first track, got %+v, %v", byID, err)
+	}
+	byID.Number, byID.Title, byID.DurationSeconds = 3, "First (Remastered)", 181
+	if err := repo.UpdateTrack(byID); err != nil {
+		t.Fatalf("failed to update track: %v", err)
+	}
+	if err := repo.UpdateTrack(&models.Track{ID: 99, Title: "Nowhere"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows updating a missing track, got %v", err)
+	}
+
+	listed, err := repo.GetTracks(1)
+	if err != nil {
+		t.Fatalf("failed to get tracks: %v", err)
+	}
+	var titles []string
+	for _, track := range listed {
+		titles = append(titles, track.Title)
+	}
+	if len(titles) != 3 || titles[0] != "Second" || titles[1] != "First (Remastered)" || titles[2] != "Side B" {
+		t.Errorf("expected tracks in disc and track order, got %v", titles)
+	}
+
+	empty, err := repo.GetTracks(3)
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("expected an empty list for an album without tracks, got %v, %v", empty, err)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupChartRoutes registers the weekly chart endpoint on an existing router.
+func SetupChartRoutes(r *mux.Router, chartController *controllers.ChartController) {
+	r.HandleFunc("/charts/{type}", chartController.GetChart).Methods("GET")
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupCollaborationRoutes registers the musician collaboration graph endpoints on an existing router.
+func SetupCollaborationRoutes(r *mux.Router, collaborationController *controllers.CollaborationController) {
+	r.HandleFunc("/musicians/{id:[0-9]+}/collaborators", collaborationController.GetCollaborators).Methods("GET")
+	r.HandleFunc("/musicians/{id:[0-9]+}/path/{to_id:[0-9]+}", collaborationController.GetPath).Methods("GET")
+	r.HandleFunc("/collaborations/components", collaborationController.GetStats).Methods("GET")
+}
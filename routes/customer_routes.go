@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupCustomerRoutes registers the customer account, session and library endpoints on an existing router.
+func SetupCustomerRoutes(r *mux.Router, customerController *controllers.CustomerController) {
+	r.HandleFunc("/customers", customerController.Register).Methods("POST")
+	r.HandleFunc("/sessions", customerController.Login).Methods("POST")
+	r.HandleFunc("/sessions", customerController.Logout).Methods("DELETE")
+	r.HandleFunc("/me", customerController.GetMe).Methods("GET")
+	r.HandleFunc("/me/library", customerController.GetLibrary).Methods("GET")
+	r.HandleFunc("/me/library", customerController.AddToLibrary).Methods("POST")
+	r.HandleFunc("/me/library/{album_id:[0-9]+}", customerController.RemoveFromLibrary).Methods("DELETE")
+	r.HandleFunc("/me/orders", customerController.GetOrders).Methods("GET")
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupEditionRoutes registers the album edition endpoints on an existing router.
+func SetupEditionRoutes(r *mux.Router, editionController *controllers.EditionController) {
+	r.HandleFunc("/albums/{id:[0-9]+}/editions", editionController.GetEditions).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/editions", editionController.AddEdition).Methods("POST")
+	r.HandleFunc("/albums/{id:[0-9]+}/editions/{edition_id:[0-9]+}", editionController.UpdateEdition).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/editions/{edition_id:[0-9]+}", editionController.RemoveEdition).Methods("DELETE")
+}
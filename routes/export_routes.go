@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupExportRoutes registers the catalog export endpoint on an existing router.
+func SetupExportRoutes(r *mux.Router, exportController *controllers.ExportController) {
+	r.HandleFunc("/export", exportController.Export).Methods("GET")
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupGenreRoutes registers the genre taxonomy endpoints on an existing router.
+func SetupGenreRoutes(r *mux.Router, genreController *controllers.GenreController) {
+	r.HandleFunc("/genres", genreController.GetGenres).Methods("GET")
+	r.HandleFunc("/genres", genreController.CreateGenre).Methods("POST")
+	r.HandleFunc("/genres/{id:[0-9]+}", genreController.GetGenre).Methods("GET")
+	r.HandleFunc("/genres/{id:[0-9]+}", genreController.UpdateGenre).Methods("PUT")
+	r.HandleFunc("/genres/{id:[0-9]+}", genreController.DeleteGenre).Methods("DELETE")
+	r.HandleFunc("/albums/{id:[0-9]+}/genres", genreController.GetAlbumGenres).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/genres", genreController.SetAlbumGenres).Methods("PUT")
+}
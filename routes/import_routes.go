@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupImportRoutes registers the bulk import endpoint on an existing router.
+func SetupImportRoutes(r *mux.Router, importController *controllers.ImportController) {
+	r.HandleFunc("/import", importController.Import).Methods("POST")
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupIngestRoutes registers the audio ingestion, conflict review and track endpoints on an existing router.
+func SetupIngestRoutes(r *mux.Router, ingestController *controllers.IngestController) {
+	r.HandleFunc("/ingest", ingestController.Ingest).Methods("POST")
+	r.HandleFunc("/ingest/conflicts", ingestController.GetConflicts).Methods("GET")
+	r.HandleFunc("/ingest/conflicts/{id:[0-9]+}", ingestController.ReviewConflict).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/tracks", ingestController.GetTracks).Methods("GET")
+}
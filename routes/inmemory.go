@@ -17,12 +17,12 @@ func (s *InMemoryAlbumService) CreateAlbum(album *models.Album) error {
 	return nil
 }
 
-func (s *InMemoryAlbumService) LinkMusiciansToAlbum(albumID uint, musicianIDs []uint) error {
+func (s *InMemoryAlbumService) LinkMusiciansToAlbum(albumID uint, credits []models.Credit) error {
 	// In-memory implementation to link musicians to an album (no-op for simplicity)
 	return nil
 }
 
-func (s *InMemoryAlbumService) GetAlbums() ([]models.Album, error) {
+func (s *InMemoryAlbumService) GetAlbums(filter models.AlbumFilter) ([]models.Album, error) {
 	return s.albums, nil
 }
 
@@ -46,9 +46,14 @@ func (s *InMemoryAlbumService) DeleteAlbum(albumID uint) error {
 	return nil
 }
 
-func (s *InMemoryAlbumService) GetAlbumsByMusician(musicianID uint) ([]models.Album, error) {
+func (s *InMemoryAlbumService) GetAlbumsByMusician(musicianID uint, role, currency string) ([]models.CreditedAlbum, error) {
 	// Return albums linked to the specified musician ID (for simplicity, return empty)
-	return []models.Album{}, nil
+	return []models.CreditedAlbum{}, nil
+}
+
+func (s *InMemoryAlbumService) GetAlbumsByMusicians(musicianIDs []uint) (map[uint][]models.Album, error) {
+	// Return albums linked to the specified musician IDs (for simplicity, return empty)
+	return map[uint][]models.Album{}, nil
 }
 
 func (s *InMemoryMusicianService) CreateMusician(musician *models.Musician) error {
@@ -81,7 +86,12 @@ func (s *InMemoryMusicianService) DeleteMusician(musicianID uint) error {
 	return nil
 }
 
-func (s *InMemoryMusicianService) GetMusiciansByAlbum(albumID uint) ([]models.Musician, error) {
+func (s *InMemoryMusicianService) GetMusiciansByAlbum(albumID uint, role string) ([]models.CreditedMusician, error) {
 	// Return musicians linked to the specified album ID (for simplicity, return empty)
-	return []models.Musician{}, nil
+	return []models.CreditedMusician{}, nil
+}
+
+func (s *InMemoryMusicianService) GetMusiciansByAlbums(albumIDs []uint) (map[uint][]models.Musician, error) {
+	// Return musicians linked to the specified album IDs (for simplicity, return empty)
+	return map[uint][]models.Musician{}, nil
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupInventoryRoutes registers the stock level and stock movement endpoints on an existing router.
+func SetupInventoryRoutes(r *mux.Router, inventoryController *controllers.InventoryController) {
+	r.HandleFunc("/albums/{id:[0-9]+}/stock", inventoryController.GetStock).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/stock", inventoryController.SetLowStockThreshold).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/stock/movements", inventoryController.GetMovements).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/stock/movements", inventoryController.RecordMovement).Methods("POST")
+	r.HandleFunc("/stock/low", inventoryController.GetLowStock).Methods("GET")
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupLabelRoutes registers the record label endpoints on an existing router.
+func SetupLabelRoutes(r *mux.Router, labelController *controllers.LabelController) {
+	r.HandleFunc("/labels", labelController.GetLabels).Methods("GET")
+	r.HandleFunc("/labels", labelController.CreateLabel).Methods("POST")
+	r.HandleFunc("/labels/{id:[0-9]+}", labelController.GetLabel).Methods("GET")
+	r.HandleFunc("/labels/{id:[0-9]+}", labelController.UpdateLabel).Methods("PUT")
+	r.HandleFunc("/labels/{id:[0-9]+}", labelController.DeleteLabel).Methods("DELETE")
+	r.HandleFunc("/labels/{id:[0-9]+}/albums", labelController.GetAlbumsByLabel).Methods("GET")
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupMembershipRoutes registers the band membership and album lineup endpoints on an existing router.
+func SetupMembershipRoutes(r *mux.Router, membershipController *controllers.MembershipController) {
+	r.HandleFunc("/musicians/{id:[0-9]+}/members", membershipController.GetMembers).Methods("GET")
+	r.HandleFunc("/musicians/{id:[0-9]+}/members", membershipController.AddMember).Methods("POST")
+	r.HandleFunc("/musicians/{id:[0-9]+}/members/{membership_id:[0-9]+}", membershipController.UpdateMembership).Methods("PUT")
+	r.HandleFunc("/musicians/{id:[0-9]+}/members/{membership_id:[0-9]+}", membershipController.RemoveMember).Methods("DELETE")
+	r.HandleFunc("/albums/{id:[0-9]+}/lineup", membershipController.GetAlbumLineup).Methods("GET")
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"encoding/json"
+	"jukebox/controllers"
+	"jukebox/openapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestEveryRouteHasSpecEntry(t *testing.T) {
+	// Build the router the server uses, so routes registered later are checked too
+	router := NewRouter(Controllers{
+		Album:    &controllers.AlbumController{Service: &InMemoryAlbumService{}},
+		Musician: &controllers.MusicianController{Service: &InMemoryMusicianService{}},
+	})
+	spec := openapi.Spec()
+
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		path := openapi.SpecPath(template)
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Routes registered without a method matcher answer GET requests
+			methods = []string{http.MethodGet}
+		}
+
+		for _, method := range methods {
+			if spec.Operation(path, method) == nil {
+				t.Errorf("route %s %s has no OpenAPI spec entry", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk routes: %v", err)
+	}
+}
+
+func TestServeOpenAPISpec(t *testing.T) {
+	router := SetupRoutes(&controllers.AlbumController{Service: &InMemoryAlbumService{}},
+		&controllers.MusicianController{Service: &InMemoryMusicianService{}})
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.NewDecoder(rr.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+
+	if doc.OpenAPI == "" || doc.Operation("/albums", "POST") == nil {
+		t.Errorf("expected a spec describing POST /albums, got %+v", doc.Paths["/albums"])
+	}
+
+	name := doc.Components.Schemas["AlbumInput"].Properties["name"]
+	if name.MinLength == nil || *name.MinLength != 5 {
+		t.Errorf("expected album name minLength 5, got %v", name.MinLength)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupOrderRoutes registers the cart, checkout, order and tax rate endpoints on an existing router.
+func SetupOrderRoutes(r *mux.Router, orderController *controllers.OrderController) {
+	r.HandleFunc("/carts", orderController.CreateCart).Methods("POST")
+	r.HandleFunc("/carts/{id:[0-9]+}", orderController.GetCart).Methods("GET")
+	r.HandleFunc("/carts/{id:[0-9]+}/items", orderController.SetCartItem).Methods("PUT")
+	r.HandleFunc("/carts/{id:[0-9]+}/checkout", orderController.Checkout).Methods("POST")
+	r.HandleFunc("/orders", orderController.GetOrders).Methods("GET")
+	r.HandleFunc("/orders/{id:[0-9]+}", orderController.GetOrder).Methods("GET")
+	r.HandleFunc("/orders/{id:[0-9]+}/pay", orderController.PayOrder).Methods("POST")
+	r.HandleFunc("/orders/{id:[0-9]+}/ship", orderController.ShipOrder).Methods("POST")
+	r.HandleFunc("/orders/{id:[0-9]+}/cancel", orderController.CancelOrder).Methods("POST")
+	r.HandleFunc("/tax-rates", orderController.GetTaxRates).Methods("GET")
+	r.HandleFunc("/tax-rates", orderController.UploadTaxRates).Methods("PUT")
+}
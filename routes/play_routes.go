@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupPlayRoutes registers the play history and listening statistics endpoints on an existing router.
+func SetupPlayRoutes(r *mux.Router, playController *controllers.PlayController) {
+	r.HandleFunc("/me/plays", playController.RecordPlay).Methods("POST")
+	r.HandleFunc("/me/plays/batch", playController.RecordPlays).Methods("POST")
+	r.HandleFunc("/me/plays", playController.GetHistory).Methods("GET")
+	r.HandleFunc("/me/plays/top/{type}", playController.GetMyTop).Methods("GET")
+	r.HandleFunc("/plays/top/{type}", playController.GetTop).Methods("GET")
+}
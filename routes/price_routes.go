@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupPriceRoutes registers the exchange rate and album price list endpoints on an existing router.
+func SetupPriceRoutes(r *mux.Router, priceController *controllers.PriceController) {
+	r.HandleFunc("/exchange-rates", priceController.GetExchangeRates).Methods("GET")
+	r.HandleFunc("/exchange-rates", priceController.UploadExchangeRates).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/prices", priceController.GetAlbumPrices).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/prices/{currency}", priceController.SetAlbumPrice).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/prices/{currency}", priceController.RemoveAlbumPrice).Methods("DELETE")
+}
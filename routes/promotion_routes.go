@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupPromotionRoutes registers the promotion, price schedule and effective price endpoints on an existing router.
+func SetupPromotionRoutes(r *mux.Router, promotionController *controllers.PromotionController) {
+	r.HandleFunc("/promotions", promotionController.GetPromotions).Methods("GET")
+	r.HandleFunc("/promotions", promotionController.CreatePromotion).Methods("POST")
+	r.HandleFunc("/promotions/{id:[0-9]+}", promotionController.GetPromotion).Methods("GET")
+	r.HandleFunc("/promotions/{id:[0-9]+}", promotionController.UpdatePromotion).Methods("PUT")
+	r.HandleFunc("/promotions/{id:[0-9]+}", promotionController.DeletePromotion).Methods("DELETE")
+	r.HandleFunc("/albums/{id:[0-9]+}/price-schedules", promotionController.GetPriceSchedules).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/price-schedules", promotionController.SchedulePrice).Methods("POST")
+	r.HandleFunc("/albums/{id:[0-9]+}/price-schedules/{schedule_id:[0-9]+}", promotionController.CancelPriceSchedule).Methods("DELETE")
+	r.HandleFunc("/albums/{id:[0-9]+}/effective-price", promotionController.GetEffectivePrice).Methods("GET")
+}
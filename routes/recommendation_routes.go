@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupRecommendationRoutes registers the related album and musician endpoints on an existing router.
+func SetupRecommendationRoutes(r *mux.Router, recommendationController *controllers.RecommendationController) {
+	r.HandleFunc("/albums/{id:[0-9]+}/related", recommendationController.GetRelatedAlbums).Methods("GET")
+	r.HandleFunc("/musicians/{id:[0-9]+}/related", recommendationController.GetRelatedMusicians).Methods("GET")
+}
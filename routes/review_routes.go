@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"jukebox/controllers"
+
+	"github.com/gorilla/mux"
+)
+
+// SetupReviewRoutes registers the album review and moderation endpoints on an existing router.
+func SetupReviewRoutes(r *mux.Router, reviewController *controllers.ReviewController) {
+	r.HandleFunc("/albums/{id:[0-9]+}/reviews", reviewController.GetAlbumReviews).Methods("GET")
+	r.HandleFunc("/albums/{id:[0-9]+}/reviews", reviewController.CreateReview).Methods("POST")
+	r.HandleFunc("/albums/{id:[0-9]+}/reviews/{review_id:[0-9]+}", reviewController.UpdateReview).Methods("PUT")
+	r.HandleFunc("/albums/{id:[0-9]+}/reviews/{review_id:[0-9]+}", reviewController.DeleteReview).Methods("DELETE")
+	r.HandleFunc("/reviews", reviewController.GetReviews).Methods("GET")
+	r.HandleFunc("/reviews/{id:[0-9]+}/status", reviewController.ModerateReview).Methods("PUT")
+}
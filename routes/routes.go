@@ -2,6 +2,8 @@ package routes
 
 import (
 	"jukebox/controllers"
+	"jukebox/graph"
+	"jukebox/openapi"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -15,6 +17,8 @@ func SetupRoutes(albumController *controllers.AlbumController, musicianControlle
 		w.Write([]byte("Welcome to the Jukebox API"))
 	})
 
+	r.HandleFunc("/openapi.json", openapi.ServeSpec).Methods("GET")
+
 	// Define Routes for Albums and Musicians
 	r.HandleFunc("/albums", albumController.GetAlbums).Methods("GET")
 	r.HandleFunc("/albums", albumController.CreateAlbum).Methods("POST")
@@ -28,5 +32,57 @@ func SetupRoutes(albumController *controllers.AlbumController, musicianControlle
 	r.HandleFunc("/musicians/{id:[0-9]+}", musicianController.DeleteMusician).Methods("DELETE")          // Delete musician by ID
 	r.HandleFunc("/albums/{id:[0-9]+}/musicians", musicianController.GetMusiciansByAlbum).Methods("GET") // Get musicians by album ID
 
+	// GraphQL endpoint backed by the same services as the REST controllers
+	r.Handle("/graphql", graph.NewHandler(albumController.Service, musicianController.Service)).Methods("POST")
+
+	// Reject requests that do not match the OpenAPI document before they reach a controller
+	r.Use(openapi.ValidateRequests(openapi.Spec()))
+
+	return r
+}
+
+// Controllers holds one controller per resource served by the API.
+type Controllers struct {
+	Album          *controllers.AlbumController
+	Musician       *controllers.MusicianController
+	Import         *controllers.ImportController
+	Export         *controllers.ExportController
+	Membership     *controllers.MembershipController
+	Label          *controllers.LabelController
+	Genre          *controllers.GenreController
+	Edition        *controllers.EditionController
+	Price          *controllers.PriceController
+	Promotion      *controllers.PromotionController
+	Inventory      *controllers.InventoryController
+	Order          *controllers.OrderController
+	Customer       *controllers.CustomerController
+	Review         *controllers.ReviewController
+	Recommendation *controllers.RecommendationController
+	Collaboration  *controllers.CollaborationController
+	Chart          *controllers.ChartController
+	Play           *controllers.PlayController
+	Ingest         *controllers.IngestController
+}
+
+// NewRouter registers every route of the API, so the server and the spec tests serve the same routes.
+func NewRouter(c Controllers) *mux.Router {
+	r := SetupRoutes(c.Album, c.Musician)
+	SetupImportRoutes(r, c.Import)
+	SetupExportRoutes(r, c.Export)
+	SetupMembershipRoutes(r, c.Membership)
+	SetupLabelRoutes(r, c.Label)
+	SetupGenreRoutes(r, c.Genre)
+	SetupEditionRoutes(r, c.Edition)
+	SetupPriceRoutes(r, c.Price)
+	SetupPromotionRoutes(r, c.Promotion)
+	SetupInventoryRoutes(r, c.Inventory)
+	SetupOrderRoutes(r, c.Order)
+	SetupCustomerRoutes(r, c.Customer)
+	SetupReviewRoutes(r, c.Review)
+	SetupRecommendationRoutes(r, c.Recommendation)
+	SetupCollaborationRoutes(r, c.Collaboration)
+	SetupChartRoutes(r, c.Chart)
+	SetupPlayRoutes(r, c.Play)
+	SetupIngestRoutes(r, c.Ingest)
 	return r
 }
@@ -2,12 +2,18 @@ package routes
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"jukebox/controllers"
 	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
 )
 
 func TestRoutesSetup(t *testing.T) {
@@ -62,3 +68,41 @@ func TestRoutesSetup(t *testing.T) {
 		t.Errorf("expected status code %v, got %v", http.StatusOK, rr.Code)
 	}
 }
+
+func TestImportRouteStreamsCSV(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE musicians (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		musician_type TEXT NOT NULL,
+		kind TEXT NOT NULL DEFAULT 'person'
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	router := SetupRoutes(&controllers.AlbumController{Service: &InMemoryAlbumService{}},
+		&controllers.MusicianController{Service: &InMemoryMusicianService{}})
+	SetupImportRoutes(router, &controllers.ImportController{Service: &services.ImportService{MusicianRepo: &repositories.MusicianRepository{DB: db}}})
+
+	// An upload is handed to the importer as is rather than validated like a decoded body
+	req := httptest.NewRequest("POST", "/import?entity=musicians", strings.NewReader("name,musician_type\nJohn Doe,Guitarist\nJane Smith,Vocalist\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var report models.ImportReport
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode import report: %v", err)
+	}
+	if report.Created != 2 {
+		t.Errorf("expected 2 musicians imported, got %+v", report)
+	}
+}
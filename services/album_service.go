@@ -1,37 +1,249 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"jukebox/models"
 	"jukebox/repositories"
+	"strings"
+	"time"
 )
 
+// Validation limits applied when creating an album.
+const (
+	MinAlbumNameLength = 5
+	MinAlbumPrice      = 100
+	MaxAlbumPrice      = 1000
+)
+
+// ErrCurrencyLocked is returned when an album's currency is changed while it has editions or pending scheduled
+// prices, which are stored in its current currency.
+var ErrCurrencyLocked = repositories.ErrCurrencyLocked
+
+// ErrInvalidAlbum is returned when an album has an invalid currency or price, or refers to a missing label.
+var ErrInvalidAlbum = errors.New("invalid album")
+
+// ErrInvalidCredit is returned when a musician credit added to an album is invalid.
+var ErrInvalidCredit = errors.New("invalid credit")
+
+// ErrCatalogNumberTaken is returned when another album of the same label already uses an album's catalog number.
+var ErrCatalogNumberTaken = errors.New("catalog number taken")
+
 // AlbumService is the real implementation which uses the repository.
 type AlbumService struct {
 	Repo *repositories.AlbumRepository
+	// Labels, when set, is used to check that an album's label exists.
+	Labels *repositories.LabelRepository
+	// Genres, when set, tags created and updated albums with the genre their genre text resolves to.
+	Genres *GenreService
+	// Prices, when set, converts prices in other currencies than models.BaseCurrency, both to check
+	// new albums against the price limits and to list albums in a requested currency.
+	Prices *PriceService
+	// Editions, when set, keeps the price and release date of albums with editions at those of
+	// their cheapest and earliest edition.
+	Editions *repositories.EditionRepository
+	// Promotions, when set, applies scheduled price changes and running promotions to the effective
+	// price of listed albums.
+	Promotions *PromotionService
+	// Reviews, when set, reports the average rating and review count of listed albums.
+	Reviews *ReviewService
+}
+
+// ValidateAlbum checks the rules an album must satisfy before it is created. The price limits are
+// in models.BaseCurrency; prices in other currencies are checked by AlbumService once converted.
+func ValidateAlbum(album *models.Album) error {
+	// Basic Validation
+	if len(album.Name) < MinAlbumNameLength {
+		return fmt.Errorf("album name must be at least %d characters long", MinAlbumNameLength)
+	}
+	if err := validateAlbumCurrency(album); err != nil {
+		return err
+	}
+	if album.Currency == models.BaseCurrency {
+		return checkPriceRange(nil, album.Price, album.Currency)
+	}
+	return nil
+}
+
+// validateAlbumCurrency normalises an album's currency and checks its price fits the currency's minor unit.
+func validateAlbumCurrency(album *models.Album) error {
+	currency, err := NormalizeCurrency(album.Currency)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAlbum, err)
+	}
+	album.Currency = currency
+	if err := validateAmount(album.Price, currency); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAlbum, err)
+	}
+	return nil
+}
+
+// validateLabel checks an album's label reference and that its catalog number is unique within the label.
+func (s *AlbumService) validateLabel(album *models.Album) error {
+	album.CatalogNumber = strings.TrimSpace(album.CatalogNumber)
+	if album.LabelID == 0 {
+		if album.CatalogNumber != "" {
+			return fmt.Errorf("%w: catalog_number requires a label_id", ErrInvalidAlbum)
+		}
+		return nil
+	}
+
+	if s.Labels != nil {
+		if _, err := s.Labels.GetLabelByID(album.LabelID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: label %d does not exist", ErrInvalidAlbum, album.LabelID)
+			}
+			return err
+		}
+	}
+
+	if album.CatalogNumber != "" {
+		taken, err := s.Repo.CatalogNumberTaken(album.LabelID, album.CatalogNumber, album.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: %q is already used by label %d", ErrCatalogNumberTaken, album.CatalogNumber, album.LabelID)
+		}
+	}
+	return nil
 }
 
 // CreateAlbum validates and creates a new album.
 func (s *AlbumService) CreateAlbum(album *models.Album) error {
-	// Basic Validation
-	if len(album.Name) < 5 {
-		return errors.New("album name must be at least 5 characters long")
+	if err := ValidateAlbum(album); err != nil {
+		return err
 	}
-	if album.Price < 100 || album.Price > 1000 {
-		return errors.New("price must be between 100 and 1000")
+	if album.Currency != models.BaseCurrency {
+		if err := checkPriceRange(s.Prices, album.Price, album.Currency); err != nil {
+			return err
+		}
 	}
-	return s.Repo.CreateAlbum(album)
+	if err := s.validateLabel(album); err != nil {
+		return err
+	}
+	if err := s.Repo.CreateAlbum(album); err != nil {
+		return err
+	}
+	return s.tagGenre(album)
 }
 
-// UpdateAlbum updates an existing album
+// UpdateAlbum updates an existing album, checking its currency, label and catalog number. It returns
+// sql.ErrNoRows if the album does not exist.
 func (s *AlbumService) UpdateAlbum(album *models.Album) error {
-	return s.Repo.UpdateAlbum(album)
+	if err := validateAlbumCurrency(album); err != nil {
+		return err
+	}
+	if err := s.validateLabel(album); err != nil {
+		return err
+	}
+	previous, err := s.previousGenre(album.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.UpdateAlbum(album); err != nil {
+		return err
+	}
+	if err := s.syncEditions(album); err != nil {
+		return err
+	}
+	if s.Genres == nil {
+		return nil
+	}
+	return s.Genres.RetagAlbumFromText(album, previous)
 }
 
-// GetAlbums retrieves all albums from the repository.
-func (s *AlbumService) GetAlbums() ([]models.Album, error) {
-	// Simply call the repository to get all albums
-	return s.Repo.GetAlbums()
+// previousGenre returns the genre text an album has before an update, so the genre tagged from it can be
+// replaced. Missing albums have none.
+func (s *AlbumService) previousGenre(albumID uint) (string, error) {
+	if s.Genres == nil {
+		return "", nil
+	}
+	stored, err := s.Repo.GetAlbumByID(albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return stored.Genre, nil
+}
+
+// syncEditions restores the price and release date derived from an album's editions after an update.
+func (s *AlbumService) syncEditions(album *models.Album) error {
+	if s.Editions == nil {
+		return nil
+	}
+	if err := s.Editions.SyncAlbum(album.ID); err != nil {
+		return err
+	}
+	stored, err := s.Repo.GetAlbumByID(album.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Updating a missing album changes nothing, as without editions
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	album.Price, album.ReleaseDate = stored.Price, stored.ReleaseDate
+	return nil
+}
+
+func (s *AlbumService) tagGenre(album *models.Album) error {
+	if s.Genres == nil {
+		return nil
+	}
+	return s.Genres.TagAlbumFromText(album)
+}
+
+// GetAlbums retrieves the albums matching a filter from the repository, priced in the filter's currency if set.
+func (s *AlbumService) GetAlbums(filter models.AlbumFilter) ([]models.Album, error) {
+	albums, err := s.Repo.GetAlbums(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := make([]*models.Album, len(albums))
+	for i := range albums {
+		priced[i] = &albums[i]
+	}
+	if err := s.priceAlbums(priced, filter.Currency); err != nil {
+		return nil, err
+	}
+	if err := s.rateAlbums(priced); err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// priceAlbums resolves the effective price of albums now, then reports prices in currency; an empty
+// currency leaves each album's own.
+func (s *AlbumService) priceAlbums(albums []*models.Album, currency string) error {
+	for _, album := range albums {
+		album.EffectivePrice = album.Price
+	}
+	if s.Promotions != nil {
+		if err := s.Promotions.ResolvePrices(albums, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if currency == "" {
+		return nil
+	}
+	if s.Prices == nil {
+		return fmt.Errorf("%w to price albums in %s", ErrNoExchangeRate, currency)
+	}
+	return s.Prices.PriceAlbums(albums, currency)
+}
+
+// rateAlbums reports the rating of listed albums when reviews are configured.
+func (s *AlbumService) rateAlbums(albums []*models.Album) error {
+	if s.Reviews == nil {
+		return nil
+	}
+	return s.Reviews.RateAlbums(albums)
 }
 
 // DeleteAlbum deletes an album by ID
@@ -39,11 +251,87 @@ func (s *AlbumService) DeleteAlbum(albumID uint) error {
 	return s.Repo.DeleteAlbum(albumID)
 }
 
-// GetAlbumsByMusician retrieves albums for a specific musician sorted by price
-func (s *AlbumService) GetAlbumsByMusician(musicianID uint) ([]models.Album, error) {
-	return s.Repo.GetAlbumsByMusician(musicianID)
+// GetAlbumsByMusician retrieves albums for a specific musician sorted by price, each with the
+// musician's credits. A non-empty role keeps only credits with that role, and a non-empty currency
+// reports prices in that currency.
+func (s *AlbumService) GetAlbumsByMusician(musicianID uint, role, currency string) ([]models.CreditedAlbum, error) {
+	if role != "" {
+		if err := ValidateCreditRole(role); err != nil {
+			return nil, err
+		}
+	}
+	albums, err := s.Repo.GetAlbumsByMusician(musicianID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := make([]*models.Album, len(albums))
+	for i := range albums {
+		priced[i] = &albums[i].Album
+	}
+	if err := s.priceAlbums(priced, currency); err != nil {
+		return nil, err
+	}
+	if err := s.rateAlbums(priced); err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// LinkMusiciansToAlbum validates and adds credits to an album. It returns sql.ErrNoRows if the album does not exist.
+func (s *AlbumService) LinkMusiciansToAlbum(albumID uint, credits []models.Credit) error {
+	for i := range credits {
+		if err := ValidateCredit(&credits[i]); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCredit, err)
+		}
+	}
+	if _, err := s.Repo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return err
+	}
+	return s.Repo.LinkMusiciansToAlbum(albumID, credits)
+}
+
+// ValidateCredit checks a credit before it is added to an album. A credit without a role is
+// treated as a performer credit.
+func ValidateCredit(credit *models.Credit) error {
+	if credit.MusicianID == 0 {
+		return errors.New("credit musician_id is required")
+	}
+	if credit.Role == "" {
+		credit.Role = models.CreditPerformer
+	}
+	if err := ValidateCreditRole(credit.Role); err != nil {
+		return err
+	}
+	if credit.Track < 0 {
+		return errors.New("credit track must not be negative")
+	}
+	return nil
+}
+
+// ValidateCreditRole checks that a role is one of models.CreditRoles.
+func ValidateCreditRole(role string) error {
+	for _, valid := range models.CreditRoles {
+		if role == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("credit role must be one of %s", strings.Join(models.CreditRoles, ", "))
+}
+
+// PerformerCredits turns a plain list of musician IDs into whole-album performer credits.
+func PerformerCredits(musicianIDs []uint) []models.Credit {
+	credits := make([]models.Credit, len(musicianIDs))
+	for i, id := range musicianIDs {
+		credits[i] = models.Credit{MusicianID: id, Role: models.CreditPerformer}
+	}
+	return credits
 }
 
-func (s *AlbumService) LinkMusiciansToAlbum(albumID uint, musicianIDs []uint) error {
-	return s.Repo.LinkMusiciansToAlbum(albumID, musicianIDs)
+// GetAlbumsByMusicians retrieves albums for several musicians at once, keyed by musician ID.
+func (s *AlbumService) GetAlbumsByMusicians(musicianIDs []uint) (map[uint][]models.Album, error) {
+	return s.Repo.GetAlbumsByMusicians(musicianIDs)
 }
@@ -22,12 +22,22 @@ func setupTestRepo(t *testing.T) *repositories.AlbumRepository {
 			name TEXT,
 			release_date TEXT,
 			genre TEXT,
-			price REAL,
-			description TEXT
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
 		);
 		CREATE TABLE album_musicians (
 			album_id INTEGER,
-			musician_id INTEGER
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE exchange_rates (
+			currency TEXT PRIMARY KEY,
+			rate REAL NOT NULL CHECK (rate > 0)
 		);
 	`)
 	if err != nil {
@@ -85,7 +95,7 @@ func TestUpdateAlbumService(t *testing.T) {
 	}
 
 	// Verify the update
-	updatedAlbum, err := repo.GetAlbums()
+	updatedAlbum, err := repo.GetAlbums(models.AlbumFilter{})
 	if err != nil {
 		t.Fatalf("failed to retrieve albums: %v", err)
 	}
@@ -119,7 +129,7 @@ func TestDeleteAlbumService(t *testing.T) {
 	}
 
 	// Verify the deletion
-	albums, err := repo.GetAlbums()
+	albums, err := repo.GetAlbums(models.AlbumFilter{})
 	if err != nil {
 		t.Fatalf("failed to get albums: %v", err)
 	}
@@ -147,7 +157,7 @@ func TestLinkMusiciansToAlbumService(t *testing.T) {
 	}
 
 	// Link musicians to the album
-	err = service.LinkMusiciansToAlbum(album.ID, []uint{101, 102})
+	err = service.LinkMusiciansToAlbum(album.ID, services.PerformerCredits([]uint{101, 102}))
 	if err != nil {
 		t.Fatalf("failed to link musicians to album: %v", err)
 	}
@@ -194,17 +204,17 @@ func TestGetAlbumsByMusicianService(t *testing.T) {
 		t.Fatalf("failed to create album 2: %v", err)
 	}
 
-	err = service.LinkMusiciansToAlbum(album1.ID, []uint{101})
+	err = service.LinkMusiciansToAlbum(album1.ID, services.PerformerCredits([]uint{101}))
 	if err != nil {
 		t.Fatalf("failed to link musicians to album 1: %v", err)
 	}
-	err = service.LinkMusiciansToAlbum(album2.ID, []uint{101})
+	err = service.LinkMusiciansToAlbum(album2.ID, services.PerformerCredits([]uint{101}))
 	if err != nil {
 		t.Fatalf("failed to link musicians to album 2: %v", err)
 	}
 
 	// Test GetAlbumsByMusician
-	albums, err := service.GetAlbumsByMusician(101)
+	albums, err := service.GetAlbumsByMusician(101, "", "")
 	if err != nil {
 		t.Fatalf("failed to get albums by musician: %v", err)
 	}
@@ -235,7 +245,7 @@ func TestGetAlbumsService(t *testing.T) {
 		Description: "A test album 2",
 	})
 
-	albums, err := service.GetAlbums()
+	albums, err := service.GetAlbums(models.AlbumFilter{})
 	if err != nil {
 		t.Fatalf("failed to get albums: %v", err)
 	}
@@ -244,3 +254,24 @@ func TestGetAlbumsService(t *testing.T) {
 		t.Errorf("expected 2 albums, got %d", len(albums))
 	}
 }
+
+func TestValidateCredit(t *testing.T) {
+	credit := models.Credit{MusicianID: 101}
+	if err := services.ValidateCredit(&credit); err != nil {
+		t.Fatalf("expected credit without a role to be valid, got %v", err)
+	}
+	if credit.Role != models.CreditPerformer {
+		t.Errorf("expected role to default to %q, got %q", models.CreditPerformer, credit.Role)
+	}
+
+	invalid := []models.Credit{
+		{Role: models.CreditProducer},
+		{MusicianID: 101, Role: "roadie"},
+		{MusicianID: 101, Role: models.CreditComposer, Track: -1},
+	}
+	for _, credit := range invalid {
+		if err := services.ValidateCredit(&credit); err == nil {
+			t.Errorf("expected %+v to be rejected", credit)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ChartScheduler keeps the purchase and rating counters of the running week up to date, so that chart
+// requests only read them. Plays are counted as they are recorded and need no aggregation.
+type ChartScheduler struct {
+	Service  *ChartService
+	Interval time.Duration
+}
+
+// Run recounts the running week until the context is cancelled.
+func (s *ChartScheduler) Run(ctx context.Context) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Service.AggregateWeek(time.Now()); err != nil {
+			log.Println("Error aggregating chart counters:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chart sizes. Charts are stored with up to MaxChartSize entries.
+const (
+	DefaultChartSize = 10
+	MaxChartSize     = 100
+	// MinChartRatings is how many approved ratings an album needs in a week to enter the ratings chart.
+	MinChartRatings = 2
+)
+
+// PeriodOf returns the ISO week a time falls in, e.g. "2026-W41".
+func PeriodOf(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// ParsePeriod returns the start of an ISO week such as "2026-W41": midnight UTC on its Monday.
+func ParsePeriod(period string) (time.Time, error) {
+	invalid := fmt.Errorf("period must be an ISO week such as 2026-W41, got %q", period)
+	yearText, weekText, found := strings.Cut(period, "-W")
+	if !found || len(yearText) != 4 || len(weekText) != 2 {
+		return time.Time{}, invalid
+	}
+	year, err := strconv.Atoi(yearText)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	week, err := strconv.Atoi(weekText)
+	if err != nil || week < 1 {
+		return time.Time{}, invalid
+	}
+
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	start := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+(week-1)*7)
+	if PeriodOf(start) != period {
+		return time.Time{}, invalid
+	}
+	return start, nil
+}
+
+type ChartService struct {
+	Repo *repositories.ChartRepository
+	// Genres resolves the genre charts are limited to.
+	Genres *repositories.GenreRepository
+}
+
+// RecordPlays counts plays of an album in the week they happened, for plays that are not stored through
+// PlayService, which counts its plays as it stores them. Plays are never retracted, so they are counted as
+// they arrive, while purchases and ratings are recounted whenever a chart is ranked.
+func (s *ChartService) RecordPlays(albumID uint, at time.Time, count int) error {
+	return s.Repo.RecordPlays(PeriodOf(at), albumID, count)
+}
+
+// GetChart ranks up to limit albums on a metric over an ISO week, overall or within a genre and its
+// subgenres, with each album's movement since the week before. An empty period means the last finished
+// week and a zero limit DefaultChartSize. Charts of finished weeks are stored on first request, along
+// with the week before's, and served from storage afterwards, so they do not change when later orders
+// are cancelled. The running week's chart only reads the counters AggregateWeek maintains.
+func (s *ChartService) GetChart(chartType, period, genre string, limit int) (*models.Chart, error) {
+	if !slices.Contains(models.ChartTypes, chartType) {
+		return nil, fmt.Errorf("chart type must be one of %s", strings.Join(models.ChartTypes, ", "))
+	}
+	if limit == 0 {
+		limit = DefaultChartSize
+	}
+	if limit < 1 || limit > MaxChartSize {
+		return nil, fmt.Errorf("limit must be from 1 to %d", MaxChartSize)
+	}
+
+	now := time.Now().UTC()
+	if period == "" {
+		period = PeriodOf(now.AddDate(0, 0, -7))
+	}
+	start, err := ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(now) {
+		return nil, fmt.Errorf("period %s has not started", period)
+	}
+
+	if genre != "" {
+		genreID, err := s.Genres.FindGenreID(genre)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("genre %q does not exist: %w", genre, err)
+			}
+			return nil, err
+		}
+		resolved, err := s.Genres.GetGenreByID(genreID)
+		if err != nil {
+			return nil, err
+		}
+		genre = resolved.Name
+	}
+	key := models.GenreKey(genre)
+	final := !now.Before(start.AddDate(0, 0, 7))
+
+	chart, err := s.chart(chartType, period, start, genre, key, final, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(chart.Entries) > limit {
+		chart.Entries = chart.Entries[:limit]
+	}
+	return chart, nil
+}
+
+// chart retrieves a stored chart, or ranks it and stores it once its week is over.
+func (s *ChartService) chart(chartType, period string, start time.Time, genre, key string, final bool, now time.Time) (*models.Chart, error) {
+	if final {
+		stored, err := s.Repo.GetChart(chartType, key, period)
+		if err == nil {
+			stored.Genre = genre
+			return stored, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return s.compute(chartType, period, start, genre, key, final, now, true)
+}
+
+// compute ranks a chart with each album's movement since the week before, storing it when final. The
+// running week is ranked from the counters AggregateWeek keeps up to date; a finished week's counters
+// are rebuilt once, as its chart is stored. With storePrevious, the previous week's chart is stored too
+// when it has not been, so that later requests compare against it without ranking it again.
+func (s *ChartService) compute(chartType, period string, start time.Time, genre, key string, final bool, now time.Time, storePrevious bool) (*models.Chart, error) {
+	if final {
+		if err := s.aggregate(period, start); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := s.rank(chartType, period, genre)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.previousPositions(chartType, start.AddDate(0, 0, -7), genre, key, now, storePrevious)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entry := &entries[i]
+		entry.PreviousPosition = previous[entry.Album.ID]
+		switch {
+		case entry.PreviousPosition == 0:
+			entry.Movement = models.MovementNew
+		case entry.PreviousPosition > entry.Position:
+			entry.Movement = models.MovementUp
+		case entry.PreviousPosition < entry.Position:
+			entry.Movement = models.MovementDown
+		default:
+			entry.Movement = models.MovementSame
+		}
+	}
+
+	chart := &models.Chart{Type: chartType, Genre: genre, Period: period, Final: final, ComputedAt: now.Format(models.TimeFormat), Entries: entries}
+	if final {
+		if err := s.Repo.SaveChart(chart, key); err != nil {
+			return nil, err
+		}
+	}
+	return chart, nil
+}
+
+// AggregateWeek recounts the purchases and ratings of the week now falls in, for the running week's
+// charts. The ChartScheduler calls it periodically so that requests only read the counters.
+func (s *ChartService) AggregateWeek(now time.Time) error {
+	period := PeriodOf(now)
+	start, err := ParsePeriod(period)
+	if err != nil {
+		return err
+	}
+	return s.aggregate(period, start)
+}
+
+// aggregate recounts a week's purchases and ratings.
+func (s *ChartService) aggregate(period string, start time.Time) error {
+	end := start.AddDate(0, 0, 7)
+	return s.Repo.AggregatePeriod(period, start.Format(models.TimeFormat), end.Format(models.TimeFormat))
+}
+
+// rank ranks the albums counted in a week on a metric, numbering their positions.
+func (s *ChartService) rank(chartType, period, genre string) ([]models.ChartEntry, error) {
+	entries, err := s.Repo.RankAlbums(chartType, period, genre, MinChartRatings, MaxChartSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Position = i + 1
+		if chartType == models.ChartRatings {
+			entries[i].Value = math.Round(entries[i].Value*100) / 100
+		}
+	}
+	return entries, nil
+}
+
+// previousPositions returns the positions on the chart of the finished week starting at start, keyed by
+// album ID, from the stored chart when there is one. Otherwise the chart is stored first when store is
+// set, or only ranked.
+func (s *ChartService) previousPositions(chartType string, start time.Time, genre, key string, now time.Time, store bool) (map[uint]int, error) {
+	period := PeriodOf(start)
+	var entries []models.ChartEntry
+	stored, err := s.Repo.GetChart(chartType, key, period)
+	switch {
+	case err == nil:
+		entries = stored.Entries
+	case errors.Is(err, sql.ErrNoRows) && store:
+		previous, err := s.compute(chartType, period, start, genre, key, true, now, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = previous.Entries
+	case errors.Is(err, sql.ErrNoRows):
+		if err := s.aggregate(period, start); err != nil {
+			return nil, err
+		}
+		if entries, err = s.rank(chartType, period, genre); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	positions := make(map[uint]int, len(entries))
+	for _, entry := range entries {
+		positions[entry.Album.ID] = entry.Position
+	}
+	return positions, nil
+}
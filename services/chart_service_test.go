@@ -0,0 +1,262 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+	"time"
+)
+
+// setupChartService seeds three albums, the second of them heavy metal, a subgenre of rock.
+func setupChartService(t *testing.T) *services.ChartService {
+	genres, _ := setupTestGenreService(t)
+	db := genres.Repo.DB
+	_, err := db.Exec(`
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			status TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);
+		CREATE TABLE order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			quantity INTEGER NOT NULL
+		);
+		CREATE TABLE reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			rating INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);
+		CREATE TABLE chart_counters (
+			period TEXT NOT NULL,
+			metric TEXT NOT NULL,
+			album_id INTEGER NOT NULL,
+			count INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (period, metric, album_id)
+		);
+		CREATE TABLE charts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chart_type TEXT NOT NULL,
+			genre TEXT NOT NULL DEFAULT '',
+			period TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			UNIQUE (chart_type, genre, period)
+		);
+		CREATE TABLE chart_entries (
+			chart_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			value REAL NOT NULL,
+			count INTEGER NOT NULL,
+			previous_position INTEGER,
+			movement TEXT NOT NULL,
+			PRIMARY KEY (chart_id, position)
+		);
+		INSERT INTO albums (id, name, release_date, genre, description) VALUES
+		(1, 'Album 1', '2001-01-01', '', ''), (2, 'Album 2', '2002-01-01', '', ''), (3, 'Album 3', '2003-01-01', '', '');
+		INSERT INTO genres (id, name, key, parent_id) VALUES (1, 'Rock', 'rock', NULL), (2, 'Heavy Metal', 'heavy metal', 1);
+		INSERT INTO album_genres (album_id, genre_id) VALUES (2, 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return &services.ChartService{Repo: &repositories.ChartRepository{DB: db}, Genres: genres.Repo}
+}
+
+func recordPlays(t *testing.T, service *services.ChartService, at time.Time, plays map[uint]int) {
+	for albumID, count := range plays {
+		if err := service.RecordPlays(albumID, at, count); err != nil {
+			t.Fatalf("failed to record plays: %v", err)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	for period, monday := range map[string]string{
+		"2026-W01": "2025-12-29",
+		"2026-W41": "2026-10-05",
+		"2020-W53": "2020-12-28",
+	} {
+		start, err := services.ParsePeriod(period)
+		if err != nil {
+			t.Errorf("failed to parse %s: %v", period, err)
+			continue
+		}
+		if start.Format(time.DateOnly) != monday || services.PeriodOf(start) != period {
+			t.Errorf("expected %s to start on %s, got %v", period, monday, start)
+		}
+	}
+
+	for _, period := range []string{"", "2026-41", "2026-W1", "2026-W00", "2021-W53", "26-W041", "abcd-W01"} {
+		if _, err := services.ParsePeriod(period); err == nil {
+			t.Errorf("expected %q to be rejected", period)
+		}
+	}
+}
+
+func TestPlaysChartMovement(t *testing.T) {
+	service := setupChartService(t)
+	now := time.Now().UTC()
+	lastWeek, _ := services.ParsePeriod(services.PeriodOf(now.AddDate(0, 0, -7)))
+
+	recordPlays(t, service, lastWeek.AddDate(0, 0, -7), map[uint]int{1: 5, 2: 3})
+	recordPlays(t, service, lastWeek, map[uint]int{1: 4, 2: 6})
+	recordPlays(t, service, lastWeek.AddDate(0, 0, 6), map[uint]int{3: 1})
+
+	chart, err := service.GetChart(models.ChartPlays, "", "", 0)
+	if err != nil {
+		t.Fatalf("failed to get chart: %v", err)
+	}
+	if chart.Period != services.PeriodOf(lastWeek) || !chart.Final || len(chart.Entries) != 3 {
+		t.Fatalf("expected last week's final chart, got %+v", chart)
+	}
+	expected := []struct {
+		albumID  uint
+		previous int
+		movement string
+	}{{2, 2, models.MovementUp}, {1, 1, models.MovementDown}, {3, 0, models.MovementNew}}
+	for i, want := range expected {
+		entry := chart.Entries[i]
+		if entry.Position != i+1 || entry.Album.ID != want.albumID || entry.PreviousPosition != want.previous || entry.Movement != want.movement {
+			t.Errorf("expected album %d %s from %d at %d, got %+v", want.albumID, want.movement, want.previous, i+1, entry)
+		}
+	}
+
+	// Finished weeks are served from storage even when late plays arrive
+	recordPlays(t, service, lastWeek, map[uint]int{3: 100})
+	stored, err := service.GetChart(models.ChartPlays, chart.Period, "", 2)
+	if err != nil {
+		t.Fatalf("failed to get stored chart: %v", err)
+	}
+	if len(stored.Entries) != 2 || stored.Entries[0].Album.ID != 2 || stored.ComputedAt != chart.ComputedAt {
+		t.Errorf("expected the stored chart cut to the limit, got %+v", stored)
+	}
+
+	recordPlays(t, service, now, map[uint]int{3: 2})
+	current, err := service.GetChart(models.ChartPlays, services.PeriodOf(now), "", 0)
+	if err != nil {
+		t.Fatalf("failed to get current chart: %v", err)
+	}
+	if current.Final || len(current.Entries) != 1 || current.Entries[0].PreviousPosition != 3 || current.Entries[0].Movement != models.MovementUp {
+		t.Errorf("expected a running chart compared with the stored one, got %+v", current)
+	}
+}
+
+func TestGenreChart(t *testing.T) {
+	service := setupChartService(t)
+	lastWeek, _ := services.ParsePeriod(services.PeriodOf(time.Now().UTC().AddDate(0, 0, -7)))
+	recordPlays(t, service, lastWeek, map[uint]int{1: 9, 2: 1})
+
+	chart, err := service.GetChart(models.ChartPlays, "", "ROCK", 0)
+	if err != nil {
+		t.Fatalf("failed to get genre chart: %v", err)
+	}
+	if chart.Genre != "Rock" || len(chart.Entries) != 1 || chart.Entries[0].Album.ID != 2 {
+		t.Errorf("expected only heavy metal albums on the rock chart, got %+v", chart)
+	}
+	stored, err := service.GetChart(models.ChartPlays, chart.Period, "rock", 0)
+	if err != nil || stored.Genre != "Rock" || len(stored.Entries) != 1 {
+		t.Errorf("expected the stored genre chart, got %+v, %v", stored, err)
+	}
+
+	if _, err := service.GetChart(models.ChartPlays, "", "Polka", 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for an unknown genre, got %v", err)
+	}
+}
+
+func TestRatingsChart(t *testing.T) {
+	service := setupChartService(t)
+	lastWeek, _ := services.ParsePeriod(services.PeriodOf(time.Now().UTC().AddDate(0, 0, -7)))
+	at := lastWeek.Add(time.Hour).Format(models.TimeFormat)
+	_, err := service.Repo.DB.Exec(`
+		INSERT INTO reviews (album_id, rating, status, created_at) VALUES
+		(1, 5, 'approved', ?), (1, 4, 'approved', ?), (1, 4, 'approved', ?), (2, 5, 'approved', ?), (2, 5, 'approved', ?),
+		(3, 5, 'approved', ?), (3, 1, 'pending', ?);
+		INSERT INTO orders (id, status, created_at) VALUES (1, 'paid', ?), (2, 'cancelled', ?);
+		INSERT INTO order_lines (order_id, album_id, quantity) VALUES (1, 3, 2), (2, 1, 5);
+	`, at, at, at, at, at, at, at, at, at)
+	if err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	chart, err := service.GetChart(models.ChartRatings, "", "", 0)
+	if err != nil {
+		t.Fatalf("failed to get ratings chart: %v", err)
+	}
+	if len(chart.Entries) != 2 || chart.Entries[0].Album.ID != 2 || chart.Entries[1].Value != 4.33 || chart.Entries[1].Count != 3 {
+		t.Errorf("expected albums with at least %d approved ratings by average, got %+v", services.MinChartRatings, chart.Entries)
+	}
+
+	purchases, err := service.GetChart(models.ChartPurchases, "", "", 0)
+	if err != nil {
+		t.Fatalf("failed to get purchases chart: %v", err)
+	}
+	if len(purchases.Entries) != 1 || purchases.Entries[0].Album.ID != 3 || purchases.Entries[0].Value != 2 {
+		t.Errorf("expected copies of paid orders only, got %+v", purchases.Entries)
+	}
+}
+
+func TestRunningChartReadsCounters(t *testing.T) {
+	service := setupChartService(t)
+	now := time.Now().UTC()
+	_, err := service.Repo.DB.Exec(`
+		INSERT INTO orders (id, status, created_at) VALUES (1, 'paid', ?);
+		INSERT INTO order_lines (order_id, album_id, quantity) VALUES (1, 3, 2);
+	`, now.Format(models.TimeFormat))
+	if err != nil {
+		t.Fatalf("failed to seed orders: %v", err)
+	}
+
+	// Requests do not recount the running week, but store last week's chart for its movements
+	chart, err := service.GetChart(models.ChartPurchases, services.PeriodOf(now), "", 0)
+	if err != nil {
+		t.Fatalf("failed to get running chart: %v", err)
+	}
+	if chart.Final || len(chart.Entries) != 0 {
+		t.Errorf("expected an empty running chart before aggregation, got %+v", chart)
+	}
+	if _, err := service.Repo.GetChart(models.ChartPurchases, "", services.PeriodOf(now.AddDate(0, 0, -7))); err != nil {
+		t.Errorf("expected last week's chart to be stored, got %v", err)
+	}
+
+	if err := service.AggregateWeek(now); err != nil {
+		t.Fatalf("failed to aggregate the running week: %v", err)
+	}
+	chart, err = service.GetChart(models.ChartPurchases, services.PeriodOf(now), "", 0)
+	if err != nil {
+		t.Fatalf("failed to get running chart: %v", err)
+	}
+	if len(chart.Entries) != 1 || chart.Entries[0].Album.ID != 3 || chart.Entries[0].Movement != models.MovementNew {
+		t.Errorf("expected the aggregated purchase, got %+v", chart.Entries)
+	}
+}
+
+func TestChartValidation(t *testing.T) {
+	service := setupChartService(t)
+	next := services.PeriodOf(time.Now().UTC().AddDate(0, 0, 14))
+
+	invalid := []struct {
+		chartType string
+		period    string
+		limit     int
+	}{
+		{"streams", "", 0},
+		{models.ChartPlays, "", -1},
+		{models.ChartPlays, "", services.MaxChartSize + 1},
+		{models.ChartPlays, "2026-W60", 0},
+		{models.ChartPlays, next, 0},
+	}
+	for _, request := range invalid {
+		if _, err := service.GetChart(request.chartType, request.period, "", request.limit); err == nil || errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected %+v to be rejected, got %v", request, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"slices"
+	"sort"
+)
+
+// ErrNotConnected is returned when no chain of collaborations links two musicians.
+var ErrNotConnected = errors.New("musicians are not connected by any collaboration")
+
+type CollaborationService struct {
+	Repo         *repositories.CollaborationRepository
+	MusicianRepo *repositories.MusicianRepository
+	AlbumRepo    *repositories.AlbumRepository
+}
+
+// musician retrieves a musician, wrapping sql.ErrNoRows when it does not exist.
+func (s *CollaborationService) musician(musicianID uint) (*models.Musician, error) {
+	musician, err := s.MusicianRepo.GetMusicianByID(musicianID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("musician %d does not exist: %w", musicianID, err)
+		}
+		return nil, err
+	}
+	return musician, nil
+}
+
+// GetCollaborators retrieves the musicians a musician shares albums with, most shared albums first.
+func (s *CollaborationService) GetCollaborators(musicianID uint) ([]models.Collaborator, error) {
+	if _, err := s.musician(musicianID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetCollaborators(musicianID)
+}
+
+// hop is how a musician was reached while searching for a path: from a neighbouring musician through
+// an album they share.
+type hop struct {
+	musicianID uint
+	albumID    uint
+}
+
+// GetPath finds a shortest chain of collaborations between two musicians. It searches from both ends
+// at once, expanding the smaller frontier one level at a time with one query per level, so only the
+// neighbourhoods of the two musicians are read. It returns ErrNotConnected when no chain exists.
+func (s *CollaborationService) GetPath(fromID, toID uint) (*models.CollaborationPath, error) {
+	for _, musicianID := range []uint{fromID, toID} {
+		if _, err := s.musician(musicianID); err != nil {
+			return nil, err
+		}
+	}
+
+	forward := map[uint]hop{fromID: {}}
+	backward := map[uint]hop{toID: {}}
+	meeting, found := fromID, fromID == toID
+	forwardFrontier, backwardFrontier := []uint{fromID}, []uint{toID}
+
+	for !found && len(forwardFrontier) > 0 && len(backwardFrontier) > 0 {
+		// A musician found at the current level of one search and already reached by the other lies on a
+		// shortest path: anything nearer to the other end would have been met at an earlier level
+		frontier, reached, other := &forwardFrontier, forward, backward
+		if len(backwardFrontier) < len(forwardFrontier) {
+			frontier, reached, other = &backwardFrontier, backward, forward
+		}
+
+		collaborations, err := s.Repo.GetCollaborations(*frontier)
+		if err != nil {
+			return nil, err
+		}
+		var next []uint
+		for _, collaboration := range collaborations {
+			if _, ok := reached[collaboration.CollaboratorID]; ok {
+				continue
+			}
+			reached[collaboration.CollaboratorID] = hop{musicianID: collaboration.MusicianID, albumID: collaboration.AlbumID}
+			next = append(next, collaboration.CollaboratorID)
+			if _, ok := other[collaboration.CollaboratorID]; ok && !found {
+				meeting, found = collaboration.CollaboratorID, true
+			}
+		}
+		slices.Sort(next)
+		*frontier = next
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %d and %d", ErrNotConnected, fromID, toID)
+	}
+
+	// Walk back from the meeting point to the first musician, then on to the second
+	var ids, via []uint
+	for id := meeting; id != fromID; id = forward[id].musicianID {
+		ids = append(ids, id)
+		via = append(via, forward[id].albumID)
+	}
+	ids = append(ids, fromID)
+	via = append(via, 0)
+	slices.Reverse(ids)
+	slices.Reverse(via)
+	for id := meeting; id != toID; id = backward[id].musicianID {
+		ids = append(ids, backward[id].musicianID)
+		via = append(via, backward[id].albumID)
+	}
+
+	path := &models.CollaborationPath{Degrees: len(ids) - 1, Steps: make([]models.PathStep, len(ids))}
+	for i, id := range ids {
+		musician, err := s.MusicianRepo.GetMusicianByID(id)
+		if err != nil {
+			return nil, err
+		}
+		path.Steps[i].Musician = *musician
+		if via[i] != 0 {
+			album, err := s.AlbumRepo.GetAlbumByID(via[i])
+			if err != nil {
+				return nil, err
+			}
+			path.Steps[i].Via = album
+		}
+	}
+	return path, nil
+}
+
+// GetStats works out the connected components of the collaboration graph with a union-find over the
+// musicians credited on each album.
+func (s *CollaborationService) GetStats() (*models.CollaborationStats, error) {
+	ids, err := s.Repo.GetMusicianIDs()
+	if err != nil {
+		return nil, err
+	}
+	credits, err := s.Repo.GetAlbumCredits()
+	if err != nil {
+		return nil, err
+	}
+	collaborations, err := s.Repo.CountCollaborations()
+	if err != nil {
+		return nil, err
+	}
+
+	parent := make(map[uint]uint, len(ids))
+	for _, id := range ids {
+		parent[id] = id
+	}
+	var find func(id uint) uint
+	find = func(id uint) uint {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	for _, musicianIDs := range credits {
+		// Credits of musicians that no longer exist are ignored
+		var first uint
+		for _, musicianID := range musicianIDs {
+			if _, ok := parent[musicianID]; !ok {
+				continue
+			}
+			if first == 0 {
+				first = musicianID
+				continue
+			}
+			parent[find(musicianID)] = find(first)
+		}
+	}
+
+	sizes := make(map[uint]int)
+	for _, id := range ids {
+		sizes[find(id)]++
+	}
+	stats := &models.CollaborationStats{Musicians: len(ids), Collaborations: collaborations, ComponentSizes: []models.ComponentSize{}}
+	counts := make(map[int]int)
+	for _, size := range sizes {
+		if size == 1 {
+			stats.Isolated++
+			continue
+		}
+		stats.Components++
+		stats.LargestComponent = max(stats.LargestComponent, size)
+		counts[size]++
+	}
+	for size, count := range counts {
+		stats.ComponentSizes = append(stats.ComponentSizes, models.ComponentSize{Size: size, Count: count})
+	}
+	sort.Slice(stats.ComponentSizes, func(i, j int) bool { return stats.ComponentSizes[i].Size > stats.ComponentSizes[j].Size })
+	return stats, nil
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"slices"
+	"testing"
+)
+
+// setupCollaborationService seeds two groups of collaborators, 1-2-3-4 with 5 on an album with 1, and
+// 6-7, plus musician 8 who has played with nobody.
+func setupCollaborationService(t *testing.T) *services.CollaborationService {
+	musicianRepo := setupTestMusicianRepo(t)
+	db := musicianRepo.DB
+
+	_, err := db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		INSERT INTO musicians (id, name, musician_type) VALUES
+		(1, 'Ann', 'Singer'), (2, 'Bob', 'Drummer'), (3, 'Cat', 'Bassist'), (4, 'Dan', 'Pianist'),
+		(5, 'Eve', 'Producer'), (6, 'Fay', 'Singer'), (7, 'Gus', 'Guitarist'), (8, 'Hal', 'Harpist');
+		INSERT INTO albums (id, name, release_date, genre, description) VALUES
+		(1, 'Album 1', '2001-01-01', '', ''), (2, 'Album 2', '2002-01-01', '', ''), (3, 'Album 3', '2003-01-01', '', ''),
+		(4, 'Album 4', '2004-01-01', '', ''), (5, 'Album 5', '2005-01-01', '', ''), (6, 'Album 6', '2006-01-01', '', '');
+		INSERT INTO album_musicians (album_id, musician_id, role) VALUES
+		(1, 1, 'performer'), (1, 2, 'performer'), (2, 2, 'performer'), (2, 3, 'performer'), (3, 3, 'performer'), (3, 4, 'performer'),
+		(4, 1, 'performer'), (4, 1, 'producer'), (4, 5, 'performer'), (5, 6, 'performer'), (5, 7, 'performer'),
+		(6, 1, 'performer'), (6, 2, 'performer');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed collaborations: %v", err)
+	}
+
+	return &services.CollaborationService{
+		Repo:         &repositories.CollaborationRepository{DB: db},
+		MusicianRepo: musicianRepo,
+		AlbumRepo:    &repositories.AlbumRepository{DB: db},
+	}
+}
+
+func TestGetCollaborators(t *testing.T) {
+	service := setupCollaborationService(t)
+
+	collaborators, err := service.GetCollaborators(1)
+	if err != nil {
+		t.Fatalf("failed to get collaborators: %v", err)
+	}
+	if len(collaborators) != 2 || collaborators[0].Name != "Bob" || collaborators[0].SharedAlbums != 2 || collaborators[1].SharedAlbums != 1 {
+		t.Errorf("expected Bob on 2 albums then Eve on 1, got %+v", collaborators)
+	}
+
+	if collaborators, _ := service.GetCollaborators(8); len(collaborators) != 0 {
+		t.Errorf("expected no collaborators, got %+v", collaborators)
+	}
+	if _, err := service.GetCollaborators(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing musician to be reported, got %v", err)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	service := setupCollaborationService(t)
+
+	path, err := service.GetPath(5, 4)
+	if err != nil {
+		t.Fatalf("failed to find path: %v", err)
+	}
+	var names []string
+	var via []uint
+	for _, step := range path.Steps {
+		names = append(names, step.Musician.Name)
+		if step.Via != nil {
+			via = append(via, step.Via.ID)
+		}
+	}
+	if path.Degrees != 4 || !slices.Equal(names, []string{"Eve", "Ann", "Bob", "Cat", "Dan"}) {
+		t.Errorf("unexpected path %d %v", path.Degrees, names)
+	}
+	if path.Steps[0].Via != nil || !slices.Equal(via, []uint{4, 1, 2, 3}) {
+		t.Errorf("unexpected albums along the path %v", via)
+	}
+
+	if path, err := service.GetPath(2, 2); err != nil || path.Degrees != 0 || len(path.Steps) != 1 {
+		t.Errorf("expected a musician to be 0 degrees from themselves, got %+v, %v", path, err)
+	}
+	if _, err := service.GetPath(1, 6); !errors.Is(err, services.ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+	if _, err := service.GetPath(1, 99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing musician to be reported, got %v", err)
+	}
+}
+
+func TestGetCollaborationStats(t *testing.T) {
+	service := setupCollaborationService(t)
+
+	stats, err := service.GetStats()
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+	expected := models.CollaborationStats{Musicians: 8, Collaborations: 5, Components: 2, LargestComponent: 5, Isolated: 1}
+	if stats.Musicians != expected.Musicians || stats.Collaborations != expected.Collaborations || stats.Components != expected.Components ||
+		stats.LargestComponent != expected.LargestComponent || stats.Isolated != expected.Isolated {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+	if !slices.Equal(stats.ComponentSizes, []models.ComponentSize{{Size: 5, Count: 1}, {Size: 2, Count: 1}}) {
+		t.Errorf("unexpected component sizes %+v", stats.ComponentSizes)
+	}
+}
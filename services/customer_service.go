@@ -0,0 +1,200 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrEmailTaken is returned when registering an email address that already has an account.
+var ErrEmailTaken = repositories.ErrEmailTaken
+
+// ErrUnauthorized is returned for a wrong email or password and for a missing, unknown or expired session.
+var ErrUnauthorized = errors.New("invalid credentials or session")
+
+// SessionLifetime is how long a session token stays valid after signing in.
+const SessionLifetime = 30 * 24 * time.Hour
+
+// Passwords must be at least MinPasswordLength characters and at most MaxPasswordBytes bytes, past which
+// bcrypt would silently ignore the rest.
+const (
+	MinPasswordLength = 8
+	MaxPasswordBytes  = 72
+)
+
+// unknownEmailHash is compared against when signing in with an unknown email address, so that it takes as
+// long as a wrong password and does not reveal which addresses are registered.
+var unknownEmailHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+type CustomerService struct {
+	Repo      *repositories.CustomerRepository
+	AlbumRepo *repositories.AlbumRepository
+	Orders    *repositories.OrderRepository
+}
+
+// NormalizeEmail lower-cases an email address and checks that it is a bare address such as ann@example.com.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", errors.New("email must be an address such as ann@example.com")
+	}
+	return email, nil
+}
+
+// Register creates a customer account, storing only a bcrypt hash of the password.
+func (s *CustomerService) Register(registration models.Registration) (*models.Customer, error) {
+	email, err := NormalizeEmail(registration.Email)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(registration.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len([]rune(registration.Password)) < MinPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	if len(registration.Password) > MaxPasswordBytes {
+		return nil, fmt.Errorf("password must be at most %d bytes", MaxPasswordBytes)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(registration.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	customer := &models.Customer{Email: email, Name: name, CreatedAt: time.Now().UTC().Format(models.TimeFormat)}
+	if err := s.Repo.CreateCustomer(customer, string(hash)); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// Login checks a customer's email and password and starts a session. The returned token is the only copy;
+// only its hash is stored.
+func (s *CustomerService) Login(credentials models.Credentials) (*models.Session, error) {
+	email := strings.ToLower(strings.TrimSpace(credentials.Email))
+	customer, hash, err := s.Repo.GetCustomerByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(unknownEmailHash(), []byte(credentials.Password))
+		return nil, ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(credentials.Password)) != nil {
+		return nil, ErrUnauthorized
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(secret)
+	now := time.Now().UTC()
+	session := &models.Session{Token: token, ExpiresAt: now.Add(SessionLifetime).Format(models.TimeFormat), Customer: *customer}
+	if err := s.Repo.CreateSession(hashToken(token), customer.ID, now.Format(models.TimeFormat), session.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Authenticate returns the customer signed in with a session token.
+func (s *CustomerService) Authenticate(token string) (*models.Customer, error) {
+	if token == "" {
+		return nil, ErrUnauthorized
+	}
+	customer, err := s.Repo.GetSessionCustomer(hashToken(token), time.Now().UTC().Format(models.TimeFormat))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnauthorized
+	}
+	return customer, err
+}
+
+// Logout ends the session with a token.
+func (s *CustomerService) Logout(token string) error {
+	if err := s.Repo.DeleteSession(hashToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	return nil
+}
+
+// hashToken is what a session token is stored as.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetLibrary retrieves the albums a customer owns, most recently added first.
+func (s *CustomerService) GetLibrary(customerID uint) ([]models.LibraryEntry, error) {
+	entries, err := s.Repo.GetLibraryEntries(customerID)
+	if err != nil {
+		return nil, err
+	}
+	albums, err := s.AlbumRepo.GetAlbums(models.AlbumFilter{CustomerID: customerID})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Album, len(albums))
+	for _, album := range albums {
+		byID[album.ID] = album
+	}
+	library := make([]models.LibraryEntry, 0, len(entries))
+	for _, entry := range entries {
+		// Albums deleted from the catalog drop out of the library
+		album, ok := byID[entry.Album.ID]
+		if !ok {
+			continue
+		}
+		entry.Album = album
+		library = append(library, entry)
+	}
+	return library, nil
+}
+
+// AddToLibrary adds an album to a customer's library by hand and returns the updated library.
+func (s *CustomerService) AddToLibrary(customerID, albumID uint) ([]models.LibraryEntry, error) {
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return nil, err
+	}
+	if err := s.Repo.AddLibraryEntry(customerID, albumID, time.Now().UTC().Format(models.TimeFormat)); err != nil {
+		return nil, err
+	}
+	return s.GetLibrary(customerID)
+}
+
+// RemoveFromLibrary removes an album from a customer's library.
+func (s *CustomerService) RemoveFromLibrary(customerID, albumID uint) error {
+	if err := s.Repo.DeleteLibraryEntry(customerID, albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d is not in the library: %w", albumID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetOrders retrieves a customer's orders, newest first.
+func (s *CustomerService) GetOrders(customerID uint) ([]models.Order, error) {
+	return s.Orders.GetCustomerOrders(customerID)
+}
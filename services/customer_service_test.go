@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"strings"
+	"testing"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	orders := setupOrderService(t, &services.FakePaymentProvider{})
+	service := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: orders.AlbumRepo.DB}, AlbumRepo: orders.AlbumRepo, Orders: orders.Repo}
+
+	for _, registration := range []models.Registration{
+		{Email: "not an address", Name: "Ann", Password: "correct horse"},
+		{Email: "Ann <ann@example.com>", Name: "Ann", Password: "correct horse"},
+		{Email: "ann@example.com", Name: " ", Password: "correct horse"},
+		{Email: "ann@example.com", Name: "Ann", Password: "short"},
+		{Email: "ann@example.com", Name: "Ann", Password: strings.Repeat("x", services.MaxPasswordBytes+1)},
+	} {
+		if _, err := service.Register(registration); err == nil {
+			t.Errorf("expected %+v to be rejected", registration)
+		}
+	}
+
+	customer, err := service.Register(models.Registration{Email: " Ann@Example.com ", Name: "Ann", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if customer.Email != "ann@example.com" {
+		t.Errorf("expected the email to be normalized, got %q", customer.Email)
+	}
+	if _, err := service.Register(models.Registration{Email: "ANN@example.com", Name: "Ann", Password: "another one"}); !errors.Is(err, services.ErrEmailTaken) {
+		t.Errorf("expected ErrEmailTaken, got %v", err)
+	}
+	var stored string
+	if err := service.Repo.DB.QueryRow("SELECT password_hash FROM customers WHERE id = ?", customer.ID).Scan(&stored); err != nil {
+		t.Fatalf("failed to read the password hash: %v", err)
+	}
+	if strings.Contains(stored, "correct horse") {
+		t.Error("expected the password to be stored hashed")
+	}
+
+	for _, credentials := range []models.Credentials{
+		{Email: "ann@example.com", Password: "wrong horse"},
+		{Email: "bob@example.com", Password: "correct horse"},
+	} {
+		if _, err := service.Login(credentials); !errors.Is(err, services.ErrUnauthorized) {
+			t.Errorf("expected %+v to be unauthorized, got %v", credentials, err)
+		}
+	}
+	session, err := service.Login(models.Credentials{Email: "ANN@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to log in: %v", err)
+	}
+	if session.Token == "" || session.Customer.ID != customer.ID {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	signedIn, err := service.Authenticate(session.Token)
+	if err != nil || signedIn.ID != customer.ID {
+		t.Fatalf("expected the token to authenticate %d, got %+v, %v", customer.ID, signedIn, err)
+	}
+	if err := service.Logout(session.Token); err != nil {
+		t.Fatalf("failed to log out: %v", err)
+	}
+	if _, err := service.Authenticate(session.Token); !errors.Is(err, services.ErrUnauthorized) {
+		t.Errorf("expected a logged out token to be unauthorized, got %v", err)
+	}
+
+	// Sessions past their expiry no longer authenticate
+	expired, err := service.Login(models.Credentials{Email: "ann@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to log in: %v", err)
+	}
+	if _, err := service.Repo.DB.Exec("UPDATE sessions SET expires_at = '2000-01-01T00:00:00Z'"); err != nil {
+		t.Fatalf("failed to expire session: %v", err)
+	}
+	if _, err := service.Authenticate(expired.Token); !errors.Is(err, services.ErrUnauthorized) {
+		t.Errorf("expected an expired token to be unauthorized, got %v", err)
+	}
+}
+
+func TestLibraryFollowsOrders(t *testing.T) {
+	orders := setupOrderService(t, &services.FakePaymentProvider{})
+	service := &services.CustomerService{Repo: &repositories.CustomerRepository{DB: orders.AlbumRepo.DB}, AlbumRepo: orders.AlbumRepo, Orders: orders.Repo}
+	if _, err := orders.UploadTaxRates([]models.TaxRate{{Country: "GB", Rate: 0.2}}); err != nil {
+		t.Fatalf("failed to upload tax rates: %v", err)
+	}
+
+	customer, err := service.Register(models.Registration{Email: "ann@example.com", Name: "Ann", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	bought := &models.Album{Name: "Bought Album", ReleaseDate: "2020-01-01", Price: 10}
+	owned := &models.Album{Name: "Owned Album", ReleaseDate: "2021-01-01", Price: 12}
+	for _, album := range []*models.Album{bought, owned} {
+		if err := orders.AlbumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	if _, err := service.AddToLibrary(customer.ID, 99); err == nil {
+		t.Error("expected a missing album to be rejected")
+	}
+	library, err := service.AddToLibrary(customer.ID, owned.ID)
+	if err != nil {
+		t.Fatalf("failed to add to library: %v", err)
+	}
+	if len(library) != 1 || library[0].Album.Name != "Owned Album" || library[0].Source != models.LibraryManual {
+		t.Errorf("unexpected library %+v", library)
+	}
+
+	cart := &models.Cart{CustomerID: customer.ID}
+	if err := orders.CreateCart(cart); err != nil {
+		t.Fatalf("failed to create cart: %v", err)
+	}
+	if _, err := orders.SetCartItem(cart.ID, models.CartItem{AlbumID: bought.ID, Quantity: 1}); err != nil {
+		t.Fatalf("failed to add to cart: %v", err)
+	}
+	order, err := orders.Checkout(cart.ID, models.Checkout{Country: "GB"})
+	if err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	// Only paid orders add to the library
+	if library, _ := service.GetLibrary(customer.ID); len(library) != 1 {
+		t.Errorf("expected a pending order not to add to the library, got %+v", library)
+	}
+	if _, err := orders.PayOrder(order.ID); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	library, err = service.GetLibrary(customer.ID)
+	if err != nil {
+		t.Fatalf("failed to get library: %v", err)
+	}
+	sources := map[uint]models.LibraryEntry{}
+	for _, entry := range library {
+		sources[entry.Album.ID] = entry
+	}
+	if len(library) != 2 || sources[bought.ID].Source != models.LibraryFromOrder || sources[bought.ID].OrderID != order.ID {
+		t.Errorf("expected the bought album to come from the order, got %+v", library)
+	}
+
+	mine, err := service.GetOrders(customer.ID)
+	if err != nil {
+		t.Fatalf("failed to get orders: %v", err)
+	}
+	if len(mine) != 1 || mine[0].ID != order.ID || mine[0].CustomerID != customer.ID {
+		t.Errorf("unexpected orders %+v", mine)
+	}
+
+	if _, err := orders.CancelOrder(order.ID); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if library, _ := service.GetLibrary(customer.ID); len(library) != 1 || library[0].Album.ID != owned.ID {
+		t.Errorf("expected cancelling to take the album back, got %+v", library)
+	}
+
+	if err := service.RemoveFromLibrary(customer.ID, owned.ID); err != nil {
+		t.Fatalf("failed to remove from library: %v", err)
+	}
+	if err := service.RemoveFromLibrary(customer.ID, owned.ID); err == nil {
+		t.Error("expected removing an album not in the library to fail")
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"slices"
+	"strings"
+)
+
+type EditionService struct {
+	Repo      *repositories.EditionRepository
+	AlbumRepo *repositories.AlbumRepository
+	// Prices, when set, converts edition prices of albums in other currencies than models.BaseCurrency
+	// to check them against the price limits.
+	Prices *PriceService
+}
+
+// ValidateBarcode checks that a barcode is an EAN-13, EAN-8 or UPC-A code with a correct check digit.
+func ValidateBarcode(barcode string) error {
+	if len(barcode) != 8 && len(barcode) != 12 && len(barcode) != 13 {
+		return errors.New("barcode must be an EAN-13, EAN-8 or UPC-A code of 13, 8 or 12 digits")
+	}
+
+	// GTIN check digit: weight the digits 3, 1, 3, ... from the right, skipping the check digit itself
+	sum := 0
+	for i := len(barcode) - 1; i >= 0; i-- {
+		c := barcode[i]
+		if c < '0' || c > '9' {
+			return errors.New("barcode must contain only digits")
+		}
+		if i == len(barcode)-1 {
+			continue
+		}
+		weight := 1
+		if (len(barcode)-2-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+	if check := (10 - sum%10) % 10; int(barcode[len(barcode)-1]-'0') != check {
+		return fmt.Errorf("barcode check digit should be %d", check)
+	}
+	return nil
+}
+
+// validateEdition checks an edition's format, barcode, release date and price in the album's currency. A missing
+// release date defaults to the album's. A missing album is reported with an error wrapping sql.ErrNoRows.
+func (s *EditionService) validateEdition(edition *models.Edition) error {
+	album, err := s.album(edition.AlbumID)
+	if err != nil {
+		return err
+	}
+
+	edition.Format = strings.ToLower(strings.TrimSpace(edition.Format))
+	if !slices.Contains(models.EditionFormats, edition.Format) {
+		return fmt.Errorf("format must be one of %s", strings.Join(models.EditionFormats, ", "))
+	}
+	edition.Name = strings.TrimSpace(edition.Name)
+
+	// Barcodes are often printed in groups separated by spaces or hyphens
+	edition.Barcode = strings.NewReplacer(" ", "", "-", "").Replace(edition.Barcode)
+	if edition.Barcode != "" {
+		if err := ValidateBarcode(edition.Barcode); err != nil {
+			return err
+		}
+		taken, err := s.Repo.BarcodeTaken(edition.Barcode, edition.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("barcode %s is already used by another edition", edition.Barcode)
+		}
+	}
+
+	if edition.ReleaseDate == "" {
+		edition.ReleaseDate = album.ReleaseDate
+	}
+	if err := ValidateDate("release_date", edition.ReleaseDate); err != nil {
+		return err
+	}
+
+	// Editions are priced in the album's currency
+	if err := validateAmount(edition.Price, album.Currency); err != nil {
+		return err
+	}
+	return checkPriceRange(s.Prices, edition.Price, album.Currency)
+}
+
+func (s *EditionService) album(albumID uint) (*models.Album, error) {
+	album, err := s.AlbumRepo.GetAlbumByID(albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+	}
+	return album, err
+}
+
+// AddEdition validates and adds an edition to an album.
+func (s *EditionService) AddEdition(edition *models.Edition) error {
+	if err := s.validateEdition(edition); err != nil {
+		return err
+	}
+	return s.Repo.CreateEdition(edition)
+}
+
+// UpdateEdition validates and updates an edition of an album.
+func (s *EditionService) UpdateEdition(edition *models.Edition) error {
+	if err := s.validateEdition(edition); err != nil {
+		return err
+	}
+	if err := s.Repo.UpdateEdition(edition); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d has no edition %d: %w", edition.AlbumID, edition.ID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveEdition removes an edition from an album.
+func (s *EditionService) RemoveEdition(albumID, editionID uint) error {
+	if err := s.Repo.DeleteEdition(albumID, editionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d has no edition %d: %w", albumID, editionID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetEditions retrieves the editions of an album sorted by release date, then price.
+func (s *EditionService) GetEditions(albumID uint) ([]models.Edition, error) {
+	if _, err := s.album(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetEditionsByAlbum(albumID)
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func TestValidateBarcode(t *testing.T) {
+	tests := []struct {
+		barcode string
+		valid   bool
+	}{
+		{"4006381333931", true}, // EAN-13
+		{"036000291452", true},  // UPC-A
+		{"96385074", true},      // EAN-8
+		{"4006381333932", false},
+		{"036000291453", false},
+		{"40063813339", false},
+		{"40063813339X1", false},
+	}
+	for _, tt := range tests {
+		if err := services.ValidateBarcode(tt.barcode); (err == nil) != tt.valid {
+			t.Errorf("ValidateBarcode(%q) = %v, want valid %v", tt.barcode, err, tt.valid)
+		}
+	}
+}
+
+func TestEditionServiceValidation(t *testing.T) {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE editions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			format TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			barcode TEXT UNIQUE,
+			release_date TEXT NOT NULL,
+			price_minor INTEGER NOT NULL
+		);
+		CREATE TABLE price_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			price_minor INTEGER NOT NULL,
+			starts_at TEXT NOT NULL,
+			applied_at TEXT
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	editionRepo := &repositories.EditionRepository{DB: albumRepo.DB}
+	service := &services.EditionService{Repo: editionRepo, AlbumRepo: albumRepo}
+
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 500}
+	if err := albumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	edition := &models.Edition{AlbumID: album.ID, Format: "CD", Barcode: "4006-3813-3393-1", Price: 200}
+	if err := service.AddEdition(edition); err != nil {
+		t.Fatalf("failed to add edition: %v", err)
+	}
+	if edition.Format != models.FormatCD || edition.Barcode != "4006381333931" || edition.ReleaseDate != album.ReleaseDate {
+		t.Errorf("expected normalised edition with the album's release date, got %+v", edition)
+	}
+
+	invalid := []models.Edition{
+		{AlbumID: album.ID, Format: "8-track", Price: 200},
+		{AlbumID: album.ID, Format: models.FormatVinyl, Barcode: "4006381333931", Price: 200},
+		{AlbumID: album.ID, Format: models.FormatVinyl, Barcode: "4006381333932", Price: 200},
+		{AlbumID: album.ID, Format: models.FormatVinyl, ReleaseDate: "2020-13-01", Price: 200},
+		{AlbumID: album.ID, Format: models.FormatDigital, Price: 5},
+	}
+	for _, edition := range invalid {
+		if err := service.AddEdition(&edition); err == nil {
+			t.Errorf("expected %+v to be rejected", edition)
+		}
+	}
+
+	// Updating the album keeps the price its editions give it
+	albumService := &services.AlbumService{Repo: albumRepo, Editions: editionRepo}
+	album.Price = 900
+	if err := albumService.UpdateAlbum(album); err != nil {
+		t.Fatalf("failed to update album: %v", err)
+	}
+	if album.Price != 200 {
+		t.Errorf("expected the edition price to win, got %v", album.Price)
+	}
+
+	if err := service.AddEdition(&models.Edition{AlbumID: 99, Format: models.FormatCD, Price: 200}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"jukebox/models"
+	"jukebox/repositories"
+)
+
+// ExportService writes the catalog as one row per album with its musicians joined.
+type ExportService struct {
+	Repo *repositories.AlbumRepository
+	// Prices reports prices in the filter's currency, when it has one.
+	Prices *PriceService
+}
+
+// Export streams every album matching the filter to w in the given format, with prices in the
+// filter's currency when it has one. Nothing is written when the prices cannot be reported in it.
+func (s *ExportService) Export(format string, filter models.AlbumFilter, w io.Writer) error {
+	var price func([]*models.Album) error
+	if filter.Currency != "" {
+		if s.Prices == nil {
+			return errors.New("prices are not configured")
+		}
+		var err error
+		if price, err = s.Prices.AlbumPricer(filter.Currency); err != nil {
+			return err
+		}
+	}
+
+	writer, err := newExportWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repo.StreamAlbumsWithMusicians(filter, func(page []models.AlbumWithMusicians) error {
+		if price != nil {
+			albums := make([]*models.Album, len(page))
+			for i := range page {
+				albums[i] = &page[i].Album
+			}
+			if err := price(albums); err != nil {
+				return err
+			}
+		}
+		for _, album := range page {
+			if err := writer.WriteAlbum(album); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
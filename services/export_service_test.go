@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestExportService(t *testing.T) *services.ExportService {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		CREATE TABLE album_musicians (
+			album_id INTEGER,
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES
+		(1, 'Later Album', '2022-02-01', 'Pop', 30000, 'Rock & "Roll"'),
+		(2, 'Early Album', '2022-01-01', 'Rock', 15050, ''),
+		(3, 'Solo Album', '2022-03-01', 'Jazz', 20000, '');
+		INSERT INTO musicians (id, name, musician_type) VALUES
+		(1, 'John Doe', 'Guitarist'),
+		(2, 'Jane Smith', 'Vocalist');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES
+		(1, 1), (1, 2), (2, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return &services.ExportService{Repo: &repositories.AlbumRepository{DB: db}}
+}
+
+func TestExportCSV(t *testing.T) {
+	service := setupTestExportService(t)
+
+	var out bytes.Buffer
+	if err := service.Export(services.FormatCSV, models.AlbumFilter{}, &out); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	expected := `id,name,release_date,genre,price,currency,description,musicians
+2,Early Album,2022-01-01,Rock,150.5,USD,,John Doe
+1,Later Album,2022-02-01,Pop,300,USD,"Rock & ""Roll""",Jane Smith; John Doe
+3,Solo Album,2022-03-01,Jazz,200,USD,,
+`
+	if out.String() != expected {
+		t.Errorf("unexpected CSV export:\n%s", out.String())
+	}
+}
+
+func TestExportJSONLWithMusicianFilter(t *testing.T) {
+	service := setupTestExportService(t)
+
+	var out bytes.Buffer
+	if err := service.Export(services.FormatJSONL, models.AlbumFilter{MusicianID: 2}, &out); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 album for musician 2, got %d", len(lines))
+	}
+
+	var album models.AlbumWithMusicians
+	if err := json.Unmarshal([]byte(lines[0]), &album); err != nil {
+		t.Fatalf("failed to decode line: %v", err)
+	}
+	// The filter selects albums, but each album still lists all of its musicians
+	if album.Name != "Later Album" || len(album.Musicians) != 2 {
+		t.Errorf("expected Later Album with 2 musicians, got %+v", album)
+	}
+}
+
+func TestExportODS(t *testing.T) {
+	service := setupTestExportService(t)
+
+	var out bytes.Buffer
+	if err := service.Export(services.FormatODS, models.AlbumFilter{}, &out); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("failed to open spreadsheet: %v", err)
+	}
+
+	if archive.File[0].Name != "mimetype" || archive.File[0].Method != zip.Store {
+		t.Errorf("expected an uncompressed mimetype entry first, got %s", archive.File[0].Name)
+	}
+
+	var content string
+	for _, file := range archive.File {
+		if file.Name == "content.xml" {
+			r, err := file.Open()
+			if err != nil {
+				t.Fatalf("failed to open content.xml: %v", err)
+			}
+			data, _ := io.ReadAll(r)
+			content = string(data)
+		}
+	}
+
+	if strings.Count(content, "<table:table-row>") != 4 {
+		t.Errorf("expected a header and 3 album rows, got:\n%s", content)
+	}
+	if !strings.Contains(content, `office:value-type="float" office:value="150.5"`) {
+		t.Error("expected price to be written as a numeric cell")
+	}
+	if !strings.Contains(content, "Rock &amp; &#34;Roll&#34;") {
+		t.Error("expected cell text to be XML escaped")
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	service := setupTestExportService(t)
+
+	if err := service.Export("xlsx", models.AlbumFilter{}, io.Discard); err == nil {
+		t.Error("expected error for unsupported format, got nil")
+	}
+}
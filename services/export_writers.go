@@ -0,0 +1,205 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"jukebox/models"
+	"strconv"
+	"strings"
+)
+
+// Formats accepted by ExportService.Export. CSV and JSON Lines are shared with the import.
+const FormatODS = "ods"
+
+// exportColumns are the columns written by the tabular export formats.
+var exportColumns = []string{"id", "name", "release_date", "genre", "price", "currency", "description", "musicians"}
+
+// exportWriter writes one album per row in a single output format.
+type exportWriter interface {
+	WriteAlbum(album models.AlbumWithMusicians) error
+	// Close finishes the document and flushes any buffered output.
+	Close() error
+}
+
+// ExportContentType returns the media type and file extension for an export format.
+func ExportContentType(format string) (string, string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv", "csv", nil
+	case FormatJSONL:
+		return "application/x-ndjson", "jsonl", nil
+	case FormatODS:
+		return "application/vnd.oasis.opendocument.spreadsheet", "ods", nil
+	}
+	return "", "", fmt.Errorf("unsupported export format %q", format)
+}
+
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportColumns); err != nil {
+			return nil, err
+		}
+		return &csvExportWriter{writer: writer}, nil
+	case FormatJSONL:
+		buffered := bufio.NewWriter(w)
+		return &jsonlExportWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case FormatODS:
+		return newODSExportWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// exportRow flattens an album into the tabular export columns.
+func exportRow(album models.AlbumWithMusicians) []string {
+	names := make([]string, len(album.Musicians))
+	for i, musician := range album.Musicians {
+		names[i] = musician.Name
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(album.ID), 10),
+		album.Name,
+		album.ReleaseDate,
+		album.Genre,
+		strconv.FormatFloat(album.Price, 'f', -1, 64),
+		album.Currency,
+		album.Description,
+		strings.Join(names, "; "),
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvExportWriter) WriteAlbum(album models.AlbumWithMusicians) error {
+	return c.writer.Write(exportRow(album))
+}
+
+func (c *csvExportWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlExportWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (j *jsonlExportWriter) WriteAlbum(album models.AlbumWithMusicians) error {
+	return j.encoder.Encode(album)
+}
+
+func (j *jsonlExportWriter) Close() error {
+	return j.buffered.Flush()
+}
+
+const (
+	odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+	odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">
+ <manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + odsMimeType + `"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+
+	odsContentStart = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.2">
+<office:body><office:spreadsheet><table:table table:name="Albums">
+`
+
+	odsContentEnd = `</table:table></office:spreadsheet></office:body></office:document-content>
+`
+)
+
+// odsExportWriter streams an OpenDocument spreadsheet: a zip archive whose
+// content.xml is written row by row as albums arrive.
+type odsExportWriter struct {
+	archive *zip.Writer
+	content *bufio.Writer
+}
+
+func newODSExportWriter(w io.Writer) (*odsExportWriter, error) {
+	archive := zip.NewWriter(w)
+
+	// The mimetype entry must come first and be stored uncompressed
+	mimetype, err := archive.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(odsMimeType)),
+		CompressedSize64:   uint64(len(odsMimeType)),
+		UncompressedSize64: uint64(len(odsMimeType)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(mimetype, odsMimeType); err != nil {
+		return nil, err
+	}
+
+	manifest, err := archive.Create("META-INF/manifest.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(manifest, odsManifest); err != nil {
+		return nil, err
+	}
+
+	content, err := archive.Create("content.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	o := &odsExportWriter{archive: archive, content: bufio.NewWriter(content)}
+	if _, err := o.content.WriteString(odsContentStart); err != nil {
+		return nil, err
+	}
+	if err := o.writeRow(exportColumns, nil); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *odsExportWriter) WriteAlbum(album models.AlbumWithMusicians) error {
+	// Price is stored as a number so spreadsheets can sum it
+	return o.writeRow(exportRow(album), map[int]bool{4: true})
+}
+
+func (o *odsExportWriter) writeRow(cells []string, numeric map[int]bool) error {
+	o.content.WriteString("<table:table-row>")
+	for i, cell := range cells {
+		if numeric[i] {
+			o.content.WriteString(`<table:table-cell office:value-type="float" office:value="`)
+			xml.EscapeText(o.content, []byte(cell))
+			o.content.WriteString(`">`)
+		} else {
+			o.content.WriteString(`<table:table-cell office:value-type="string">`)
+		}
+		o.content.WriteString("<text:p>")
+		if err := xml.EscapeText(o.content, []byte(cell)); err != nil {
+			return err
+		}
+		o.content.WriteString("</text:p></table:table-cell>")
+	}
+	_, err := o.content.WriteString("</table:table-row>\n")
+	return err
+}
+
+func (o *odsExportWriter) Close() error {
+	if _, err := o.content.WriteString(odsContentEnd); err != nil {
+		return err
+	}
+	if err := o.content.Flush(); err != nil {
+		return err
+	}
+	return o.archive.Close()
+}
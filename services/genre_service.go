@@ -0,0 +1,224 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"strings"
+	"time"
+)
+
+// ErrGenreHasChildren is returned when deleting a genre that other genres descend from.
+var ErrGenreHasChildren = errors.New("genre still has child genres")
+
+type GenreService struct {
+	Repo      *repositories.GenreRepository
+	AlbumRepo *repositories.AlbumRepository
+}
+
+// validateGenre checks a genre's name, aliases and parent. Names and aliases must not collide
+// with those of another genre once normalised, and a genre cannot be its own ancestor.
+func (s *GenreService) validateGenre(genre *models.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if models.GenreKey(genre.Name) == "" {
+		return errors.New("genre name must contain a letter or digit")
+	}
+
+	keys := map[string]bool{}
+	aliases := []string{}
+	for _, name := range append([]string{genre.Name}, genre.Aliases...) {
+		key := models.GenreKey(name)
+		if key == "" {
+			return errors.New("genre aliases must contain a letter or digit")
+		}
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		if name != genre.Name {
+			aliases = append(aliases, strings.TrimSpace(name))
+		}
+
+		id, err := s.Repo.FindGenreID(name)
+		if err == nil && id != genre.ID {
+			return fmt.Errorf("%q already names genre %d", name, id)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	genre.Aliases = aliases
+
+	// Walk up from the parent; reaching the genre itself would create a cycle
+	for parentID := genre.ParentID; parentID != 0; {
+		if parentID == genre.ID {
+			return errors.New("genre cannot be its own ancestor")
+		}
+		parent, err := s.genre(parentID)
+		if err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+func (s *GenreService) genre(id uint) (*models.Genre, error) {
+	genre, err := s.Repo.GetGenreByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("genre %d does not exist: %w", id, err)
+	}
+	return genre, err
+}
+
+// CreateGenre validates and creates a new genre.
+func (s *GenreService) CreateGenre(genre *models.Genre) error {
+	if err := s.validateGenre(genre); err != nil {
+		return err
+	}
+	return s.Repo.CreateGenre(genre)
+}
+
+// GetGenres retrieves every genre sorted by name.
+func (s *GenreService) GetGenres() ([]models.Genre, error) {
+	return s.Repo.GetGenres()
+}
+
+// GetGenre retrieves a genre by ID.
+func (s *GenreService) GetGenre(genreID uint) (*models.Genre, error) {
+	return s.genre(genreID)
+}
+
+// UpdateGenre validates and updates an existing genre.
+func (s *GenreService) UpdateGenre(genre *models.Genre) error {
+	if _, err := s.genre(genre.ID); err != nil {
+		return err
+	}
+	if err := s.validateGenre(genre); err != nil {
+		return err
+	}
+	return s.Repo.UpdateGenre(genre)
+}
+
+// DeleteGenre deletes a genre without child genres, removing it from every album.
+func (s *GenreService) DeleteGenre(genreID uint) error {
+	if _, err := s.genre(genreID); err != nil {
+		return err
+	}
+	hasChildren, err := s.Repo.HasChildren(genreID)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return ErrGenreHasChildren
+	}
+	return s.Repo.DeleteGenre(genreID)
+}
+
+// GetAlbumGenres retrieves the genres an album is tagged with.
+func (s *GenreService) GetAlbumGenres(albumID uint) ([]models.Genre, error) {
+	if err := s.requireAlbum(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAlbumGenres(albumID)
+}
+
+// SetAlbumGenres replaces the genres of an album. Each name is resolved through genre names and aliases.
+func (s *GenreService) SetAlbumGenres(albumID uint, names []string) ([]models.Genre, error) {
+	if err := s.requireAlbum(albumID); err != nil {
+		return nil, err
+	}
+
+	genreIDs := make([]uint, len(names))
+	for i, name := range names {
+		id, err := s.Repo.FindGenreID(name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("unknown genre %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		genreIDs[i] = id
+	}
+
+	if err := s.Repo.SetAlbumGenres(albumID, genreIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAlbumGenres(albumID)
+}
+
+func (s *GenreService) requireAlbum(albumID uint) error {
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// TagAlbumFromText tags an album with the genre its free-text genre resolves to, creating a
+// top-level genre when no name or alias matches.
+func (s *GenreService) TagAlbumFromText(album *models.Album) error {
+	if models.GenreKey(album.Genre) == "" {
+		return nil
+	}
+
+	id, err := s.Repo.FindGenreID(album.Genre)
+	if errors.Is(err, sql.ErrNoRows) {
+		genre := &models.Genre{Name: strings.TrimSpace(album.Genre)}
+		if err := s.Repo.CreateGenre(genre); err != nil {
+			return err
+		}
+		id, err = genre.ID, nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.Repo.TagAlbum(album.ID, id)
+}
+
+// RetagAlbumFromText tags an album whose free-text genre changed from previous, replacing the genre the
+// previous text resolved to with the genre its new text resolves to.
+func (s *GenreService) RetagAlbumFromText(album *models.Album, previous string) error {
+	if key := models.GenreKey(previous); key != "" && key != models.GenreKey(album.Genre) {
+		id, err := s.Repo.FindGenreID(previous)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil {
+			if err := s.Repo.UntagAlbum(album.ID, id); err != nil {
+				return err
+			}
+		}
+	}
+	return s.TagAlbumFromText(album)
+}
+
+// BackfillAlbumGenres tags every album that has genre text but no genre yet, and returns how many were tagged.
+// It runs once per database: later runs tag nothing, so genres cleared by hand stay cleared.
+func (s *GenreService) BackfillAlbumGenres() (int, error) {
+	done, err := s.Repo.AlbumGenresBackfilled()
+	if err != nil || done {
+		return 0, err
+	}
+	albums, err := s.Repo.GetUntaggedAlbums()
+	if err != nil {
+		return 0, err
+	}
+
+	tagged := 0
+	for i := range albums {
+		// Genre text without a letter or digit names no genre and stays untagged
+		if models.GenreKey(albums[i].Genre) == "" {
+			continue
+		}
+		if err := s.TagAlbumFromText(&albums[i]); err != nil {
+			return tagged, err
+		}
+		tagged++
+	}
+	return tagged, s.Repo.MarkAlbumGenresBackfilled(time.Now().UTC().Format(models.TimeFormat))
+}
@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func setupTestGenreService(t *testing.T) (*services.GenreService, *repositories.AlbumRepository) {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE genres (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL UNIQUE,
+			parent_id INTEGER
+		);
+		CREATE TABLE genre_aliases (
+			key TEXT PRIMARY KEY,
+			alias TEXT NOT NULL,
+			genre_id INTEGER NOT NULL
+		);
+		CREATE TABLE album_genres (
+			album_id INTEGER NOT NULL,
+			genre_id INTEGER NOT NULL,
+			PRIMARY KEY (album_id, genre_id)
+		);
+		CREATE TABLE backfills (
+			name TEXT PRIMARY KEY,
+			completed_at TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return &services.GenreService{Repo: &repositories.GenreRepository{DB: albumRepo.DB}, AlbumRepo: albumRepo}, albumRepo
+}
+
+func TestGenreServiceValidation(t *testing.T) {
+	service, _ := setupTestGenreService(t)
+
+	rock := &models.Genre{Name: "Rock"}
+	if err := service.CreateGenre(rock); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	roll := &models.Genre{Name: "Rock & Roll", ParentID: rock.ID, Aliases: []string{"Rock'n'Roll", "rock n roll"}}
+	if err := service.CreateGenre(roll); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	// "Rock'n'Roll" normalises to the genre's own name, so only one alias is kept
+	if len(roll.Aliases) != 1 || roll.Aliases[0] != "rock n roll" {
+		t.Errorf("expected duplicate alias to be dropped, got %+v", roll.Aliases)
+	}
+
+	invalid := []models.Genre{
+		{Name: "  "},
+		{Name: "ROCK"},
+		{Name: "Rockabilly", Aliases: []string{"Rock and Roll"}},
+		{Name: "Orphan", ParentID: 99},
+	}
+	for _, genre := range invalid {
+		if err := service.CreateGenre(&genre); err == nil {
+			t.Errorf("expected %+v to be rejected", genre)
+		}
+	}
+
+	rock.ParentID = roll.ID
+	if err := service.UpdateGenre(rock); err == nil {
+		t.Error("expected a genre cycle to be rejected")
+	}
+
+	if err := service.DeleteGenre(rock.ID); !errors.Is(err, services.ErrGenreHasChildren) {
+		t.Errorf("expected ErrGenreHasChildren, got %v", err)
+	}
+	if err := service.DeleteGenre(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestBackfillAlbumGenres(t *testing.T) {
+	service, albumRepo := setupTestGenreService(t)
+
+	rock := &models.Genre{Name: "Rock"}
+	if err := service.CreateGenre(rock); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	hipHop := &models.Genre{Name: "Hip Hop", Aliases: []string{"Rap"}}
+	if err := service.CreateGenre(hipHop); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+
+	for _, album := range []*models.Album{
+		{Name: "Rock Album", ReleaseDate: "2020-01-01", Genre: "ROCK", Price: 10},
+		{Name: "Rap Album", ReleaseDate: "2020-01-01", Genre: "rap", Price: 10},
+		{Name: "Polka Album", ReleaseDate: "2020-01-01", Genre: "Polka", Price: 10},
+		{Name: "Untitled", ReleaseDate: "2020-01-01", Genre: "", Price: 10},
+	} {
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+
+	tagged, err := service.BackfillAlbumGenres()
+	if err != nil {
+		t.Fatalf("failed to backfill genres: %v", err)
+	}
+	if tagged != 3 {
+		t.Errorf("expected 3 albums to be tagged, got %d", tagged)
+	}
+
+	expected := map[uint]string{1: "Rock", 2: "Hip Hop", 3: "Polka"}
+	for albumID, name := range expected {
+		genres, err := service.GetAlbumGenres(albumID)
+		if err != nil {
+			t.Fatalf("failed to get album genres: %v", err)
+		}
+		if len(genres) != 1 || genres[0].Name != name {
+			t.Errorf("album %d: expected genre %q, got %+v", albumID, name, genres)
+		}
+	}
+
+	// A second run tags nothing, not even albums whose genres were cleared since
+	if _, err := service.SetAlbumGenres(1, nil); err != nil {
+		t.Fatalf("failed to clear album genres: %v", err)
+	}
+	if tagged, err := service.BackfillAlbumGenres(); err != nil || tagged != 0 {
+		t.Errorf("expected nothing to tag, got %d (%v)", tagged, err)
+	}
+	if genres, err := service.GetAlbumGenres(1); err != nil || len(genres) != 0 {
+		t.Errorf("expected cleared genres to stay cleared, got %+v (%v)", genres, err)
+	}
+
+	if _, err := service.SetAlbumGenres(1, []string{"Polka", "Jazz"}); err == nil {
+		t.Error("expected an unknown genre to be rejected")
+	}
+	if _, err := service.SetAlbumGenres(99, []string{"Rock"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestUpdatingGenreTextReplacesItsGenre(t *testing.T) {
+	genres, albumRepo := setupTestGenreService(t)
+	service := &services.AlbumService{Repo: albumRepo, Genres: genres}
+
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Genre: "Rock", Price: 200}
+	if err := service.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	jazz := &models.Genre{Name: "Jazz"}
+	if err := genres.CreateGenre(jazz); err != nil {
+		t.Fatalf("failed to create genre: %v", err)
+	}
+	if _, err := genres.SetAlbumGenres(album.ID, []string{"Rock", "Jazz"}); err != nil {
+		t.Fatalf("failed to set album genres: %v", err)
+	}
+
+	album.Genre = "Blues"
+	if err := service.UpdateAlbum(album); err != nil {
+		t.Fatalf("failed to update album: %v", err)
+	}
+	tagged, err := genres.GetAlbumGenres(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album genres: %v", err)
+	}
+	if len(tagged) != 2 || tagged[0].Name != "Blues" || tagged[1].Name != "Jazz" {
+		t.Errorf("expected rock replaced by blues and jazz kept, got %+v", tagged)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats accepted by ImportService.Import.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// rowError is a problem with a single row; the import continues with the next one.
+type rowError struct {
+	row    int
+	reason string
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.row, e.reason)
+}
+
+// recordReader streams rows from an upload as field name to value maps.
+type recordReader interface {
+	// Next returns the next row and its 1-based number, a *rowError for a
+	// malformed row, or io.EOF when the upload is exhausted.
+	Next() (int, map[string]string, error)
+}
+
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return &csvRecordReader{reader: reader}, nil
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &jsonlRecordReader{scanner: scanner}, nil
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// csvRecordReader reads a CSV upload whose first line names the columns.
+type csvRecordReader struct {
+	reader *csv.Reader
+	header []string
+	row    int
+}
+
+func (c *csvRecordReader) Next() (int, map[string]string, error) {
+	if c.header == nil {
+		header, err := c.reader.Read()
+		if err == io.EOF {
+			return 0, nil, errors.New("CSV upload is missing a header row")
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		for i, name := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(name))
+		}
+		c.header = header
+	}
+
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	c.row++
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return c.row, nil, &rowError{c.row, parseErr.Err.Error()}
+	}
+	if err != nil {
+		return c.row, nil, err
+	}
+
+	if len(record) != len(c.header) {
+		return c.row, nil, &rowError{c.row, fmt.Sprintf("expected %d fields, got %d", len(c.header), len(record))}
+	}
+
+	fields := make(map[string]string, len(record))
+	for i, value := range record {
+		fields[c.header[i]] = value
+	}
+	return c.row, fields, nil
+}
+
+// jsonlRecordReader reads a JSON Lines upload with one object per line.
+type jsonlRecordReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (j *jsonlRecordReader) Next() (int, map[string]string, error) {
+	for j.scanner.Scan() {
+		line := bytes.TrimSpace(j.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		j.row++
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			return j.row, nil, &rowError{j.row, "invalid JSON: " + err.Error()}
+		}
+
+		fields := make(map[string]string, len(object))
+		for name, value := range object {
+			switch v := value.(type) {
+			case string:
+				fields[name] = v
+			case json.Number:
+				fields[name] = v.String()
+			case nil:
+				fields[name] = ""
+			default:
+				return j.row, nil, &rowError{j.row, fmt.Sprintf("field %s must be a string or number", name)}
+			}
+		}
+		return j.row, fields, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		return j.row, nil, err
+	}
+	return 0, nil, io.EOF
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"jukebox/models"
+	"jukebox/repositories"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Entities accepted by ImportService.Import.
+const (
+	ImportAlbums    = "albums"
+	ImportMusicians = "musicians"
+	ImportLinks     = "links"
+)
+
+// DefaultImportBatchSize is the number of rows committed per transaction when BatchSize is not set.
+const DefaultImportBatchSize = 500
+
+// ImportService loads albums, musicians and album–musician links in bulk.
+// Rows are streamed from the upload, validated with the same rules as the
+// create endpoints, and committed in batches.
+type ImportService struct {
+	AlbumRepo    *repositories.AlbumRepository
+	MusicianRepo *repositories.MusicianRepository
+	// Genres, when set, tags imported albums with the genre their genre text resolves to.
+	Genres    *GenreService
+	BatchSize int
+}
+
+// rowImporter validates rows for one entity and commits them in batches.
+type rowImporter interface {
+	// columns lists the fields a row may contain.
+	columns() []string
+	// add validates a row. It returns a result for rows that are skipped or
+	// failed straight away, or nil when the row is queued for the next batch.
+	add(row int, fields map[string]string) *models.ImportRowResult
+	pending() int
+	// flush commits the queued rows and reports their outcome.
+	flush() []models.ImportRowResult
+}
+
+// Import reads every row of the upload and returns a per-row report. An error
+// is only returned when the upload itself cannot be read.
+func (s *ImportService) Import(entity, format string, r io.Reader) (*models.ImportReport, error) {
+	importer, err := s.importerFor(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := newRecordReader(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	report := &models.ImportReport{Rows: []models.ImportRowResult{}}
+	for {
+		row, fields, err := records.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			record(report, models.ImportRowResult{Row: row, Status: models.ImportFailed, Reason: rowErr.reason})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if unknown := unknownColumn(fields, importer.columns()); unknown != "" {
+			record(report, models.ImportRowResult{Row: row, Status: models.ImportFailed, Reason: "unknown field " + unknown})
+			continue
+		}
+
+		if result := importer.add(row, fields); result != nil {
+			record(report, *result)
+		}
+		if importer.pending() >= batchSize {
+			for _, result := range importer.flush() {
+				record(report, result)
+			}
+		}
+	}
+
+	for _, result := range importer.flush() {
+		record(report, result)
+	}
+
+	// Batched rows are reported when they are committed; restore upload order
+	sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Row < report.Rows[j].Row })
+	return report, nil
+}
+
+func (s *ImportService) importerFor(entity string) (rowImporter, error) {
+	switch entity {
+	case ImportAlbums:
+		return &albumImporter{repo: s.AlbumRepo, genres: s.Genres, seen: make(map[string]int)}, nil
+	case ImportMusicians:
+		return &musicianImporter{repo: s.MusicianRepo, seen: make(map[string]int)}, nil
+	case ImportLinks:
+		return &linkImporter{albums: s.AlbumRepo, musicians: s.MusicianRepo, seen: make(map[models.AlbumMusician]int)}, nil
+	}
+	return nil, fmt.Errorf("unsupported import entity %q", entity)
+}
+
+func record(report *models.ImportReport, result models.ImportRowResult) {
+	switch result.Status {
+	case models.ImportCreated:
+		report.Created++
+	case models.ImportSkipped:
+		report.Skipped++
+	case models.ImportFailed:
+		report.Failed++
+	}
+	report.Rows = append(report.Rows, result)
+}
+
+func unknownColumn(fields map[string]string, columns []string) string {
+	for name := range fields {
+		known := false
+		for _, column := range columns {
+			if name == column {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return name
+		}
+	}
+	return ""
+}
+
+func failed(row int, reason string) *models.ImportRowResult {
+	return &models.ImportRowResult{Row: row, Status: models.ImportFailed, Reason: reason}
+}
+
+func skipped(row int, reason string) *models.ImportRowResult {
+	return &models.ImportRowResult{Row: row, Status: models.ImportSkipped, Reason: reason}
+}
+
+// flushFailed reports every queued row as failed when its batch could not be committed.
+func flushFailed(rows []int, err error) []models.ImportRowResult {
+	results := make([]models.ImportRowResult, len(rows))
+	for i, row := range rows {
+		results[i] = models.ImportRowResult{Row: row, Status: models.ImportFailed, Reason: "batch commit failed: " + err.Error()}
+	}
+	return results
+}
+
+type albumImporter struct {
+	repo   *repositories.AlbumRepository
+	genres *GenreService
+	rows   []int
+	albums []*models.Album
+	// seen maps the name and release date of queued albums to their row, to skip duplicates within a batch
+	seen map[string]int
+}
+
+func (a *albumImporter) columns() []string {
+	return []string{"name", "release_date", "genre", "price", "description"}
+}
+
+func (a *albumImporter) add(row int, fields map[string]string) *models.ImportRowResult {
+	price, err := strconv.ParseFloat(strings.TrimSpace(fields["price"]), 64)
+	if err != nil {
+		return failed(row, "price must be a number")
+	}
+
+	album := &models.Album{
+		Name:        fields["name"],
+		ReleaseDate: fields["release_date"],
+		Genre:       fields["genre"],
+		Price:       price,
+		Description: fields["description"],
+	}
+	if err := ValidateAlbum(album); err != nil {
+		return failed(row, err.Error())
+	}
+
+	key := album.Name + "\x00" + album.ReleaseDate
+	if first, ok := a.seen[key]; ok {
+		return skipped(row, fmt.Sprintf("duplicate of row %d", first))
+	}
+	exists, err := a.repo.AlbumExists(album.Name, album.ReleaseDate)
+	if err != nil {
+		return failed(row, err.Error())
+	}
+	if exists {
+		return skipped(row, "album with the same name and release date already exists")
+	}
+
+	a.seen[key] = row
+	a.rows = append(a.rows, row)
+	a.albums = append(a.albums, album)
+	return nil
+}
+
+func (a *albumImporter) pending() int {
+	return len(a.albums)
+}
+
+func (a *albumImporter) flush() []models.ImportRowResult {
+	if len(a.albums) == 0 {
+		return nil
+	}
+	defer func() {
+		a.rows, a.albums = nil, nil
+		a.seen = make(map[string]int)
+	}()
+
+	if err := a.repo.CreateAlbums(a.albums); err != nil {
+		return flushFailed(a.rows, err)
+	}
+
+	results := make([]models.ImportRowResult, len(a.albums))
+	for i, album := range a.albums {
+		results[i] = models.ImportRowResult{Row: a.rows[i], Status: models.ImportCreated, ID: album.ID}
+		if a.genres == nil {
+			continue
+		}
+		// The album is committed already, so a genre that cannot be tagged is only reported
+		if err := a.genres.TagAlbumFromText(album); err != nil {
+			results[i].Reason = "created without its genre: " + err.Error()
+		}
+	}
+	return results
+}
+
+type musicianImporter struct {
+	repo      *repositories.MusicianRepository
+	rows      []int
+	musicians []*models.Musician
+	// seen maps the name and type of queued musicians to their row, to skip duplicates within a batch
+	seen map[string]int
+}
+
+func (m *musicianImporter) columns() []string {
+	return []string{"name", "musician_type", "kind"}
+}
+
+func (m *musicianImporter) add(row int, fields map[string]string) *models.ImportRowResult {
+	musician := &models.Musician{Name: fields["name"], MusicianType: fields["musician_type"], Kind: fields["kind"]}
+	if err := ValidateMusician(musician); err != nil {
+		return failed(row, err.Error())
+	}
+
+	key := musician.Name + "\x00" + musician.MusicianType
+	if first, ok := m.seen[key]; ok {
+		return skipped(row, fmt.Sprintf("duplicate of row %d", first))
+	}
+	exists, err := m.repo.MusicianExists(musician.Name, musician.MusicianType)
+	if err != nil {
+		return failed(row, err.Error())
+	}
+	if exists {
+		return skipped(row, "musician with the same name and type already exists")
+	}
+
+	m.seen[key] = row
+	m.rows = append(m.rows, row)
+	m.musicians = append(m.musicians, musician)
+	return nil
+}
+
+func (m *musicianImporter) pending() int {
+	return len(m.musicians)
+}
+
+func (m *musicianImporter) flush() []models.ImportRowResult {
+	if len(m.musicians) == 0 {
+		return nil
+	}
+	defer func() {
+		m.rows, m.musicians = nil, nil
+		m.seen = make(map[string]int)
+	}()
+
+	if err := m.repo.CreateMusicians(m.musicians); err != nil {
+		return flushFailed(m.rows, err)
+	}
+
+	results := make([]models.ImportRowResult, len(m.musicians))
+	for i, musician := range m.musicians {
+		results[i] = models.ImportRowResult{Row: m.rows[i], Status: models.ImportCreated, ID: musician.ID}
+	}
+	return results
+}
+
+type linkImporter struct {
+	albums    *repositories.AlbumRepository
+	musicians *repositories.MusicianRepository
+	rows      []int
+	links     []models.AlbumMusician
+	// seen maps queued links to their row, to skip duplicates within a batch
+	seen map[models.AlbumMusician]int
+}
+
+func (l *linkImporter) columns() []string {
+	return []string{"album_id", "musician_id"}
+}
+
+func (l *linkImporter) add(row int, fields map[string]string) *models.ImportRowResult {
+	albumID, err := strconv.ParseUint(strings.TrimSpace(fields["album_id"]), 10, 32)
+	if err != nil {
+		return failed(row, "album_id must be a positive integer")
+	}
+	musicianID, err := strconv.ParseUint(strings.TrimSpace(fields["musician_id"]), 10, 32)
+	if err != nil {
+		return failed(row, "musician_id must be a positive integer")
+	}
+	link := models.AlbumMusician{AlbumID: uint(albumID), MusicianID: uint(musicianID)}
+
+	if _, err := l.albums.GetAlbumByID(link.AlbumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return failed(row, fmt.Sprintf("album %d does not exist", link.AlbumID))
+		}
+		return failed(row, err.Error())
+	}
+	if _, err := l.musicians.GetMusicianByID(link.MusicianID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return failed(row, fmt.Sprintf("musician %d does not exist", link.MusicianID))
+		}
+		return failed(row, err.Error())
+	}
+
+	if first, ok := l.seen[link]; ok {
+		return skipped(row, fmt.Sprintf("duplicate of row %d", first))
+	}
+	exists, err := l.albums.HasLink(link.AlbumID, link.MusicianID)
+	if err != nil {
+		return failed(row, err.Error())
+	}
+	if exists {
+		return skipped(row, "musician is already linked to the album")
+	}
+
+	l.seen[link] = row
+	l.rows = append(l.rows, row)
+	l.links = append(l.links, link)
+	return nil
+}
+
+func (l *linkImporter) pending() int {
+	return len(l.links)
+}
+
+func (l *linkImporter) flush() []models.ImportRowResult {
+	if len(l.links) == 0 {
+		return nil
+	}
+	defer func() {
+		l.rows, l.links = nil, nil
+		l.seen = make(map[models.AlbumMusician]int)
+	}()
+
+	if err := l.albums.CreateLinks(l.links); err != nil {
+		return flushFailed(l.rows, err)
+	}
+
+	results := make([]models.ImportRowResult, len(l.links))
+	for i, row := range l.rows {
+		results[i] = models.ImportRowResult{Row: row, Status: models.ImportCreated}
+	}
+	return results
+}
@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"database/sql"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestImportService(t *testing.T) (*sql.DB, *services.ImportService) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		CREATE TABLE album_musicians (
+			album_id INTEGER,
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE genres (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL UNIQUE,
+			parent_id INTEGER
+		);
+		CREATE TABLE genre_aliases (
+			key TEXT PRIMARY KEY,
+			alias TEXT NOT NULL,
+			genre_id INTEGER NOT NULL
+		);
+		CREATE TABLE album_genres (
+			album_id INTEGER NOT NULL,
+			genre_id INTEGER NOT NULL,
+			PRIMARY KEY (album_id, genre_id)
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	albumRepo := &repositories.AlbumRepository{DB: db}
+	return db, &services.ImportService{
+		AlbumRepo:    albumRepo,
+		MusicianRepo: &repositories.MusicianRepository{DB: db},
+		Genres:       &services.GenreService{Repo: &repositories.GenreRepository{DB: db}, AlbumRepo: albumRepo},
+		BatchSize:    2,
+	}
+}
+
+func TestImportAlbumsCSV(t *testing.T) {
+	db, service := setupTestImportService(t)
+
+	_, err := db.Exec(`INSERT INTO albums (name, release_date, genre, price_minor, description) VALUES ('Existing Album', '2020-01-01', 'Rock', 20000, '')`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	upload := `name,release_date,genre,price,description
+First Album,2022-01-01,Rock,150,The first one
+Tiny,2022-01-01,Rock,150,Name too short
+Second Album,2022-02-01,Pop,abc,Bad price
+Existing Album,2020-01-01,Rock,200,Already there
+Third Album,2022-03-01,Jazz,300,
+First Album,2022-01-01,Rock,150,Repeated row
+Fourth Album,2022-04-01,Jazz
+`
+	report, err := service.Import(services.ImportAlbums, services.FormatCSV, strings.NewReader(upload))
+	if err != nil {
+		t.Fatalf("failed to import albums: %v", err)
+	}
+
+	if report.Created != 2 || report.Skipped != 2 || report.Failed != 3 {
+		t.Errorf("expected 2 created, 2 skipped and 3 failed, got %+v", report)
+	}
+
+	expected := []string{
+		models.ImportCreated, models.ImportFailed, models.ImportFailed, models.ImportSkipped,
+		models.ImportCreated, models.ImportSkipped, models.ImportFailed,
+	}
+	if len(report.Rows) != len(expected) {
+		t.Fatalf("expected %d row results, got %d", len(expected), len(report.Rows))
+	}
+	for i, status := range expected {
+		if report.Rows[i].Row != i+1 || report.Rows[i].Status != status {
+			t.Errorf("expected row %d to be %s, got %+v", i+1, status, report.Rows[i])
+		}
+	}
+	if report.Rows[1].Reason != "album name must be at least 5 characters long" {
+		t.Errorf("expected service validation reason, got %q", report.Rows[1].Reason)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM albums").Scan(&count); err != nil {
+		t.Fatalf("failed to count albums: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 albums in the catalog, got %d", count)
+	}
+
+	// Imported albums are tagged with the genre their genre text names
+	for _, row := range []models.ImportRowResult{report.Rows[0], report.Rows[4]} {
+		genres, err := service.Genres.GetAlbumGenres(row.ID)
+		if err != nil {
+			t.Fatalf("failed to get album genres: %v", err)
+		}
+		if len(genres) != 1 {
+			t.Errorf("row %d: expected the album to be tagged, got %+v", row.Row, genres)
+		}
+	}
+	if genres, err := service.Genres.GetGenres(); err != nil || len(genres) != 2 {
+		t.Errorf("expected rock and jazz genres, got %+v (%v)", genres, err)
+	}
+}
+
+func TestImportMusiciansAndLinksJSONL(t *testing.T) {
+	db, service := setupTestImportService(t)
+
+	_, err := db.Exec(`INSERT INTO albums (id, name, release_date, genre, price_minor, description) VALUES (1, 'First Album', '2022-01-01', 'Rock', 15000, '')`)
+	if err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+
+	musicians := `{"name": "John Doe", "musician_type": "Guitarist"}
+{"name": "Jo", "musician_type": "Drummer"}
+
+{"name": "Jane Smith", "musician_type": "Vocalist", "age": 30}
+not json
+{"name": "Jane Smith", "musician_type": "Vocalist"}
+`
+	report, err := service.Import(services.ImportMusicians, services.FormatJSONL, strings.NewReader(musicians))
+	if err != nil {
+		t.Fatalf("failed to import musicians: %v", err)
+	}
+	if report.Created != 2 || report.Failed != 3 {
+		t.Errorf("expected 2 created and 3 failed, got %+v", report)
+	}
+
+	links := `{"album_id": 1, "musician_id": 1}
+{"album_id": 1, "musician_id": 2}
+{"album_id": 1, "musician_id": 1}
+{"album_id": 9, "musician_id": 1}
+`
+	report, err = service.Import(services.ImportLinks, services.FormatJSONL, strings.NewReader(links))
+	if err != nil {
+		t.Fatalf("failed to import links: %v", err)
+	}
+	if report.Created != 2 || report.Skipped != 1 || report.Failed != 1 {
+		t.Errorf("expected 2 created, 1 skipped and 1 failed, got %+v", report)
+	}
+	if report.Rows[3].Reason != "album 9 does not exist" {
+		t.Errorf("expected missing album reason, got %q", report.Rows[3].Reason)
+	}
+}
+
+func TestImportUnsupportedEntity(t *testing.T) {
+	_, service := setupTestImportService(t)
+
+	if _, err := service.Import("labels", services.FormatCSV, strings.NewReader("")); err == nil {
+		t.Error("expected error for unsupported entity, got nil")
+	}
+}
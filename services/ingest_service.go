@@ -0,0 +1,445 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/tags"
+	"strings"
+	"time"
+)
+
+// Limits of an ingestion upload.
+const (
+	MaxIngestFiles = 100
+	// MaxIngestUploadBytes caps the size of a whole upload.
+	MaxIngestUploadBytes = 1 << 30
+)
+
+// Musician types given to musicians created from tags.
+const (
+	IngestArtistType   = "Artist"
+	IngestComposerType = "Composer"
+)
+
+// IngestFile is an uploaded audio file.
+type IngestFile struct {
+	Name   string
+	Reader io.ReaderAt
+	Size   int64
+}
+
+// IngestService adds albums, musicians and tracks to the catalog from the tags of audio files.
+// Musicians are matched by name and albums by name and artist, ignoring case. Whatever is missing is
+// created; where the tags disagree with the catalog or match it ambiguously, nothing is changed and a
+// conflict is recorded for review instead.
+type IngestService struct {
+	Repo      *repositories.IngestRepository
+	Tracks    *repositories.TrackRepository
+	Albums    *AlbumService
+	Musicians *MusicianService
+}
+
+// ingestion is the progress of ingesting one file.
+type ingestion struct {
+	result  *models.IngestFileResult
+	created bool
+	now     string
+}
+
+// Ingest reads the tags of each file and reconciles them with the catalog. Albums it creates get price
+// in currency, or models.BaseCurrency and MinAlbumPrice when those are not given. A file that cannot
+// be read or lacks the album, title, artist or track number tags is reported as failed; an error is
+// only returned when the upload itself is invalid.
+func (s *IngestService) Ingest(files []IngestFile, price float64, currency string) (*models.IngestReport, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files to ingest")
+	}
+	if len(files) > MaxIngestFiles {
+		return nil, fmt.Errorf("at most %d files can be ingested at once", MaxIngestFiles)
+	}
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if price == 0 {
+		price = MinAlbumPrice
+	}
+	if err := validateAmount(price, currency); err != nil {
+		return nil, err
+	}
+	if err := checkPriceRange(s.Albums.Prices, price, currency); err != nil {
+		return nil, err
+	}
+
+	report := &models.IngestReport{Files: []models.IngestFileResult{}}
+	for _, file := range files {
+		result := s.ingestFile(file, price, currency)
+		switch result.Status {
+		case models.IngestCreated:
+			report.Created++
+		case models.IngestMatched:
+			report.Matched++
+		case models.IngestConflicted:
+			report.Conflicts++
+		case models.IngestFailed:
+			report.Failed++
+		}
+		report.Files = append(report.Files, result)
+	}
+	return report, nil
+}
+
+func (s *IngestService) ingestFile(file IngestFile, price float64, currency string) models.IngestFileResult {
+	result := models.IngestFileResult{FileName: file.Name}
+	fail := func(err error) models.IngestFileResult {
+		result.Status = models.IngestFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	t, err := tags.Read(file.Reader, file.Size)
+	if err != nil {
+		return fail(err)
+	}
+	result.Format = t.Format
+	if err := validateIngestTags(t); err != nil {
+		return fail(err)
+	}
+
+	if err := s.check(t, price, currency); err != nil {
+		return fail(err)
+	}
+
+	in := &ingestion{result: &result, now: time.Now().UTC().Format(models.TimeFormat)}
+	if err := s.ingest(in, t, price, currency); err != nil {
+		return fail(err)
+	}
+	switch {
+	case len(result.Conflicts) > 0:
+		result.Status = models.IngestConflicted
+	case in.created:
+		result.Status = models.IngestCreated
+	default:
+		result.Status = models.IngestMatched
+	}
+	return result
+}
+
+// validateIngestTags checks a file is tagged well enough to place it in the catalog, trimming its tags.
+func validateIngestTags(t *tags.Tags) error {
+	for _, field := range []*string{&t.Title, &t.Album, &t.Artist, &t.AlbumArtist, &t.Composer, &t.Genre} {
+		*field = strings.TrimSpace(*field)
+	}
+	switch {
+	case t.Album == "":
+		return errors.New("file has no album tag")
+	case t.Title == "":
+		return errors.New("file has no title tag")
+	case t.AlbumArtist == "" && t.Artist == "":
+		return errors.New("file has no artist tag")
+	case t.Track <= 0:
+		return errors.New("file has no track number tag")
+	}
+	return nil
+}
+
+// check validates the musicians and album a file's tags would create before anything is written, so
+// that a file failing on, say, a short album name does not leave its artist behind.
+func (s *IngestService) check(t *tags.Tags, price float64, currency string) error {
+	for _, name := range []string{t.AlbumArtist, t.Artist, t.Composer} {
+		if name == "" {
+			continue
+		}
+		found, err := s.Repo.FindMusicians(name)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			if err := ValidateMusician(&models.Musician{Name: name}); err != nil {
+				return err
+			}
+		}
+	}
+
+	found, err := s.Repo.FindAlbums(t.Album)
+	if err != nil || len(found) > 0 {
+		return err
+	}
+	return ValidateAlbum(&models.Album{Name: t.Album, Price: price, Currency: currency})
+}
+
+func (s *IngestService) ingest(in *ingestion, t *tags.Tags, price float64, currency string) error {
+	artistName := t.AlbumArtist
+	if artistName == "" {
+		artistName = t.Artist
+	}
+	artist, err := s.musician(in, artistName, IngestArtistType)
+	if err != nil || artist == nil {
+		return err
+	}
+
+	album, err := s.album(in, t, artist, price, currency)
+	if err != nil || album == nil {
+		return err
+	}
+	in.result.AlbumID = album.ID
+
+	matched, err := s.track(in, t, album)
+	if err != nil || !matched {
+		return err
+	}
+
+	var credits []models.Credit
+	if t.Artist != "" && !strings.EqualFold(t.Artist, artist.Name) {
+		performer, err := s.musician(in, t.Artist, IngestArtistType)
+		if err != nil {
+			return err
+		}
+		if performer != nil {
+			credits = append(credits, models.Credit{MusicianID: performer.ID, Role: models.CreditPerformer, Track: t.Track})
+		}
+	}
+	if t.Composer != "" {
+		composer, err := s.musician(in, t.Composer, IngestComposerType)
+		if err != nil {
+			return err
+		}
+		if composer != nil {
+			credits = append(credits, models.Credit{MusicianID: composer.ID, Role: models.CreditComposer, Track: t.Track})
+		}
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	return s.Albums.LinkMusiciansToAlbum(album.ID, credits)
+}
+
+// musician finds the musician named in a tag, creating it when there is none. It returns nil when
+// several musicians have the name, recording a conflict.
+func (s *IngestService) musician(in *ingestion, name, musicianType string) (*models.Musician, error) {
+	found, err := s.Repo.FindMusicians(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var musician *models.Musician
+	switch len(found) {
+	case 0:
+		musician = &models.Musician{Name: name, MusicianType: musicianType}
+		if err := s.Musicians.CreateMusician(musician); err != nil {
+			return nil, err
+		}
+		in.created = true
+	case 1:
+		musician = &found[0]
+	default:
+		ids := make([]uint, len(found))
+		for i, m := range found {
+			ids[i] = m.ID
+		}
+		return nil, s.conflict(in, models.IngestConflict{
+			Kind:     models.ConflictMusician,
+			Field:    "name",
+			Existing: "musicians " + joinIDs(ids),
+			Incoming: name,
+		})
+	}
+
+	for _, id := range in.result.MusicianIDs {
+		if id == musician.ID {
+			return musician, nil
+		}
+	}
+	in.result.MusicianIDs = append(in.result.MusicianIDs, musician.ID)
+	return musician, nil
+}
+
+// album finds the album named in the tags that credits artist, creating it when there is none. It
+// returns nil when the match is ambiguous or disagrees with the tags, recording a conflict.
+func (s *IngestService) album(in *ingestion, t *tags.Tags, artist *models.Musician, price float64, currency string) (*models.Album, error) {
+	candidates, err := s.Repo.FindAlbums(t.Album)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(candidates))
+	for i, album := range candidates {
+		ids[i] = album.ID
+	}
+	credited, err := s.Musicians.GetMusiciansByAlbums(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var byArtist, uncredited []models.Album
+	for _, album := range candidates {
+		musicians := credited[album.ID]
+		if len(musicians) == 0 {
+			uncredited = append(uncredited, album)
+		}
+		for _, musician := range musicians {
+			if musician.ID == artist.ID {
+				byArtist = append(byArtist, album)
+				break
+			}
+		}
+	}
+
+	releaseDate := t.ReleaseDate()
+	switch {
+	case len(byArtist) == 1:
+		album := byArtist[0]
+		if releaseDate != "" && len(album.ReleaseDate) >= 4 && releaseDate[:4] != album.ReleaseDate[:4] {
+			return nil, s.conflict(in, models.IngestConflict{
+				Kind:     models.ConflictAlbum,
+				EntityID: album.ID,
+				Field:    "release_date",
+				Existing: album.ReleaseDate,
+				Incoming: releaseDate,
+			})
+		}
+		return &album, nil
+	case len(byArtist) > 1:
+		matches := make([]uint, len(byArtist))
+		for i, album := range byArtist {
+			matches[i] = album.ID
+		}
+		return nil, s.conflict(in, models.IngestConflict{
+			Kind:     models.ConflictAlbum,
+			Field:    "name",
+			Existing: "albums " + joinIDs(matches),
+			Incoming: t.Album,
+		})
+	case len(uncredited) > 0:
+		conflict := models.IngestConflict{
+			Kind:     models.ConflictAlbum,
+			Field:    "musicians",
+			Existing: "no musicians credited",
+			Incoming: artist.Name,
+		}
+		if len(uncredited) == 1 {
+			conflict.EntityID = uncredited[0].ID
+		}
+		return nil, s.conflict(in, conflict)
+	}
+
+	album := &models.Album{
+		Name:        t.Album,
+		ReleaseDate: releaseDate,
+		Genre:       t.Genre,
+		Price:       price,
+		Currency:    currency,
+	}
+	if err := s.Albums.CreateAlbum(album); err != nil {
+		return nil, err
+	}
+	if err := s.Albums.LinkMusiciansToAlbum(album.ID, []models.Credit{{MusicianID: artist.ID, Role: models.CreditPerformer}}); err != nil {
+		return nil, err
+	}
+	in.created = true
+	return album, nil
+}
+
+// track finds the track at the tagged position of album, creating it when there is none. It returns
+// false when the track there has another title, recording a conflict.
+func (s *IngestService) track(in *ingestion, t *tags.Tags, album *models.Album) (bool, error) {
+	disc := tagDisc(t)
+	existing, err := s.Tracks.GetTrack(album.ID, disc, t.Track)
+	if errors.Is(err, sql.ErrNoRows) {
+		track := &models.Track{AlbumID: album.ID, Disc: disc, Number: t.Track, Title: t.Title, DurationSeconds: t.DurationSeconds}
+		if err := s.Tracks.CreateTrack(track); err != nil {
+			return false, err
+		}
+		in.result.TrackID = track.ID
+		in.created = true
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !strings.EqualFold(existing.Title, t.Title) {
+		return false, s.conflict(in, models.IngestConflict{
+			Kind:     models.ConflictTrack,
+			EntityID: existing.ID,
+			Field:    "title",
+			Existing: existing.Title,
+			Incoming: t.Title,
+		})
+	}
+	in.result.TrackID = existing.ID
+	return true, nil
+}
+
+// tagDisc returns the tagged disc number, 1 for files without one.
+func tagDisc(t *tags.Tags) int {
+	if t.Disc <= 0 {
+		return 1
+	}
+	return t.Disc
+}
+
+func (s *IngestService) conflict(in *ingestion, conflict models.IngestConflict) error {
+	conflict.FileName = in.result.FileName
+	conflict.CreatedAt = in.now
+	if err := s.Repo.CreateConflict(&conflict); err != nil {
+		return err
+	}
+	in.result.Conflicts = append(in.result.Conflicts, conflict)
+	return nil
+}
+
+func joinIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetConflicts returns the ingestion conflicts with a status, the open ones when status is empty.
+func (s *IngestService) GetConflicts(status string) ([]models.IngestConflict, error) {
+	if status == "" {
+		status = models.ConflictOpen
+	}
+	if err := validateConflictStatus(status); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetConflicts(status)
+}
+
+// ReviewConflict sets the status of a conflict, recording when it was resolved or dismissed. Reopening
+// a conflict clears that time. It returns sql.ErrNoRows if the conflict does not exist.
+func (s *IngestService) ReviewConflict(id uint, status string) (*models.IngestConflict, error) {
+	if err := validateConflictStatus(status); err != nil {
+		return nil, err
+	}
+	reviewedAt := ""
+	if status != models.ConflictOpen {
+		reviewedAt = time.Now().UTC().Format(models.TimeFormat)
+	}
+	if err := s.Repo.ReviewConflict(id, status, reviewedAt); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetConflictByID(id)
+}
+
+func validateConflictStatus(status string) error {
+	for _, known := range models.ConflictStatuses {
+		if status == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("conflict status must be one of %s", strings.Join(models.ConflictStatuses, ", "))
+}
+
+// GetTracks returns the tracks of an album. It returns sql.ErrNoRows if the album does not exist.
+func (s *IngestService) GetTracks(albumID uint) ([]models.Track, error) {
+	if _, err := s.Albums.Repo.GetAlbumByID(albumID); err != nil {
+		return nil, err
+	}
+	return s.Tracks.GetTracks(albumID)
+}
@@ -0,0 +1,299 @@
+package services_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func setupIngestService(t *testing.T) *services.IngestService {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE tracks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			disc INTEGER NOT NULL DEFAULT 1,
+			number INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (album_id, disc, number)
+		);
+		CREATE TABLE library_files (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			path TEXT NOT NULL UNIQUE,
+			size INTEGER NOT NULL,
+			modified_ns INTEGER NOT NULL,
+			hash TEXT NOT NULL,
+			status TEXT NOT NULL,
+			album_id INTEGER NOT NULL DEFAULT 0,
+			track_id INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			scanned_at TEXT NOT NULL,
+			missing_since TEXT
+		);
+		CREATE TABLE ingest_conflicts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			file_name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			entity_id INTEGER NOT NULL DEFAULT 0,
+			field TEXT NOT NULL,
+			existing TEXT NOT NULL,
+			incoming TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open',
+			created_at TEXT NOT NULL,
+			reviewed_at TEXT
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	db := albumRepo.DB
+	return &services.IngestService{
+		Repo:      &repositories.IngestRepository{DB: db},
+		Tracks:    &repositories.TrackRepository{DB: db},
+		Albums:    &services.AlbumService{Repo: albumRepo},
+		Musicians: &services.MusicianService{Repo: &repositories.MusicianRepository{DB: db}},
+	}
+}
+
+// id3File builds an MP3 file holding only an ID3v2.3 tag with the given text frames.
+func id3File(frames map[string]string) []byte {
+	var body bytes.Buffer
+	for _, id := range []string{"TIT2", "TALB", "TPE1", "TPE2", "TCOM", "TCON", "TYER", "TRCK", "TPOS"} {
+		text, ok := frames[id]
+		if !ok {
+			continue
+		}
+		body.WriteString(id)
+		binary.Write(&body, binary.BigEndian, uint32(len(text)+1))
+		body.Write([]byte{0, 0, 0})
+		body.WriteString(text)
+	}
+
+	size := body.Len()
+	header := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}
+	return append(header, body.Bytes()...)
+}
+
+func ingestFile(name string, data []byte) services.IngestFile {
+	return services.IngestFile{Name: name, Reader: bytes.NewReader(data), Size: int64(len(data))}
+}
+
+func trackFrames(title, number string) map[string]string {
+	return map[string]string{"TIT2": title, "TALB": "First Light", "TPE1": "The Lanterns", "TYER": "1999", "TRCK": number, "TCON": "Rock"}
+}
+
+func TestIngestCreatesAndMatches(t *testing.T) {
+	service := setupIngestService(t)
+
+	guest := trackFrames("Night Drive", "2/10")
+	guest["TPE1"] = "Guest Singer"
+	guest["TPE2"] = "The Lanterns"
+	guest["TCOM"] = "Ann Writer"
+
+	report, err := service.Ingest([]services.IngestFile{
+		ingestFile("01.mp3", id3File(trackFrames("Dawn", "1/10"))),
+		ingestFile("02.mp3", id3File(guest)),
+		ingestFile("01-copy.mp3", id3File(trackFrames("DAWN", "1"))),
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+	if report.Created != 2 || report.Matched != 1 || report.Conflicts != 0 || report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	first, second, copy := report.Files[0], report.Files[1], report.Files[2]
+	if first.Status != models.IngestCreated || first.Format != "id3v2" || first.AlbumID == 0 || first.TrackID == 0 {
+		t.Errorf("unexpected first file: %+v", first)
+	}
+	if second.AlbumID != first.AlbumID || len(second.MusicianIDs) != 3 {
+		t.Errorf("expected the second file on the same album crediting 3 musicians, got %+v", second)
+	}
+	if copy.Status != models.IngestMatched || copy.TrackID != first.TrackID {
+		t.Errorf("expected the copy to match the first track, got %+v", copy)
+	}
+
+	album, err := service.Albums.Repo.GetAlbumByID(first.AlbumID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if album.Name != "First Light" || album.ReleaseDate != "1999-01-01" || album.Price != services.MinAlbumPrice || album.Currency != models.BaseCurrency {
+		t.Errorf("unexpected album: %+v", album)
+	}
+
+	tracks, err := service.GetTracks(first.AlbumID)
+	if err != nil {
+		t.Fatalf("failed to get tracks: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Title != "Dawn" || tracks[1].Title != "Night Drive" {
+		t.Errorf("unexpected tracks: %+v", tracks)
+	}
+
+	musicians, err := service.Musicians.GetMusiciansByAlbum(first.AlbumID, "")
+	if err != nil {
+		t.Fatalf("failed to get musicians: %v", err)
+	}
+	if len(musicians) != 3 {
+		t.Errorf("expected the band, the guest and the composer credited, got %+v", musicians)
+	}
+
+	if _, err := service.GetTracks(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing album, got %v", err)
+	}
+}
+
+func TestIngestConflicts(t *testing.T) {
+	service := setupIngestService(t)
+
+	if _, err := service.Ingest([]services.IngestFile{ingestFile("01.mp3", id3File(trackFrames("Dawn", "1")))}, 0, ""); err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+
+	retitled := trackFrames("Sunrise", "1")
+	redated := trackFrames("Dawn", "1")
+	redated["TYER"] = "2005"
+	report, err := service.Ingest([]services.IngestFile{
+		ingestFile("retitled.mp3", id3File(retitled)),
+		ingestFile("redated.mp3", id3File(redated)),
+		ingestFile("retitled-again.mp3", id3File(retitled)),
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+	if report.Conflicts != 3 || report.Created != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	title := report.Files[0].Conflicts[0]
+	if title.Kind != models.ConflictTrack || title.Field != "title" || title.Existing != "Dawn" || title.Incoming != "Sunrise" {
+		t.Errorf("unexpected title conflict: %+v", title)
+	}
+	date := report.Files[1].Conflicts[0]
+	if date.Kind != models.ConflictAlbum || date.Field != "release_date" || date.Existing != "1999-01-01" || date.Incoming != "2005-01-01" {
+		t.Errorf("unexpected release date conflict: %+v", date)
+	}
+	if report.Files[2].Conflicts[0].ID != title.ID {
+		t.Error("expected the same disagreement to reuse the open conflict")
+	}
+
+	tracks, _ := service.GetTracks(report.Files[0].AlbumID)
+	if len(tracks) != 1 || tracks[0].Title != "Dawn" {
+		t.Errorf("expected conflicts to leave the catalog unchanged, got %+v", tracks)
+	}
+
+	open, err := service.GetConflicts("")
+	if err != nil || len(open) != 2 {
+		t.Fatalf("expected 2 open conflicts, got %v, %v", open, err)
+	}
+	reviewed, err := service.ReviewConflict(title.ID, models.ConflictDismissed)
+	if err != nil {
+		t.Fatalf("failed to review conflict: %v", err)
+	}
+	if reviewed.Status != models.ConflictDismissed || reviewed.ReviewedAt == "" {
+		t.Errorf("unexpected reviewed conflict: %+v", reviewed)
+	}
+	if _, err := service.ReviewConflict(title.ID, "ignored"); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+	if _, err := service.ReviewConflict(99, models.ConflictResolved); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing conflict, got %v", err)
+	}
+	if _, err := service.GetConflicts("closed"); err == nil {
+		t.Error("expected an unknown status filter to be rejected")
+	}
+}
+
+func TestIngestAmbiguousMatches(t *testing.T) {
+	service := setupIngestService(t)
+	_, err := service.Repo.DB.Exec(`
+		INSERT INTO musicians (id, name, musician_type) VALUES (1, 'Twin Name', 'Artist'), (2, 'twin name', 'Artist'), (3, 'The Lanterns', 'Artist');
+		INSERT INTO albums (id, name, release_date, genre, description, price_minor) VALUES (1, 'First Light', '1999-01-01', '', '', 10000);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed catalog: %v", err)
+	}
+
+	twins := trackFrames("Dawn", "1")
+	twins["TPE1"] = "Twin Name"
+	report, err := service.Ingest([]services.IngestFile{
+		ingestFile("twins.mp3", id3File(twins)),
+		ingestFile("uncredited.mp3", id3File(trackFrames("Dawn", "1"))),
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+
+	musician := report.Files[0].Conflicts
+	if len(musician) != 1 || musician[0].Kind != models.ConflictMusician || musician[0].EntityID != 0 || musician[0].Existing != "musicians 1, 2" {
+		t.Errorf("unexpected musician conflict: %+v", musician)
+	}
+	album := report.Files[1].Conflicts
+	if len(album) != 1 || album[0].Kind != models.ConflictAlbum || album[0].EntityID != 1 || album[0].Field != "musicians" {
+		t.Errorf("unexpected album conflict: %+v", album)
+	}
+
+	var albums int
+	service.Repo.DB.QueryRow("SELECT COUNT(*) FROM albums").Scan(&albums)
+	if albums != 1 {
+		t.Errorf("expected no album to be created, got %d albums", albums)
+	}
+}
+
+func TestIngestFailures(t *testing.T) {
+	service := setupIngestService(t)
+
+	untitled := trackFrames("", "1")
+	unnumbered := trackFrames("Dawn", "")
+	shortName := trackFrames("Dawn", "1")
+	shortName["TALB"] = "Hi"
+	shortComposer := trackFrames("Dawn", "1")
+	shortComposer["TCOM"] = "Al"
+	report, err := service.Ingest([]services.IngestFile{
+		ingestFile("notes.txt", []byte("not an audio file")),
+		ingestFile("untitled.mp3", id3File(untitled)),
+		ingestFile("unnumbered.mp3", id3File(unnumbered)),
+		ingestFile("short.mp3", id3File(shortName)),
+		ingestFile("composer.mp3", id3File(shortComposer)),
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+	if report.Failed != 5 {
+		t.Fatalf("expected every file to fail, got %+v", report)
+	}
+	for _, file := range report.Files {
+		if file.Status != models.IngestFailed || file.Error == "" {
+			t.Errorf("expected %s to fail with a reason, got %+v", file.FileName, file)
+		}
+	}
+
+	// Failed files leave nothing behind
+	var rows int
+	service.Repo.DB.QueryRow("SELECT (SELECT COUNT(*) FROM musicians) + (SELECT COUNT(*) FROM albums) + (SELECT COUNT(*) FROM tracks)").Scan(&rows)
+	if rows != 0 {
+		t.Errorf("expected failed files to create nothing, got %d rows", rows)
+	}
+
+	if _, err := service.Ingest(nil, 0, ""); err == nil {
+		t.Error("expected an empty upload to be rejected")
+	}
+	if _, err := service.Ingest([]services.IngestFile{ingestFile("a.mp3", nil)}, 5, ""); err == nil {
+		t.Error("expected a price below the minimum to be rejected")
+	}
+	if _, err := service.Ingest([]services.IngestFile{ingestFile("a.mp3", nil)}, 0, "XYZ"); err == nil {
+		t.Error("expected an unsupported currency to be rejected")
+	}
+}
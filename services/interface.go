@@ -1,7 +1,9 @@
 package services
 
 import (
+	"io"
 	"jukebox/models"
+	"time"
 )
 
 // AlbumServiceInterface defines the methods that must be implemented by any album service.
@@ -9,9 +11,10 @@ type AlbumServiceInterface interface {
 	CreateAlbum(album *models.Album) error
 	UpdateAlbum(album *models.Album) error
 	DeleteAlbum(albumID uint) error
-	GetAlbums() ([]models.Album, error)
-	LinkMusiciansToAlbum(albumID uint, musicianIDs []uint) error
-	GetAlbumsByMusician(musicianID uint) ([]models.Album, error)
+	GetAlbums(filter models.AlbumFilter) ([]models.Album, error)
+	LinkMusiciansToAlbum(albumID uint, credits []models.Credit) error
+	GetAlbumsByMusician(musicianID uint, role, currency string) ([]models.CreditedAlbum, error)
+	GetAlbumsByMusicians(musicianIDs []uint) (map[uint][]models.Album, error)
 }
 
 // MusicianServiceInterface defines the methods that must be implemented by any musician service.
@@ -20,5 +23,156 @@ type MusicianServiceInterface interface {
 	UpdateMusician(musician *models.Musician) error
 	DeleteMusician(musicianID uint) error
 	GetMusicians() ([]models.Musician, error)
-	GetMusiciansByAlbum(albumID uint) ([]models.Musician, error)
+	GetMusiciansByAlbum(albumID uint, role string) ([]models.CreditedMusician, error)
+	GetMusiciansByAlbums(albumIDs []uint) (map[uint][]models.Musician, error)
+}
+
+// ImportServiceInterface defines the methods that must be implemented by any bulk import service.
+type ImportServiceInterface interface {
+	Import(entity, format string, r io.Reader) (*models.ImportReport, error)
+}
+
+// ExportServiceInterface defines the methods that must be implemented by any catalog export service.
+type ExportServiceInterface interface {
+	Export(format string, filter models.AlbumFilter, w io.Writer) error
+}
+
+// MembershipServiceInterface defines the methods that must be implemented by any band membership service.
+type MembershipServiceInterface interface {
+	AddMember(membership *models.Membership) error
+	UpdateMembership(membership *models.Membership) error
+	RemoveMember(groupID, membershipID uint) error
+	GetMembers(groupID uint, at string) ([]models.Membership, error)
+	GetAlbumLineup(albumID uint) ([]models.Membership, error)
+}
+
+// LabelServiceInterface defines the methods that must be implemented by any record label service.
+type LabelServiceInterface interface {
+	CreateLabel(label *models.Label) error
+	UpdateLabel(label *models.Label) error
+	DeleteLabel(labelID uint) error
+	GetLabels() ([]models.Label, error)
+	GetLabel(labelID uint) (*models.Label, error)
+	GetAlbumsByLabel(labelID uint) ([]models.Album, error)
+}
+
+// GenreServiceInterface defines the methods that must be implemented by any genre taxonomy service.
+type GenreServiceInterface interface {
+	CreateGenre(genre *models.Genre) error
+	UpdateGenre(genre *models.Genre) error
+	DeleteGenre(genreID uint) error
+	GetGenres() ([]models.Genre, error)
+	GetGenre(genreID uint) (*models.Genre, error)
+	GetAlbumGenres(albumID uint) ([]models.Genre, error)
+	SetAlbumGenres(albumID uint, names []string) ([]models.Genre, error)
+}
+
+// EditionServiceInterface defines the methods that must be implemented by any album edition service.
+type EditionServiceInterface interface {
+	AddEdition(edition *models.Edition) error
+	UpdateEdition(edition *models.Edition) error
+	RemoveEdition(albumID, editionID uint) error
+	GetEditions(albumID uint) ([]models.Edition, error)
+}
+
+// PriceServiceInterface defines the methods that must be implemented by any exchange rate and price list service.
+type PriceServiceInterface interface {
+	UploadExchangeRates(rates []models.ExchangeRate) ([]models.ExchangeRate, error)
+	GetExchangeRates() ([]models.ExchangeRate, error)
+	SetAlbumPrice(price *models.AlbumPrice) error
+	RemoveAlbumPrice(albumID uint, currency string) error
+	GetAlbumPrices(albumID uint) ([]models.AlbumPrice, error)
+}
+
+// PromotionServiceInterface defines the methods that must be implemented by any promotion and price schedule service.
+type PromotionServiceInterface interface {
+	CreatePromotion(promotion *models.Promotion) error
+	UpdatePromotion(promotion *models.Promotion) error
+	DeletePromotion(promotionID uint) error
+	GetPromotions() ([]models.Promotion, error)
+	GetPromotion(promotionID uint) (*models.Promotion, error)
+	SchedulePrice(schedule *models.PriceSchedule) error
+	CancelPriceSchedule(albumID, scheduleID uint) error
+	GetPriceSchedules(albumID uint) ([]models.PriceSchedule, error)
+	GetEffectivePrice(albumID uint, at time.Time) (*models.EffectivePrice, error)
+}
+
+// InventoryServiceInterface defines the methods that must be implemented by any inventory service.
+type InventoryServiceInterface interface {
+	RecordMovement(movement *models.StockMovement) error
+	GetMovements(albumID uint) ([]models.StockMovement, error)
+	SetLowStockThreshold(level *models.StockLevel) error
+	GetStock(albumID uint) ([]models.StockLevel, error)
+	GetLowStock() ([]models.StockLevel, error)
+}
+
+// OrderServiceInterface defines the methods that must be implemented by any cart and order service.
+type OrderServiceInterface interface {
+	CreateCart(cart *models.Cart) error
+	GetCart(cartID uint) (*models.Cart, error)
+	SetCartItem(cartID uint, item models.CartItem) (*models.Cart, error)
+	Checkout(cartID uint, checkout models.Checkout) (*models.Order, error)
+	GetOrder(orderID uint) (*models.Order, error)
+	GetOrders(status string) ([]models.Order, error)
+	PayOrder(orderID uint) (*models.Order, error)
+	ShipOrder(orderID uint) (*models.Order, error)
+	CancelOrder(orderID uint) (*models.Order, error)
+	UploadTaxRates(rates []models.TaxRate) ([]models.TaxRate, error)
+	GetTaxRates() ([]models.TaxRate, error)
+}
+
+// CustomerServiceInterface defines the methods that must be implemented by any customer account service.
+type CustomerServiceInterface interface {
+	Register(registration models.Registration) (*models.Customer, error)
+	Login(credentials models.Credentials) (*models.Session, error)
+	Authenticate(token string) (*models.Customer, error)
+	Logout(token string) error
+	GetLibrary(customerID uint) ([]models.LibraryEntry, error)
+	AddToLibrary(customerID, albumID uint) ([]models.LibraryEntry, error)
+	RemoveFromLibrary(customerID, albumID uint) error
+	GetOrders(customerID uint) ([]models.Order, error)
+}
+
+// ReviewServiceInterface defines the methods that must be implemented by any album review service.
+type ReviewServiceInterface interface {
+	CreateReview(review *models.Review) error
+	UpdateReview(review *models.Review) error
+	DeleteReview(albumID, reviewID, customerID uint) error
+	ModerateReview(reviewID uint, status string) (*models.Review, error)
+	GetAlbumReviews(albumID uint, page, perPage int) (*models.ReviewPage, error)
+	GetReviews(status string, page, perPage int) (*models.ReviewPage, error)
+}
+
+// RecommendationServiceInterface defines the methods that must be implemented by any recommendation service.
+type RecommendationServiceInterface interface {
+	GetRelatedAlbums(albumID uint, limit int, currency string) ([]models.RelatedAlbum, error)
+	GetRelatedMusicians(musicianID uint, limit int) ([]models.RelatedMusician, error)
+}
+
+// CollaborationServiceInterface defines the methods that must be implemented by any collaboration graph service.
+type CollaborationServiceInterface interface {
+	GetCollaborators(musicianID uint) ([]models.Collaborator, error)
+	GetPath(fromID, toID uint) (*models.CollaborationPath, error)
+	GetStats() (*models.CollaborationStats, error)
+}
+
+// ChartServiceInterface defines the methods that must be implemented by any chart service.
+type ChartServiceInterface interface {
+	GetChart(chartType, period, genre string, limit int) (*models.Chart, error)
+}
+
+// PlayServiceInterface defines the methods that must be implemented by any play service.
+type PlayServiceInterface interface {
+	RecordPlay(customerID uint, play *models.Play) (bool, error)
+	RecordPlays(customerID uint, batch models.PlayBatch) (*models.PlayBatchResult, error)
+	GetHistory(customerID uint, from, to string, page, perPage int) (*models.PlayPage, error)
+	GetTop(topType string, customerID uint, from, to string, limit int) (*models.PlayStats, error)
+}
+
+// IngestServiceInterface defines the methods that must be implemented by any audio ingestion service.
+type IngestServiceInterface interface {
+	Ingest(files []IngestFile, price float64, currency string) (*models.IngestReport, error)
+	GetConflicts(status string) ([]models.IngestConflict, error)
+	ReviewConflict(id uint, status string) (*models.IngestConflict, error)
+	GetTracks(albumID uint) ([]models.Track, error)
 }
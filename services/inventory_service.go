@@ -0,0 +1,116 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInsufficientStock is returned when a movement would take the stock level below zero.
+var ErrInsufficientStock = repositories.ErrInsufficientStock
+
+type InventoryService struct {
+	Repo      *repositories.InventoryRepository
+	AlbumRepo *repositories.AlbumRepository
+	Editions  *repositories.EditionRepository
+}
+
+// validateItem checks that an album is stocked and sold as a whole when it has no editions, and by edition
+// when it has, returning the album and the edition if any. A missing album or edition is reported with an
+// error wrapping sql.ErrNoRows.
+func validateItem(albums *repositories.AlbumRepository, editions *repositories.EditionRepository, albumID, editionID uint) (*models.Album, *models.Edition, error) {
+	album, err := albums.GetAlbumByID(albumID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return nil, nil, err
+	}
+
+	albumEditions, err := editions.GetEditionsByAlbum(albumID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if editionID == 0 {
+		if len(albumEditions) > 0 {
+			return nil, nil, fmt.Errorf("album %d is sold per edition; edition_id is required", albumID)
+		}
+		return album, nil, nil
+	}
+	for _, edition := range albumEditions {
+		if edition.ID == editionID {
+			return album, &edition, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("album %d has no edition %d: %w", albumID, editionID, sql.ErrNoRows)
+}
+
+// RecordMovement validates a stock movement and applies it to the stock level. Receipts, sales and returns
+// take a positive quantity; adjustments add or, when negative, remove copies. A movement that would take the
+// stock below zero is rejected with ErrInsufficientStock.
+func (s *InventoryService) RecordMovement(movement *models.StockMovement) error {
+	movement.Kind = strings.ToLower(strings.TrimSpace(movement.Kind))
+	if !slices.Contains(models.MovementKinds, movement.Kind) {
+		return fmt.Errorf("kind must be one of %s", strings.Join(models.MovementKinds, ", "))
+	}
+	if movement.Kind == models.MovementAdjust {
+		if movement.Quantity == 0 {
+			return errors.New("quantity of an adjustment must not be 0")
+		}
+	} else if movement.Quantity <= 0 {
+		return fmt.Errorf("quantity of a %s movement must be positive", movement.Kind)
+	}
+	movement.Note = strings.TrimSpace(movement.Note)
+
+	if _, _, err := validateItem(s.AlbumRepo, s.Editions, movement.AlbumID, movement.EditionID); err != nil {
+		return err
+	}
+
+	movement.CreatedAt = time.Now().UTC().Format(models.TimeFormat)
+	return s.Repo.RecordMovement(movement)
+}
+
+// GetMovements retrieves the stock ledger of an album and its editions, oldest first.
+func (s *InventoryService) GetMovements(albumID uint) ([]models.StockMovement, error) {
+	if err := s.albumExists(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetMovements(albumID)
+}
+
+// SetLowStockThreshold validates and sets the low-stock threshold of an album or one of its editions.
+func (s *InventoryService) SetLowStockThreshold(level *models.StockLevel) error {
+	if level.LowStockThreshold < 0 {
+		return errors.New("low_stock_threshold must not be negative")
+	}
+	if _, _, err := validateItem(s.AlbumRepo, s.Editions, level.AlbumID, level.EditionID); err != nil {
+		return err
+	}
+	return s.Repo.SetLowStockThreshold(level)
+}
+
+// GetStock retrieves the stock levels of an album and its editions.
+func (s *InventoryService) GetStock(albumID uint) ([]models.StockLevel, error) {
+	if err := s.albumExists(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetStockLevels(albumID)
+}
+
+// GetLowStock retrieves every stock level at or below its low-stock threshold.
+func (s *InventoryService) GetLowStock() ([]models.StockLevel, error) {
+	return s.Repo.GetLowStockLevels()
+}
+
+func (s *InventoryService) albumExists(albumID uint) error {
+	_, err := s.AlbumRepo.GetAlbumByID(albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("album %d does not exist: %w", albumID, err)
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func TestInventoryServiceValidation(t *testing.T) {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE editions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			format TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			barcode TEXT UNIQUE,
+			release_date TEXT NOT NULL,
+			price_minor INTEGER NOT NULL
+		);
+		CREATE TABLE price_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			price_minor INTEGER NOT NULL,
+			starts_at TEXT NOT NULL,
+			applied_at TEXT
+		);
+		CREATE TABLE stock_levels (
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL DEFAULT 0,
+			low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (album_id, edition_id)
+		);
+		CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			quantity_change INTEGER NOT NULL,
+			balance INTEGER NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	editionRepo := &repositories.EditionRepository{DB: albumRepo.DB}
+	service := &services.InventoryService{Repo: &repositories.InventoryRepository{DB: albumRepo.DB}, AlbumRepo: albumRepo, Editions: editionRepo}
+
+	single := &models.Album{Name: "Single Release", ReleaseDate: "2020-01-01", Price: 200}
+	editioned := &models.Album{Name: "Many Editions", ReleaseDate: "2020-01-01", Price: 200}
+	for _, album := range []*models.Album{single, editioned} {
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	vinyl := &models.Edition{AlbumID: editioned.ID, Format: models.FormatVinyl, ReleaseDate: "2020-01-01", Price: 300}
+	if err := editionRepo.CreateEdition(vinyl); err != nil {
+		t.Fatalf("failed to create edition: %v", err)
+	}
+
+	movement := &models.StockMovement{AlbumID: single.ID, Kind: " Receive ", Quantity: 2, Note: " first delivery "}
+	if err := service.RecordMovement(movement); err != nil {
+		t.Fatalf("failed to record movement: %v", err)
+	}
+	if movement.Kind != models.MovementReceive || movement.Note != "first delivery" || movement.Balance != 2 || movement.CreatedAt == "" {
+		t.Errorf("expected a normalised movement, got %+v", movement)
+	}
+	if err := service.RecordMovement(&models.StockMovement{AlbumID: editioned.ID, EditionID: vinyl.ID, Kind: models.MovementReceive, Quantity: 1}); err != nil {
+		t.Fatalf("failed to record edition movement: %v", err)
+	}
+
+	invalid := []models.StockMovement{
+		{AlbumID: single.ID, Kind: "steal", Quantity: 1},
+		{AlbumID: single.ID, Kind: models.MovementSell, Quantity: 0},
+		{AlbumID: single.ID, Kind: models.MovementReturn, Quantity: -1},
+		{AlbumID: single.ID, Kind: models.MovementAdjust, Quantity: 0},
+		{AlbumID: editioned.ID, Kind: models.MovementReceive, Quantity: 1},
+	}
+	for _, movement := range invalid {
+		if err := service.RecordMovement(&movement); err == nil {
+			t.Errorf("expected %+v to be rejected", movement)
+		}
+	}
+
+	for _, movement := range []models.StockMovement{
+		{AlbumID: 99, Kind: models.MovementReceive, Quantity: 1},
+		{AlbumID: single.ID, EditionID: vinyl.ID, Kind: models.MovementReceive, Quantity: 1},
+	} {
+		if err := service.RecordMovement(&movement); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for %+v, got %v", movement, err)
+		}
+	}
+
+	if err := service.RecordMovement(&models.StockMovement{AlbumID: single.ID, Kind: models.MovementAdjust, Quantity: -3}); !errors.Is(err, services.ErrInsufficientStock) {
+		t.Errorf("expected ErrInsufficientStock, got %v", err)
+	}
+	if err := service.SetLowStockThreshold(&models.StockLevel{AlbumID: single.ID, LowStockThreshold: -1}); err == nil {
+		t.Error("expected a negative threshold to be rejected")
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"regexp"
+	"strings"
+)
+
+// MinLabelNameLength is the shortest label name accepted.
+const MinLabelNameLength = 2
+
+// ErrLabelInUse is returned when deleting a label that albums or imprints still refer to.
+var ErrLabelInUse = errors.New("label still has albums or imprints")
+
+// countryCode matches an ISO 3166-1 alpha-2 country code.
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type LabelService struct {
+	Repo *repositories.LabelRepository
+}
+
+// validateLabel checks the name, country and parent of a label. Labels cannot be their own ancestor.
+func (s *LabelService) validateLabel(label *models.Label) error {
+	if len(strings.TrimSpace(label.Name)) < MinLabelNameLength {
+		return fmt.Errorf("label name must be at least %d characters long", MinLabelNameLength)
+	}
+	label.Country = strings.ToUpper(label.Country)
+	if label.Country != "" && !countryCode.MatchString(label.Country) {
+		return errors.New("label country must be an ISO 3166-1 alpha-2 code such as GB")
+	}
+
+	// Walk up from the parent; reaching the label itself would create a cycle
+	for parentID := label.ParentID; parentID != 0; {
+		if parentID == label.ID {
+			return errors.New("label cannot be its own parent")
+		}
+		parent, err := s.label(parentID)
+		if err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+func (s *LabelService) label(id uint) (*models.Label, error) {
+	label, err := s.Repo.GetLabelByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("label %d does not exist: %w", id, err)
+	}
+	return label, err
+}
+
+// CreateLabel validates and creates a new label.
+func (s *LabelService) CreateLabel(label *models.Label) error {
+	if err := s.validateLabel(label); err != nil {
+		return err
+	}
+	return s.Repo.CreateLabel(label)
+}
+
+// GetLabels retrieves all labels sorted by name.
+func (s *LabelService) GetLabels() ([]models.Label, error) {
+	return s.Repo.GetLabels()
+}
+
+// GetLabel retrieves a label by ID.
+func (s *LabelService) GetLabel(labelID uint) (*models.Label, error) {
+	return s.label(labelID)
+}
+
+// UpdateLabel validates and updates an existing label.
+func (s *LabelService) UpdateLabel(label *models.Label) error {
+	if _, err := s.label(label.ID); err != nil {
+		return err
+	}
+	if err := s.validateLabel(label); err != nil {
+		return err
+	}
+	return s.Repo.UpdateLabel(label)
+}
+
+// DeleteLabel deletes a label that no album or imprint refers to.
+func (s *LabelService) DeleteLabel(labelID uint) error {
+	if _, err := s.label(labelID); err != nil {
+		return err
+	}
+	inUse, err := s.Repo.LabelInUse(labelID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrLabelInUse
+	}
+	return s.Repo.DeleteLabel(labelID)
+}
+
+// GetAlbumsByLabel retrieves the albums released on a label sorted by release date.
+func (s *LabelService) GetAlbumsByLabel(labelID uint) ([]models.Album, error) {
+	if _, err := s.label(labelID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAlbumsByLabel(labelID)
+}
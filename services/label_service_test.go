@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func setupTestLabelService(t *testing.T) (*services.LabelService, *services.AlbumService) {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE labels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			country TEXT NOT NULL DEFAULT '',
+			parent_id INTEGER
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	labelRepo := &repositories.LabelRepository{DB: albumRepo.DB}
+	return &services.LabelService{Repo: labelRepo}, &services.AlbumService{Repo: albumRepo, Labels: labelRepo}
+}
+
+func TestLabelServiceValidation(t *testing.T) {
+	service, _ := setupTestLabelService(t)
+
+	parent := &models.Label{Name: "Universal", Country: "us"}
+	if err := service.CreateLabel(parent); err != nil {
+		t.Fatalf("failed to create label: %v", err)
+	}
+	if parent.Country != "US" {
+		t.Errorf("expected country to be upper-cased, got %q", parent.Country)
+	}
+
+	imprint := &models.Label{Name: "Island", ParentID: parent.ID}
+	if err := service.CreateLabel(imprint); err != nil {
+		t.Fatalf("failed to create imprint: %v", err)
+	}
+
+	invalid := []models.Label{
+		{Name: "X"},
+		{Name: "Bad Country", Country: "GBR"},
+		{Name: "Orphan", ParentID: 99},
+	}
+	for _, label := range invalid {
+		if err := service.CreateLabel(&label); err == nil {
+			t.Errorf("expected %+v to be rejected", label)
+		}
+	}
+
+	// Making the parent an imprint of its own imprint would create a cycle
+	parent.ParentID = imprint.ID
+	if err := service.UpdateLabel(parent); err == nil {
+		t.Error("expected a parent cycle to be rejected")
+	}
+
+	if err := service.DeleteLabel(parent.ID); !errors.Is(err, services.ErrLabelInUse) {
+		t.Errorf("expected ErrLabelInUse, got %v", err)
+	}
+}
+
+func TestAlbumCatalogNumberUniquePerLabel(t *testing.T) {
+	labelService, albumService := setupTestLabelService(t)
+
+	island := &models.Label{Name: "Island"}
+	other := &models.Label{Name: "Other"}
+	labelService.CreateLabel(island)
+	labelService.CreateLabel(other)
+
+	first := &models.Album{Name: "First Album", ReleaseDate: "2020-01-01", Price: 200, LabelID: island.ID, CatalogNumber: "ILPS 1"}
+	if err := albumService.CreateAlbum(first); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	duplicate := &models.Album{Name: "Second Album", ReleaseDate: "2020-01-01", Price: 200, LabelID: island.ID, CatalogNumber: "ILPS 1"}
+	if err := albumService.CreateAlbum(duplicate); err == nil {
+		t.Error("expected a duplicate catalog number on the same label to be rejected")
+	}
+
+	duplicate.LabelID = other.ID
+	if err := albumService.CreateAlbum(duplicate); err != nil {
+		t.Errorf("expected the same catalog number on another label to be accepted, got %v", err)
+	}
+
+	// Updating an album keeps its own catalog number
+	if err := albumService.UpdateAlbum(first); err != nil {
+		t.Errorf("failed to update album: %v", err)
+	}
+
+	unlabelled := &models.Album{Name: "Third Album", ReleaseDate: "2020-01-01", Price: 200, CatalogNumber: "X 1"}
+	if err := albumService.CreateAlbum(unlabelled); err == nil {
+		t.Error("expected a catalog number without a label to be rejected")
+	}
+}
@@ -0,0 +1,574 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/tags"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// libraryExtensions lists the extensions of the files the library scanner reads.
+var libraryExtensions = map[string]bool{".mp3": true, ".flac": true, ".m4a": true, ".mp4": true}
+
+// LibraryScanner keeps the catalog in step with the audio files under library folders. New files are
+// ingested like uploads to IngestService, files whose content changed update the albums and tracks
+// they were ingested as, moved files are followed by their content hash and files no longer found are marked
+// missing. Files whose size and modification time are unchanged are not read again.
+type LibraryScanner struct {
+	Repo   *repositories.LibraryRepository
+	Ingest *IngestService
+	// Price and Currency are given to albums created from new files, as in IngestService.Ingest.
+	Price    float64
+	Currency string
+	// DryRun plans the changes without making them.
+	DryRun bool
+}
+
+// libraryScan is the state of one scan.
+type libraryScan struct {
+	report *models.ScanReport
+	byPath map[string]*models.LibraryFile
+	byHash map[string][]*models.LibraryFile
+	seen   map[uint]bool
+	now    string
+}
+
+// Scan walks the roots and reconciles the audio files under them with the catalog. A root that is not
+// a readable folder is an error, so an unmounted library is not marked missing; files that cannot be
+// read are reported as failed and the scan goes on.
+func (s *LibraryScanner) Scan(roots []string) (*models.ScanReport, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("no library folders to scan")
+	}
+	roots = append([]string(nil), roots...)
+	for i, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a folder", abs)
+		}
+		roots[i] = abs
+	}
+
+	files, err := s.Repo.GetLibraryFiles()
+	if err != nil {
+		return nil, err
+	}
+	scan := &libraryScan{
+		report: &models.ScanReport{Roots: roots, DryRun: s.DryRun, Changes: []models.ScanChange{}},
+		byPath: make(map[string]*models.LibraryFile, len(files)),
+		byHash: make(map[string][]*models.LibraryFile),
+		seen:   make(map[uint]bool),
+		now:    time.Now().UTC().Format(models.TimeFormat),
+	}
+	for i := range files {
+		file := &files[i]
+		scan.byPath[file.Path] = file
+		scan.byHash[file.Hash] = append(scan.byHash[file.Hash], file)
+	}
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				scan.fail(path, err)
+				return nil
+			}
+			if entry.IsDir() || !libraryExtensions[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+			return s.scanFile(scan, path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var missing []uint
+	for i := range files {
+		file := &files[i]
+		if scan.seen[file.ID] || file.MissingSince != "" || !underRoot(file.Path, roots) {
+			continue
+		}
+		missing = append(missing, file.ID)
+		scan.report.Missing++
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanMissing, Path: file.Path, AlbumID: file.AlbumID, TrackID: file.TrackID})
+	}
+	if !s.DryRun && len(missing) > 0 {
+		if err := s.Repo.MarkMissing(missing, scan.now); err != nil {
+			return nil, err
+		}
+	}
+	return scan.report, nil
+}
+
+// scanFile reconciles one audio file. It only returns an error when the catalog cannot be updated.
+func (s *LibraryScanner) scanFile(scan *libraryScan, path string) error {
+	scan.report.Scanned++
+	info, err := os.Stat(path)
+	if err != nil {
+		scan.fail(path, err)
+		return nil
+	}
+
+	known := scan.byPath[path]
+	if known != nil {
+		scan.seen[known.ID] = true
+		if known.Size == info.Size() && known.ModifiedNS == info.ModTime().UnixNano() && known.MissingSince == "" {
+			if known.Status == models.IngestConflicted {
+				return s.retry(scan, known, info)
+			}
+			scan.report.Unchanged++
+			return nil
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		scan.fail(path, err)
+		return nil
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		scan.fail(path, err)
+		return nil
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	switch {
+	case known != nil && known.Hash == sum:
+		// Touched, or back after going missing, with the same content
+		scan.report.Unchanged++
+		return s.save(scan, known, info, sum)
+	case known != nil:
+		return s.change(scan, known, file, info, sum)
+	}
+
+	if moved := scan.movedFrom(sum); moved != nil {
+		from := moved.Path
+		delete(scan.byPath, from)
+		moved.Path = path
+		scan.byPath[path] = moved
+		scan.seen[moved.ID] = true
+		scan.report.Moved++
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanMoved, Path: path, From: from})
+		return s.save(scan, moved, info, sum)
+	}
+	return s.add(scan, path, file, info, sum)
+}
+
+// add ingests a file the scanner has not seen before.
+func (s *LibraryScanner) add(scan *libraryScan, path string, file *os.File, info os.FileInfo, hash string) error {
+	scan.report.Added++
+	if s.DryRun {
+		details, ok := describeTags(file, info.Size())
+		if !ok {
+			scan.report.Failed++
+		}
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanAdded, Path: path, Details: details})
+		return nil
+	}
+
+	result, err := s.ingest(path, file, info.Size())
+	if err != nil {
+		return err
+	}
+	scan.count(result)
+	scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanAdded, Path: path, Result: &result})
+
+	known := &models.LibraryFile{Path: path}
+	setResult(known, result)
+	return s.save(scan, known, info, hash)
+}
+
+// change updates the catalog from a known file whose content changed. When the file was ingested as a
+// track, that track and its album are updated from its tags; otherwise the file is ingested again.
+func (s *LibraryScanner) change(scan *libraryScan, known *models.LibraryFile, file *os.File, info os.FileInfo, hash string) error {
+	plan, err := s.retag(known, file, info.Size())
+	if err != nil {
+		return err
+	}
+	if plan != nil {
+		return s.update(scan, known, plan, info, hash)
+	}
+
+	scan.report.Changed++
+	if s.DryRun {
+		details, ok := describeTags(file, info.Size())
+		if ok {
+			details = "ingest again as " + details
+		} else {
+			scan.report.Failed++
+		}
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanChanged, Path: known.Path, Details: details})
+		return nil
+	}
+
+	result, err := s.ingest(known.Path, file, info.Size())
+	if err != nil {
+		return err
+	}
+	scan.count(result)
+	scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanChanged, Path: known.Path, Result: &result})
+	setResult(known, result)
+	return s.save(scan, known, info, hash)
+}
+
+// retagging is how the tags of a changed file differ from the album and track it was ingested as.
+type retagging struct {
+	album *models.Album
+	track *models.Track
+	// albumChanged and trackChanged tell which of album and track the updates apply to.
+	albumChanged bool
+	trackChanged bool
+	updates      []string
+	// conflict is set when the tags cannot be applied in place: they name an artist the album does not
+	// credit, or an album or track position another album or track already has.
+	conflict *models.IngestConflict
+	// failure is set when the tags would make the album invalid.
+	failure error
+}
+
+// retag compares a changed file's tags with the album and track it was ingested as, setting both from
+// the tags. It returns nil when the file was not ingested as a track, its tags are unusable or the
+// track is gone, so that the file is ingested again. Musicians are never renamed, as other albums may
+// credit them; an artist the album does not credit is a conflict instead.
+func (s *LibraryScanner) retag(known *models.LibraryFile, file io.ReaderAt, size int64) (*retagging, error) {
+	if known.TrackID == 0 {
+		return nil, nil
+	}
+	t, err := tags.Read(file, size)
+	if err != nil || validateIngestTags(t) != nil {
+		return nil, nil
+	}
+
+	track, err := s.Ingest.Tracks.GetTrackByID(known.TrackID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	album, err := s.Ingest.Albums.Repo.GetAlbumByID(track.AlbumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan := &retagging{album: album, track: track}
+
+	artist := t.AlbumArtist
+	if artist == "" {
+		artist = t.Artist
+	}
+	credited, err := s.Ingest.Musicians.GetMusiciansByAlbums([]uint{album.ID})
+	if err != nil {
+		return nil, err
+	}
+	if !credits(credited[album.ID], artist) {
+		names := make([]string, len(credited[album.ID]))
+		for i, musician := range credited[album.ID] {
+			names[i] = musician.Name
+		}
+		existing := strings.Join(names, ", ")
+		if existing == "" {
+			existing = "no musicians credited"
+		}
+		plan.conflict = &models.IngestConflict{Kind: models.ConflictAlbum, EntityID: album.ID, Field: "musicians", Existing: existing, Incoming: artist}
+		return plan, nil
+	}
+
+	if album.Name != t.Album {
+		if len(t.Album) < MinAlbumNameLength {
+			plan.failure = fmt.Errorf("album name must be at least %d characters long", MinAlbumNameLength)
+			return plan, nil
+		}
+		others, err := s.namesake(album.ID, t.Album, artist)
+		if err != nil {
+			return nil, err
+		}
+		if len(others) > 0 {
+			plan.conflict = &models.IngestConflict{Kind: models.ConflictAlbum, EntityID: album.ID, Field: "name", Existing: "albums " + joinIDs(others), Incoming: t.Album}
+			return plan, nil
+		}
+		plan.updates = append(plan.updates, fmt.Sprintf("album %q → %q", album.Name, t.Album))
+		album.Name = t.Album
+		plan.albumChanged = true
+	}
+	if releaseDate := t.ReleaseDate(); releaseDate != "" && len(album.ReleaseDate) >= 4 && releaseDate[:4] != album.ReleaseDate[:4] {
+		plan.updates = append(plan.updates, fmt.Sprintf("release date %s → %s", album.ReleaseDate, releaseDate))
+		album.ReleaseDate = releaseDate
+		plan.albumChanged = true
+	}
+
+	if disc := tagDisc(t); track.Disc != disc || track.Number != t.Track {
+		existing, err := s.Ingest.Tracks.GetTrack(album.ID, disc, t.Track)
+		if err == nil {
+			plan.conflict = &models.IngestConflict{Kind: models.ConflictTrack, EntityID: existing.ID, Field: "number", Existing: existing.Title, Incoming: t.Title}
+			plan.updates = nil
+			return plan, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		plan.updates = append(plan.updates, fmt.Sprintf("disc %d track %d → disc %d track %d", track.Disc, track.Number, disc, t.Track))
+		track.Disc, track.Number = disc, t.Track
+		plan.trackChanged = true
+	}
+	if track.Title != t.Title {
+		plan.updates = append(plan.updates, fmt.Sprintf("title %q → %q", track.Title, t.Title))
+		track.Title = t.Title
+		plan.trackChanged = true
+	}
+	if t.DurationSeconds != 0 && track.DurationSeconds != t.DurationSeconds {
+		plan.updates = append(plan.updates, fmt.Sprintf("duration %ds → %ds", track.DurationSeconds, t.DurationSeconds))
+		track.DurationSeconds = t.DurationSeconds
+		plan.trackChanged = true
+	}
+	return plan, nil
+}
+
+// namesake returns the IDs of the albums other than albumID named name that credit artist.
+func (s *LibraryScanner) namesake(albumID uint, name, artist string) ([]uint, error) {
+	candidates, err := s.Ingest.Repo.FindAlbums(name)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(candidates))
+	for i, album := range candidates {
+		ids[i] = album.ID
+	}
+	credited, err := s.Ingest.Musicians.GetMusiciansByAlbums(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var others []uint
+	for _, id := range ids {
+		if id != albumID && credits(credited[id], artist) {
+			others = append(others, id)
+		}
+	}
+	return others, nil
+}
+
+// update applies a retagging to the catalog, or records its conflict or failure against the file.
+func (s *LibraryScanner) update(scan *libraryScan, known *models.LibraryFile, plan *retagging, info os.FileInfo, hash string) error {
+	if plan.conflict == nil && plan.failure == nil {
+		if len(plan.updates) == 0 {
+			// Only tags the catalog does not keep, such as artwork, changed
+			scan.report.Unchanged++
+			return s.save(scan, known, info, hash)
+		}
+		scan.report.Changed++
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanChanged, Path: known.Path, Details: strings.Join(plan.updates, ", ")})
+		if s.DryRun {
+			return nil
+		}
+		if err := s.apply(plan); err != nil {
+			return err
+		}
+		if known.Status == models.IngestConflicted {
+			known.Status = models.IngestMatched
+		}
+		return s.save(scan, known, info, hash)
+	}
+
+	scan.report.Changed++
+	result := models.IngestFileResult{FileName: known.Path, Status: models.IngestConflicted, AlbumID: known.AlbumID, TrackID: known.TrackID}
+	if plan.failure != nil {
+		result.Status = models.IngestFailed
+		result.Error = plan.failure.Error()
+	}
+	scan.count(result)
+	if s.DryRun {
+		details := "fails: " + result.Error
+		if conflict := plan.conflict; conflict != nil {
+			details = fmt.Sprintf("conflicts on %s %s: %q ≠ %q", conflict.Kind, conflict.Field, conflict.Existing, conflict.Incoming)
+		}
+		scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanChanged, Path: known.Path, Details: details})
+		return nil
+	}
+
+	if plan.conflict != nil {
+		if err := s.Ingest.conflict(&ingestion{result: &result, now: scan.now}, *plan.conflict); err != nil {
+			return err
+		}
+	}
+	scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanChanged, Path: known.Path, Result: &result})
+	setResult(known, result)
+	return s.save(scan, known, info, hash)
+}
+
+// apply writes the album and track of a retagging.
+func (s *LibraryScanner) apply(plan *retagging) error {
+	if plan.albumChanged {
+		if err := s.Ingest.Albums.UpdateAlbum(plan.album); err != nil {
+			return err
+		}
+	}
+	if plan.trackChanged {
+		return s.Ingest.Tracks.UpdateTrack(plan.track)
+	}
+	return nil
+}
+
+// retry ingests an unchanged file left in conflict again, as the conflict may have been resolved in
+// the catalog since. A file whose retagging conflicted is applied to its album and track once it no
+// longer does. Dry runs leave such files alone.
+func (s *LibraryScanner) retry(scan *libraryScan, known *models.LibraryFile, info os.FileInfo) error {
+	if s.DryRun {
+		scan.report.Unchanged++
+		return nil
+	}
+	file, err := os.Open(known.Path)
+	if err != nil {
+		scan.fail(known.Path, err)
+		return nil
+	}
+	defer file.Close()
+
+	plan, err := s.retag(known, file, info.Size())
+	if err != nil {
+		return err
+	}
+	var details string
+	if plan != nil {
+		if plan.conflict != nil || plan.failure != nil {
+			scan.report.Unchanged++
+			return nil
+		}
+		if err := s.apply(plan); err != nil {
+			return err
+		}
+		details = strings.Join(plan.updates, ", ")
+	}
+
+	result, err := s.ingest(known.Path, file, info.Size())
+	if err != nil {
+		return err
+	}
+	if result.Status == known.Status {
+		scan.report.Unchanged++
+		return nil
+	}
+	scan.report.Retried++
+	scan.count(result)
+	scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanRetried, Path: known.Path, Details: details, Result: &result})
+	setResult(known, result)
+	return s.save(scan, known, info, known.Hash)
+}
+
+func (s *LibraryScanner) ingest(path string, file *os.File, size int64) (models.IngestFileResult, error) {
+	report, err := s.Ingest.Ingest([]IngestFile{{Name: path, Reader: file, Size: size}}, s.Price, s.Currency)
+	if err != nil {
+		return models.IngestFileResult{}, err
+	}
+	return report.Files[0], nil
+}
+
+// save records a file as found with its current size, modification time and hash, unless in a dry run.
+func (s *LibraryScanner) save(scan *libraryScan, file *models.LibraryFile, info os.FileInfo, hash string) error {
+	if s.DryRun {
+		return nil
+	}
+	file.Size = info.Size()
+	file.ModifiedNS = info.ModTime().UnixNano()
+	file.Hash = hash
+	file.ScannedAt = scan.now
+	file.MissingSince = ""
+	return s.Repo.SaveLibraryFile(file)
+}
+
+// credits reports whether one of musicians is named name, ignoring case.
+func credits(musicians []models.Musician, name string) bool {
+	for _, musician := range musicians {
+		if strings.EqualFold(musician.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func setResult(file *models.LibraryFile, result models.IngestFileResult) {
+	file.Status = result.Status
+	file.AlbumID = result.AlbumID
+	file.TrackID = result.TrackID
+	file.Error = result.Error
+}
+
+// movedFrom returns the known file with a content hash whose path no longer exists, if any.
+func (scan *libraryScan) movedFrom(hash string) *models.LibraryFile {
+	for _, file := range scan.byHash[hash] {
+		if scan.seen[file.ID] {
+			continue
+		}
+		if _, err := os.Stat(file.Path); errors.Is(err, fs.ErrNotExist) {
+			return file
+		}
+	}
+	return nil
+}
+
+func (scan *libraryScan) count(result models.IngestFileResult) {
+	switch result.Status {
+	case models.IngestConflicted:
+		scan.report.Conflicts++
+	case models.IngestFailed:
+		scan.report.Failed++
+	}
+}
+
+func (scan *libraryScan) fail(path string, err error) {
+	scan.report.Failed++
+	scan.report.Changes = append(scan.report.Changes, models.ScanChange{Action: models.ScanFailed, Path: path, Details: err.Error()})
+}
+
+// describeTags summarises the tags of a file for dry runs, reporting false when it would fail ingestion.
+func describeTags(file io.ReaderAt, size int64) (string, bool) {
+	t, err := tags.Read(file, size)
+	if err == nil {
+		err = validateIngestTags(t)
+	}
+	if err != nil {
+		return "fails: " + err.Error(), false
+	}
+
+	artist := t.AlbumArtist
+	if artist == "" {
+		artist = t.Artist
+	}
+	return fmt.Sprintf("%q by %s, disc %d track %d %q", t.Album, artist, tagDisc(t), t.Track, t.Title), true
+}
+
+// underRoot reports whether path is inside one of the roots.
+func underRoot(path string, roots []string) bool {
+	for _, root := range roots {
+		prefix := root
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
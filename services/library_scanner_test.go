@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setupLibraryScanner(t *testing.T) *services.LibraryScanner {
+	ingest := setupIngestService(t)
+	return &services.LibraryScanner{Repo: &repositories.LibraryRepository{DB: ingest.Repo.DB}, Ingest: ingest}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create folder: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func scanLibrary(t *testing.T, scanner *services.LibraryScanner, root string) *models.ScanReport {
+	report, err := scanner.Scan([]string{root})
+	if err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	return report
+}
+
+func TestLibraryScan(t *testing.T) {
+	scanner := setupLibraryScanner(t)
+	root := t.TempDir()
+	dawn := filepath.Join(root, "First Light", "01.mp3")
+	writeFile(t, dawn, id3File(trackFrames("Dawn", "1")))
+	writeFile(t, filepath.Join(root, "First Light", "02.mp3"), id3File(trackFrames("Night Drive", "2")))
+	writeFile(t, filepath.Join(root, "First Light", "cover.jpg"), []byte("not audio"))
+	writeFile(t, filepath.Join(root, "broken.flac"), []byte("not really flac"))
+
+	report := scanLibrary(t, scanner, root)
+	if report.Scanned != 3 || report.Added != 3 || report.Failed != 1 {
+		t.Fatalf("unexpected first scan: %+v", report)
+	}
+
+	report = scanLibrary(t, scanner, root)
+	if report.Unchanged != 3 || len(report.Changes) != 0 {
+		t.Fatalf("expected nothing to change on a second scan, got %+v", report)
+	}
+
+	// Touching a file without changing its content only updates the scanner's record
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(dawn, later, later)
+	report = scanLibrary(t, scanner, root)
+	if report.Unchanged != 3 || len(report.Changes) != 0 {
+		t.Fatalf("expected a touched file to be unchanged, got %+v", report)
+	}
+
+	// Retagging a file is planned in a dry run, then applied to its track
+	writeFile(t, dawn, id3File(trackFrames("Daybreak", "1")))
+	scanner.DryRun = true
+	report = scanLibrary(t, scanner, root)
+	if report.Changed != 1 || report.Changes[0].Details != `title "Dawn" → "Daybreak"` {
+		t.Fatalf("unexpected dry run: %+v", report)
+	}
+	scanner.DryRun = false
+	report = scanLibrary(t, scanner, root)
+	if report.Changed != 1 || report.Changes[0].Action != models.ScanChanged {
+		t.Fatalf("unexpected scan of a retagged file: %+v", report)
+	}
+	tracks, err := scanner.Ingest.GetTracks(1)
+	if err != nil || len(tracks) != 2 || tracks[0].Title != "Daybreak" {
+		t.Fatalf("expected the track retitled, got %+v, %v", tracks, err)
+	}
+
+	// A moved file keeps its track; a deleted one is marked missing
+	moved := filepath.Join(root, "Lanterns", "01 Daybreak.mp3")
+	writeFile(t, moved, id3File(trackFrames("Daybreak", "1")))
+	os.Remove(dawn)
+	os.Remove(filepath.Join(root, "First Light", "02.mp3"))
+	report = scanLibrary(t, scanner, root)
+	if report.Moved != 1 || report.Missing != 1 || report.Added != 0 {
+		t.Fatalf("unexpected scan after moving and deleting: %+v", report)
+	}
+	if change := report.Changes[0]; change.Action != models.ScanMoved || change.From != dawn || change.Path != moved {
+		t.Errorf("unexpected move: %+v", change)
+	}
+
+	files, err := scanner.Repo.GetLibraryFiles()
+	if err != nil {
+		t.Fatalf("failed to get library files: %v", err)
+	}
+	missing := 0
+	for _, file := range files {
+		if file.MissingSince != "" {
+			missing++
+		}
+		if file.Path == moved && file.TrackID != tracks[0].ID {
+			t.Errorf("expected the moved file to keep its track, got %+v", file)
+		}
+	}
+	if len(files) != 3 || missing != 1 {
+		t.Errorf("expected 3 library files with 1 missing, got %+v", files)
+	}
+	listed, err := scanner.Ingest.GetTracks(1)
+	if err != nil || len(listed) != 2 || listed[0].MissingSince != "" || listed[1].MissingSince == "" {
+		t.Errorf("expected only the deleted file's track to be missing, got %+v, %v", listed, err)
+	}
+
+	// Files already missing are not reported again
+	report = scanLibrary(t, scanner, root)
+	if report.Missing != 0 || len(report.Changes) != 0 {
+		t.Errorf("expected nothing to change, got %+v", report)
+	}
+}
+
+func TestLibraryScanDryRun(t *testing.T) {
+	scanner := setupLibraryScanner(t)
+	scanner.DryRun = true
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "01.mp3"), id3File(trackFrames("Dawn", "1")))
+	writeFile(t, filepath.Join(root, "untitled.mp3"), id3File(trackFrames("", "2")))
+
+	report := scanLibrary(t, scanner, root)
+	if !report.DryRun || report.Added != 2 || report.Failed != 1 {
+		t.Fatalf("unexpected dry run: %+v", report)
+	}
+	if details := report.Changes[0].Details; details != `"First Light" by The Lanterns, disc 1 track 1 "Dawn"` {
+		t.Errorf("unexpected details: %s", details)
+	}
+
+	files, _ := scanner.Repo.GetLibraryFiles()
+	var albums int
+	scanner.Repo.DB.QueryRow("SELECT COUNT(*) FROM albums").Scan(&albums)
+	if len(files) != 0 || albums != 0 {
+		t.Errorf("expected a dry run to change nothing, got %d files and %d albums", len(files), albums)
+	}
+}
+
+func TestLibraryScanRetriesConflicts(t *testing.T) {
+	scanner := setupLibraryScanner(t)
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "01.mp3"), id3File(trackFrames("Dawn", "1")))
+	scanLibrary(t, scanner, root)
+
+	other := filepath.Join(root, "other", "01.mp3")
+	writeFile(t, other, id3File(trackFrames("Sunrise", "1")))
+	report := scanLibrary(t, scanner, root)
+	if report.Conflicts != 1 {
+		t.Fatalf("expected a title conflict, got %+v", report)
+	}
+
+	report = scanLibrary(t, scanner, root)
+	if report.Retried != 0 || report.Unchanged != 2 {
+		t.Fatalf("expected the conflict to stand, got %+v", report)
+	}
+
+	// Fixing the catalog lets the next scan match the file
+	if _, err := scanner.Repo.DB.Exec("UPDATE tracks SET title = 'Sunrise'"); err != nil {
+		t.Fatalf("failed to retitle track: %v", err)
+	}
+	report = scanLibrary(t, scanner, root)
+	if report.Retried != 1 || report.Changes[0].Path != other || report.Changes[0].Result.Status != models.IngestMatched {
+		t.Errorf("expected the conflicted file to be matched, got %+v", report)
+	}
+}
+
+func TestLibraryScanRetags(t *testing.T) {
+	scanner := setupLibraryScanner(t)
+	root := t.TempDir()
+	dawn, night := filepath.Join(root, "01.mp3"), filepath.Join(root, "02.mp3")
+	writeFile(t, dawn, id3File(trackFrames("Dawn", "1")))
+	writeFile(t, night, id3File(trackFrames("Night Drive", "2")))
+	scanLibrary(t, scanner, root)
+	countAlbums := func() int {
+		var albums int
+		scanner.Repo.DB.QueryRow("SELECT COUNT(*) FROM albums").Scan(&albums)
+		return albums
+	}
+
+	// Renaming the album updates it rather than ingesting a second one
+	renamed := trackFrames("Dawn", "1")
+	renamed["TALB"] = "First Lights"
+	writeFile(t, dawn, id3File(renamed))
+	report := scanLibrary(t, scanner, root)
+	if report.Changed != 1 || report.Changes[0].Details != `album "First Light" → "First Lights"` {
+		t.Fatalf("unexpected scan of a renamed album: %+v", report)
+	}
+	album, err := scanner.Ingest.Albums.Repo.GetAlbumByID(1)
+	if err != nil || album.Name != "First Lights" || countAlbums() != 1 {
+		t.Fatalf("expected the album renamed in place, got %+v, %v", album, err)
+	}
+
+	// Another artist, or a position another track has, is a conflict instead
+	otherArtist := trackFrames("Night Drive", "2")
+	otherArtist["TALB"], otherArtist["TPE1"] = "First Lights", "Somebody Else"
+	writeFile(t, night, id3File(otherArtist))
+	taken := trackFrames("Dawn", "2")
+	taken["TALB"] = "First Lights"
+	writeFile(t, dawn, id3File(taken))
+	report = scanLibrary(t, scanner, root)
+	if report.Changed != 2 || report.Conflicts != 2 || countAlbums() != 1 {
+		t.Fatalf("expected two conflicts and no new album, got %+v", report)
+	}
+	for _, change := range report.Changes {
+		if change.Result == nil || change.Result.Status != models.IngestConflicted || change.Result.TrackID == 0 {
+			t.Errorf("expected a conflict against the file's track, got %+v", change)
+		}
+	}
+
+	// Freeing the position lets the next scan move the track there
+	if _, err := scanner.Repo.DB.Exec("UPDATE tracks SET number = 3 WHERE title = 'Night Drive'"); err != nil {
+		t.Fatalf("failed to renumber track: %v", err)
+	}
+	report = scanLibrary(t, scanner, root)
+	if report.Retried != 1 || report.Changes[0].Path != dawn || report.Changes[0].Details != "disc 1 track 1 → disc 1 track 2" {
+		t.Fatalf("expected the retagged track moved, got %+v", report)
+	}
+	tracks, err := scanner.Ingest.GetTracks(1)
+	if err != nil || len(tracks) != 2 || tracks[0].Title != "Dawn" || tracks[0].Number != 2 {
+		t.Fatalf("unexpected tracks %+v, %v", tracks, err)
+	}
+
+	// Missing files report the album and track they were ingested as
+	os.Remove(dawn)
+	report = scanLibrary(t, scanner, root)
+	if report.Missing != 1 || report.Changes[0].AlbumID != 1 || report.Changes[0].TrackID != tracks[0].ID {
+		t.Errorf("expected the missing file's album and track, got %+v", report)
+	}
+}
+
+func TestLibraryScanRoots(t *testing.T) {
+	scanner := setupLibraryScanner(t)
+	if _, err := scanner.Scan(nil); err == nil {
+		t.Error("expected scanning no folders to fail")
+	}
+	if _, err := scanner.Scan([]string{filepath.Join(t.TempDir(), "unmounted")}); err == nil {
+		t.Error("expected a missing folder to fail the scan")
+	}
+}
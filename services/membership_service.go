@@ -0,0 +1,128 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"time"
+)
+
+// DateLayout is the format of membership dates and album release dates.
+const DateLayout = "2006-01-02"
+
+type MembershipService struct {
+	Repo         *repositories.MembershipRepository
+	MusicianRepo *repositories.MusicianRepository
+	AlbumRepo    *repositories.AlbumRepository
+}
+
+// ValidateDate checks that a date is in YYYY-MM-DD form.
+func ValidateDate(field, value string) error {
+	if _, err := time.Parse(DateLayout, value); err != nil {
+		return fmt.Errorf("%s must be a date in YYYY-MM-DD form", field)
+	}
+	return nil
+}
+
+// validateMembership checks that a membership links a person to a group over a valid period.
+// Missing musicians are reported with an error wrapping sql.ErrNoRows.
+func (s *MembershipService) validateMembership(membership *models.Membership) error {
+	group, err := s.musician(membership.GroupID)
+	if err != nil {
+		return err
+	}
+	if group.Kind != models.MusicianKindGroup {
+		return fmt.Errorf("musician %d is not a group", group.ID)
+	}
+
+	member, err := s.musician(membership.MemberID)
+	if err != nil {
+		return err
+	}
+	if member.Kind != models.MusicianKindPerson {
+		return fmt.Errorf("member %d must be a person", member.ID)
+	}
+
+	if err := ValidateDate("joined_on", membership.JoinedOn); err != nil {
+		return err
+	}
+	if membership.LeftOn != "" {
+		if err := ValidateDate("left_on", membership.LeftOn); err != nil {
+			return err
+		}
+		// Dates in YYYY-MM-DD form sort the same way as strings
+		if membership.LeftOn < membership.JoinedOn {
+			return errors.New("left_on must not be before joined_on")
+		}
+	}
+
+	membership.Member = *member
+	return nil
+}
+
+func (s *MembershipService) musician(id uint) (*models.Musician, error) {
+	musician, err := s.MusicianRepo.GetMusicianByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("musician %d does not exist: %w", id, err)
+	}
+	return musician, err
+}
+
+// AddMember validates and adds a member to a group.
+func (s *MembershipService) AddMember(membership *models.Membership) error {
+	if err := s.validateMembership(membership); err != nil {
+		return err
+	}
+	return s.Repo.CreateMembership(membership)
+}
+
+// UpdateMembership validates and updates a membership of a group.
+func (s *MembershipService) UpdateMembership(membership *models.Membership) error {
+	if err := s.validateMembership(membership); err != nil {
+		return err
+	}
+	if err := s.Repo.UpdateMembership(membership); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("group %d has no membership %d: %w", membership.GroupID, membership.ID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveMember deletes a membership of a group.
+func (s *MembershipService) RemoveMember(groupID, membershipID uint) error {
+	if err := s.Repo.DeleteMembership(groupID, membershipID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("group %d has no membership %d: %w", groupID, membershipID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetMembers retrieves the members of a group, optionally only those in the group on a given date.
+func (s *MembershipService) GetMembers(groupID uint, at string) ([]models.Membership, error) {
+	if at != "" {
+		if err := ValidateDate("at", at); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.musician(groupID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetMembershipsByGroup(groupID, at)
+}
+
+// GetAlbumLineup retrieves the group members who played on an album, based on its release date.
+func (s *MembershipService) GetAlbumLineup(albumID uint) ([]models.Membership, error) {
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return nil, err
+	}
+	return s.Repo.GetLineupByAlbum(albumID)
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func setupTestMembershipService(t *testing.T) *services.MembershipService {
+	musicianRepo := setupTestMusicianRepo(t)
+	db := musicianRepo.DB
+
+	_, err := db.Exec(`
+		CREATE TABLE albums (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			release_date TEXT,
+			genre TEXT,
+			price_minor INTEGER NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			description TEXT,
+			label_id INTEGER,
+			catalog_number TEXT
+		);
+		CREATE TABLE group_memberships (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_id INTEGER NOT NULL,
+			member_id INTEGER NOT NULL,
+			instrument TEXT NOT NULL DEFAULT '',
+			joined_on DATE NOT NULL,
+			left_on DATE
+		);
+		INSERT INTO musicians (id, name, musician_type, kind) VALUES
+		(1, 'The Band', 'Rock', 'group'),
+		(2, 'Alice', 'Vocalist', 'person');
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return &services.MembershipService{
+		Repo:         &repositories.MembershipRepository{DB: db},
+		MusicianRepo: musicianRepo,
+		AlbumRepo:    &repositories.AlbumRepository{DB: db},
+	}
+}
+
+func TestAddMemberService(t *testing.T) {
+	service := setupTestMembershipService(t)
+
+	membership := &models.Membership{GroupID: 1, MemberID: 2, Instrument: "vocals", JoinedOn: "1990-01-01"}
+	if err := service.AddMember(membership); err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	if membership.ID == 0 || membership.Member.Name != "Alice" {
+		t.Errorf("expected created membership with member details, got %+v", membership)
+	}
+}
+
+func TestAddMemberValidation(t *testing.T) {
+	service := setupTestMembershipService(t)
+
+	tests := []struct {
+		name       string
+		membership models.Membership
+		notFound   bool
+	}{
+		{"group is a person", models.Membership{GroupID: 2, MemberID: 2, JoinedOn: "1990-01-01"}, false},
+		{"member is a group", models.Membership{GroupID: 1, MemberID: 1, JoinedOn: "1990-01-01"}, false},
+		{"missing member", models.Membership{GroupID: 1, MemberID: 9, JoinedOn: "1990-01-01"}, true},
+		{"invalid date", models.Membership{GroupID: 1, MemberID: 2, JoinedOn: "01/01/1990"}, false},
+		{"left before joining", models.Membership{GroupID: 1, MemberID: 2, JoinedOn: "1990-01-01", LeftOn: "1989-12-31"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			membership := tt.membership
+			err := service.AddMember(&membership)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if errors.Is(err, sql.ErrNoRows) != tt.notFound {
+				t.Errorf("unexpected error kind: %v", err)
+			}
+		})
+	}
+}
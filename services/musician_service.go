@@ -2,18 +2,44 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"jukebox/models"
 	"jukebox/repositories"
 )
 
+// MinMusicianNameLength is the shortest musician name accepted on creation.
+const MinMusicianNameLength = 3
+
+// ErrInvalidMusician is returned when an updated musician has an invalid kind.
+var ErrInvalidMusician = errors.New("invalid musician")
+
 type MusicianService struct {
 	Repo *repositories.MusicianRepository
 }
 
+// ValidateMusician checks the rules a musician must satisfy before it is created.
+// A musician without a kind is treated as a person.
+func ValidateMusician(musician *models.Musician) error {
+	if len(musician.Name) < MinMusicianNameLength {
+		return fmt.Errorf("musician name must be at least %d characters long", MinMusicianNameLength)
+	}
+	if musician.Kind == "" {
+		musician.Kind = models.MusicianKindPerson
+	}
+	return validateMusicianKind(musician.Kind)
+}
+
+func validateMusicianKind(kind string) error {
+	if kind != models.MusicianKindPerson && kind != models.MusicianKindGroup {
+		return fmt.Errorf("musician kind must be %q or %q", models.MusicianKindPerson, models.MusicianKindGroup)
+	}
+	return nil
+}
+
 // CreateMusician validates and creates a new musician.
 func (s *MusicianService) CreateMusician(musician *models.Musician) error {
-	if len(musician.Name) < 3 {
-		return errors.New("musician name must be at least 3 characters long")
+	if err := ValidateMusician(musician); err != nil {
+		return err
 	}
 	return s.Repo.CreateMusician(musician)
 }
@@ -23,8 +49,13 @@ func (s *MusicianService) GetMusicians() ([]models.Musician, error) {
 	return s.Repo.GetMusicians()
 }
 
-// UpdateMusician updates an existing musician.
+// UpdateMusician updates an existing musician. An empty kind keeps the current one.
 func (s *MusicianService) UpdateMusician(musician *models.Musician) error {
+	if musician.Kind != "" {
+		if err := validateMusicianKind(musician.Kind); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMusician, err)
+		}
+	}
 	return s.Repo.UpdateMusician(musician)
 }
 
@@ -33,7 +64,18 @@ func (s *MusicianService) DeleteMusician(musicianID uint) error {
 	return s.Repo.DeleteMusician(musicianID)
 }
 
-// GetMusiciansByAlbum retrieves musicians for a specific album.
-func (s *MusicianService) GetMusiciansByAlbum(albumID uint) ([]models.Musician, error) {
-	return s.Repo.GetMusiciansByAlbum(albumID)
+// GetMusiciansByAlbum retrieves musicians for a specific album, each with their credits.
+// A non-empty role keeps only credits with that role.
+func (s *MusicianService) GetMusiciansByAlbum(albumID uint, role string) ([]models.CreditedMusician, error) {
+	if role != "" {
+		if err := ValidateCreditRole(role); err != nil {
+			return nil, err
+		}
+	}
+	return s.Repo.GetMusiciansByAlbum(albumID, role)
+}
+
+// GetMusiciansByAlbums retrieves musicians for several albums at once, keyed by album ID.
+func (s *MusicianService) GetMusiciansByAlbums(albumIDs []uint) (map[uint][]models.Musician, error) {
+	return s.Repo.GetMusiciansByAlbums(albumIDs)
 }
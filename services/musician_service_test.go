@@ -20,11 +20,15 @@ func setupTestMusicianRepo(t *testing.T) *repositories.MusicianRepository {
 		CREATE TABLE musicians (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			name TEXT NOT NULL,
-			musician_type TEXT NOT NULL
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
 		);
 		CREATE TABLE album_musicians (
 			album_id INTEGER,
-			musician_id INTEGER
+			musician_id INTEGER,
+			role TEXT NOT NULL DEFAULT 'performer',
+			instrument TEXT NOT NULL DEFAULT '',
+			track INTEGER NOT NULL DEFAULT 0
 		);
 	`)
 	if err != nil {
@@ -145,7 +149,7 @@ func TestGetMusiciansByAlbumService(t *testing.T) {
 	}
 
 	// Get musicians by album
-	musicians, err := service.GetMusiciansByAlbum(1)
+	musicians, err := service.GetMusiciansByAlbum(1, "")
 	if err != nil {
 		t.Fatalf("failed to get musicians by album: %v", err)
 	}
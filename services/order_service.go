@@ -0,0 +1,318 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrCartCheckedOut is returned when a cart that has already become an order is changed or checked out again.
+var ErrCartCheckedOut = repositories.ErrCartCheckedOut
+
+// ErrInvalidTransition is returned when an order cannot move from its current status to the requested one.
+var ErrInvalidTransition = errors.New("invalid order status change")
+
+// orderTransitions lists the statuses an order may move to each status from.
+var orderTransitions = map[string][]string{
+	models.OrderPaid:      {models.OrderPending},
+	models.OrderShipped:   {models.OrderPaid},
+	models.OrderCancelled: {models.OrderPending, models.OrderPaid},
+}
+
+type OrderService struct {
+	Repo      *repositories.OrderRepository
+	AlbumRepo *repositories.AlbumRepository
+	Editions  *repositories.EditionRepository
+	// Prices, when set, prices carts in other currencies than their albums'.
+	Prices *PriceService
+	// Promotions, when set, applies scheduled prices and running promotions at checkout.
+	Promotions *PromotionService
+	Payments   PaymentProvider
+}
+
+// NormalizeCountry upper-cases a country code and checks that it is an ISO 3166-1 alpha-2 code.
+func NormalizeCountry(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if !countryCode.MatchString(country) {
+		return "", errors.New("country must be an ISO 3166-1 alpha-2 code such as GB")
+	}
+	return country, nil
+}
+
+// CreateCart creates an empty cart in its currency, which defaults to models.BaseCurrency.
+func (s *OrderService) CreateCart(cart *models.Cart) error {
+	if cart.Currency == "" {
+		cart.Currency = models.BaseCurrency
+	}
+	currency, err := NormalizeCurrency(cart.Currency)
+	if err != nil {
+		return err
+	}
+	cart.Currency = currency
+	cart.CreatedAt = time.Now().UTC().Format(models.TimeFormat)
+	cart.CheckedOutAt = ""
+	return s.Repo.CreateCart(cart)
+}
+
+// GetCart retrieves a cart with its items.
+func (s *OrderService) GetCart(cartID uint) (*models.Cart, error) {
+	cart, err := s.Repo.GetCart(cartID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cart %d does not exist: %w", cartID, err)
+	}
+	return cart, err
+}
+
+// SetCartItem sets the quantity of an album or edition in a cart, removing it at quantity 0, and returns the
+// updated cart.
+func (s *OrderService) SetCartItem(cartID uint, item models.CartItem) (*models.Cart, error) {
+	if item.Quantity < 0 {
+		return nil, errors.New("quantity must not be negative")
+	}
+	if item.Quantity > 0 {
+		if _, _, err := validateItem(s.AlbumRepo, s.Editions, item.AlbumID, item.EditionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Repo.SetCartItem(cartID, item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("cart %d does not exist: %w", cartID, err)
+		}
+		return nil, err
+	}
+	return s.GetCart(cartID)
+}
+
+// Checkout turns a cart into a pending order. Each line is priced at what the album or edition costs now,
+// after scheduled price changes and promotions, in the cart's currency. Tax is charged on the subtotal at
+// the rate of the checkout's country and rounded half away from zero to the currency's minor unit. Copies
+// whose stock is tracked are taken from stock; a cart the stock cannot cover is rejected with
+// ErrInsufficientStock.
+func (s *OrderService) Checkout(cartID uint, checkout models.Checkout) (*models.Order, error) {
+	cart, err := s.GetCart(cartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.CheckedOutAt != "" {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrCartCheckedOut)
+	}
+	if len(cart.Items) == 0 {
+		return nil, fmt.Errorf("cart %d is empty", cartID)
+	}
+
+	country, err := NormalizeCountry(checkout.Country)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.Repo.GetTaxRate(country)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no tax rate for country %s", country)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	order := &models.Order{CartID: cartID, CustomerID: cart.CustomerID, Status: models.OrderPending, Country: country, Currency: cart.Currency,
+		TaxRate: rate, Lines: make([]models.OrderLine, 0, len(cart.Items))}
+	var subtotal int64
+	for _, item := range cart.Items {
+		album, edition, err := validateItem(s.AlbumRepo, s.Editions, item.AlbumID, item.EditionID)
+		if err != nil {
+			return nil, err
+		}
+		unitPrice, err := s.unitPrice(album, edition, cart.Currency, now)
+		if err != nil {
+			return nil, err
+		}
+
+		line := models.OrderLine{AlbumID: item.AlbumID, EditionID: item.EditionID, Name: album.Name, Quantity: item.Quantity, UnitPrice: unitPrice}
+		if edition != nil {
+			line.Name = fmt.Sprintf("%s (%s)", album.Name, strings.TrimSpace(edition.Name+" "+edition.Format))
+		}
+		total := models.ToMinor(unitPrice, cart.Currency) * int64(item.Quantity)
+		line.Total = models.FromMinor(total, cart.Currency)
+		subtotal += total
+		order.Lines = append(order.Lines, line)
+	}
+
+	tax := int64(math.Round(float64(subtotal) * rate))
+	order.Subtotal = models.FromMinor(subtotal, cart.Currency)
+	order.Tax = models.FromMinor(tax, cart.Currency)
+	order.Total = models.FromMinor(subtotal+tax, cart.Currency)
+	order.CreatedAt = now.Format(models.TimeFormat)
+	order.UpdatedAt = order.CreatedAt
+
+	if err := s.Repo.CreateOrder(order); err != nil {
+		if errors.Is(err, ErrCartCheckedOut) {
+			return nil, fmt.Errorf("cart %d: %w", cartID, err)
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// unitPrice works out what an album, or one of its editions, costs at a point in time in a currency.
+func (s *OrderService) unitPrice(album *models.Album, edition *models.Edition, currency string, at time.Time) (float64, error) {
+	priced := *album
+	if edition != nil {
+		priced.Price = edition.Price
+	}
+	priced.EffectivePrice = priced.Price
+	if s.Promotions != nil {
+		if err := s.Promotions.ResolvePrices([]*models.Album{&priced}, at); err != nil {
+			return 0, err
+		}
+	}
+	if priced.Currency == currency {
+		return priced.EffectivePrice, nil
+	}
+
+	if s.Prices == nil {
+		return 0, fmt.Errorf("%w to price album %d in %s", ErrNoExchangeRate, album.ID, currency)
+	}
+	// The album's price list covers the album as a whole, not its editions
+	if edition != nil {
+		return s.Prices.Convert(priced.EffectivePrice, priced.Currency, currency)
+	}
+	if err := s.Prices.PriceAlbums([]*models.Album{&priced}, currency); err != nil {
+		return 0, err
+	}
+	return priced.EffectivePrice, nil
+}
+
+// GetOrder retrieves an order with its lines.
+func (s *OrderService) GetOrder(orderID uint) (*models.Order, error) {
+	order, err := s.Repo.GetOrder(orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("order %d does not exist: %w", orderID, err)
+	}
+	return order, err
+}
+
+// GetOrders retrieves the orders with a status, or every order for an empty status, newest first.
+func (s *OrderService) GetOrders(status string) ([]models.Order, error) {
+	if status != "" && !slices.Contains(models.OrderStatuses, status) {
+		return nil, fmt.Errorf("status must be one of %s", strings.Join(models.OrderStatuses, ", "))
+	}
+	return s.Repo.GetOrders(status)
+}
+
+// PayOrder charges a pending order through the payment provider and marks it paid.
+func (s *OrderService) PayOrder(orderID uint) (*models.Order, error) {
+	order, from, err := s.beginTransition(orderID, models.OrderPaid)
+	if err != nil {
+		return nil, err
+	}
+	if s.Payments == nil {
+		return nil, errors.New("no payment provider is configured")
+	}
+
+	reference, err := s.Payments.Charge(order)
+	if err != nil {
+		return nil, fmt.Errorf("charging order %d: %w", orderID, err)
+	}
+	order.PaymentReference = reference
+	if err := s.finishTransition(order, from); err != nil {
+		// Another request moved the order on while it was being charged
+		if refundErr := s.Payments.Refund(order); refundErr != nil {
+			return nil, errors.Join(err, refundErr)
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// ShipOrder marks a paid order shipped.
+func (s *OrderService) ShipOrder(orderID uint) (*models.Order, error) {
+	order, from, err := s.beginTransition(orderID, models.OrderShipped)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishTransition(order, from); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// CancelOrder cancels a pending or paid order, returns its copies to stock and refunds its payment.
+func (s *OrderService) CancelOrder(orderID uint) (*models.Order, error) {
+	order, from, err := s.beginTransition(orderID, models.OrderCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishTransition(order, from); err != nil {
+		return nil, err
+	}
+	if from == models.OrderPaid && s.Payments != nil {
+		if err := s.Payments.Refund(order); err != nil {
+			return nil, fmt.Errorf("order %d was cancelled but refunding it failed: %w", orderID, err)
+		}
+	}
+	return order, nil
+}
+
+// beginTransition retrieves an order and checks that it may move to status, returning it with the status
+// it is moving from.
+func (s *OrderService) beginTransition(orderID uint, status string) (*models.Order, string, error) {
+	order, err := s.GetOrder(orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !slices.Contains(orderTransitions[status], order.Status) {
+		return nil, "", fmt.Errorf("%w: a %s order cannot become %s", ErrInvalidTransition, order.Status, status)
+	}
+	from := order.Status
+	order.Status = status
+	order.UpdatedAt = time.Now().UTC().Format(models.TimeFormat)
+	return order, from, nil
+}
+
+// finishTransition stores an order's new status, provided nobody changed it since beginTransition.
+func (s *OrderService) finishTransition(order *models.Order, from string) error {
+	if err := s.Repo.UpdateOrderStatus(order, from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: order %d is no longer %s", ErrInvalidTransition, order.ID, from)
+		}
+		return err
+	}
+	return nil
+}
+
+// UploadTaxRates validates and replaces every tax rate. Rates are fractions of the subtotal, e.g. 0.2 for 20%.
+func (s *OrderService) UploadTaxRates(rates []models.TaxRate) ([]models.TaxRate, error) {
+	seen := map[string]bool{}
+	stored := []models.TaxRate{}
+	for _, rate := range rates {
+		country, err := NormalizeCountry(rate.Country)
+		if err != nil {
+			return nil, err
+		}
+		if seen[country] {
+			return nil, fmt.Errorf("country %s is listed twice", country)
+		}
+		seen[country] = true
+
+		if rate.Rate < 0 || rate.Rate >= 1 {
+			return nil, fmt.Errorf("rate for %s must be a fraction from 0 up to 1, e.g. 0.2 for 20%%", country)
+		}
+		stored = append(stored, models.TaxRate{Country: country, Rate: rate.Rate})
+	}
+
+	if err := s.Repo.ReplaceTaxRates(stored); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTaxRates()
+}
+
+// GetTaxRates retrieves every tax rate sorted by country.
+func (s *OrderService) GetTaxRates() ([]models.TaxRate, error) {
+	return s.Repo.GetTaxRates()
+}
@@ -0,0 +1,269 @@
+package services_test
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+	"time"
+)
+
+func setupOrderService(t *testing.T, payments services.PaymentProvider) *services.OrderService {
+	promotions := setupPromotionService(t)
+	db := promotions.AlbumRepo.DB
+	_, err := db.Exec(`
+		CREATE TABLE stock_levels (
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL DEFAULT 0,
+			low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (album_id, edition_id)
+		);
+		CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			quantity_change INTEGER NOT NULL,
+			balance INTEGER NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);
+		CREATE TABLE carts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			currency TEXT NOT NULL DEFAULT 'USD',
+			created_at TEXT NOT NULL,
+			checked_out_at TEXT,
+			customer_id INTEGER
+		);
+		CREATE TABLE cart_items (
+			cart_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			quantity INTEGER NOT NULL,
+			PRIMARY KEY (cart_id, album_id, edition_id)
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			cart_id INTEGER NOT NULL UNIQUE,
+			status TEXT NOT NULL,
+			country TEXT NOT NULL,
+			currency TEXT NOT NULL,
+			subtotal_minor INTEGER NOT NULL,
+			tax_rate REAL NOT NULL,
+			tax_minor INTEGER NOT NULL,
+			total_minor INTEGER NOT NULL,
+			payment_reference TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			customer_id INTEGER
+		);
+		CREATE TABLE order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			edition_id INTEGER NOT NULL DEFAULT 0,
+			name TEXT NOT NULL,
+			quantity INTEGER NOT NULL,
+			unit_price_minor INTEGER NOT NULL,
+			total_minor INTEGER NOT NULL,
+			stock_tracked INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE tax_rates (
+			country TEXT PRIMARY KEY,
+			rate REAL NOT NULL
+		);
+		CREATE TABLE customers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			password_hash TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			is_admin INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE sessions (
+			token_hash TEXT PRIMARY KEY,
+			customer_id INTEGER NOT NULL,
+			created_at TEXT NOT NULL,
+			expires_at TEXT NOT NULL
+		);
+		CREATE TABLE library_entries (
+			customer_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL,
+			source TEXT NOT NULL,
+			order_id INTEGER,
+			added_at TEXT NOT NULL,
+			PRIMARY KEY (customer_id, album_id)
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	promotions.Editions = &repositories.EditionRepository{DB: db}
+	return &services.OrderService{
+		Repo:       &repositories.OrderRepository{DB: db},
+		AlbumRepo:  promotions.AlbumRepo,
+		Editions:   promotions.Editions,
+		Prices:     promotions.Prices,
+		Promotions: promotions,
+		Payments:   payments,
+	}
+}
+
+// fillCart creates a cart in currency holding items.
+func fillCart(t *testing.T, service *services.OrderService, currency string, items ...models.CartItem) *models.Cart {
+	cart := &models.Cart{Currency: currency}
+	if err := service.CreateCart(cart); err != nil {
+		t.Fatalf("failed to create cart: %v", err)
+	}
+	for _, item := range items {
+		if _, err := service.SetCartItem(cart.ID, item); err != nil {
+			t.Fatalf("failed to add %+v: %v", item, err)
+		}
+	}
+	return cart
+}
+
+func TestCheckoutSnapshotsPricesAndTax(t *testing.T) {
+	service := setupOrderService(t, &services.FakePaymentProvider{})
+	if _, err := service.UploadTaxRates([]models.TaxRate{{Country: "us", Rate: 0.0825}}); err != nil {
+		t.Fatalf("failed to upload tax rates: %v", err)
+	}
+	if _, err := service.Prices.UploadExchangeRates([]models.ExchangeRate{{Currency: "EUR", Rate: 0.9}}); err != nil {
+		t.Fatalf("failed to upload exchange rates: %v", err)
+	}
+
+	album := &models.Album{Name: "Discounted Album", ReleaseDate: "2020-01-01", Price: 19.99}
+	editioned := &models.Album{Name: "Editioned Album", ReleaseDate: "2020-01-01", Price: 25}
+	for _, a := range []*models.Album{album, editioned} {
+		if err := service.AlbumRepo.CreateAlbum(a); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	vinyl := &models.Edition{AlbumID: editioned.ID, Format: models.FormatVinyl, Name: "Deluxe", ReleaseDate: "2020-01-01", Price: 25}
+	if err := service.Editions.CreateEdition(vinyl); err != nil {
+		t.Fatalf("failed to create edition: %v", err)
+	}
+	now := time.Now().UTC()
+	promotion := &models.Promotion{Name: "Tenth Off", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID,
+		StartsAt: now.Add(-time.Hour).Format(time.RFC3339), EndsAt: now.Add(time.Hour).Format(time.RFC3339)}
+	if err := service.Promotions.CreatePromotion(promotion); err != nil {
+		t.Fatalf("failed to create promotion: %v", err)
+	}
+
+	if _, err := service.SetCartItem(fillCart(t, service, "").ID, models.CartItem{AlbumID: editioned.ID, Quantity: 1}); err == nil {
+		t.Error("expected an album with editions to need an edition")
+	}
+
+	cart := fillCart(t, service, "", models.CartItem{AlbumID: album.ID, Quantity: 3}, models.CartItem{AlbumID: editioned.ID, EditionID: vinyl.ID, Quantity: 1})
+	if _, err := service.Checkout(cart.ID, models.Checkout{Country: "GB"}); err == nil {
+		t.Error("expected a country without a tax rate to be rejected")
+	}
+	order, err := service.Checkout(cart.ID, models.Checkout{Country: "us"})
+	if err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+	// 3 × 17.99 + 25 = 78.97, and 8.25% of it is 6.515025, rounded to the cent
+	if order.Lines[0].UnitPrice != 17.99 || order.Lines[0].Total != 53.97 || order.Lines[1].Name != "Editioned Album (Deluxe vinyl)" ||
+		order.Subtotal != 78.97 || order.Tax != 6.52 || order.Total != 85.49 || order.Status != models.OrderPending || order.Country != "US" {
+		t.Errorf("unexpected order: %+v", order)
+	}
+
+	// Prices are fixed at checkout
+	album.Price = 30
+	if err := service.AlbumRepo.UpdateAlbum(album); err != nil {
+		t.Fatalf("failed to update album: %v", err)
+	}
+	stored, err := service.GetOrder(order.ID)
+	if err != nil {
+		t.Fatalf("failed to get order: %v", err)
+	}
+	if stored.Lines[0].UnitPrice != 17.99 || stored.Total != 85.49 {
+		t.Errorf("expected the checkout prices to be kept, got %+v", stored)
+	}
+
+	if _, err := service.Checkout(cart.ID, models.Checkout{Country: "US"}); !errors.Is(err, services.ErrCartCheckedOut) {
+		t.Errorf("expected ErrCartCheckedOut, got %v", err)
+	}
+	if _, err := service.SetCartItem(cart.ID, models.CartItem{AlbumID: album.ID, Quantity: 1}); !errors.Is(err, services.ErrCartCheckedOut) {
+		t.Errorf("expected ErrCartCheckedOut, got %v", err)
+	}
+	if _, err := service.Checkout(fillCart(t, service, "").ID, models.Checkout{Country: "US"}); err == nil {
+		t.Error("expected an empty cart to be rejected")
+	}
+
+	euro, err := service.Checkout(fillCart(t, service, "eur", models.CartItem{AlbumID: album.ID, Quantity: 1}).ID, models.Checkout{Country: "US"})
+	if err != nil {
+		t.Fatalf("failed to check out in EUR: %v", err)
+	}
+	// 30 USD is 27 EUR, less 10%
+	if euro.Currency != "EUR" || euro.Lines[0].UnitPrice != 24.3 {
+		t.Errorf("unexpected EUR order: %+v", euro)
+	}
+}
+
+func TestOrderTransitions(t *testing.T) {
+	payments := &services.FakePaymentProvider{}
+	service := setupOrderService(t, payments)
+	if _, err := service.UploadTaxRates([]models.TaxRate{{Country: "GB", Rate: 0.2}}); err != nil {
+		t.Fatalf("failed to upload tax rates: %v", err)
+	}
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 10}
+	if err := service.AlbumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	checkout := func() *models.Order {
+		order, err := service.Checkout(fillCart(t, service, "", models.CartItem{AlbumID: album.ID, Quantity: 1}).ID, models.Checkout{Country: "GB"})
+		if err != nil {
+			t.Fatalf("failed to check out: %v", err)
+		}
+		return order
+	}
+
+	shipped := checkout()
+	if _, err := service.ShipOrder(shipped.ID); !errors.Is(err, services.ErrInvalidTransition) {
+		t.Errorf("expected an unpaid order not to ship, got %v", err)
+	}
+	payments.Decline = true
+	if _, err := service.PayOrder(shipped.ID); !errors.Is(err, services.ErrPaymentDeclined) {
+		t.Errorf("expected ErrPaymentDeclined, got %v", err)
+	}
+	payments.Decline = false
+	paid, err := service.PayOrder(shipped.ID)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	if paid.Status != models.OrderPaid || paid.PaymentReference == "" {
+		t.Errorf("expected a paid order with a payment reference, got %+v", paid)
+	}
+	if _, err := service.ShipOrder(shipped.ID); err != nil {
+		t.Fatalf("failed to ship: %v", err)
+	}
+	if _, err := service.CancelOrder(shipped.ID); !errors.Is(err, services.ErrInvalidTransition) {
+		t.Errorf("expected a shipped order not to be cancelled, got %v", err)
+	}
+
+	cancelled := checkout()
+	paid, err = service.PayOrder(cancelled.ID)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	if _, err := service.CancelOrder(cancelled.ID); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if refunded := payments.Refunded(); len(refunded) != 1 || refunded[0] != paid.PaymentReference {
+		t.Errorf("expected the cancelled order's payment to be refunded, got %v", refunded)
+	}
+
+	orders, err := service.GetOrders(models.OrderCancelled)
+	if err != nil {
+		t.Fatalf("failed to get orders: %v", err)
+	}
+	if len(orders) != 1 || orders[0].ID != cancelled.ID {
+		t.Errorf("expected only the cancelled order, got %+v", orders)
+	}
+	if _, err := service.GetOrders("lost"); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+}
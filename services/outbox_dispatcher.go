@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"log"
+	"sync"
+	"time"
+)
+
+// EventHandler receives a domain event. Returning an error leaves the event in
+// the outbox so it is delivered again on the next pass, until it has failed
+// MaxAttempts times.
+type EventHandler func(event models.Event) error
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+// LogEvent is an EventHandler that writes each event to the standard logger, so changes show up in the
+// server log until other systems subscribe to them.
+func LogEvent(event models.Event) error {
+	log.Printf("Event %d: %s %s %d %s", event.ID, event.Type, event.AggregateType, event.AggregateID, event.Payload)
+	return nil
+}
+
+// DefaultMaxOutboxAttempts is the number of failed deliveries after which an event is parked when
+// MaxAttempts is not set.
+const DefaultMaxOutboxAttempts = 10
+
+// OutboxDispatcher polls the outbox and delivers events to in-process subscribers.
+//
+// Delivery is at-least-once: an event is only marked as dispatched after every
+// subscriber has handled it, so a crash in between causes it to be delivered
+// again. Events for the same entity are delivered in the order they were
+// written; when one fails, later events for that entity wait until it succeeds
+// or, after MaxAttempts failures, is parked as dead with its last error.
+type OutboxDispatcher struct {
+	Repo        *repositories.OutboxRepository
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+// Subscribe registers a handler for an event type, or for AllEvents.
+func (d *OutboxDispatcher) Subscribe(eventType string, handler EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.handlers == nil {
+		d.handlers = make(map[string][]EventHandler)
+	}
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Run dispatches pending events until the context is cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(); err != nil {
+			log.Println("Error dispatching outbox events:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending delivers one batch of pending events and returns how many were dispatched.
+func (d *OutboxDispatcher) DispatchPending() (int, error) {
+	batchSize := d.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxOutboxAttempts
+	}
+
+	events, err := d.Repo.GetPendingEvents(batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Entities whose earlier event failed in this batch; their later events must wait.
+	blocked := make(map[string]bool)
+	dispatched := 0
+
+	for _, event := range events {
+		key := fmt.Sprintf("%s:%d", event.AggregateType, event.AggregateID)
+		if blocked[key] {
+			continue
+		}
+
+		if err := d.deliver(event); err != nil {
+			blocked[key] = true
+			mark := d.Repo.MarkFailed
+			if event.Attempts+1 >= maxAttempts {
+				log.Printf("Parking outbox event %d after %d failed attempts: %v", event.ID, event.Attempts+1, err)
+				mark = d.Repo.MarkDead
+			}
+			if err := mark(event.ID, err.Error()); err != nil {
+				return dispatched, err
+			}
+			continue
+		}
+
+		if err := d.Repo.MarkDispatched(event.ID); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// deliver calls every handler subscribed to the event, stopping at the first error.
+func (d *OutboxDispatcher) deliver(event models.Event) error {
+	d.mu.RLock()
+	handlers := append(append([]EventHandler(nil), d.handlers[event.Type]...), d.handlers[AllEvents]...)
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"log"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestOutbox(t *testing.T) (*sql.DB, *repositories.OutboxRepository) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE outbox_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			aggregate_type TEXT NOT NULL,
+			aggregate_id INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			dispatched_at TEXT,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			dead_at TEXT
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	return db, &repositories.OutboxRepository{DB: db}
+}
+
+func appendTestEvent(t *testing.T, db *sql.DB, outbox *repositories.OutboxRepository, aggregateID uint, eventType string) {
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if err := outbox.Append(tx, "album", aggregateID, eventType, map[string]uint{"id": aggregateID}); err != nil {
+		t.Fatalf("failed to append event: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit event: %v", err)
+	}
+}
+
+func TestOutboxDispatcherDeliversInOrder(t *testing.T) {
+	db, outbox := setupTestOutbox(t)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumCreated)
+	appendTestEvent(t, db, outbox, 2, models.EventAlbumCreated)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumUpdated)
+
+	dispatcher := &services.OutboxDispatcher{Repo: outbox}
+
+	var received []string
+	dispatcher.Subscribe(services.AllEvents, func(event models.Event) error {
+		received = append(received, event.Type)
+		return nil
+	})
+
+	dispatched, err := dispatcher.DispatchPending()
+	if err != nil {
+		t.Fatalf("failed to dispatch events: %v", err)
+	}
+	if dispatched != 3 {
+		t.Errorf("expected 3 dispatched events, got %d", dispatched)
+	}
+
+	expected := []string{models.EventAlbumCreated, models.EventAlbumCreated, models.EventAlbumUpdated}
+	for i, eventType := range expected {
+		if received[i] != eventType {
+			t.Errorf("expected event %d to be %s, got %s", i, eventType, received[i])
+		}
+	}
+
+	// Dispatched events are not delivered again
+	dispatched, err = dispatcher.DispatchPending()
+	if err != nil {
+		t.Fatalf("failed to dispatch events: %v", err)
+	}
+	if dispatched != 0 {
+		t.Errorf("expected no events on second pass, got %d", dispatched)
+	}
+}
+
+func TestOutboxDispatcherRetriesFailedEventsInOrder(t *testing.T) {
+	db, outbox := setupTestOutbox(t)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumCreated)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumUpdated)
+	appendTestEvent(t, db, outbox, 2, models.EventAlbumCreated)
+
+	dispatcher := &services.OutboxDispatcher{Repo: outbox}
+
+	failing := true
+	var received []uint
+	dispatcher.Subscribe(models.EventAlbumCreated, func(event models.Event) error {
+		if event.AggregateID == 1 && failing {
+			return errors.New("index unavailable")
+		}
+		received = append(received, event.ID)
+		return nil
+	})
+	dispatcher.Subscribe(models.EventAlbumUpdated, func(event models.Event) error {
+		received = append(received, event.ID)
+		return nil
+	})
+
+	// The update for album 1 must wait behind its failed create; album 2 is unaffected
+	dispatched, err := dispatcher.DispatchPending()
+	if err != nil {
+		t.Fatalf("failed to dispatch events: %v", err)
+	}
+	if dispatched != 1 || len(received) != 1 || received[0] != 3 {
+		t.Fatalf("expected only the album 2 event to be delivered, got %v", received)
+	}
+
+	failing = false
+	dispatched, err = dispatcher.DispatchPending()
+	if err != nil {
+		t.Fatalf("failed to dispatch events: %v", err)
+	}
+	if dispatched != 2 {
+		t.Fatalf("expected 2 dispatched events on retry, got %d", dispatched)
+	}
+	if received[1] != 1 || received[2] != 2 {
+		t.Errorf("expected album 1 events in order, got %v", received[1:])
+	}
+}
+
+func TestOutboxDispatcherParksPoisonEvents(t *testing.T) {
+	db, outbox := setupTestOutbox(t)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumCreated)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumUpdated)
+
+	dispatcher := &services.OutboxDispatcher{Repo: outbox, MaxAttempts: 3}
+	var received []string
+	dispatcher.Subscribe(models.EventAlbumCreated, func(event models.Event) error {
+		return errors.New("malformed payload")
+	})
+	dispatcher.Subscribe(models.EventAlbumUpdated, func(event models.Event) error {
+		received = append(received, event.Type)
+		return nil
+	})
+
+	for pass := 0; pass < 3; pass++ {
+		if _, err := dispatcher.DispatchPending(); err != nil {
+			t.Fatalf("failed to dispatch events: %v", err)
+		}
+	}
+	if len(received) != 0 {
+		t.Fatalf("expected the update to wait while the create is retried, got %v", received)
+	}
+
+	var attempts int
+	var lastError string
+	var deadAt sql.NullString
+	db.QueryRow("SELECT attempts, last_error, dead_at FROM outbox_events WHERE id = 1").Scan(&attempts, &lastError, &deadAt)
+	if attempts != 3 || lastError != "malformed payload" || !deadAt.Valid {
+		t.Fatalf("expected the create to be parked after 3 attempts, got %d attempts, %q, %v", attempts, lastError, deadAt)
+	}
+
+	// Once parked the event is no longer retried and no longer holds up the entity's later events
+	dispatched, err := dispatcher.DispatchPending()
+	if err != nil {
+		t.Fatalf("failed to dispatch events: %v", err)
+	}
+	if dispatched != 1 || len(received) != 1 || received[0] != models.EventAlbumUpdated {
+		t.Errorf("expected only the update to be delivered, got %d: %v", dispatched, received)
+	}
+}
+
+func TestLogEventDispatchesEvents(t *testing.T) {
+	db, outbox := setupTestOutbox(t)
+	appendTestEvent(t, db, outbox, 1, models.EventAlbumCreated)
+
+	var logged bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logged)
+
+	dispatcher := &services.OutboxDispatcher{Repo: outbox}
+	dispatcher.Subscribe(services.AllEvents, services.LogEvent)
+	if dispatched, err := dispatcher.DispatchPending(); err != nil || dispatched != 1 {
+		t.Fatalf("expected the event to be dispatched, got %d (%v)", dispatched, err)
+	}
+	if !strings.Contains(logged.String(), models.EventAlbumCreated+" album 1") {
+		t.Errorf("expected the event to be logged, got %q", logged.String())
+	}
+}
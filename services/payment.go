@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"sync"
+)
+
+// ErrPaymentDeclined is returned when the payment provider refuses to charge an order.
+var ErrPaymentDeclined = errors.New("payment declined")
+
+// PaymentProvider charges orders and refunds them when paid orders are cancelled.
+type PaymentProvider interface {
+	// Charge takes the order's total and returns the provider's reference for the payment.
+	Charge(order *models.Order) (string, error)
+	// Refund returns the payment with the order's PaymentReference.
+	Refund(order *models.Order) error
+}
+
+// FakePaymentProvider accepts every payment without charging anyone. It stands in for a real provider in
+// development and tests, and declines every charge when Decline is set.
+type FakePaymentProvider struct {
+	Decline bool
+
+	mu       sync.Mutex
+	charged  []string
+	refunded []string
+}
+
+// Charge records a payment of the order's total, or declines it.
+func (p *FakePaymentProvider) Charge(order *models.Order) (string, error) {
+	if p.Decline {
+		return "", ErrPaymentDeclined
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reference := fmt.Sprintf("fake-%d-%d", order.ID, len(p.charged)+1)
+	p.charged = append(p.charged, reference)
+	return reference, nil
+}
+
+// Refund records the refund of an order's payment.
+func (p *FakePaymentProvider) Refund(order *models.Order) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refunded = append(p.refunded, order.PaymentReference)
+	return nil
+}
+
+// Refunded returns the references of the payments refunded so far.
+func (p *FakePaymentProvider) Refunded() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.refunded...)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Play limits.
+const (
+	MaxPlayBatch = 500
+	// MaxClientIDLength bounds the client's identifier of a play.
+	MaxClientIDLength = 100
+	// PlayClockSkew is how far in the future a play may be dated, allowing for clients with fast clocks.
+	PlayClockSkew = 5 * time.Minute
+
+	DefaultPlaysPage = 50
+	MaxPlaysPage     = 200
+	DefaultTopPlays  = 10
+	MaxTopPlays      = 100
+)
+
+type PlayService struct {
+	Repo *repositories.PlayRepository
+}
+
+// validatePlay checks a play's client ID, album, track and duration, and normalises its time, which
+// defaults to now. It returns the time played.
+func validatePlay(play *models.Play, now time.Time) (time.Time, error) {
+	play.ClientID = strings.TrimSpace(play.ClientID)
+	if play.ClientID == "" {
+		return time.Time{}, errors.New("client_id is required")
+	}
+	if len(play.ClientID) > MaxClientIDLength {
+		return time.Time{}, fmt.Errorf("client_id must be at most %d characters", MaxClientIDLength)
+	}
+	if play.AlbumID == 0 {
+		return time.Time{}, errors.New("album_id is required")
+	}
+	if play.Track < 0 {
+		return time.Time{}, errors.New("track must not be negative")
+	}
+	if play.DurationSeconds < 0 {
+		return time.Time{}, errors.New("duration_seconds must not be negative")
+	}
+
+	if play.PlayedAt == "" {
+		play.PlayedAt = now.Format(models.TimeFormat)
+		return now, nil
+	}
+	playedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(play.PlayedAt))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("played_at %q must be in RFC 3339 format, e.g. 2024-11-29T20:15:00Z", play.PlayedAt)
+	}
+	if playedAt.After(now.Add(PlayClockSkew)) {
+		return time.Time{}, fmt.Errorf("played_at %s is in the future", play.PlayedAt)
+	}
+	play.PlayedAt = playedAt.UTC().Format(models.TimeFormat)
+	return playedAt, nil
+}
+
+// RecordPlay records that a customer played an album. It reports false, with the play replaced by the
+// stored one, when the customer already sent a play with the same client ID.
+func (s *PlayService) RecordPlay(customerID uint, play *models.Play) (bool, error) {
+	plays := []models.Play{*play}
+	results, rejections, err := s.record(customerID, plays)
+	if err != nil {
+		return false, err
+	}
+	if rejections[0] != nil {
+		return false, rejections[0]
+	}
+	*play = plays[0]
+	return results[0].Status == models.PlayAccepted, nil
+}
+
+// RecordPlays records up to MaxPlayBatch plays of a customer at once. Invalid plays are rejected one by
+// one without holding up the others, and plays whose client ID the customer already sent are reported
+// as duplicates.
+func (s *PlayService) RecordPlays(customerID uint, batch models.PlayBatch) (*models.PlayBatchResult, error) {
+	if len(batch.Plays) == 0 {
+		return nil, errors.New("at least one play is required")
+	}
+	if len(batch.Plays) > MaxPlayBatch {
+		return nil, fmt.Errorf("at most %d plays can be sent at once", MaxPlayBatch)
+	}
+
+	results, _, err := s.record(customerID, batch.Plays)
+	if err != nil {
+		return nil, err
+	}
+	outcome := &models.PlayBatchResult{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case models.PlayAccepted:
+			outcome.Accepted++
+		case models.PlayDuplicate:
+			outcome.Duplicates++
+		default:
+			outcome.Rejected++
+		}
+	}
+	return outcome, nil
+}
+
+// record validates and stores plays of a customer, updating them in place, and counts the new ones
+// towards the weekly plays chart in the same transaction. It returns the outcome of each play and why
+// each rejected play was rejected.
+func (s *PlayService) record(customerID uint, plays []models.Play) ([]models.PlayResult, []error, error) {
+	now := time.Now().UTC()
+	rejections := make([]error, len(plays))
+	periods := make([]string, len(plays))
+	var albumIDs []uint
+	for i := range plays {
+		plays[i].ID, plays[i].CustomerID, plays[i].AlbumName = 0, customerID, ""
+		playedAt, err := validatePlay(&plays[i], now)
+		if err != nil {
+			rejections[i] = err
+			continue
+		}
+		periods[i] = PeriodOf(playedAt)
+		albumIDs = append(albumIDs, plays[i].AlbumID)
+	}
+
+	names, err := s.Repo.GetAlbumNames(albumIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var valid []models.Play
+	var validPeriods []string
+	var indexes []int
+	for i, play := range plays {
+		if rejections[i] != nil {
+			continue
+		}
+		name, ok := names[play.AlbumID]
+		if !ok {
+			rejections[i] = fmt.Errorf("album %d does not exist: %w", play.AlbumID, sql.ErrNoRows)
+			continue
+		}
+		play.AlbumName = name
+		valid = append(valid, play)
+		validPeriods = append(validPeriods, periods[i])
+		indexes = append(indexes, i)
+	}
+
+	var created []bool
+	if len(valid) > 0 {
+		if created, err = s.Repo.CreatePlays(valid, validPeriods, now.Format(models.TimeFormat)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	results := make([]models.PlayResult, len(plays))
+	for i, play := range plays {
+		results[i] = models.PlayResult{ClientID: play.ClientID, Status: models.PlayRejected}
+		if rejections[i] != nil {
+			results[i].Error = rejections[i].Error()
+		}
+	}
+	for j, i := range indexes {
+		plays[i] = valid[j]
+		results[i].PlayID = valid[j].ID
+		results[i].Status = models.PlayDuplicate
+		if created[j] {
+			results[i].Status = models.PlayAccepted
+		}
+	}
+	return results, rejections, nil
+}
+
+// playRange normalises an optional RFC 3339 time range into a filter, checking that it is not empty.
+func playRange(from, to string) (models.PlayFilter, error) {
+	var filter models.PlayFilter
+	var err error
+	if from != "" {
+		if filter.From, err = ParseTime(from); err != nil {
+			return filter, err
+		}
+	}
+	if to != "" {
+		if filter.To, err = ParseTime(to); err != nil {
+			return filter, err
+		}
+	}
+	if filter.From != "" && filter.To != "" && filter.From >= filter.To {
+		return filter, errors.New("from must be before to")
+	}
+	return filter, nil
+}
+
+// GetHistory retrieves one page of a customer's plays between two optional RFC 3339 times, most recently
+// played first. A zero perPage means DefaultPlaysPage.
+func (s *PlayService) GetHistory(customerID uint, from, to string, page, perPage int) (*models.PlayPage, error) {
+	if perPage == 0 {
+		perPage = DefaultPlaysPage
+	}
+	if page < 1 {
+		return nil, errors.New("page must be at least 1")
+	}
+	if perPage < 1 || perPage > MaxPlaysPage {
+		return nil, fmt.Errorf("per_page must be from 1 to %d", MaxPlaysPage)
+	}
+	filter, err := playRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.CustomerID = customerID
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+	plays, total, err := s.Repo.GetPlays(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlayPage{Plays: plays, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// GetTop ranks the most played albums, musicians or genres between two optional RFC 3339 times, by
+// every customer or, for a non-zero customerID, by one. The range ends now unless to is given. A zero
+// limit means DefaultTopPlays.
+func (s *PlayService) GetTop(topType string, customerID uint, from, to string, limit int) (*models.PlayStats, error) {
+	if !slices.Contains(models.TopTypes, topType) {
+		return nil, fmt.Errorf("statistics type must be one of %s", strings.Join(models.TopTypes, ", "))
+	}
+	if limit == 0 {
+		limit = DefaultTopPlays
+	}
+	if limit < 1 || limit > MaxTopPlays {
+		return nil, fmt.Errorf("limit must be from 1 to %d", MaxTopPlays)
+	}
+	if to == "" {
+		to = time.Now().UTC().Format(models.TimeFormat)
+	}
+	filter, err := playRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.CustomerID = customerID
+	filter.Limit = limit
+	top, plays, err := s.Repo.GetTop(topType, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlayStats{Type: topType, From: filter.From, To: filter.To, Plays: plays, Top: top}, nil
+}
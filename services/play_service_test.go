@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+	"time"
+)
+
+func setupPlayService(t *testing.T) (*services.PlayService, *services.ChartService) {
+	charts := setupChartService(t)
+	_, err := charts.Repo.DB.Exec(`
+		CREATE TABLE plays (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			customer_id INTEGER NOT NULL,
+			client_id TEXT NOT NULL,
+			album_id INTEGER NOT NULL,
+			track INTEGER NOT NULL DEFAULT 0,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			played_at TEXT NOT NULL,
+			received_at TEXT NOT NULL,
+			UNIQUE (customer_id, client_id)
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create plays table: %v", err)
+	}
+	return &services.PlayService{Repo: &repositories.PlayRepository{DB: charts.Repo.DB}}, charts
+}
+
+func TestRecordPlay(t *testing.T) {
+	service, charts := setupPlayService(t)
+
+	play := models.Play{ClientID: " phone-1 ", AlbumID: 2, Track: 4}
+	created, err := service.RecordPlay(7, &play)
+	if err != nil {
+		t.Fatalf("failed to record play: %v", err)
+	}
+	if !created || play.ID == 0 || play.ClientID != "phone-1" || play.CustomerID != 7 || play.AlbumName != "Album 2" || play.PlayedAt == "" {
+		t.Errorf("unexpected play %+v", play)
+	}
+
+	again := models.Play{ClientID: "phone-1", AlbumID: 1}
+	created, err = service.RecordPlay(7, &again)
+	if err != nil {
+		t.Fatalf("failed to record play again: %v", err)
+	}
+	if created || again.ID != play.ID || again.AlbumID != 2 || again.Track != 4 {
+		t.Errorf("expected the stored play for a client ID sent before, got %+v", again)
+	}
+
+	chart, err := charts.GetChart(models.ChartPlays, services.PeriodOf(time.Now()), "", 0)
+	if err != nil {
+		t.Fatalf("failed to get chart: %v", err)
+	}
+	if len(chart.Entries) != 1 || chart.Entries[0].Album.ID != 2 || chart.Entries[0].Count != 1 {
+		t.Errorf("expected the play counted once towards this week's chart, got %+v", chart.Entries)
+	}
+
+	if _, err := service.RecordPlay(7, &models.Play{ClientID: "phone-2", AlbumID: 99}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing album, got %v", err)
+	}
+}
+
+func TestRecordPlays(t *testing.T) {
+	service, _ := setupPlayService(t)
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+
+	result, err := service.RecordPlays(7, models.PlayBatch{Plays: []models.Play{
+		{ClientID: "1", AlbumID: 1, PlayedAt: "2026-01-05T12:00:00+02:00"},
+		{ClientID: "1", AlbumID: 1},
+		{ClientID: "2", AlbumID: 99},
+		{ClientID: "", AlbumID: 1},
+		{ClientID: "3", AlbumID: 1, PlayedAt: future},
+		{ClientID: "4", AlbumID: 1, PlayedAt: "yesterday"},
+		{ClientID: "5", AlbumID: 3, DurationSeconds: 240},
+	}})
+	if err != nil {
+		t.Fatalf("failed to record plays: %v", err)
+	}
+	if result.Accepted != 2 || result.Duplicates != 1 || result.Rejected != 4 || len(result.Results) != 7 {
+		t.Fatalf("unexpected outcome %+v", result)
+	}
+	statuses := []string{models.PlayAccepted, models.PlayDuplicate, models.PlayRejected, models.PlayRejected, models.PlayRejected, models.PlayRejected, models.PlayAccepted}
+	for i, status := range statuses {
+		if result.Results[i].Status != status {
+			t.Errorf("play %d: expected %s, got %+v", i, status, result.Results[i])
+		}
+	}
+	if result.Results[1].PlayID != result.Results[0].PlayID || result.Results[2].Error == "" || result.Results[2].PlayID != 0 {
+		t.Errorf("unexpected results %+v", result.Results)
+	}
+
+	history, err := service.GetHistory(7, "", "", 1, 0)
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	if history.Total != 2 || history.PerPage != services.DefaultPlaysPage || history.Plays[1].PlayedAt != "2026-01-05T10:00:00Z" {
+		t.Errorf("expected times stored in UTC, got %+v", history)
+	}
+
+	if _, err := service.RecordPlays(7, models.PlayBatch{}); err == nil {
+		t.Error("expected an empty batch to be rejected")
+	}
+	if _, err := service.RecordPlays(7, models.PlayBatch{Plays: make([]models.Play, services.MaxPlayBatch+1)}); err == nil {
+		t.Error("expected an oversized batch to be rejected")
+	}
+}
+
+func TestPlayStatistics(t *testing.T) {
+	service, _ := setupPlayService(t)
+	for customerID, albums := range map[uint][]uint{1: {1, 1, 2}, 2: {2, 3}} {
+		for i, albumID := range albums {
+			play := models.Play{ClientID: fmt.Sprintf("play-%d", i), AlbumID: albumID, PlayedAt: fmt.Sprintf("2026-03-%02dT00:00:00Z", i+1)}
+			if _, err := service.RecordPlay(customerID, &play); err != nil {
+				t.Fatalf("failed to record play: %v", err)
+			}
+		}
+	}
+
+	stats, err := service.GetTop(models.TopAlbums, 0, "", "", 0)
+	if err != nil {
+		t.Fatalf("failed to get top albums: %v", err)
+	}
+	if stats.Plays != 5 || stats.To == "" || len(stats.Top) != 3 || stats.Top[0].Name != "Album 1" || stats.Top[1].Plays != 2 || stats.Top[1].Listeners != 2 {
+		t.Errorf("expected ties ranked by name, got %+v", stats)
+	}
+	later, err := service.GetTop(models.TopAlbums, 0, "2026-03-02T00:00:00Z", "", 1)
+	if err != nil {
+		t.Fatalf("failed to get top albums: %v", err)
+	}
+	if later.Plays != 3 || len(later.Top) != 1 || later.Top[0].Plays != 1 {
+		t.Errorf("expected only plays from the start of the range, got %+v", later)
+	}
+
+	mine, err := service.GetTop(models.TopGenres, 2, "", "", 0)
+	if err != nil {
+		t.Fatalf("failed to get top genres: %v", err)
+	}
+	if mine.Plays != 2 || len(mine.Top) != 1 || mine.Top[0].Name != "Heavy Metal" {
+		t.Errorf("expected one customer's genres, got %+v", mine)
+	}
+
+	invalid := []struct {
+		topType, from, to string
+		limit             int
+	}{
+		{"labels", "", "", 0},
+		{models.TopAlbums, "", "", services.MaxTopPlays + 1},
+		{models.TopAlbums, "2026-03-02", "", 0},
+		{models.TopAlbums, "2026-03-02T00:00:00Z", "2026-03-01T00:00:00Z", 0},
+	}
+	for _, request := range invalid {
+		if _, err := service.GetTop(request.topType, 0, request.from, request.to, request.limit); err == nil {
+			t.Errorf("expected %+v to be rejected", request)
+		}
+	}
+	if _, err := service.GetHistory(1, "", "", 0, 0); err == nil {
+		t.Error("expected page 0 to be rejected")
+	}
+	if _, err := service.GetHistory(1, "", "", 1, services.MaxPlaysPage+1); err == nil {
+		t.Error("expected an oversized page to be rejected")
+	}
+}
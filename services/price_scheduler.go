@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PriceScheduler changes album prices when their scheduled changes fall due. Promotions need no
+// activation: effective prices are resolved against their start and end times whenever albums are read.
+type PriceScheduler struct {
+	Service  *PromotionService
+	Interval time.Duration
+}
+
+// Run applies due price changes until the context is cancelled.
+func (s *PriceScheduler) Run(ctx context.Context) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := s.Service.ApplyDueSchedules(time.Now())
+		if err != nil {
+			log.Println("Error applying scheduled price changes:", err)
+		} else if applied > 0 {
+			log.Printf("Applied %d scheduled price changes", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"strings"
+)
+
+// ErrNoExchangeRate is returned when a price has to be converted from or to a currency without an exchange rate.
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// PriceService manages exchange rates and per-currency album price lists, and reports album
+// prices in a requested currency.
+type PriceService struct {
+	Repo      *repositories.PriceRepository
+	AlbumRepo *repositories.AlbumRepository
+}
+
+// NormalizeCurrency upper-cases an ISO 4217 currency code, defaulting an empty one to
+// models.BaseCurrency, and rejects currencies that are not supported.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return models.BaseCurrency, nil
+	}
+	if _, ok := models.CurrencyExponents[currency]; !ok {
+		return "", fmt.Errorf("unsupported currency %q", currency)
+	}
+	return currency, nil
+}
+
+// validateAmount checks that an amount has no more decimals than the currency's minor unit allows.
+func validateAmount(amount float64, currency string) error {
+	if math.Abs(models.FromMinor(models.ToMinor(amount, currency), currency)-amount) > 1e-9 {
+		return fmt.Errorf("price has more decimals than %s allows", currency)
+	}
+	return nil
+}
+
+// checkPriceRange checks that a price lies between MinAlbumPrice and MaxAlbumPrice once converted to
+// models.BaseCurrency. prices is only needed for prices in other currencies.
+func checkPriceRange(prices *PriceService, amount float64, currency string) error {
+	base := amount
+	if currency != models.BaseCurrency {
+		if prices == nil {
+			return fmt.Errorf("%w for %s to check the price against", ErrNoExchangeRate, currency)
+		}
+		converted, err := prices.Convert(amount, currency, models.BaseCurrency)
+		if err != nil {
+			return err
+		}
+		base = converted
+	}
+
+	if base < MinAlbumPrice || base > MaxAlbumPrice {
+		return fmt.Errorf("price must be between %d and %d %s", MinAlbumPrice, MaxAlbumPrice, models.BaseCurrency)
+	}
+	return nil
+}
+
+// rates returns the exchange rate of every currency that has one, including models.BaseCurrency.
+func (s *PriceService) rates() (map[string]float64, error) {
+	stored, err := s.Repo.GetExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+	rates := map[string]float64{models.BaseCurrency: 1}
+	for _, rate := range stored {
+		rates[rate.Currency] = rate.Rate
+	}
+	return rates, nil
+}
+
+// Convert converts an amount between currencies through their exchange rates, rounding half away
+// from zero to the minor unit of the target currency.
+func (s *PriceService) Convert(amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	rates, err := s.rates()
+	if err != nil {
+		return 0, err
+	}
+	return convert(rates, amount, from, to)
+}
+
+func convert(rates map[string]float64, amount float64, from, to string) (float64, error) {
+	fromRate, ok := rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w for %s", ErrNoExchangeRate, from)
+	}
+	toRate, ok := rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w for %s", ErrNoExchangeRate, to)
+	}
+	minor := models.ConvertMinor(models.ToMinor(amount, from), from, fromRate, to, toRate)
+	return models.FromMinor(minor, to), nil
+}
+
+// UploadExchangeRates validates and replaces every exchange rate. Rates are quoted as units of the
+// currency per unit of models.BaseCurrency, whose own rate is always 1.
+func (s *PriceService) UploadExchangeRates(rates []models.ExchangeRate) ([]models.ExchangeRate, error) {
+	seen := map[string]bool{}
+	stored := []models.ExchangeRate{}
+	for _, rate := range rates {
+		currency, err := NormalizeCurrency(rate.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if seen[currency] {
+			return nil, fmt.Errorf("currency %s is listed twice", currency)
+		}
+		seen[currency] = true
+
+		if rate.Rate <= 0 {
+			return nil, fmt.Errorf("rate for %s must be positive", currency)
+		}
+		if currency == models.BaseCurrency {
+			if rate.Rate != 1 {
+				return nil, fmt.Errorf("rate for the base currency %s must be 1", currency)
+			}
+			continue
+		}
+		stored = append(stored, models.ExchangeRate{Currency: currency, Rate: rate.Rate})
+	}
+
+	if err := s.Repo.ReplaceExchangeRates(stored); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetExchangeRates()
+}
+
+// GetExchangeRates retrieves every exchange rate sorted by currency.
+func (s *PriceService) GetExchangeRates() ([]models.ExchangeRate, error) {
+	return s.Repo.GetExchangeRates()
+}
+
+func (s *PriceService) requireAlbum(albumID uint) error {
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// SetAlbumPrice validates and sets the price of an album in one currency.
+func (s *PriceService) SetAlbumPrice(price *models.AlbumPrice) error {
+	if err := s.requireAlbum(price.AlbumID); err != nil {
+		return err
+	}
+	currency, err := NormalizeCurrency(price.Currency)
+	if err != nil {
+		return err
+	}
+	price.Currency = currency
+	if price.Price <= 0 {
+		return errors.New("price must be positive")
+	}
+	if err := validateAmount(price.Price, currency); err != nil {
+		return err
+	}
+	return s.Repo.SetAlbumPrice(*price)
+}
+
+// RemoveAlbumPrice removes the price of an album in one currency.
+func (s *PriceService) RemoveAlbumPrice(albumID uint, currency string) error {
+	currency = strings.ToUpper(currency)
+	if err := s.Repo.DeleteAlbumPrice(albumID, currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d has no price in %s: %w", albumID, currency, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAlbumPrices retrieves the price list of an album sorted by currency.
+func (s *PriceService) GetAlbumPrices(albumID uint) ([]models.AlbumPrice, error) {
+	if err := s.requireAlbum(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAlbumPrices(albumID)
+}
+
+// PriceAlbums reports the prices of albums in a currency. An album's price list entry for the
+// currency is used as is; otherwise its own price is converted through the exchange rates. The
+// effective price is scaled along with the price.
+func (s *PriceService) PriceAlbums(albums []*models.Album, currency string) error {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uint, len(albums))
+	for i, album := range albums {
+		ids[i] = album.ID
+	}
+	listed, err := s.Repo.GetAlbumPricesIn(ids, currency)
+	if err != nil {
+		return err
+	}
+	rates, err := s.rates()
+	if err != nil {
+		return err
+	}
+
+	for _, album := range albums {
+		if err := priceAlbum(album, currency, listed, rates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AlbumPricer returns a function pricing streamed chunks of albums in a currency as PriceAlbums does.
+// The exchange rates are read up front, and each chunk's listed prices with one query by album ID. It
+// returns ErrNoExchangeRate when the currency has no rate, before anything is priced.
+func (s *PriceService) AlbumPricer(currency string) (func([]*models.Album) error, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.rates()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := rates[currency]; !ok {
+		return nil, fmt.Errorf("%w for %s", ErrNoExchangeRate, currency)
+	}
+
+	return func(albums []*models.Album) error {
+		ids := make([]uint, len(albums))
+		for i, album := range albums {
+			ids[i] = album.ID
+		}
+		listed, err := s.Repo.GetAlbumPricesIn(ids, currency)
+		if err != nil {
+			return err
+		}
+		for _, album := range albums {
+			if err := priceAlbum(album, currency, listed, rates); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// priceAlbum reports an album's price in a currency from its listed price, or converted through the rates.
+func priceAlbum(album *models.Album, currency string, listed map[uint]float64, rates map[string]float64) error {
+	own := album.Price
+	if price, ok := listed[album.ID]; ok {
+		album.Price = price
+	} else if album.Currency != currency {
+		converted, err := convert(rates, album.Price, album.Currency, currency)
+		if err != nil {
+			return err
+		}
+		album.Price = converted
+	}
+	// The effective price keeps its discount in proportion to the price reported
+	if own != 0 {
+		album.EffectivePrice = models.FromMinor(models.ToMinor(album.EffectivePrice*album.Price/own, currency), currency)
+	}
+	album.Currency = currency
+	return nil
+}
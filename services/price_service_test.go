@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+func setupPriceService(t *testing.T) *services.PriceService {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE album_prices (
+			album_id INTEGER NOT NULL,
+			currency TEXT NOT NULL,
+			price_minor INTEGER NOT NULL,
+			PRIMARY KEY (album_id, currency)
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	return &services.PriceService{Repo: &repositories.PriceRepository{DB: albumRepo.DB}, AlbumRepo: albumRepo}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		currency string
+		expected string
+		valid    bool
+	}{
+		{"", models.BaseCurrency, true},
+		{" eur ", "EUR", true},
+		{"JPY", "JPY", true},
+		{"XXX", "", false},
+		{"EURO", "", false},
+	}
+	for _, tt := range tests {
+		currency, err := services.NormalizeCurrency(tt.currency)
+		if (err == nil) != tt.valid || currency != tt.expected {
+			t.Errorf("NormalizeCurrency(%q) = %q, %v, want %q, valid %v", tt.currency, currency, err, tt.expected, tt.valid)
+		}
+	}
+}
+
+func TestUploadExchangeRatesValidation(t *testing.T) {
+	service := setupPriceService(t)
+
+	invalid := [][]models.ExchangeRate{
+		{{Currency: "XXX", Rate: 1.5}},
+		{{Currency: "EUR", Rate: 0}},
+		{{Currency: "EUR", Rate: 0.9}, {Currency: "eur", Rate: 0.91}},
+		{{Currency: "USD", Rate: 1.1}},
+	}
+	for _, rates := range invalid {
+		if _, err := service.UploadExchangeRates(rates); err == nil {
+			t.Errorf("expected %+v to be rejected", rates)
+		}
+	}
+
+	stored, err := service.UploadExchangeRates([]models.ExchangeRate{{Currency: "usd", Rate: 1}, {Currency: "gbp", Rate: 0.8}})
+	if err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Currency != "GBP" {
+		t.Errorf("expected only the upper-cased non-base rate to be stored, got %+v", stored)
+	}
+}
+
+func TestConvertRounding(t *testing.T) {
+	service := setupPriceService(t)
+	if _, err := service.UploadExchangeRates([]models.ExchangeRate{
+		{Currency: "EUR", Rate: 0.9},
+		{Currency: "JPY", Rate: 150.25},
+		{Currency: "KWD", Rate: 0.3075},
+	}); err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+
+	tests := []struct {
+		amount   float64
+		from, to string
+		expected float64
+	}{
+		{10, "USD", "EUR", 9},
+		{0.05, "USD", "EUR", 0.05}, // 4.5 cents rounds half away from zero
+		{12.99, "USD", "JPY", 1952},
+		{12.99, "USD", "KWD", 3.994},
+		{1000, "JPY", "USD", 6.66},
+		{9, "EUR", "USD", 10},
+		{12.99, "EUR", "EUR", 12.99},
+	}
+	for _, tt := range tests {
+		converted, err := service.Convert(tt.amount, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("failed to convert %v %s: %v", tt.amount, tt.from, err)
+		}
+		if converted != tt.expected {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.amount, tt.from, tt.to, converted, tt.expected)
+		}
+	}
+
+	if _, err := service.Convert(10, "USD", "GBP"); !errors.Is(err, services.ErrNoExchangeRate) {
+		t.Errorf("expected ErrNoExchangeRate, got %v", err)
+	}
+}
+
+func TestGetAlbumsInCurrency(t *testing.T) {
+	prices := setupPriceService(t)
+	albumService := &services.AlbumService{Repo: prices.AlbumRepo, Prices: prices}
+
+	if _, err := prices.UploadExchangeRates([]models.ExchangeRate{{Currency: "EUR", Rate: 0.9}}); err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+
+	listed := &models.Album{Name: "Listed Album", ReleaseDate: "2020-01-01", Price: 200}
+	converted := &models.Album{Name: "Converted Album", ReleaseDate: "2021-01-01", Price: 180, Currency: "eur"}
+	for _, album := range []*models.Album{listed, converted} {
+		if err := albumService.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	if err := prices.SetAlbumPrice(&models.AlbumPrice{AlbumID: listed.ID, Currency: "EUR", Price: 175}); err != nil {
+		t.Fatalf("failed to set price: %v", err)
+	}
+
+	albums, err := albumService.GetAlbums(models.AlbumFilter{Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if len(albums) != 2 || albums[0].Price != 175 || albums[1].Price != 180 || albums[0].Currency != "EUR" {
+		t.Errorf("expected the listed price and the album's own EUR price, got %+v", albums)
+	}
+
+	albums, err = albumService.GetAlbums(models.AlbumFilter{Currency: "USD"})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if albums[0].Price != 200 || albums[1].Price != 200 || albums[1].Currency != "USD" {
+		t.Errorf("expected the EUR album converted to USD, got %+v", albums)
+	}
+
+	if _, err := albumService.GetAlbums(models.AlbumFilter{Currency: "GBP"}); !errors.Is(err, services.ErrNoExchangeRate) {
+		t.Errorf("expected ErrNoExchangeRate, got %v", err)
+	}
+
+	// 50 EUR converts to about 55.56 USD, below the price limit
+	if err := albumService.CreateAlbum(&models.Album{Name: "Cheap Album", ReleaseDate: "2022-01-01", Price: 50, Currency: "EUR"}); err == nil {
+		t.Error("expected a price below the limit once converted to be rejected")
+	}
+	if err := albumService.CreateAlbum(&models.Album{Name: "Pound Album", ReleaseDate: "2022-01-01", Price: 200, Currency: "GBP"}); !errors.Is(err, services.ErrNoExchangeRate) {
+		t.Errorf("expected ErrNoExchangeRate, got %v", err)
+	}
+	if err := albumService.CreateAlbum(&models.Album{Name: "Yen Album", ReleaseDate: "2022-01-01", Price: 300.5, Currency: "JPY"}); err == nil || errors.Is(err, services.ErrNoExchangeRate) {
+		t.Error("expected a JPY price with decimals to be rejected")
+	}
+}
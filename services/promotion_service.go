@@ -0,0 +1,321 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"strings"
+	"time"
+)
+
+// PromotionService manages promotions and scheduled price changes, and resolves what albums cost at a
+// point in time.
+type PromotionService struct {
+	Repo      *repositories.PromotionRepository
+	AlbumRepo *repositories.AlbumRepository
+	// Prices, when set, converts fixed discounts to the currency of the albums they apply to, and checks
+	// scheduled prices in other currencies than models.BaseCurrency against the price limits.
+	Prices *PriceService
+	// Editions, when set, is used to refuse price changes for albums whose price follows their editions.
+	Editions *repositories.EditionRepository
+}
+
+// ParseTime parses an RFC 3339 time and formats it in UTC as models.TimeFormat.
+func ParseTime(value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("time %q must be in RFC 3339 format, e.g. 2024-11-29T00:00:00Z", value)
+	}
+	return t.UTC().Format(models.TimeFormat), nil
+}
+
+// validatePromotion checks a promotion's name, discount, target and period, normalising its kind,
+// currency and times.
+func (s *PromotionService) validatePromotion(promotion *models.Promotion) error {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" {
+		return errors.New("promotion name is required")
+	}
+
+	promotion.Kind = strings.ToLower(strings.TrimSpace(promotion.Kind))
+	switch promotion.Kind {
+	case models.PromotionPercentage:
+		if promotion.PercentOff <= 0 || promotion.PercentOff > 100 {
+			return errors.New("percent_off must be more than 0 and at most 100")
+		}
+		if promotion.AmountOff != 0 || promotion.Currency != "" {
+			return errors.New("percentage promotions take no amount_off or currency")
+		}
+	case models.PromotionFixed:
+		if promotion.PercentOff != 0 {
+			return errors.New("fixed promotions take no percent_off")
+		}
+		currency, err := NormalizeCurrency(promotion.Currency)
+		if err != nil {
+			return err
+		}
+		promotion.Currency = currency
+		if promotion.AmountOff <= 0 {
+			return errors.New("amount_off must be positive")
+		}
+		if err := validateAmount(promotion.AmountOff, currency); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("kind must be one of %s", strings.Join(models.PromotionKinds, ", "))
+	}
+
+	if err := s.validateTarget(promotion); err != nil {
+		return err
+	}
+
+	var err error
+	if promotion.StartsAt, err = ParseTime(promotion.StartsAt); err != nil {
+		return err
+	}
+	if promotion.EndsAt, err = ParseTime(promotion.EndsAt); err != nil {
+		return err
+	}
+	if promotion.EndsAt <= promotion.StartsAt {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// validateTarget checks that a promotion targets exactly one album, genre, label or musician, and that it exists.
+func (s *PromotionService) validateTarget(promotion *models.Promotion) error {
+	targets := map[string]uint{
+		"albums":    promotion.AlbumID,
+		"genres":    promotion.GenreID,
+		"labels":    promotion.LabelID,
+		"musicians": promotion.MusicianID,
+	}
+
+	var table string
+	for name, id := range targets {
+		if id == 0 {
+			continue
+		}
+		if table != "" {
+			return errors.New("promotion must target only one of album_id, genre_id, label_id or musician_id")
+		}
+		table = name
+	}
+	if table == "" {
+		return errors.New("promotion must target an album_id, genre_id, label_id or musician_id")
+	}
+
+	exists, err := s.Repo.TargetExists(table, targets[table])
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s %d does not exist", strings.TrimSuffix(table, "s"), targets[table])
+	}
+	return nil
+}
+
+// CreatePromotion validates and creates a promotion.
+func (s *PromotionService) CreatePromotion(promotion *models.Promotion) error {
+	if err := s.validatePromotion(promotion); err != nil {
+		return err
+	}
+	return s.Repo.CreatePromotion(promotion)
+}
+
+// UpdatePromotion validates and updates a promotion.
+func (s *PromotionService) UpdatePromotion(promotion *models.Promotion) error {
+	if err := s.validatePromotion(promotion); err != nil {
+		return err
+	}
+	if err := s.Repo.UpdatePromotion(promotion); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promotion %d does not exist: %w", promotion.ID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeletePromotion deletes a promotion.
+func (s *PromotionService) DeletePromotion(promotionID uint) error {
+	if err := s.Repo.DeletePromotion(promotionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promotion %d does not exist: %w", promotionID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetPromotions retrieves every promotion sorted by start time.
+func (s *PromotionService) GetPromotions() ([]models.Promotion, error) {
+	return s.Repo.GetPromotions()
+}
+
+// GetPromotion retrieves a promotion by ID.
+func (s *PromotionService) GetPromotion(promotionID uint) (*models.Promotion, error) {
+	promotion, err := s.Repo.GetPromotionByID(promotionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("promotion %d does not exist: %w", promotionID, err)
+	}
+	return promotion, err
+}
+
+func (s *PromotionService) album(albumID uint) (*models.Album, error) {
+	album, err := s.AlbumRepo.GetAlbumByID(albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+	}
+	return album, err
+}
+
+// SchedulePrice validates and schedules a change of an album's own price. Albums with editions take their
+// price from their editions and cannot be scheduled.
+func (s *PromotionService) SchedulePrice(schedule *models.PriceSchedule) error {
+	album, err := s.album(schedule.AlbumID)
+	if err != nil {
+		return err
+	}
+	if s.Editions != nil {
+		editions, err := s.Editions.GetEditionsByAlbum(album.ID)
+		if err != nil {
+			return err
+		}
+		if len(editions) > 0 {
+			return fmt.Errorf("album %d takes its price from its editions", album.ID)
+		}
+	}
+
+	if err := validateAmount(schedule.Price, album.Currency); err != nil {
+		return err
+	}
+	if err := checkPriceRange(s.Prices, schedule.Price, album.Currency); err != nil {
+		return err
+	}
+	if schedule.StartsAt, err = ParseTime(schedule.StartsAt); err != nil {
+		return err
+	}
+	schedule.AppliedAt = ""
+	return s.Repo.CreatePriceSchedule(schedule, album.Currency)
+}
+
+// CancelPriceSchedule cancels a pending price change of an album.
+func (s *PromotionService) CancelPriceSchedule(albumID, scheduleID uint) error {
+	if err := s.Repo.DeletePriceSchedule(albumID, scheduleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d has no pending price change %d: %w", albumID, scheduleID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetPriceSchedules retrieves the scheduled price changes of an album sorted by start time.
+func (s *PromotionService) GetPriceSchedules(albumID uint) ([]models.PriceSchedule, error) {
+	if _, err := s.album(albumID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetPriceSchedules(albumID)
+}
+
+// ApplyDueSchedules changes the price of every album with a scheduled change due by now.
+func (s *PromotionService) ApplyDueSchedules(now time.Time) (int, error) {
+	return s.Repo.ApplyDueSchedules(now.UTC().Format(models.TimeFormat))
+}
+
+// GetEffectivePrice resolves what an album costs at a point in time.
+func (s *PromotionService) GetEffectivePrice(albumID uint, at time.Time) (*models.EffectivePrice, error) {
+	album, err := s.album(albumID)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.resolve([]*models.Album{album}, at)
+	if err != nil {
+		return nil, err
+	}
+	return &prices[0], nil
+}
+
+// ResolvePrices sets the EffectivePrice of albums at a point in time.
+func (s *PromotionService) ResolvePrices(albums []*models.Album, at time.Time) error {
+	prices, err := s.resolve(albums, at)
+	if err != nil {
+		return err
+	}
+	for i, album := range albums {
+		album.EffectivePrice = prices[i].EffectivePrice
+	}
+	return nil
+}
+
+// resolve works out what each album costs at a point in time. An album's own price is replaced by the
+// latest pending scheduled change due by then, so prices are right even before the scheduler has run.
+// Promotions do not stack: the running promotion giving the lowest price applies, and prices never drop
+// below zero. Fixed discounts in another currency than the album's are converted through the exchange
+// rates, and do not apply to albums whose currency has no exchange rate.
+func (s *PromotionService) resolve(albums []*models.Album, at time.Time) ([]models.EffectivePrice, error) {
+	when := at.UTC().Format(models.TimeFormat)
+	ids := make([]uint, len(albums))
+	for i, album := range albums {
+		ids[i] = album.ID
+	}
+	scheduled, err := s.Repo.GetScheduledPrices(ids, when)
+	if err != nil {
+		return nil, err
+	}
+	running, err := s.Repo.GetRunningPromotions(ids, when)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]models.EffectivePrice, len(albums))
+	for i, album := range albums {
+		price := album.Price
+		if scheduledPrice, ok := scheduled[album.ID]; ok {
+			price = scheduledPrice
+		}
+		effective := models.EffectivePrice{AlbumID: album.ID, At: when, Currency: album.Currency, Price: price, EffectivePrice: price}
+
+		for _, promotion := range running[album.ID] {
+			discounted, err := s.discount(price, album.Currency, promotion)
+			if errors.Is(err, ErrNoExchangeRate) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if discounted < effective.EffectivePrice {
+				effective.EffectivePrice = discounted
+				effective.PromotionID = promotion.ID
+			}
+		}
+		prices[i] = effective
+	}
+	return prices, nil
+}
+
+// discount applies a promotion to a price, rounding half away from zero to the currency's minor unit.
+func (s *PromotionService) discount(price float64, currency string, promotion models.Promotion) (float64, error) {
+	minor := models.ToMinor(price, currency)
+	if promotion.Kind == models.PromotionPercentage {
+		minor = int64(math.Round(float64(minor) * (100 - promotion.PercentOff) / 100))
+	} else {
+		amountOff := promotion.AmountOff
+		if promotion.Currency != currency {
+			if s.Prices == nil {
+				return 0, fmt.Errorf("%w to convert a %s discount to %s", ErrNoExchangeRate, promotion.Currency, currency)
+			}
+			converted, err := s.Prices.Convert(amountOff, promotion.Currency, currency)
+			if err != nil {
+				return 0, err
+			}
+			amountOff = converted
+		}
+		minor -= models.ToMinor(amountOff, currency)
+	}
+	return models.FromMinor(max(minor, 0), currency), nil
+}
@@ -0,0 +1,236 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+	"time"
+)
+
+func setupPromotionService(t *testing.T) *services.PromotionService {
+	prices := setupPriceService(t)
+	_, err := prices.AlbumRepo.DB.Exec(`
+		CREATE TABLE genres (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL UNIQUE,
+			parent_id INTEGER
+		);
+		CREATE TABLE album_genres (
+			album_id INTEGER NOT NULL,
+			genre_id INTEGER NOT NULL,
+			PRIMARY KEY (album_id, genre_id)
+		);
+		CREATE TABLE labels (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			country TEXT NOT NULL DEFAULT '',
+			parent_id INTEGER
+		);
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE promotions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			percent_off REAL,
+			amount_off_minor INTEGER,
+			currency TEXT,
+			album_id INTEGER,
+			genre_id INTEGER,
+			label_id INTEGER,
+			musician_id INTEGER,
+			starts_at TEXT NOT NULL,
+			ends_at TEXT NOT NULL
+		);
+		CREATE TABLE price_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			price_minor INTEGER NOT NULL,
+			starts_at TEXT NOT NULL,
+			applied_at TEXT
+		);
+		CREATE TABLE editions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			format TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			barcode TEXT UNIQUE,
+			release_date TEXT NOT NULL,
+			price_minor INTEGER NOT NULL
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	return &services.PromotionService{Repo: &repositories.PromotionRepository{DB: prices.AlbumRepo.DB}, AlbumRepo: prices.AlbumRepo, Prices: prices}
+}
+
+func TestPromotionValidation(t *testing.T) {
+	service := setupPromotionService(t)
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 200}
+	if err := service.AlbumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	promotion := &models.Promotion{Name: " Spring Sale ", Kind: "Fixed", AmountOff: 5, AlbumID: album.ID, StartsAt: "2024-03-01T01:00:00+01:00", EndsAt: "2024-03-08T00:00:00Z"}
+	if err := service.CreatePromotion(promotion); err != nil {
+		t.Fatalf("failed to create promotion: %v", err)
+	}
+	if promotion.Name != "Spring Sale" || promotion.Kind != models.PromotionFixed || promotion.Currency != "USD" || promotion.StartsAt != "2024-03-01T00:00:00Z" {
+		t.Errorf("expected a normalised promotion, got %+v", promotion)
+	}
+
+	invalid := []models.Promotion{
+		{Name: "", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: "bogo", AlbumID: album.ID, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 120, AlbumID: album.ID, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, Currency: "EUR", AlbumID: album.ID, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionFixed, AmountOff: 5.555, AlbumID: album.ID, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID, LabelID: 1, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, MusicianID: 9, StartsAt: "2024-03-01T00:00:00Z", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID, StartsAt: "2024-03-01", EndsAt: "2024-03-08T00:00:00Z"},
+		{Name: "Sale", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID, StartsAt: "2024-03-08T00:00:00Z", EndsAt: "2024-03-01T00:00:00Z"},
+	}
+	for _, promotion := range invalid {
+		if err := service.CreatePromotion(&promotion); err == nil {
+			t.Errorf("expected %+v to be rejected", promotion)
+		}
+	}
+
+	if err := service.DeletePromotion(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestGetEffectivePrice(t *testing.T) {
+	service := setupPromotionService(t)
+	if _, err := service.Prices.UploadExchangeRates([]models.ExchangeRate{{Currency: "EUR", Rate: 0.9}}); err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 199.99}
+	if err := service.AlbumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+	schedule := &models.PriceSchedule{AlbumID: album.ID, Price: 250, StartsAt: "2024-02-01T00:00:00Z"}
+	if err := service.SchedulePrice(schedule); err != nil {
+		t.Fatalf("failed to schedule price: %v", err)
+	}
+	for _, promotion := range []*models.Promotion{
+		{Name: "Tenth Off", Kind: models.PromotionPercentage, PercentOff: 10, AlbumID: album.ID, StartsAt: "2024-01-01T00:00:00Z", EndsAt: "2024-03-01T00:00:00Z"},
+		{Name: "Euro Sale", Kind: models.PromotionFixed, AmountOff: 27, Currency: "EUR", AlbumID: album.ID, StartsAt: "2024-02-01T00:00:00Z", EndsAt: "2024-02-15T00:00:00Z"},
+	} {
+		if err := service.CreatePromotion(promotion); err != nil {
+			t.Fatalf("failed to create promotion: %v", err)
+		}
+	}
+
+	tests := []struct {
+		at          string
+		price       float64
+		effective   float64
+		promotionID uint
+	}{
+		{"2023-12-31T23:59:59Z", 199.99, 199.99, 0},
+		// 10% off 199.99 is 179.991, rounded to the cent
+		{"2024-01-15T00:00:00Z", 199.99, 179.99, 1},
+		// The scheduled 250 applies; 27 EUR is 30 USD, which beats 10% off 250
+		{"2024-02-10T00:00:00Z", 250, 220, 2},
+		{"2024-02-20T00:00:00Z", 250, 225, 1},
+	}
+	for _, tt := range tests {
+		at, _ := time.Parse(time.RFC3339, tt.at)
+		price, err := service.GetEffectivePrice(album.ID, at)
+		if err != nil {
+			t.Fatalf("failed to resolve price at %s: %v", tt.at, err)
+		}
+		if price.Price != tt.price || price.EffectivePrice != tt.effective || price.PromotionID != tt.promotionID {
+			t.Errorf("at %s: expected %v, %v with promotion %d, got %+v", tt.at, tt.price, tt.effective, tt.promotionID, price)
+		}
+	}
+
+	if _, err := service.GetEffectivePrice(99, time.Now()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestAlbumsReportEffectivePrice(t *testing.T) {
+	promotions := setupPromotionService(t)
+	albumService := &services.AlbumService{Repo: promotions.AlbumRepo, Prices: promotions.Prices, Promotions: promotions}
+	if _, err := promotions.Prices.UploadExchangeRates([]models.ExchangeRate{{Currency: "EUR", Rate: 0.9}}); err != nil {
+		t.Fatalf("failed to upload rates: %v", err)
+	}
+
+	discounted := &models.Album{Name: "Discounted Album", ReleaseDate: "2020-01-01", Price: 200}
+	full := &models.Album{Name: "Full Price Album", ReleaseDate: "2021-01-01", Price: 300}
+	for _, album := range []*models.Album{discounted, full} {
+		if err := albumService.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	now := time.Now().UTC()
+	promotion := &models.Promotion{Name: "Running Sale", Kind: models.PromotionPercentage, PercentOff: 25, AlbumID: discounted.ID,
+		StartsAt: now.Add(-time.Hour).Format(time.RFC3339), EndsAt: now.Add(time.Hour).Format(time.RFC3339)}
+	if err := promotions.CreatePromotion(promotion); err != nil {
+		t.Fatalf("failed to create promotion: %v", err)
+	}
+
+	albums, err := albumService.GetAlbums(models.AlbumFilter{})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if albums[0].Price != 200 || albums[0].EffectivePrice != 150 || albums[1].EffectivePrice != 300 {
+		t.Errorf("expected a 25%% discount on the first album only, got %+v", albums)
+	}
+
+	albums, err = albumService.GetAlbums(models.AlbumFilter{Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if albums[0].Price != 180 || albums[0].EffectivePrice != 135 {
+		t.Errorf("expected the discount to carry over to EUR, got %+v", albums[0])
+	}
+}
+
+func TestApplyDueSchedulesChangesAlbumPrice(t *testing.T) {
+	service := setupPromotionService(t)
+	album := &models.Album{Name: "Test Album", ReleaseDate: "2020-01-01", Price: 200}
+	if err := service.AlbumRepo.CreateAlbum(album); err != nil {
+		t.Fatalf("failed to create album: %v", err)
+	}
+
+	for _, schedule := range []models.PriceSchedule{
+		{AlbumID: album.ID, Price: 50, StartsAt: "2024-01-01T00:00:00Z"},
+		{AlbumID: album.ID, Price: 250.555, StartsAt: "2024-01-01T00:00:00Z"},
+		{AlbumID: album.ID, Price: 250, StartsAt: "tomorrow"},
+		{AlbumID: 99, Price: 250, StartsAt: "2024-01-01T00:00:00Z"},
+	} {
+		if err := service.SchedulePrice(&schedule); err == nil {
+			t.Errorf("expected %+v to be rejected", schedule)
+		}
+	}
+
+	if err := service.SchedulePrice(&models.PriceSchedule{AlbumID: album.ID, Price: 250, StartsAt: "2024-01-01T00:00:00Z"}); err != nil {
+		t.Fatalf("failed to schedule price: %v", err)
+	}
+	applied, err := service.ApplyDueSchedules(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("failed to apply schedules: %v", err)
+	}
+	stored, err := service.AlbumRepo.GetAlbumByID(album.ID)
+	if err != nil {
+		t.Fatalf("failed to get album: %v", err)
+	}
+	if applied != 1 || stored.Price != 250 {
+		t.Errorf("expected the change to apply on time, got %d applied and price %v", applied, stored.Price)
+	}
+}
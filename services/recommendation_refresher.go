@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RecommendationRefresher recomputes recommendations periodically, so related albums and musicians are read
+// from precomputed scores instead of being worked out on every request.
+type RecommendationRefresher struct {
+	Service  *RecommendationService
+	Interval time.Duration
+}
+
+// Run refreshes recommendations immediately and then every interval until the context is cancelled.
+func (s *RecommendationRefresher) Run(ctx context.Context) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stored, err := s.Service.Refresh(time.Now())
+		if err != nil {
+			log.Println("Error refreshing recommendations:", err)
+		} else {
+			log.Printf("Refreshed %d recommendations", stored)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
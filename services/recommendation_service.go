@@ -0,0 +1,342 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Recommendation limits. Only the MaxRelated best matches of each album and musician are stored.
+const (
+	DefaultRelated = 10
+	MaxRelated     = 50
+	// RecommendedRating is the lowest approved rating that counts a reviewer as a listener of an album.
+	RecommendedRating = 4
+	// EraYears is how many years apart two releases can be and still count as the same era.
+	EraYears = 10
+)
+
+// How much each signal contributes to a score. They add up to 1, so scores are from 0 to 1.
+const (
+	musiciansWeight = 0.4
+	genreWeight     = 0.25
+	listenersWeight = 0.25
+	eraWeight       = 0.1
+)
+
+// maxCandidatePosting skips features shared by more subjects than this when looking for candidates, so a
+// broad genre such as "Rock" does not make every pair of rock albums a candidate on a large catalog.
+// Candidates found through other features are still scored on them.
+const maxCandidatePosting = 1000
+
+type RecommendationService struct {
+	Repo         *repositories.RecommendationRepository
+	AlbumRepo    *repositories.AlbumRepository
+	MusicianRepo *repositories.MusicianRepository
+	// Albums prices and rates related albums like album listings.
+	Albums *AlbumService
+}
+
+// set holds the features of an album or musician.
+type set[K comparable] map[K]struct{}
+
+func (s set[K]) add(key K) {
+	s[key] = struct{}{}
+}
+
+// jaccard is the share of features two sets have in common, from 0 to 1.
+func jaccard[K comparable](a, b set[K]) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for key := range a {
+		if _, ok := b[key]; ok {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// profile is what an album or musician is compared on. year is 0 when unknown.
+type profile struct {
+	// musicians holds an album's musicians, or the albums and groups a musician played on or in.
+	musicians set[string]
+	genres    set[uint]
+	listeners set[string]
+	year      int
+}
+
+func newProfile() *profile {
+	return &profile{musicians: set[string]{}, genres: set[uint]{}, listeners: set[string]{}}
+}
+
+// score compares two profiles. Sharing only an era does not make two subjects related.
+func (p *profile) score(other *profile) (float64, []string) {
+	var score float64
+	var reasons []string
+	for _, signal := range []struct {
+		reason string
+		weight float64
+		value  float64
+	}{
+		{models.ReasonMusicians, musiciansWeight, jaccard(p.musicians, other.musicians)},
+		{models.ReasonGenre, genreWeight, jaccard(p.genres, other.genres)},
+		{models.ReasonListeners, listenersWeight, jaccard(p.listeners, other.listeners)},
+	} {
+		if signal.value > 0 {
+			score += signal.weight * signal.value
+			reasons = append(reasons, signal.reason)
+		}
+	}
+	if score == 0 {
+		return 0, nil
+	}
+
+	if p.year != 0 && other.year != 0 {
+		gap := math.Abs(float64(p.year - other.year))
+		if proximity := 1 - gap/EraYears; proximity > 0 {
+			score += eraWeight * proximity
+			reasons = append(reasons, models.ReasonEra)
+		}
+	}
+	return math.Round(score*10000) / 10000, reasons
+}
+
+// releaseYear returns the year of a YYYY-MM-DD date, or 0 if it has none.
+func releaseYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// Refresh recomputes every album and musician recommendation from the current catalog, orders and
+// reviews, and returns how many were stored.
+func (s *RecommendationService) Refresh(now time.Time) (int, error) {
+	signals, err := s.Repo.GetSignals(RecommendedRating)
+	if err != nil {
+		return 0, err
+	}
+
+	albums := albumProfiles(signals)
+	albumRecommendations := recommend(albums)
+	musicianRecommendations := recommend(musicianProfiles(signals, albums))
+
+	computedAt := now.UTC().Format(models.TimeFormat)
+	if err := s.Repo.ReplaceAlbumRecommendations(albumRecommendations, computedAt); err != nil {
+		return 0, err
+	}
+	if err := s.Repo.ReplaceMusicianRecommendations(musicianRecommendations, computedAt); err != nil {
+		return 0, err
+	}
+	return len(albumRecommendations) + len(musicianRecommendations), nil
+}
+
+// albumProfiles builds the profile of every album.
+func albumProfiles(signals *models.RecommendationSignals) map[uint]*profile {
+	profiles := make(map[uint]*profile, len(signals.ReleaseDates))
+	for albumID, releaseDate := range signals.ReleaseDates {
+		p := newProfile()
+		p.year = releaseYear(releaseDate)
+		for _, musicianID := range signals.AlbumMusicians[albumID] {
+			p.musicians.add("musician:" + strconv.FormatUint(uint64(musicianID), 10))
+		}
+		for _, genreID := range signals.AlbumGenres[albumID] {
+			p.genres.add(genreID)
+		}
+		for _, listener := range signals.AlbumListeners[albumID] {
+			p.listeners.add(listener)
+		}
+		profiles[albumID] = p
+	}
+	return profiles
+}
+
+// musicianProfiles builds the profile of every musician from the albums they are credited on and the
+// groups they belong to. A musician's year is the average release year of their albums.
+func musicianProfiles(signals *models.RecommendationSignals, albums map[uint]*profile) map[uint]*profile {
+	profiles := make(map[uint]*profile)
+	musician := func(musicianID uint) *profile {
+		p, ok := profiles[musicianID]
+		if !ok {
+			p = newProfile()
+			profiles[musicianID] = p
+		}
+		return p
+	}
+
+	years := make(map[uint][]int)
+	for albumID, musicianIDs := range signals.AlbumMusicians {
+		album, ok := albums[albumID]
+		if !ok {
+			continue
+		}
+		for _, musicianID := range musicianIDs {
+			p := musician(musicianID)
+			p.musicians.add("album:" + strconv.FormatUint(uint64(albumID), 10))
+			for genreID := range album.genres {
+				p.genres.add(genreID)
+			}
+			for listener := range album.listeners {
+				p.listeners.add(listener)
+			}
+			if album.year != 0 {
+				years[musicianID] = append(years[musicianID], album.year)
+			}
+		}
+	}
+	// A group and its members share the group, as do members of the same group
+	for memberID, groupIDs := range signals.MusicianGroups {
+		for _, groupID := range groupIDs {
+			group := "group:" + strconv.FormatUint(uint64(groupID), 10)
+			musician(memberID).musicians.add(group)
+			musician(groupID).musicians.add(group)
+		}
+	}
+
+	for musicianID, albumYears := range years {
+		total := 0
+		for _, year := range albumYears {
+			total += year
+		}
+		profiles[musicianID].year = int(math.Round(float64(total) / float64(len(albumYears))))
+	}
+	return profiles
+}
+
+// recommend scores every subject against the subjects it shares a feature with and keeps the MaxRelated
+// best of each.
+func recommend(profiles map[uint]*profile) []models.Recommendation {
+	index := make(map[string][]uint)
+	for id, p := range profiles {
+		for key := range p.musicians {
+			index["m:"+key] = append(index["m:"+key], id)
+		}
+		for genreID := range p.genres {
+			key := "g:" + strconv.FormatUint(uint64(genreID), 10)
+			index[key] = append(index[key], id)
+		}
+		for listener := range p.listeners {
+			index["l:"+listener] = append(index["l:"+listener], id)
+		}
+	}
+
+	ids := make([]uint, 0, len(profiles))
+	for id := range profiles {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var recommendations []models.Recommendation
+	for _, id := range ids {
+		p := profiles[id]
+		candidates := make(set[uint])
+		visit := func(key string) {
+			if posting := index[key]; len(posting) <= maxCandidatePosting {
+				for _, candidate := range posting {
+					candidates.add(candidate)
+				}
+			}
+		}
+		for key := range p.musicians {
+			visit("m:" + key)
+		}
+		for genreID := range p.genres {
+			visit("g:" + strconv.FormatUint(uint64(genreID), 10))
+		}
+		for listener := range p.listeners {
+			visit("l:" + listener)
+		}
+		delete(candidates, id)
+
+		var related []models.Recommendation
+		for candidate := range candidates {
+			if score, reasons := p.score(profiles[candidate]); score > 0 {
+				related = append(related, models.Recommendation{SubjectID: id, RelatedID: candidate, Score: score, Reasons: reasons})
+			}
+		}
+		sort.Slice(related, func(i, j int) bool {
+			if related[i].Score != related[j].Score {
+				return related[i].Score > related[j].Score
+			}
+			return related[i].RelatedID < related[j].RelatedID
+		})
+		if len(related) > MaxRelated {
+			related = related[:MaxRelated]
+		}
+		recommendations = append(recommendations, related...)
+	}
+	return recommendations
+}
+
+// validateRelatedLimit applies DefaultRelated to a zero limit and checks it is within MaxRelated.
+func validateRelatedLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultRelated, nil
+	}
+	if limit < 1 || limit > MaxRelated {
+		return 0, fmt.Errorf("limit must be from 1 to %d", MaxRelated)
+	}
+	return limit, nil
+}
+
+// GetRelatedAlbums retrieves the albums listeners of an album also like, best first, as of the last
+// refresh. A non-empty currency reports prices in that currency.
+func (s *RecommendationService) GetRelatedAlbums(albumID uint, limit int, currency string) ([]models.RelatedAlbum, error) {
+	limit, err := validateRelatedLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return nil, err
+	}
+
+	related, err := s.Repo.GetRelatedAlbums(albumID, limit)
+	if err != nil {
+		return nil, err
+	}
+	priced := make([]*models.Album, len(related))
+	for i := range related {
+		priced[i] = &related[i].Album
+	}
+	if err := s.Albums.priceAlbums(priced, currency); err != nil {
+		return nil, err
+	}
+	if err := s.Albums.rateAlbums(priced); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
+
+// GetRelatedMusicians retrieves the musicians listeners of a musician also like, best first, as of the
+// last refresh.
+func (s *RecommendationService) GetRelatedMusicians(musicianID uint, limit int) ([]models.RelatedMusician, error) {
+	limit, err := validateRelatedLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.MusicianRepo.GetMusicianByID(musicianID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("musician %d does not exist: %w", musicianID, err)
+		}
+		return nil, err
+	}
+	return s.Repo.GetRelatedMusicians(musicianID, limit)
+}
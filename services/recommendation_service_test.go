@@ -0,0 +1,174 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"slices"
+	"testing"
+	"time"
+)
+
+// setupRecommendationService seeds a catalog where albums 1 and 2 share a musician, a parent genre and an
+// era, albums 1 and 4 were bought in the same order, and albums 3 and 4 share a genre and a fan.
+func setupRecommendationService(t *testing.T) *services.RecommendationService {
+	albumRepo := setupTestRepo(t)
+	db := albumRepo.DB
+	_, err := db.Exec(`
+		CREATE TABLE musicians (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			musician_type TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'person'
+		);
+		CREATE TABLE genres (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL UNIQUE,
+			parent_id INTEGER
+		);
+		CREATE TABLE album_genres (
+			album_id INTEGER NOT NULL,
+			genre_id INTEGER NOT NULL,
+			PRIMARY KEY (album_id, genre_id)
+		);
+		CREATE TABLE group_memberships (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_id INTEGER NOT NULL,
+			member_id INTEGER NOT NULL,
+			instrument TEXT NOT NULL DEFAULT '',
+			joined_on DATE NOT NULL,
+			left_on DATE
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			status TEXT NOT NULL,
+			customer_id INTEGER
+		);
+		CREATE TABLE order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER NOT NULL,
+			album_id INTEGER NOT NULL
+		);
+		CREATE TABLE reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			customer_id INTEGER NOT NULL,
+			rating INTEGER NOT NULL,
+			status TEXT NOT NULL
+		);
+		CREATE TABLE album_recommendations (
+			album_id INTEGER NOT NULL,
+			related_album_id INTEGER NOT NULL,
+			score REAL NOT NULL,
+			reasons TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			PRIMARY KEY (album_id, related_album_id)
+		);
+		CREATE TABLE musician_recommendations (
+			musician_id INTEGER NOT NULL,
+			related_musician_id INTEGER NOT NULL,
+			score REAL NOT NULL,
+			reasons TEXT NOT NULL,
+			computed_at TEXT NOT NULL,
+			PRIMARY KEY (musician_id, related_musician_id)
+		);
+
+		INSERT INTO albums (id, name, release_date, genre, description, price_minor) VALUES
+		(1, 'Album 1', '2000-01-01', '', '', 1000), (2, 'Album 2', '2004-01-01', '', '', 1000),
+		(3, 'Album 3', '1970-01-01', '', '', 1000), (4, 'Album 4', '2001-01-01', '', '', 1000);
+		INSERT INTO musicians (id, name, musician_type, kind) VALUES
+		(1, 'The Band', 'Rock', 'group'), (2, 'Quartet', 'Jazz', 'group'), (3, 'Trio', 'Jazz', 'group'), (4, 'Alice', 'Vocalist', 'person');
+		INSERT INTO album_musicians (album_id, musician_id) VALUES (1, 1), (2, 1), (3, 2), (4, 3);
+		INSERT INTO genres (id, name, key, parent_id) VALUES
+		(1, 'Rock', 'rock', NULL), (2, 'Heavy Metal', 'heavy metal', 1), (3, 'Punk', 'punk', 1), (4, 'Jazz', 'jazz', NULL);
+		INSERT INTO album_genres (album_id, genre_id) VALUES (1, 2), (2, 3), (3, 4), (4, 4);
+		INSERT INTO group_memberships (group_id, member_id, joined_on) VALUES (1, 4, '1990-01-01');
+		INSERT INTO orders (id, status, customer_id) VALUES (1, 'paid', NULL), (2, 'pending', 5);
+		INSERT INTO order_lines (order_id, album_id) VALUES (1, 1), (1, 4), (2, 1), (2, 3);
+		INSERT INTO reviews (album_id, customer_id, rating, status) VALUES
+		(3, 6, 5, 'approved'), (4, 6, 4, 'approved'), (1, 6, 3, 'approved');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed catalog: %v", err)
+	}
+
+	return &services.RecommendationService{
+		Repo:         &repositories.RecommendationRepository{DB: db},
+		AlbumRepo:    albumRepo,
+		MusicianRepo: &repositories.MusicianRepository{DB: db},
+		Albums:       &services.AlbumService{Repo: albumRepo},
+	}
+}
+
+func TestRelatedAlbums(t *testing.T) {
+	service := setupRecommendationService(t)
+	if _, err := service.Refresh(time.Now()); err != nil {
+		t.Fatalf("failed to refresh recommendations: %v", err)
+	}
+
+	related, err := service.GetRelatedAlbums(1, 0, "")
+	if err != nil {
+		t.Fatalf("failed to get related albums: %v", err)
+	}
+	// Album 3 shares only a pending order and a low rating with album 1, which do not count
+	if len(related) != 2 || related[0].ID != 2 || related[1].ID != 4 {
+		t.Fatalf("expected albums 2 and 4 in that order, got %+v", related)
+	}
+	if !slices.Equal(related[0].Reasons, []string{models.ReasonMusicians, models.ReasonGenre, models.ReasonEra}) {
+		t.Errorf("unexpected reasons %v", related[0].Reasons)
+	}
+	// 0.4 for the musician, 0.25 * 1/3 for the shared parent genre and 0.1 * 0.6 for releases 4 years apart
+	if related[0].Score != 0.5433 {
+		t.Errorf("expected score 0.5433, got %v", related[0].Score)
+	}
+	if !slices.Equal(related[1].Reasons, []string{models.ReasonListeners, models.ReasonEra}) {
+		t.Errorf("expected a co-purchase, got %v", related[1].Reasons)
+	}
+	if related[0].EffectivePrice != 10 {
+		t.Errorf("expected related albums to be priced, got %+v", related[0].Album)
+	}
+
+	related, _ = service.GetRelatedAlbums(3, 0, "")
+	if len(related) != 1 || related[0].ID != 4 || !slices.Contains(related[0].Reasons, models.ReasonListeners) {
+		t.Errorf("expected album 4 to share a genre and a fan with album 3, got %+v", related)
+	}
+
+	if related, _ := service.GetRelatedAlbums(1, 1, ""); len(related) != 1 {
+		t.Errorf("expected the limit to apply, got %+v", related)
+	}
+	for _, limit := range []int{-1, services.MaxRelated + 1} {
+		if _, err := service.GetRelatedAlbums(1, limit, ""); err == nil {
+			t.Errorf("expected limit %d to be rejected", limit)
+		}
+	}
+	if _, err := service.GetRelatedAlbums(99, 0, ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing album to be reported, got %v", err)
+	}
+}
+
+func TestRelatedMusicians(t *testing.T) {
+	service := setupRecommendationService(t)
+	if _, err := service.Refresh(time.Now()); err != nil {
+		t.Fatalf("failed to refresh recommendations: %v", err)
+	}
+
+	related, err := service.GetRelatedMusicians(1, 0)
+	if err != nil {
+		t.Fatalf("failed to get related musicians: %v", err)
+	}
+	ids := make([]uint, len(related))
+	for i, musician := range related {
+		ids[i] = musician.ID
+	}
+	// Alice is in the band; the trio's album was bought with the band's
+	if !slices.Contains(ids, 4) || !slices.Contains(ids, 3) || slices.Contains(ids, 2) {
+		t.Errorf("expected Alice and the trio but not the quartet, got %+v", related)
+	}
+
+	if _, err := service.GetRelatedMusicians(99, 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing musician to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrAlreadyReviewed is returned when a customer reviews an album they have already reviewed.
+var ErrAlreadyReviewed = repositories.ErrAlreadyReviewed
+
+// Review limits and the page sizes review listings accept.
+const (
+	MinRating          = 1
+	MaxRating          = 5
+	MaxReviewLength    = 5000
+	DefaultReviewsPage = 20
+	MaxReviewsPage     = 100
+)
+
+type ReviewService struct {
+	Repo      *repositories.ReviewRepository
+	AlbumRepo *repositories.AlbumRepository
+}
+
+// validateReview checks a review's rating and text and that its album exists.
+func (s *ReviewService) validateReview(review *models.Review) error {
+	if review.Rating < MinRating || review.Rating > MaxRating {
+		return fmt.Errorf("rating must be from %d to %d stars", MinRating, MaxRating)
+	}
+	review.Text = strings.TrimSpace(review.Text)
+	if len([]rune(review.Text)) > MaxReviewLength {
+		return fmt.Errorf("text must be at most %d characters", MaxReviewLength)
+	}
+	if _, err := s.AlbumRepo.GetAlbumByID(review.AlbumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album %d does not exist: %w", review.AlbumID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateReview adds a customer's review of an album, pending moderation.
+func (s *ReviewService) CreateReview(review *models.Review) error {
+	if err := s.validateReview(review); err != nil {
+		return err
+	}
+	review.Status = models.ReviewPending
+	review.CreatedAt = time.Now().UTC().Format(models.TimeFormat)
+	review.UpdatedAt = review.CreatedAt
+	if err := s.Repo.CreateReview(review); err != nil {
+		return err
+	}
+	return s.reload(review)
+}
+
+// UpdateReview changes the rating and text of a customer's own review, which goes back to moderation.
+func (s *ReviewService) UpdateReview(review *models.Review) error {
+	if err := s.validateReview(review); err != nil {
+		return err
+	}
+	review.Status = models.ReviewPending
+	review.UpdatedAt = time.Now().UTC().Format(models.TimeFormat)
+	if err := s.Repo.UpdateReview(review); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("review %d of album %d by customer %d does not exist: %w", review.ID, review.AlbumID, review.CustomerID, err)
+		}
+		return err
+	}
+	return s.reload(review)
+}
+
+// reload fills in a stored review's author and timestamps.
+func (s *ReviewService) reload(review *models.Review) error {
+	stored, err := s.Repo.GetReview(review.ID)
+	if err != nil {
+		return err
+	}
+	*review = *stored
+	return nil
+}
+
+// DeleteReview deletes a customer's own review of an album.
+func (s *ReviewService) DeleteReview(albumID, reviewID, customerID uint) error {
+	if err := s.Repo.DeleteReview(albumID, reviewID, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("review %d of album %d by customer %d does not exist: %w", reviewID, albumID, customerID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// ModerateReview sets the status of a review and returns it.
+func (s *ReviewService) ModerateReview(reviewID uint, status string) (*models.Review, error) {
+	if !slices.Contains(models.ReviewStatuses, status) {
+		return nil, fmt.Errorf("status must be one of %s", strings.Join(models.ReviewStatuses, ", "))
+	}
+	if err := s.Repo.SetReviewStatus(reviewID, status, time.Now().UTC().Format(models.TimeFormat)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("review %d does not exist: %w", reviewID, err)
+		}
+		return nil, err
+	}
+	return s.Repo.GetReview(reviewID)
+}
+
+// GetAlbumReviews retrieves one page of an album's approved reviews, newest first. A zero perPage means
+// DefaultReviewsPage.
+func (s *ReviewService) GetAlbumReviews(albumID uint, page, perPage int) (*models.ReviewPage, error) {
+	if _, err := s.AlbumRepo.GetAlbumByID(albumID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("album %d does not exist: %w", albumID, err)
+		}
+		return nil, err
+	}
+	return s.getPage(models.ReviewFilter{AlbumID: albumID, Status: models.ReviewApproved}, page, perPage)
+}
+
+// GetReviews retrieves one page of every review with a status, or of every review for an empty status,
+// newest first. It is the moderation queue.
+func (s *ReviewService) GetReviews(status string, page, perPage int) (*models.ReviewPage, error) {
+	if status != "" && !slices.Contains(models.ReviewStatuses, status) {
+		return nil, fmt.Errorf("status must be one of %s", strings.Join(models.ReviewStatuses, ", "))
+	}
+	return s.getPage(models.ReviewFilter{Status: status}, page, perPage)
+}
+
+// getPage retrieves a page of the reviews matching a filter.
+func (s *ReviewService) getPage(filter models.ReviewFilter, page, perPage int) (*models.ReviewPage, error) {
+	if perPage == 0 {
+		perPage = DefaultReviewsPage
+	}
+	if page < 1 {
+		return nil, errors.New("page must be at least 1")
+	}
+	if perPage < 1 || perPage > MaxReviewsPage {
+		return nil, fmt.Errorf("per_page must be from 1 to %d", MaxReviewsPage)
+	}
+
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+	reviews, total, err := s.Repo.GetReviews(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReviewPage{Reviews: reviews, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// RateAlbums fills in the average rating and review count of albums from their approved reviews, loading
+// every album's summary in one query. Averages are rounded to two decimals.
+func (s *ReviewService) RateAlbums(albums []*models.Album) error {
+	ids := make([]uint, len(albums))
+	for i, album := range albums {
+		ids[i] = album.ID
+	}
+	summaries, err := s.Repo.GetRatingSummaries(ids)
+	if err != nil {
+		return err
+	}
+	for _, album := range albums {
+		summary := summaries[album.ID]
+		album.AverageRating = math.Round(summary.AverageRating*100) / 100
+		album.ReviewCount = summary.ReviewCount
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"jukebox/models"
+	"jukebox/repositories"
+	"jukebox/services"
+	"testing"
+)
+
+// setupReviewService creates albums, customers and reviews tables with the given number of customers.
+func setupReviewService(t *testing.T, customers int) *services.ReviewService {
+	albumRepo := setupTestRepo(t)
+	_, err := albumRepo.DB.Exec(`
+		CREATE TABLE customers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			password_hash TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			is_admin INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE reviews (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			album_id INTEGER NOT NULL,
+			customer_id INTEGER NOT NULL,
+			rating INTEGER NOT NULL,
+			body TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE (album_id, customer_id)
+		);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+
+	customerRepo := &repositories.CustomerRepository{DB: albumRepo.DB}
+	for i := 1; i <= customers; i++ {
+		customer := &models.Customer{Email: fmt.Sprintf("listener%d@example.com", i), Name: fmt.Sprintf("Listener %d", i), CreatedAt: "2024-01-01T00:00:00Z"}
+		if err := customerRepo.CreateCustomer(customer, "hash"); err != nil {
+			t.Fatalf("failed to create customer: %v", err)
+		}
+	}
+	for _, album := range []*models.Album{
+		{Name: "Album 1", ReleaseDate: "2020-01-01", Price: 200},
+		{Name: "Album 2", ReleaseDate: "2021-01-01", Price: 300},
+	} {
+		if err := albumRepo.CreateAlbum(album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+	}
+	return &services.ReviewService{Repo: &repositories.ReviewRepository{DB: albumRepo.DB}, AlbumRepo: albumRepo}
+}
+
+func TestReviewModeration(t *testing.T) {
+	service := setupReviewService(t, 2)
+
+	for _, review := range []models.Review{
+		{AlbumID: 1, CustomerID: 1, Rating: 0},
+		{AlbumID: 1, CustomerID: 1, Rating: 6},
+	} {
+		if err := service.CreateReview(&review); err == nil {
+			t.Errorf("expected %+v to be rejected", review)
+		}
+	}
+	if err := service.CreateReview(&models.Review{AlbumID: 99, CustomerID: 1, Rating: 3}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing album to be reported, got %v", err)
+	}
+
+	review := &models.Review{AlbumID: 1, CustomerID: 1, Rating: 4, Text: "  Great record  "}
+	if err := service.CreateReview(review); err != nil {
+		t.Fatalf("failed to create review: %v", err)
+	}
+	if review.Status != models.ReviewPending || review.Author != "Listener 1" || review.Text != "Great record" {
+		t.Errorf("unexpected review %+v", review)
+	}
+	if err := service.CreateReview(&models.Review{AlbumID: 1, CustomerID: 1, Rating: 5}); !errors.Is(err, services.ErrAlreadyReviewed) {
+		t.Errorf("expected ErrAlreadyReviewed, got %v", err)
+	}
+
+	page, err := service.GetAlbumReviews(1, 1, 0)
+	if err != nil {
+		t.Fatalf("failed to get reviews: %v", err)
+	}
+	if page.Total != 0 || len(page.Reviews) != 0 || page.PerPage != services.DefaultReviewsPage {
+		t.Errorf("expected pending reviews not to be listed, got %+v", page)
+	}
+
+	if _, err := service.ModerateReview(review.ID, "published"); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+	if _, err := service.ModerateReview(review.ID, models.ReviewApproved); err != nil {
+		t.Fatalf("failed to approve review: %v", err)
+	}
+	if page, _ := service.GetAlbumReviews(1, 1, 0); page.Total != 1 {
+		t.Errorf("expected the approved review to be listed, got %+v", page)
+	}
+
+	// Only the author can edit a review, and editing sends it back to moderation
+	if err := service.UpdateReview(&models.Review{ID: review.ID, AlbumID: 1, CustomerID: 2, Rating: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected another customer's edit to fail, got %v", err)
+	}
+	edited := &models.Review{ID: review.ID, AlbumID: 1, CustomerID: 1, Rating: 5}
+	if err := service.UpdateReview(edited); err != nil {
+		t.Fatalf("failed to update review: %v", err)
+	}
+	if edited.Status != models.ReviewPending || edited.Rating != 5 {
+		t.Errorf("unexpected edited review %+v", edited)
+	}
+
+	queue, err := service.GetReviews(models.ReviewPending, 1, 10)
+	if err != nil {
+		t.Fatalf("failed to get moderation queue: %v", err)
+	}
+	if queue.Total != 1 || queue.Reviews[0].ID != review.ID {
+		t.Errorf("unexpected moderation queue %+v", queue)
+	}
+
+	if err := service.DeleteReview(1, review.ID, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected another customer's delete to fail, got %v", err)
+	}
+	if err := service.DeleteReview(1, review.ID, 1); err != nil {
+		t.Fatalf("failed to delete review: %v", err)
+	}
+}
+
+func TestReviewPagination(t *testing.T) {
+	service := setupReviewService(t, 5)
+	for customerID := uint(1); customerID <= 5; customerID++ {
+		review := &models.Review{AlbumID: 1, CustomerID: customerID, Rating: int(customerID)}
+		if err := service.CreateReview(review); err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+		if _, err := service.ModerateReview(review.ID, models.ReviewApproved); err != nil {
+			t.Fatalf("failed to approve review: %v", err)
+		}
+	}
+
+	page, err := service.GetAlbumReviews(1, 3, 2)
+	if err != nil {
+		t.Fatalf("failed to get reviews: %v", err)
+	}
+	if page.Total != 5 || page.Page != 3 || page.PerPage != 2 || len(page.Reviews) != 1 {
+		t.Errorf("unexpected last page %+v", page)
+	}
+
+	for _, bounds := range [][2]int{{0, 10}, {1, -1}, {1, services.MaxReviewsPage + 1}} {
+		if _, err := service.GetAlbumReviews(1, bounds[0], bounds[1]); err == nil {
+			t.Errorf("expected page %d of %d to be rejected", bounds[0], bounds[1])
+		}
+	}
+	if _, err := service.GetAlbumReviews(99, 1, 10); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected a missing album to be reported, got %v", err)
+	}
+}
+
+func TestAlbumsReportRatings(t *testing.T) {
+	reviews := setupReviewService(t, 3)
+	for i, rating := range []int{4, 5, 1} {
+		review := &models.Review{AlbumID: 1, CustomerID: uint(i + 1), Rating: rating}
+		if err := reviews.CreateReview(review); err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+		status := models.ReviewApproved
+		if rating == 1 {
+			status = models.ReviewRejected
+		}
+		if _, err := reviews.ModerateReview(review.ID, status); err != nil {
+			t.Fatalf("failed to moderate review: %v", err)
+		}
+	}
+
+	service := &services.AlbumService{Repo: reviews.AlbumRepo, Reviews: reviews}
+	albums, err := service.GetAlbums(models.AlbumFilter{})
+	if err != nil {
+		t.Fatalf("failed to get albums: %v", err)
+	}
+	if albums[0].AverageRating != 4.5 || albums[0].ReviewCount != 2 {
+		t.Errorf("expected album 1 to average 4.5 over 2 approved reviews, got %+v", albums[0])
+	}
+	if albums[1].AverageRating != 0 || albums[1].ReviewCount != 0 {
+		t.Errorf("expected album 2 to be unrated, got %+v", albums[1])
+	}
+}
@@ -0,0 +1,53 @@
+package tags
+
+import (
+	"strconv"
+	"strings"
+)
+
+// id3Genres are the genres of ID3v1, which ID3v2 and MP4 tags may refer to by index.
+var id3Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop", "Jazz", "Metal",
+	"New Age", "Oldies", "Other", "Pop", "R&B", "Rap", "Reggae", "Rock", "Techno", "Industrial",
+	"Alternative", "Ska", "Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk",
+	"Fusion", "Trance", "Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"Alternative Rock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic",
+	"Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream", "Southern Rock", "Comedy", "Cult", "Gangsta",
+	"Top 40", "Christian Rap", "Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave", "Psychedelic", "Rave", "Showtunes",
+	"Trailer", "Lo-Fi", "Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+}
+
+// genreName resolves an ID3v1 genre index to its name, or "" when it is out of range.
+func genreName(index int) string {
+	if index < 0 || index >= len(id3Genres) {
+		return ""
+	}
+	return id3Genres[index]
+}
+
+// parseID3Genre resolves the ID3v1 references an ID3v2 genre may hold, such as "17", "(17)" or
+// "(17)Rock and Roll", in which case the text after the reference wins.
+func parseID3Genre(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "(") {
+		if end := strings.Index(value, ")"); end > 0 {
+			ref, rest := value[1:end], strings.TrimSpace(value[end+1:])
+			if rest != "" {
+				return rest
+			}
+			switch ref {
+			case "RX":
+				return "Remix"
+			case "CR":
+				return "Cover"
+			}
+			if index, err := strconv.Atoi(ref); err == nil {
+				return genreName(index)
+			}
+		}
+	}
+	if index, err := strconv.Atoi(value); err == nil {
+		return genreName(index)
+	}
+	return value
+}
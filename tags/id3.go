@@ -0,0 +1,226 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Header flags of ID3v2 tags and frames.
+const (
+	id3Unsynchronisation = 0x80
+	id3ExtendedHeader    = 0x40
+	// ID3v2.4 frame format flags
+	id3FrameCompressed       = 0x08
+	id3FrameEncrypted        = 0x04
+	id3FrameUnsynchronised   = 0x02
+	id3FrameDataLengthHeader = 0x01
+	// ID3v2.3 frame format flags
+	id3v23FrameCompressed = 0x80
+	id3v23FrameEncrypted  = 0x40
+)
+
+// id3v22Frames maps the three-character frame IDs of ID3v2.2 to their ID3v2.3 equivalents.
+var id3v22Frames = map[string]string{
+	"TT2": "TIT2", "TAL": "TALB", "TP1": "TPE1", "TP2": "TPE2", "TCM": "TCOM", "TCO": "TCON",
+	"TYE": "TYER", "TDA": "TDAT", "TRK": "TRCK", "TPA": "TPOS", "TLE": "TLEN",
+}
+
+// readID3 reads an ID3v2.2, 2.3 or 2.4 tag at the start of a file.
+func readID3(r io.ReaderAt, size int64) (*Tags, error) {
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("%w: truncated ID3v2 header", ErrMalformed)
+	}
+	version, flags := header[3], header[5]
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("%w: ID3v2.%d tags are not supported", ErrUnsupported, version)
+	}
+	tagSize, ok := syncsafe(header[6:10])
+	if !ok || int64(tagSize)+10 > size {
+		return nil, fmt.Errorf("%w: invalid ID3v2 tag size", ErrMalformed)
+	}
+
+	body := make([]byte, tagSize)
+	if _, err := r.ReadAt(body, 10); err != nil {
+		return nil, fmt.Errorf("%w: truncated ID3v2 tag", ErrMalformed)
+	}
+	if flags&id3Unsynchronisation != 0 && version < 4 {
+		body = resynchronise(body)
+	}
+	if flags&id3ExtendedHeader != 0 {
+		if version == 2 {
+			// In ID3v2.2 this flag marks a compressed tag, which no reader is known to support
+			return nil, fmt.Errorf("%w: compressed ID3v2.2 tags are not supported", ErrUnsupported)
+		}
+		if len(body) < 4 {
+			return nil, fmt.Errorf("%w: truncated ID3v2 extended header", ErrMalformed)
+		}
+		// The ID3v2.3 size leaves out its own four bytes; the ID3v2.4 one is syncsafe and includes them
+		extended := int(binary.BigEndian.Uint32(body)) + 4
+		if version == 4 {
+			extended, ok = syncsafe(body[:4])
+		}
+		if !ok || extended > len(body) {
+			return nil, fmt.Errorf("%w: invalid ID3v2 extended header size", ErrMalformed)
+		}
+		body = body[extended:]
+	}
+
+	idLength, headerLength := 4, 10
+	if version == 2 {
+		idLength, headerLength = 3, 6
+	}
+	tags := &Tags{Format: FormatID3}
+	var year, dayMonth string
+	for len(body) >= headerLength && body[0] != 0 {
+		id := string(body[:idLength])
+		var frameSize int
+		switch version {
+		case 2:
+			frameSize = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(body[4:8]))
+		case 4:
+			if frameSize, ok = syncsafe(body[4:8]); !ok {
+				return nil, fmt.Errorf("%w: invalid size of frame %s", ErrMalformed, id)
+			}
+		}
+		if frameSize < 0 || frameSize > len(body)-headerLength {
+			return nil, fmt.Errorf("%w: frame %s overruns the tag", ErrMalformed, id)
+		}
+		data := body[headerLength : headerLength+frameSize]
+		var format byte
+		if version > 2 {
+			format = body[9]
+		}
+		body = body[headerLength+frameSize:]
+
+		switch version {
+		case 2:
+			if id = id3v22Frames[id]; id == "" {
+				continue
+			}
+		case 3:
+			if format&(id3v23FrameCompressed|id3v23FrameEncrypted) != 0 {
+				continue
+			}
+		case 4:
+			if format&(id3FrameCompressed|id3FrameEncrypted) != 0 {
+				continue
+			}
+			if format&id3FrameDataLengthHeader != 0 {
+				if len(data) < 4 {
+					continue
+				}
+				data = data[4:]
+			}
+			if format&id3FrameUnsynchronised != 0 {
+				data = resynchronise(data)
+			}
+		}
+		if len(data) == 0 || id[0] != 'T' {
+			continue
+		}
+
+		text := decodeID3Text(data)
+		switch id {
+		case "TIT2":
+			setText(&tags.Title, text)
+		case "TALB":
+			setText(&tags.Album, text)
+		case "TPE1":
+			setText(&tags.Artist, text)
+		case "TPE2":
+			setText(&tags.AlbumArtist, text)
+		case "TCOM":
+			setText(&tags.Composer, text)
+		case "TCON":
+			setText(&tags.Genre, parseID3Genre(text))
+		case "TDRC", "TDRL":
+			setText(&tags.Date, text)
+		case "TYER":
+			setText(&year, text)
+		case "TDAT":
+			setText(&dayMonth, text)
+		case "TRCK":
+			setPosition(&tags.Track, &tags.TrackTotal, text)
+		case "TPOS":
+			setPosition(&tags.Disc, &tags.DiscTotal, text)
+		case "TLEN":
+			if milliseconds, err := strconv.Atoi(text); err == nil && milliseconds > 0 && tags.DurationSeconds == 0 {
+				tags.DurationSeconds = (milliseconds + 500) / 1000
+			}
+		}
+	}
+
+	// ID3v2.3 splits the date into the year and a DDMM day and month
+	if tags.Date == "" && year != "" {
+		tags.Date = year
+		if _, err := strconv.Atoi(dayMonth); err == nil && len(dayMonth) == 4 {
+			tags.Date += "-" + dayMonth[2:] + "-" + dayMonth[:2]
+		}
+	}
+	return tags, nil
+}
+
+// syncsafe decodes a four-byte integer of which each byte holds seven bits.
+func syncsafe(b []byte) (int, bool) {
+	var n int
+	for _, c := range b[:4] {
+		if c&0x80 != 0 {
+			return 0, false
+		}
+		n = n<<7 | int(c)
+	}
+	return n, true
+}
+
+// resynchronise undoes unsynchronisation, which inserts a zero byte after every 0xFF.
+func resynchronise(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xFF, 0x00}, []byte{0xFF})
+}
+
+// decodeID3Text decodes the first value of a text frame, whose first byte gives the encoding: ISO-8859-1,
+// UTF-16 with a byte order mark, UTF-16BE or UTF-8.
+func decodeID3Text(data []byte) string {
+	encoding, text := data[0], data[1:]
+	switch encoding {
+	case 0:
+		if end := bytes.IndexByte(text, 0); end >= 0 {
+			text = text[:end]
+		}
+		runes := make([]rune, len(text))
+		for i, c := range text {
+			runes[i] = rune(c)
+		}
+		return string(runes)
+	case 1, 2:
+		order := binary.ByteOrder(binary.BigEndian)
+		if encoding == 1 && len(text) >= 2 {
+			switch {
+			case text[0] == 0xFF && text[1] == 0xFE:
+				order, text = binary.LittleEndian, text[2:]
+			case text[0] == 0xFE && text[1] == 0xFF:
+				text = text[2:]
+			}
+		}
+		var units []uint16
+		for i := 0; i+1 < len(text); i += 2 {
+			unit := order.Uint16(text[i:])
+			if unit == 0 {
+				break
+			}
+			units = append(units, unit)
+		}
+		return string(utf16.Decode(units))
+	default:
+		if end := bytes.IndexByte(text, 0); end >= 0 {
+			text = text[:end]
+		}
+		return string(text)
+	}
+}
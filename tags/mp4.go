@@ -0,0 +1,190 @@
+package tags
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxMP4Value bounds the metadata values read from MP4 files; larger items such as cover art are skipped.
+const maxMP4Value = 1 << 20
+
+// atom is an MP4 box: its type and where its payload lies in the file.
+type atom struct {
+	kind   string
+	offset int64
+	size   int64
+}
+
+// readAtoms lists the atoms between two offsets of a file.
+func readAtoms(r io.ReaderAt, start, end int64) ([]atom, error) {
+	var atoms []atom
+	header := make([]byte, 16)
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return nil, fmt.Errorf("%w: truncated MP4 atom", ErrMalformed)
+		}
+		size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+		switch size {
+		case 0:
+			// The last atom may extend to the end of the file
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, fmt.Errorf("%w: truncated MP4 atom", ErrMalformed)
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(header[8:16])), 16
+		}
+		if size < headerSize || size > end-offset {
+			return nil, fmt.Errorf("%w: MP4 atom %q overruns its parent", ErrMalformed, header[4:8])
+		}
+		atoms = append(atoms, atom{kind: string(header[4:8]), offset: offset + headerSize, size: size - headerSize})
+		offset += size
+	}
+	return atoms, nil
+}
+
+// findAtom returns the first atom of a kind.
+func findAtom(atoms []atom, kind string) (atom, bool) {
+	for _, a := range atoms {
+		if a.kind == kind {
+			return a, true
+		}
+	}
+	return atom{}, false
+}
+
+// children lists the atoms inside an atom, skipping the first skip bytes of its payload.
+func children(r io.ReaderAt, parent atom, skip int64) ([]atom, error) {
+	return readAtoms(r, parent.offset+skip, parent.offset+parent.size)
+}
+
+// readMP4 reads the duration from moov/mvhd and the metadata items of moov/udta/meta/ilst.
+func readMP4(r io.ReaderAt, size int64) (*Tags, error) {
+	top, err := readAtoms(r, 0, size)
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findAtom(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("%w: MP4 file without a moov atom", ErrMalformed)
+	}
+	movie, err := children(r, moov, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := &Tags{Format: FormatMP4}
+	if mvhd, ok := findAtom(movie, "mvhd"); ok && mvhd.size >= 32 {
+		header := make([]byte, 32)
+		if _, err := r.ReadAt(header, mvhd.offset); err != nil {
+			return nil, fmt.Errorf("%w: truncated mvhd atom", ErrMalformed)
+		}
+		// Version 1 headers have 64-bit times and duration
+		timescale, duration := uint64(binary.BigEndian.Uint32(header[12:16])), uint64(binary.BigEndian.Uint32(header[16:20]))
+		if header[0] == 1 {
+			timescale, duration = uint64(binary.BigEndian.Uint32(header[20:24])), binary.BigEndian.Uint64(header[24:32])
+		}
+		if timescale > 0 {
+			tags.DurationSeconds = int((duration + timescale/2) / timescale)
+		}
+	}
+
+	items, err := metadataItems(r, movie)
+	if err != nil || items == nil {
+		return tags, err
+	}
+	for _, item := range items {
+		if err := readMP4Item(r, item, tags); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// metadataItems lists the items of moov/udta/meta/ilst, or nil when the file has no metadata.
+func metadataItems(r io.ReaderAt, movie []atom) ([]atom, error) {
+	udta, ok := findAtom(movie, "udta")
+	if !ok {
+		return nil, nil
+	}
+	userData, err := children(r, udta, 0)
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := findAtom(userData, "meta")
+	if !ok || meta.size < 12 {
+		return nil, nil
+	}
+	// meta is a full atom with four bytes of version and flags, except in some QuickTime files where
+	// the hdlr atom follows straight away
+	skip := int64(4)
+	peek := make([]byte, 4)
+	if _, err := r.ReadAt(peek, meta.offset+4); err == nil && string(peek) == "hdlr" {
+		skip = 0
+	}
+	metadata, err := children(r, meta, skip)
+	if err != nil {
+		return nil, err
+	}
+	ilst, ok := findAtom(metadata, "ilst")
+	if !ok {
+		return nil, nil
+	}
+	return children(r, ilst, 0)
+}
+
+// readMP4Item reads the value of one metadata item from its data atom, which starts with four bytes of
+// type and four of locale.
+func readMP4Item(r io.ReaderAt, item atom, tags *Tags) error {
+	switch item.kind {
+	case "\xa9nam", "\xa9alb", "\xa9ART", "aART", "\xa9wrt", "\xa9gen", "\xa9day", "trkn", "disk", "gnre":
+	default:
+		return nil
+	}
+	values, err := children(r, item, 0)
+	if err != nil {
+		return err
+	}
+	data, ok := findAtom(values, "data")
+	if !ok || data.size < 8 || data.size > maxMP4Value {
+		return nil
+	}
+	value := make([]byte, data.size-8)
+	if _, err := r.ReadAt(value, data.offset+8); err != nil {
+		return fmt.Errorf("%w: truncated MP4 metadata", ErrMalformed)
+	}
+
+	switch item.kind {
+	case "\xa9nam":
+		setText(&tags.Title, string(value))
+	case "\xa9alb":
+		setText(&tags.Album, string(value))
+	case "\xa9ART":
+		setText(&tags.Artist, string(value))
+	case "aART":
+		setText(&tags.AlbumArtist, string(value))
+	case "\xa9wrt":
+		setText(&tags.Composer, string(value))
+	case "\xa9gen":
+		setText(&tags.Genre, string(value))
+	case "\xa9day":
+		setText(&tags.Date, string(value))
+	case "gnre":
+		// The ID3v1 genre index plus one
+		if len(value) >= 2 {
+			setText(&tags.Genre, genreName(int(binary.BigEndian.Uint16(value))-1))
+		}
+	case "trkn", "disk":
+		// Two bytes of padding, the number and the total
+		if len(value) >= 6 {
+			number, total := &tags.Track, &tags.TrackTotal
+			if item.kind == "disk" {
+				number, total = &tags.Disc, &tags.DiscTotal
+			}
+			setPosition(number, total, strconv.Itoa(int(binary.BigEndian.Uint16(value[2:4])))+"/"+strconv.Itoa(int(binary.BigEndian.Uint16(value[4:6]))))
+		}
+	}
+	return nil
+}
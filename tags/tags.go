@@ -0,0 +1,112 @@
+// Package tags reads the metadata of audio files: ID3v2 tags of MP3 files, Vorbis comments of FLAC files
+// and the iTunes-style metadata of MP4 (M4A) files. It is pure Go and never decodes audio.
+package tags
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats of tagged files.
+const (
+	FormatID3  = "id3v2"
+	FormatFLAC = "flac"
+	FormatMP4  = "mp4"
+)
+
+var (
+	// ErrUnsupported is returned for files in none of the supported formats.
+	ErrUnsupported = errors.New("unsupported audio format")
+	// ErrMalformed is returned when a file's metadata is truncated or inconsistent.
+	ErrMalformed = errors.New("malformed audio metadata")
+)
+
+// Tags is the metadata of an audio file. Fields the file does not tag are empty or zero.
+type Tags struct {
+	Format      string
+	Title       string
+	Album       string
+	Artist      string
+	AlbumArtist string
+	Composer    string
+	Genre       string
+	// Date is the release date as tagged: usually a year, sometimes a full date.
+	Date       string
+	Track      int
+	TrackTotal int
+	Disc       int
+	DiscTotal  int
+	// DurationSeconds is the length of the audio where the metadata records it.
+	DurationSeconds int
+}
+
+// Read detects the format of a file from its first bytes and reads its tags.
+func Read(r io.ReaderAt, size int64) (*Tags, error) {
+	header := make([]byte, 8)
+	if n, err := r.ReadAt(header, 0); n < len(header) {
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		return nil, ErrUnsupported
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return readID3(r, size)
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return readFLAC(r, size)
+	case string(header[4:8]) == "ftyp":
+		return readMP4(r, size)
+	}
+	return nil, ErrUnsupported
+}
+
+// ReleaseDate returns the tagged date as YYYY-MM-DD, filling in a missing month or day with 01, or "" when
+// the date does not start with a year.
+func (t *Tags) ReleaseDate() string {
+	date := strings.TrimSpace(t.Date)
+	if len(date) < 4 {
+		return ""
+	}
+	if _, err := strconv.Atoi(date[:4]); err != nil {
+		return ""
+	}
+	parts := []string{date[:4], "01", "01"}
+	for i, part := range strings.SplitN(strings.SplitN(date, "T", 2)[0], "-", 3)[1:] {
+		if n, err := strconv.Atoi(part); err == nil && len(part) == 2 && n >= 1 {
+			parts[i+1] = part
+		} else {
+			break
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// setText stores a tag's text in a field unless the field is already set, so the first value wins.
+func setText(field *string, value string) {
+	value = strings.TrimSpace(value)
+	if *field == "" && value != "" {
+		*field = value
+	}
+}
+
+// setPosition stores a position such as "3" or "3/12" in a number and total unless already set.
+func setPosition(number, total *int, value string) {
+	first, second, _ := strings.Cut(strings.TrimSpace(value), "/")
+	if n, err := strconv.Atoi(strings.TrimSpace(first)); err == nil && n > 0 && *number == 0 {
+		*number = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(second)); err == nil && n > 0 && *total == 0 {
+		*total = n
+	}
+}
+
+// setTotal stores a count tagged on its own, such as TRACKTOTAL, unless already set.
+func setTotal(total *int, value string) {
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n > 0 && *total == 0 {
+		*total = n
+	}
+}
@@ -0,0 +1,225 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode/utf16"
+)
+
+// id3Tag builds an ID3v2 tag of a version from frames that are already encoded.
+func id3Tag(version byte, flags byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	if flags&id3Unsynchronisation != 0 {
+		body = bytes.ReplaceAll(body, []byte{0xFF}, []byte{0xFF, 0x00})
+	}
+	body = append(body, make([]byte, 16)...) // padding
+	size := len(body)
+	header := []byte{'I', 'D', '3', version, 0, flags, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(append(header, body...), 0xFF, 0xFB) // followed by audio
+}
+
+func id3Frame(version byte, id string, data []byte) []byte {
+	size := len(data)
+	switch version {
+	case 2:
+		return append([]byte{id[0], id[1], id[2], byte(size >> 16), byte(size >> 8), byte(size)}, data...)
+	case 3:
+		header := append([]byte(id), 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(header[4:], uint32(size))
+		return append(header, data...)
+	}
+	header := append([]byte(id), byte(size>>21&0x7F), byte(size>>14&0x7F), byte(size>>7&0x7F), byte(size&0x7F), 0, 0)
+	return append(header, data...)
+}
+
+func latin1(text string) []byte {
+	return append([]byte{0}, text...)
+}
+
+func utf16LE(text string) []byte {
+	data := []byte{1, 0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return append(data, 0, 0)
+}
+
+func read(t *testing.T, file []byte) *Tags {
+	t.Helper()
+	tags, err := Read(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("failed to read tags: %v", err)
+	}
+	return tags
+}
+
+func TestReadID3v23(t *testing.T) {
+	file := id3Tag(3, 0,
+		id3Frame(3, "TIT2", utf16LE("Café del Mar")),
+		id3Frame(3, "TALB", latin1("Caf\xe9 Classics")),
+		id3Frame(3, "TPE1", latin1("Ann Artist")),
+		id3Frame(3, "TPE2", latin1("The Band")),
+		id3Frame(3, "TCON", latin1("(17)")),
+		id3Frame(3, "TYER", latin1("1997")),
+		id3Frame(3, "TDAT", latin1("2405")),
+		id3Frame(3, "TRCK", latin1("3/12")),
+		id3Frame(3, "TPOS", latin1("2")),
+		id3Frame(3, "TLEN", latin1("241600")),
+		id3Frame(3, "APIC", []byte{0, 1, 2, 3}),
+	)
+	tags := read(t, file)
+	expected := Tags{Format: FormatID3, Title: "Café del Mar", Album: "Café Classics", Artist: "Ann Artist", AlbumArtist: "The Band",
+		Genre: "Rock", Date: "1997-05-24", Track: 3, TrackTotal: 12, Disc: 2, DurationSeconds: 242}
+	if *tags != expected {
+		t.Errorf("expected %+v, got %+v", expected, *tags)
+	}
+}
+
+func TestReadID3v24(t *testing.T) {
+	// A frame of 200 bytes has a syncsafe size different from its plain one
+	title := bytes.Repeat([]byte("a"), 199)
+	file := id3Tag(4, 0,
+		id3Frame(4, "TIT2", append([]byte{3}, title...)),
+		id3Frame(4, "TALB", []byte("\x03Blue Train\x00Other Value")),
+		id3Frame(4, "TCON", []byte("\x03(9)Thrash")),
+		id3Frame(4, "TDRC", []byte("\x032001-03")),
+		id3Frame(4, "TCOM", []byte("\x02\x00B\x00o\x00b")),
+	)
+	tags := read(t, file)
+	if tags.Title != string(title) || tags.Album != "Blue Train" || tags.Genre != "Thrash" || tags.Composer != "Bob" {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+	if date := tags.ReleaseDate(); date != "2001-03-01" {
+		t.Errorf("expected a release date filled in to 2001-03-01, got %s", date)
+	}
+}
+
+func TestReadID3v22Unsynchronised(t *testing.T) {
+	file := id3Tag(2, id3Unsynchronisation,
+		id3Frame(2, "TT2", []byte{0, 'A', 0xFF, 'B'}),
+		id3Frame(2, "TAL", latin1("Old Album")),
+		id3Frame(2, "TCO", latin1("32")),
+		id3Frame(2, "TRK", latin1("7")),
+	)
+	tags := read(t, file)
+	if tags.Title != "AÿB" || tags.Album != "Old Album" || tags.Genre != "Classical" || tags.Track != 7 {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+}
+
+// vorbisComment builds a Vorbis comment from NAME=value fields.
+func vorbisComment(fields ...string) []byte {
+	block := binary.LittleEndian.AppendUint32(nil, 6)
+	block = append(block, "jukebox"[:6]...)
+	block = binary.LittleEndian.AppendUint32(block, uint32(len(fields)))
+	for _, field := range fields {
+		block = binary.LittleEndian.AppendUint32(block, uint32(len(field)))
+		block = append(block, field...)
+	}
+	return block
+}
+
+func flacBlock(blockType byte, last bool, data []byte) []byte {
+	if last {
+		blockType |= flacLastBlock
+	}
+	return append([]byte{blockType, byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+func TestReadFLAC(t *testing.T) {
+	// 44100 Hz and 10 seconds of samples
+	streamInfo := make([]byte, 34)
+	streamInfo[10], streamInfo[11], streamInfo[12] = 0x0A, 0xC4, 0x40
+	binary.BigEndian.PutUint32(streamInfo[14:18], 441000)
+
+	file := []byte("fLaC")
+	file = append(file, flacBlock(flacStreamInfo, false, streamInfo)...)
+	file = append(file, flacBlock(1, false, make([]byte, 100))...)
+	file = append(file, flacBlock(flacVorbisComment, true, vorbisComment(
+		"title=So What", "ALBUM=Kind of Blue", "Artist=Miles Davis", "ALBUM ARTIST=Miles Davis",
+		"GENRE=Jazz", "DATE=1959-08-17", "TRACKNUMBER=1", "TRACKTOTAL=5", "DISCNUMBER=1/1", "NOEQUALS",
+	))...)
+	tags := read(t, file)
+	expected := Tags{Format: FormatFLAC, Title: "So What", Album: "Kind of Blue", Artist: "Miles Davis", AlbumArtist: "Miles Davis",
+		Genre: "Jazz", Date: "1959-08-17", Track: 1, TrackTotal: 5, Disc: 1, DiscTotal: 1, DurationSeconds: 10}
+	if *tags != expected {
+		t.Errorf("expected %+v, got %+v", expected, *tags)
+	}
+}
+
+func mp4Atom(kind string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	return append(binary.BigEndian.AppendUint32([]byte{}, uint32(8+len(body))), append([]byte(kind), body...)...)
+}
+
+func mp4Data(dataType uint32, value []byte) []byte {
+	return mp4Atom("data", binary.BigEndian.AppendUint32(nil, dataType), []byte{0, 0, 0, 0}, value)
+}
+
+func TestReadMP4(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 185400)
+
+	ilst := mp4Atom("ilst",
+		mp4Atom("\xa9nam", mp4Data(1, []byte("Track Name"))),
+		mp4Atom("\xa9alb", mp4Data(1, []byte("Album Name"))),
+		mp4Atom("\xa9ART", mp4Data(1, []byte("Track Artist"))),
+		mp4Atom("aART", mp4Data(1, []byte("Album Artist"))),
+		mp4Atom("\xa9wrt", mp4Data(1, []byte("Composer"))),
+		mp4Atom("gnre", mp4Data(0, []byte{0, 18})),
+		mp4Atom("\xa9day", mp4Data(1, []byte("2012-06-01T07:00:00Z"))),
+		mp4Atom("trkn", mp4Data(0, []byte{0, 0, 0, 4, 0, 10, 0, 0})),
+		mp4Atom("disk", mp4Data(0, []byte{0, 0, 0, 1, 0, 2})),
+		mp4Atom("covr", mp4Data(13, make([]byte, 64))),
+	)
+	meta := mp4Atom("meta", []byte{0, 0, 0, 0}, mp4Atom("hdlr", make([]byte, 25)), ilst)
+	file := bytes.Join([][]byte{
+		mp4Atom("ftyp", []byte("M4A "), make([]byte, 4)),
+		mp4Atom("mdat", make([]byte, 32)),
+		mp4Atom("moov", mp4Atom("mvhd", mvhd), mp4Atom("udta", meta)),
+	}, nil)
+
+	tags := read(t, file)
+	expected := Tags{Format: FormatMP4, Title: "Track Name", Album: "Album Name", Artist: "Track Artist", AlbumArtist: "Album Artist", Composer: "Composer",
+		Genre: "Rock", Date: "2012-06-01T07:00:00Z", Track: 4, TrackTotal: 10, Disc: 1, DiscTotal: 2, DurationSeconds: 185}
+	if *tags != expected {
+		t.Errorf("expected %+v, got %+v", expected, *tags)
+	}
+	if date := tags.ReleaseDate(); date != "2012-06-01" {
+		t.Errorf("expected release date 2012-06-01, got %s", date)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	valid := id3Tag(3, 0, id3Frame(3, "TIT2", latin1("Title")))
+	tests := []struct {
+		name string
+		file []byte
+		err  error
+	}{
+		{"empty", nil, ErrUnsupported},
+		{"wave", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), ErrUnsupported},
+		{"ID3v2.5", append([]byte("ID3\x05"), valid[4:]...), ErrUnsupported},
+		{"truncated ID3", valid[:15], ErrMalformed},
+		{"overrunning frame", id3Tag(3, 0, append(id3Frame(3, "TIT2", latin1("Title"))[:7], 0xFF, 0, 0)), ErrMalformed},
+		{"truncated FLAC", append([]byte("fLaC"), flacBlock(flacVorbisComment, true, vorbisComment("TITLE=x"))[:20]...), ErrMalformed},
+		{"MP4 without moov", mp4Atom("ftyp", []byte("M4A "), make([]byte, 4)), ErrMalformed},
+	}
+	for _, tt := range tests {
+		if _, err := Read(bytes.NewReader(tt.file), int64(len(tt.file))); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestReleaseDate(t *testing.T) {
+	for date, expected := range map[string]string{"1997": "1997-01-01", "1997-05-24": "1997-05-24", "1997-5": "1997-01-01", "97": "", "c. 1970": "", "": ""} {
+		tags := Tags{Date: date}
+		if got := tags.ReleaseDate(); got != expected {
+			t.Errorf("%q: expected %q, got %q", date, expected, got)
+		}
+	}
+}
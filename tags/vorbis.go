@@ -0,0 +1,123 @@
+package tags
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FLAC metadata block types.
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+	flacLastBlock     = 0x80
+)
+
+// readFLAC reads the Vorbis comment and stream info blocks of a FLAC file.
+func readFLAC(r io.ReaderAt, size int64) (*Tags, error) {
+	tags := &Tags{Format: FormatFLAC}
+	header := make([]byte, 4)
+	for offset := int64(4); ; {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return nil, fmt.Errorf("%w: truncated FLAC metadata", ErrMalformed)
+		}
+		blockType := header[0] &^ flacLastBlock
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+		if offset+length > size {
+			return nil, fmt.Errorf("%w: FLAC metadata block overruns the file", ErrMalformed)
+		}
+
+		if blockType == flacStreamInfo || blockType == flacVorbisComment {
+			block := make([]byte, length)
+			if _, err := r.ReadAt(block, offset); err != nil {
+				return nil, fmt.Errorf("%w: truncated FLAC metadata block", ErrMalformed)
+			}
+			if blockType == flacStreamInfo {
+				readStreamInfo(block, tags)
+			} else if err := readVorbisComment(block, tags); err != nil {
+				return nil, err
+			}
+		}
+
+		offset += length
+		if header[0]&flacLastBlock != 0 {
+			return tags, nil
+		}
+	}
+}
+
+// readStreamInfo works out the duration from a FLAC stream info block: its 20-bit sample rate and
+// 36-bit number of samples.
+func readStreamInfo(block []byte, tags *Tags) {
+	if len(block) < 18 {
+		return
+	}
+	sampleRate := uint64(block[10])<<12 | uint64(block[11])<<4 | uint64(block[12])>>4
+	samples := uint64(block[13]&0x0F)<<32 | uint64(binary.BigEndian.Uint32(block[14:18]))
+	if sampleRate > 0 {
+		tags.DurationSeconds = int((samples + sampleRate/2) / sampleRate)
+	}
+}
+
+// readVorbisComment reads a Vorbis comment: a vendor string and a list of NAME=value fields, every length
+// little-endian. Field names are case-insensitive.
+func readVorbisComment(block []byte, tags *Tags) error {
+	next := func() (string, bool) {
+		if len(block) < 4 {
+			return "", false
+		}
+		length := binary.LittleEndian.Uint32(block)
+		if uint64(length) > uint64(len(block)-4) {
+			return "", false
+		}
+		value := string(block[4 : 4+length])
+		block = block[4+length:]
+		return value, true
+	}
+
+	if _, ok := next(); !ok {
+		return fmt.Errorf("%w: truncated Vorbis comment vendor", ErrMalformed)
+	}
+	if len(block) < 4 {
+		return fmt.Errorf("%w: truncated Vorbis comment", ErrMalformed)
+	}
+	count := binary.LittleEndian.Uint32(block)
+	block = block[4:]
+	for i := uint32(0); i < count; i++ {
+		field, ok := next()
+		if !ok {
+			return fmt.Errorf("%w: truncated Vorbis comment field", ErrMalformed)
+		}
+		name, value, found := strings.Cut(field, "=")
+		if !found {
+			continue
+		}
+		switch strings.ToUpper(name) {
+		case "TITLE":
+			setText(&tags.Title, value)
+		case "ALBUM":
+			setText(&tags.Album, value)
+		case "ARTIST":
+			setText(&tags.Artist, value)
+		case "ALBUMARTIST", "ALBUM ARTIST":
+			setText(&tags.AlbumArtist, value)
+		case "COMPOSER":
+			setText(&tags.Composer, value)
+		case "GENRE":
+			setText(&tags.Genre, value)
+		case "DATE", "YEAR":
+			setText(&tags.Date, value)
+		case "TRACKNUMBER":
+			setPosition(&tags.Track, &tags.TrackTotal, value)
+		case "TRACKTOTAL", "TOTALTRACKS":
+			setTotal(&tags.TrackTotal, value)
+		case "DISCNUMBER":
+			setPosition(&tags.Disc, &tags.DiscTotal, value)
+		case "DISCTOTAL", "TOTALDISCS":
+			setTotal(&tags.DiscTotal, value)
+		}
+	}
+	return nil
+}